@@ -0,0 +1,151 @@
+package main
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	execintf "github.com/scrymastic/goosquery/sql/executor/interface"
+)
+
+// sqlKeywords are the keywords offered by tab completion
+var sqlKeywords = []string{
+	"SELECT", "FROM", "WHERE", "AND", "OR", "NOT", "IN", "IS", "NULL",
+	"LIKE", "GLOB", "REGEXP", "BETWEEN", "ORDER", "GROUP", "BY", "LIMIT",
+	"OFFSET", "AS", "ASC", "DESC", "DISTINCT", "JOIN", "CROSS", "ON",
+	"USING", "CAST", "COUNT", "SUM", "AVG", "MIN", "MAX",
+}
+
+// tableReference matches the tables of a statement with their optional alias
+var tableReference = regexp.MustCompile(`(?i)\b(?:from|join)\s+(\w+)(?:\s+(?:as\s+)?(\w+))?`)
+
+// completer completes dot-commands, table names, column names and keywords
+type completer struct {
+	tables   []string
+	columns  map[string][]string
+	keywords map[string]bool
+}
+
+// newCompleter creates a completer for the supported tables
+func newCompleter() *completer {
+	c := &completer{
+		columns:  make(map[string][]string),
+		keywords: make(map[string]bool),
+	}
+	for _, table := range execintf.GetTables() {
+		c.tables = append(c.tables, table.Name)
+		for _, column := range table.Schema {
+			c.columns[table.Name] = append(c.columns[table.Name], column.Name)
+		}
+	}
+	for _, keyword := range sqlKeywords {
+		c.keywords[keyword] = true
+	}
+	return c
+}
+
+// complete returns the completions of the word ending at pos in text
+func (c *completer) complete(text string, pos int) (int, []string) {
+	before := text[:pos]
+	start := pos
+	for start > 0 && isIdentifierByte(before[start-1]) {
+		start--
+	}
+	word := before[start:]
+
+	// Dot-commands are typed at the start of the input, .explain being
+	// followed by a query completed as such
+	trimmed := strings.TrimLeft(before, " \t")
+	fields := strings.Fields(trimmed)
+	explain := len(fields) > 0 && strings.EqualFold(fields[0], ".explain") && strings.ContainsAny(trimmed, " \t")
+	if strings.HasPrefix(trimmed, ".") && !explain {
+		if !strings.ContainsAny(trimmed, " \t") {
+			var names []string
+			for _, command := range shellCommands {
+				names = append(names, command.name())
+			}
+			return pos - len(trimmed), matchPrefix(names, trimmed)
+		}
+
+		// The first argument of commands taking one of a few values
+		if len(fields) > 2 || len(fields) == 2 && word == "" {
+			return pos, nil
+		}
+		if strings.EqualFold(fields[0], ".schema") {
+			return start, matchPrefix(c.tables, word)
+		}
+		for _, command := range shellCommands {
+			if strings.EqualFold(command.name(), fields[0]) {
+				return start, matchPrefix(command.arguments, word)
+			}
+		}
+		return pos, nil
+	}
+
+	// Columns of the table or alias before a dot
+	if start > 0 && before[start-1] == '.' {
+		qualifierStart := start - 1
+		for qualifierStart > 0 && isIdentifierByte(before[qualifierStart-1]) {
+			qualifierStart--
+		}
+		qualifier := before[qualifierStart : start-1]
+		return start, matchPrefix(c.referencedColumns(text, qualifier), word)
+	}
+
+	// Table names after FROM and JOIN
+	fields = strings.Fields(before[:start])
+	if len(fields) > 0 {
+		switch strings.ToUpper(fields[len(fields)-1]) {
+		case "FROM", "JOIN":
+			return start, matchPrefix(c.tables, word)
+		}
+	}
+
+	// Otherwise columns of the tables in the statement and keywords, which
+	// follow the case of the word typed so far
+	keywords := sqlKeywords
+	if word != strings.ToUpper(word) {
+		keywords = make([]string, len(sqlKeywords))
+		for i, keyword := range sqlKeywords {
+			keywords[i] = strings.ToLower(keyword)
+		}
+	}
+	candidates := append(c.referencedColumns(text, ""), keywords...)
+	return start, matchPrefix(candidates, word)
+}
+
+// referencedColumns returns the columns of the tables referenced in the
+// statement, or only of the table with the given name or alias
+func (c *completer) referencedColumns(text, qualifier string) []string {
+	var columns []string
+	for _, match := range tableReference.FindAllStringSubmatch(text, -1) {
+		table, alias := match[1], match[2]
+		if c.keywords[strings.ToUpper(alias)] {
+			alias = ""
+		}
+		if qualifier == "" || strings.EqualFold(qualifier, table) || strings.EqualFold(qualifier, alias) {
+			columns = append(columns, c.columns[strings.ToLower(table)]...)
+		}
+	}
+	return columns
+}
+
+// matchPrefix returns the sorted, distinct values starting with prefix,
+// ignoring case
+func matchPrefix(values []string, prefix string) []string {
+	seen := make(map[string]bool)
+	var matches []string
+	for _, value := range values {
+		if !seen[value] && len(value) >= len(prefix) && strings.EqualFold(value[:len(prefix)], prefix) {
+			seen[value] = true
+			matches = append(matches, value)
+		}
+	}
+	sort.Strings(matches)
+	return matches
+}
+
+// isIdentifierByte reports whether b can be part of a table or column name
+func isIdentifierByte(b byte) bool {
+	return b == '_' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b >= '0' && b <= '9'
+}
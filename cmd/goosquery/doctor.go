@@ -0,0 +1,187 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/scrymastic/goosquery/cmd/goosquery/format"
+	"github.com/scrymastic/goosquery/cmd/goosquery/lineedit"
+	"github.com/scrymastic/goosquery/sql/engine"
+	execintf "github.com/scrymastic/goosquery/sql/executor/interface"
+	"github.com/scrymastic/goosquery/sql/result"
+)
+
+// Statuses of the tables probed by the doctor subcommand
+const (
+	probeOK                 = "ok"
+	probeUnavailable        = "unavailable"
+	probeNotImplemented     = "not implemented"
+	probePermissionDenied   = "permission denied"
+	probeRequiresConstraint = "requires constraint"
+	probeError              = "error"
+)
+
+// probeResult is the outcome of querying a table
+type probeResult struct {
+	status   string
+	rows     int
+	duration time.Duration
+	message  string
+	ran      bool // whether the table was queried
+}
+
+// runDoctor implements the doctor subcommand, which queries every table to
+// report which of them work on this host
+func runDoctor(args []string) int {
+	flags := flag.NewFlagSet("doctor", flag.ContinueOnError)
+	formatFlag := flags.String("format", "table", "Output format: "+strings.Join(format.Names(), ", "))
+	timeoutFlag := flags.Duration("timeout", 30*time.Second, "Time allowed to each table")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: goosquery doctor [-format name] [-timeout duration] [table ...]")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+
+	formatter, err := format.Get(*formatFlag)
+	if err != nil {
+		printError(err)
+		return exitUsage
+	}
+	tables, err := selectTables(flags.Args(), execintf.GetTables())
+	if err != nil {
+		printError(err)
+		return exitUsage
+	}
+
+	sqlEngine := engine.NewEngine()
+	index := result.NewColumnIndex("table", "status", "rows", "time_ms", "message")
+	report := result.NewQueryResult()
+	counts := make(map[string]int)
+	for _, table := range tables {
+		probe := probeTable(sqlEngine.Execute, table, *timeoutFlag)
+		counts[probe.status]++
+
+		row := index.NewRow()
+		row.Add("table", table.Name)
+		row.Add("status", probe.status)
+		// Only the queries that succeeded have a row count
+		var rows, timeMs interface{}
+		if probe.status == probeOK {
+			rows = int64(probe.rows)
+		}
+		if probe.ran {
+			timeMs = probe.duration.Milliseconds()
+		}
+		row.Add("rows", rows)
+		row.Add("time_ms", timeMs)
+		row.Add("message", probe.message)
+		report.AppendResult(row)
+	}
+
+	options := format.DefaultOptions()
+	if lineedit.IsTerminal(os.Stdout) {
+		options.TerminalWidth, _ = lineedit.TerminalSize(os.Stdout)
+	}
+	var output bytes.Buffer
+	if err := formatter.Format(&output, report, options); err != nil {
+		printError(err)
+		return exitError
+	}
+	os.Stdout.Write(output.Bytes())
+
+	// The summary is kept out of the report, which may be read by programs
+	var summary []string
+	for _, status := range []string{probeOK, probeUnavailable, probeNotImplemented, probePermissionDenied, probeRequiresConstraint, probeError} {
+		if counts[status] > 0 {
+			summary = append(summary, fmt.Sprintf("%d %s", counts[status], status))
+		}
+	}
+	fmt.Fprintf(os.Stderr, "%d tables: %s\n", len(tables), strings.Join(summary, ", "))
+	return exitOK
+}
+
+// probeTable counts the rows of a table, unless the table is known not to
+// work without a constraint, not to be implemented or not to be provided on
+// this platform. Counting requests no column, so that generators skip the
+// work of the columns they compute on demand.
+func probeTable(execute func(query string) (*result.Results, error), table result.Table, timeout time.Duration) probeResult {
+	if !table.AvailableOn(runtime.GOOS) {
+		return probeResult{
+			status:  probeUnavailable,
+			message: "only on " + strings.Join(table.Platforms, ", "),
+		}
+	}
+	if table.StatusOn(runtime.GOOS) == result.NotImplemented {
+		return probeResult{status: probeNotImplemented}
+	}
+	var required []string
+	for _, column := range table.Schema {
+		if column.Options&result.Required != 0 {
+			required = append(required, column.Name)
+		}
+	}
+	if len(required) > 0 {
+		return probeResult{
+			status:  probeRequiresConstraint,
+			message: "WHERE clause needs " + strings.Join(required, " or "),
+		}
+	}
+
+	done := make(chan probeResult, 1)
+	started := time.Now()
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- probeResult{status: probeError, duration: time.Since(started), message: fmt.Sprintf("panic: %v", r), ran: true}
+			}
+		}()
+		results, err := execute("SELECT count(*) AS count FROM " + table.Name)
+		probe := probeResult{status: probeOK, duration: time.Since(started), ran: true}
+		if err != nil {
+			probe.status, probe.message = classifyProbeError(err), err.Error()
+		} else if results.Size() == 1 {
+			probe.rows, _ = (*results)[0].Get("count").(int)
+		}
+		done <- probe
+	}()
+
+	// Generators cannot be cancelled, so a query past the timeout is left
+	// running, its result being dropped by the buffered channel
+	select {
+	case probe := <-done:
+		return probe
+	case <-time.After(timeout):
+		return probeResult{status: probeError, duration: timeout, message: fmt.Sprintf("timed out after %v", timeout), ran: true}
+	}
+}
+
+// classifyProbeError tells the status of a table from the error of its query.
+// Generators often wrap errors as text, so messages are matched as well.
+func classifyProbeError(err error) string {
+	message := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(message, "not implemented"):
+		return probeNotImplemented
+	case strings.Contains(message, "not supported on this platform"):
+		return probeUnavailable
+	case errors.Is(err, fs.ErrPermission),
+		strings.Contains(message, "access is denied"),
+		strings.Contains(message, "access denied"),
+		strings.Contains(message, "permission denied"),
+		strings.Contains(message, "privilege"):
+		return probePermissionDenied
+	}
+	return probeError
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/scrymastic/goosquery/sql/engine"
+	"github.com/scrymastic/goosquery/sql/result"
+)
+
+func TestClassifyProbeError(t *testing.T) {
+	tests := []struct {
+		err    error
+		status string
+	}{
+		{errors.New("startup items are not implemented on windows"), probeNotImplemented},
+		{errors.New("Not Implemented"), probeNotImplemented},
+		{fmt.Errorf("reading the cache: %w", &fs.PathError{Op: "open", Path: "/proc/1/io", Err: fs.ErrPermission}), probePermissionDenied},
+		{os.ErrPermission, probePermissionDenied},
+		{errors.New("open /etc/shadow: permission denied"), probePermissionDenied},
+		{errors.New("OpenProcess: Access is denied."), probePermissionDenied},
+		{errors.New("access denied to the registry key"), probePermissionDenied},
+		{errors.New("a required privilege is not held by the client"), probePermissionDenied},
+		{errors.New("routes are not supported on this platform"), probeUnavailable},
+		{errors.New("the ARP cache is not supported on this platform"), probeUnavailable},
+		{errors.New("unexpected end of JSON input"), probeError},
+		{fs.ErrNotExist, probeError},
+	}
+	for _, test := range tests {
+		if status := classifyProbeError(test.err); status != test.status {
+			t.Errorf("%v: expected %s, got %s", test.err, test.status, status)
+		}
+	}
+}
+
+func TestProbeTableWithoutQuery(t *testing.T) {
+	tests := []struct {
+		table   result.Table
+		status  string
+		message string
+	}{
+		{
+			result.Table{Name: "elsewhere", Platforms: []string{"plan9"}},
+			probeUnavailable, "only on plan9",
+		},
+		{
+			result.Table{Name: "unfinished", Platforms: []string{runtime.GOOS}, Status: result.NotImplemented},
+			probeNotImplemented, "",
+		},
+		{
+			result.Table{Name: "lookup", Platforms: []string{runtime.GOOS}, Schema: result.Schema{
+				{Name: "path", Type: "TEXT", Options: result.Required},
+				{Name: "directory", Type: "TEXT", Options: result.Required},
+			}},
+			probeRequiresConstraint, "WHERE clause needs path or directory",
+		},
+	}
+	for _, test := range tests {
+		probe := probeTable(engine.NewEngine().Execute, test.table, time.Second)
+		if probe.status != test.status || probe.message != test.message || probe.ran {
+			t.Errorf("%s: expected %s %q without running, got %+v", test.table.Name, test.status, test.message, probe)
+		}
+	}
+}
+
+func TestProbeTableTimeout(t *testing.T) {
+	// The generator blocks until the end of the test
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+	blocked := func(query string) (*result.Results, error) {
+		<-release
+		return result.NewQueryResult(), nil
+	}
+
+	table := result.Table{Name: "hung", Platforms: []string{runtime.GOOS}}
+	started := time.Now()
+	probe := probeTable(blocked, table, 50*time.Millisecond)
+	if elapsed := time.Since(started); elapsed > 5*time.Second {
+		t.Fatalf("Expected the probe to return at the timeout, took %v", elapsed)
+	}
+	if probe.status != probeError || probe.message != "timed out after 50ms" || !probe.ran {
+		t.Errorf("Expected the table to time out, got %+v", probe)
+	}
+
+	// The next tables are probed as usual
+	counted := func(query string) (*result.Results, error) {
+		if query != "SELECT count(*) AS count FROM quick" {
+			t.Errorf("Unexpected probe query %q", query)
+		}
+		row := result.NewColumnIndex("count").NewRow()
+		row.Add("count", 3)
+		rows := result.NewQueryResult()
+		rows.AppendResult(row)
+		return rows, nil
+	}
+	probe = probeTable(counted, result.Table{Name: "quick", Platforms: []string{runtime.GOOS}}, time.Second)
+	if probe.status != probeOK || probe.rows != 3 {
+		t.Errorf("Expected 3 rows, got %+v", probe)
+	}
+}
@@ -0,0 +1,126 @@
+// Package format writes query results in the output formats of the shell
+package format
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/scrymastic/goosquery/sql/executor/operations"
+	"github.com/scrymastic/goosquery/sql/result"
+)
+
+// Options controls how values and headers are written
+type Options struct {
+	// Headers enables the row of column names in formats where it is optional
+	Headers bool
+	// NullValue is the text written for NULL values
+	NullValue string
+
+	// MaxWidth limits the display width of table cells, 0 for no limit
+	MaxWidth int
+	// Wrap breaks long table cells over several lines instead of truncating them
+	Wrap bool
+	// TerminalWidth is the width tables are fitted to, 0 when not writing to a terminal
+	TerminalWidth int
+	// Expanded selects the vertical layout of tables, one line per column
+	Expanded Expanded
+}
+
+// Expanded selects when tables are shown vertically, one line per column
+type Expanded int
+
+const (
+	// ExpandedOff always shows a row per line
+	ExpandedOff Expanded = iota
+	// ExpandedOn always shows a line per column
+	ExpandedOn
+	// ExpandedAuto shows a line per column when rows are wider than the terminal
+	ExpandedAuto
+)
+
+var expandedNames = []string{"off", "on", "auto"}
+
+func (e Expanded) String() string {
+	return expandedNames[e]
+}
+
+// ParseExpanded parses "off", "on" or "auto"
+func ParseExpanded(name string) (Expanded, error) {
+	for i, expandedName := range expandedNames {
+		if strings.EqualFold(name, expandedName) {
+			return Expanded(i), nil
+		}
+	}
+	return ExpandedOff, fmt.Errorf("invalid expanded mode: %s (expected on, off or auto)", name)
+}
+
+// DefaultOptions returns the options used unless changed by the user
+func DefaultOptions() Options {
+	return Options{Headers: true}
+}
+
+// Formatter writes query results in an output format
+type Formatter interface {
+	Format(w io.Writer, results *result.Results, opts Options) error
+}
+
+var formatters = map[string]Formatter{
+	"table":    tableFormatter{},
+	"json":     jsonFormatter{},
+	"ndjson":   ndjsonFormatter{},
+	"csv":      delimitedFormatter{separator: ','},
+	"tsv":      delimitedFormatter{separator: '\t'},
+	"line":     lineFormatter{},
+	"markdown": markdownFormatter{},
+	"html":     htmlFormatter{},
+}
+
+// Register makes a formatter available under the given name
+func Register(name string, formatter Formatter) {
+	formatters[strings.ToLower(name)] = formatter
+}
+
+// Get returns the formatter registered under the given name
+func Get(name string) (Formatter, error) {
+	formatter, ok := formatters[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("unknown output format: %s (available: %s)", name, strings.Join(Names(), ", "))
+	}
+	return formatter, nil
+}
+
+// Names returns the names of the registered formatters in order
+func Names() []string {
+	names := make([]string, 0, len(formatters))
+	for name := range formatters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// formatValue returns the text of a value, or the NULL text for nil
+func formatValue(value interface{}, opts Options) string {
+	switch v := value.(type) {
+	case nil:
+		return opts.NullValue
+	case []byte:
+		return string(v)
+	}
+	return operations.FormatValue(value)
+}
+
+// rowValues returns the text of the values of a row for the given columns
+func rowValues(row result.Result, columns []string, opts Options) []string {
+	values := make([]string, len(columns))
+	for i, column := range columns {
+		value, exists := row.Lookup(column)
+		if !exists {
+			value = nil
+		}
+		values[i] = formatValue(value, opts)
+	}
+	return values
+}
@@ -0,0 +1,193 @@
+package format
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/scrymastic/goosquery/sql/result"
+)
+
+// testResults returns rows with values that need quoting or escaping
+func testResults() *result.Results {
+	index := result.NewColumnIndex("name", "pid", "cmdline")
+	results := result.NewQueryResult()
+
+	first := index.NewRow()
+	first.Add("name", "cmd.exe")
+	first.Add("pid", int64(4))
+	first.Add("cmdline", `cmd /c "echo a,b"`)
+	results.AppendResult(first)
+
+	second := index.NewRow()
+	second.Add("name", "a|b\n<c>")
+	second.Add("pid", int64(10))
+	second.Add("cmdline", nil)
+	results.AppendResult(second)
+	return results
+}
+
+func formatString(t *testing.T, name string, results *result.Results, opts Options) string {
+	t.Helper()
+	formatter, err := Get(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := formatter.Format(&buf, results, opts); err != nil {
+		t.Fatalf("Format(%s) failed: %v", name, err)
+	}
+	return buf.String()
+}
+
+func TestFormats(t *testing.T) {
+	opts := Options{Headers: true, NullValue: "NULL"}
+	testCases := []struct {
+		format   string
+		expected string
+	}{
+		{"csv", "name,pid,cmdline\ncmd.exe,4,\"cmd /c \"\"echo a,b\"\"\"\n\"a|b\n<c>\",10,NULL\n"},
+		{"tsv", "name\tpid\tcmdline\ncmd.exe\t4\t\"cmd /c \"\"echo a,b\"\"\"\n\"a|b\n<c>\"\t10\tNULL\n"},
+		{"line", "   name = cmd.exe\n    pid = 4\ncmdline = cmd /c \"echo a,b\"\n\n   name = a|b\n<c>\n    pid = 10\ncmdline = NULL\n"},
+		{"ndjson", "{\"name\":\"cmd.exe\",\"pid\":4,\"cmdline\":\"cmd /c \\\"echo a,b\\\"\"}\n{\"name\":\"a|b\\n\\u003cc\\u003e\",\"pid\":10,\"cmdline\":null}\n"},
+		{"markdown", "| name | pid | cmdline |\n| --- | --- | --- |\n| cmd.exe | 4 | cmd /c \"echo a,b\" |\n| a\\|b<br><c> | 10 | NULL |\n"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.format, func(t *testing.T) {
+			if got := formatString(t, tc.format, testResults(), opts); got != tc.expected {
+				t.Errorf("Unexpected output:\n%s\nExpected:\n%s", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestTable(t *testing.T) {
+	results := testResults()
+	(*results)[1].Set("name", "a|b")
+
+	expected := "" +
+		"┌─────────┬─────┬───────────────────┐\n" +
+		"│ name    │ pid │ cmdline           │\n" +
+		"├─────────┼─────┼───────────────────┤\n" +
+		"│ cmd.exe │ 4   │ cmd /c \"echo a,b\" │\n" +
+		"│ a|b     │ 10  │ NULL              │\n" +
+		"└─────────┴─────┴───────────────────┘\n"
+	if got := formatString(t, "table", results, Options{Headers: true, NullValue: "NULL"}); got != expected {
+		t.Errorf("Unexpected output:\n%s\nExpected:\n%s", got, expected)
+	}
+}
+
+func TestTableFitsTerminal(t *testing.T) {
+	results := testResults()
+	(*results)[0].Set("cmdline", "C:\\Windows\\System32\\svchost.exe -k netsvcs -p -s Schedule")
+
+	opts := Options{Headers: true, TerminalWidth: 40}
+	expected := "" +
+		"┌──────────┬─────┬─────────────────────┐\n" +
+		"│ name     │ pid │ cmdline             │\n" +
+		"├──────────┼─────┼─────────────────────┤\n" +
+		"│ cmd.exe  │ 4   │ C:\\Windows\\System3… │\n" +
+		"│ a|b\\n<c> │ 10  │                     │\n" +
+		"└──────────┴─────┴─────────────────────┘\n"
+	if got := formatString(t, "table", results, opts); got != expected {
+		t.Errorf("Unexpected output:\n%s\nExpected:\n%s", got, expected)
+	}
+
+	opts.Wrap = true
+	opts.MaxWidth = 12
+	expected = "" +
+		"┌─────────┬─────┬──────────────┐\n" +
+		"│ name    │ pid │ cmdline      │\n" +
+		"├─────────┼─────┼──────────────┤\n" +
+		"│ cmd.exe │ 4   │ C:\\Windows\\S │\n" +
+		"│         │     │ ystem32\\svch │\n" +
+		"│         │     │ ost.exe -k   │\n" +
+		"│         │     │ netsvcs -p   │\n" +
+		"│         │     │ -s Schedule  │\n" +
+		"│ a|b     │ 10  │              │\n" +
+		"│ <c>     │     │              │\n" +
+		"└─────────┴─────┴──────────────┘\n"
+	if got := formatString(t, "table", results, opts); got != expected {
+		t.Errorf("Unexpected output:\n%s\nExpected:\n%s", got, expected)
+	}
+}
+
+func TestTableExpanded(t *testing.T) {
+	expected := "" +
+		"─[ RECORD 1 ]──────────────\n" +
+		"name    │ cmd.exe\n" +
+		"pid     │ 4\n" +
+		"cmdline │ cmd /c \"echo a,b\"\n" +
+		"─[ RECORD 2 ]──────────────\n" +
+		"name    │ a|b\\n<c>\n" +
+		"pid     │ 10\n" +
+		"cmdline │ \n"
+	if got := formatString(t, "table", testResults(), Options{Expanded: ExpandedOn}); got != expected {
+		t.Errorf("Unexpected output:\n%s\nExpected:\n%s", got, expected)
+	}
+
+	// Automatically only when the rows do not fit
+	opts := Options{Headers: true, Expanded: ExpandedAuto, TerminalWidth: 80}
+	if got := formatString(t, "table", testResults(), opts); !strings.HasPrefix(got, "┌") {
+		t.Errorf("Expected a table, got:\n%s", got)
+	}
+	opts.TerminalWidth = 30
+	if got := formatString(t, "table", testResults(), opts); !strings.HasPrefix(got, "─[ RECORD 1 ]") {
+		t.Errorf("Expected records, got:\n%s", got)
+	}
+}
+
+func TestHTML(t *testing.T) {
+	got := formatString(t, "html", testResults(), DefaultOptions())
+	for _, expected := range []string{
+		"<!DOCTYPE html>",
+		"<tr><th>name</th><th>pid</th><th>cmdline</th></tr>",
+		"<tr><td>cmd.exe</td><td>4</td><td>cmd /c &#34;echo a,b&#34;</td></tr>",
+		"<tr><td>a|b\n&lt;c&gt;</td><td>10</td><td></td></tr>",
+		"</html>",
+	} {
+		if !strings.Contains(got, expected) {
+			t.Errorf("Expected the document to contain %q:\n%s", expected, got)
+		}
+	}
+}
+
+func TestHeadersOff(t *testing.T) {
+	opts := Options{Headers: false}
+	if got := formatString(t, "csv", testResults(), opts); strings.HasPrefix(got, "name") {
+		t.Errorf("Expected no header row, got:\n%s", got)
+	}
+	if got := formatString(t, "table", testResults(), opts); strings.Contains(got, "name") || strings.Contains(got, "├") {
+		t.Errorf("Expected no header row, got:\n%s", got)
+	}
+}
+
+func TestEmptyResults(t *testing.T) {
+	for _, name := range Names() {
+		got := formatString(t, name, result.NewQueryResult(), DefaultOptions())
+		switch name {
+		case "json":
+			if got != "[]\n" {
+				t.Errorf("Expected an empty JSON array, got %q", got)
+			}
+		case "html":
+			if !strings.Contains(got, "<tbody>\n</tbody>") {
+				t.Errorf("Expected an empty table, got %q", got)
+			}
+		default:
+			if got != "" {
+				t.Errorf("%s: expected no output, got %q", name, got)
+			}
+		}
+	}
+}
+
+func TestGetUnknownFormat(t *testing.T) {
+	if _, err := Get("yaml"); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+	if _, err := Get("CSV"); err != nil {
+		t.Errorf("Expected format names to be case-insensitive: %v", err)
+	}
+}
@@ -0,0 +1,58 @@
+package format
+
+import (
+	"bufio"
+	"html"
+	"io"
+
+	"github.com/scrymastic/goosquery/sql/result"
+)
+
+// htmlFormatter writes a standalone HTML document with the rows in a table
+type htmlFormatter struct{}
+
+const htmlHeader = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>goosquery results</title>
+<style>
+table { border-collapse: collapse; font-family: sans-serif; font-size: 14px; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; vertical-align: top; }
+th { background: #f0f0f0; }
+td { white-space: pre-wrap; }
+</style>
+</head>
+<body>
+<table>
+`
+
+const htmlFooter = `</table>
+</body>
+</html>
+`
+
+func (htmlFormatter) Format(w io.Writer, results *result.Results, opts Options) error {
+	columns := results.GetColumns()
+
+	out := bufio.NewWriter(w)
+	out.WriteString(htmlHeader)
+	if opts.Headers && len(columns) > 0 {
+		out.WriteString("<thead>\n<tr>")
+		for _, column := range columns {
+			out.WriteString("<th>" + html.EscapeString(column) + "</th>")
+		}
+		out.WriteString("</tr>\n</thead>\n")
+	}
+	out.WriteString("<tbody>\n")
+	for _, row := range *results {
+		out.WriteString("<tr>")
+		for _, value := range rowValues(row, columns, opts) {
+			out.WriteString("<td>" + html.EscapeString(value) + "</td>")
+		}
+		out.WriteString("</tr>\n")
+	}
+	out.WriteString("</tbody>\n")
+	out.WriteString(htmlFooter)
+	return out.Flush()
+}
@@ -0,0 +1,42 @@
+package format
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+
+	"github.com/scrymastic/goosquery/sql/result"
+)
+
+// jsonFormatter writes the rows as an indented JSON array of objects
+type jsonFormatter struct{}
+
+func (jsonFormatter) Format(w io.Writer, results *result.Results, opts Options) error {
+	if results == nil || results.Size() == 0 {
+		_, err := io.WriteString(w, "[]\n")
+		return err
+	}
+
+	jsonData, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(jsonData, '\n'))
+	return err
+}
+
+// ndjsonFormatter writes one JSON object per row and line
+type ndjsonFormatter struct{}
+
+func (ndjsonFormatter) Format(w io.Writer, results *result.Results, opts Options) error {
+	out := bufio.NewWriter(w)
+	for _, row := range *results {
+		jsonData, err := row.MarshalJSON()
+		if err != nil {
+			return err
+		}
+		out.Write(jsonData)
+		out.WriteString("\n")
+	}
+	return out.Flush()
+}
@@ -0,0 +1,216 @@
+package format
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/scrymastic/goosquery/sql/result"
+)
+
+// minColumnWidth is the narrowest a column gets when fitting a table to
+// the terminal
+const minColumnWidth = 6
+
+// tableFormatter prints results in a table with box-drawing characters,
+// fitted to the terminal, or vertically with one line per column
+type tableFormatter struct{}
+
+func (tableFormatter) Format(w io.Writer, results *result.Results, opts Options) error {
+	if results == nil || results.Size() == 0 {
+		return nil
+	}
+
+	// Get column names and the text of every value
+	columns := results.GetColumns()
+	rows := make([][]string, 0, results.Size())
+	for _, row := range *results {
+		values := rowValues(row, columns, opts)
+		if !opts.Wrap {
+			for i, value := range values {
+				values[i] = controlEscaper.Replace(value)
+			}
+		}
+		rows = append(rows, values)
+	}
+
+	// Calculate column widths, the maximum width needed for each column
+	columnWidths := make([]int, len(columns))
+	if opts.Headers {
+		for i, col := range columns {
+			columnWidths[i] = displayWidth(col)
+		}
+	}
+	for _, values := range rows {
+		for i, value := range values {
+			for _, line := range strings.Split(value, "\n") {
+				columnWidths[i] = max(columnWidths[i], displayWidth(line))
+			}
+		}
+	}
+	if opts.MaxWidth > 0 {
+		for i := range columnWidths {
+			columnWidths[i] = min(columnWidths[i], opts.MaxWidth)
+		}
+	}
+
+	out := bufio.NewWriter(w)
+	wide := opts.TerminalWidth > 0 && tableWidth(columnWidths) > opts.TerminalWidth
+	if opts.Expanded == ExpandedOn || opts.Expanded == ExpandedAuto && wide {
+		writeVertical(out, columns, rows, opts)
+		return out.Flush()
+	}
+	if wide {
+		fitColumns(columnWidths, opts.TerminalWidth-tableWidth(make([]int, len(columns))))
+	}
+
+	border := func(left, middle, right string) {
+		out.WriteString(left)
+		for i, width := range columnWidths {
+			out.WriteString(strings.Repeat("─", width+2))
+			if i < len(columnWidths)-1 {
+				out.WriteString(middle)
+			}
+		}
+		out.WriteString(right + "\n")
+	}
+	line := func(values []string) {
+		cells := make([][]string, len(values))
+		height := 1
+		for i, value := range values {
+			cells[i] = cellLines(value, columnWidths[i], opts)
+			height = max(height, len(cells[i]))
+		}
+		for j := 0; j < height; j++ {
+			out.WriteString("│")
+			for i := range values {
+				text := ""
+				if j < len(cells[i]) {
+					text = cells[i][j]
+				}
+				out.WriteString(" " + pad(text, columnWidths[i]) + " │")
+			}
+			out.WriteString("\n")
+		}
+	}
+
+	// Print header
+	border("┌", "┬", "┐")
+	if opts.Headers {
+		line(columns)
+		border("├", "┼", "┤")
+	}
+
+	// Print rows
+	for _, values := range rows {
+		line(values)
+	}
+	border("└", "┴", "┘")
+
+	return out.Flush()
+}
+
+// writeVertical prints every row as a record with one line per column
+func writeVertical(out *bufio.Writer, columns []string, rows [][]string, opts Options) {
+	nameWidth := 0
+	for _, column := range columns {
+		nameWidth = max(nameWidth, displayWidth(column))
+	}
+
+	// Values take the rest of the terminal, or up to the maximum width
+	valueWidth := opts.MaxWidth
+	if opts.TerminalWidth > 0 {
+		available := max(opts.TerminalWidth-nameWidth-3, minColumnWidth)
+		if valueWidth == 0 || available < valueWidth {
+			valueWidth = available
+		}
+	}
+
+	// The record headers span the names and the widest value
+	lines := make([][][]string, len(rows))
+	recordWidth := nameWidth + 3
+	for i, values := range rows {
+		lines[i] = make([][]string, len(values))
+		for j, value := range values {
+			lines[i][j] = cellLines(value, valueWidth, opts)
+			for _, text := range lines[i][j] {
+				recordWidth = max(recordWidth, nameWidth+3+displayWidth(text))
+			}
+		}
+	}
+
+	for i := range rows {
+		header := fmt.Sprintf("─[ RECORD %d ]", i+1)
+		out.WriteString(header + strings.Repeat("─", max(recordWidth-displayWidth(header), 0)) + "\n")
+
+		for j, column := range columns {
+			for k, text := range lines[i][j] {
+				name := ""
+				if k == 0 {
+					name = column
+				}
+				out.WriteString(pad(name, nameWidth) + " │ " + text + "\n")
+			}
+		}
+	}
+}
+
+// cellLines returns the lines showing a value in a column of the given
+// width, wrapped or truncated. A width of 0 means the column is unlimited.
+func cellLines(value string, width int, opts Options) []string {
+	if width <= 0 {
+		return strings.Split(value, "\n")
+	}
+	if opts.Wrap {
+		return wrap(value, width)
+	}
+	return []string{truncate(value, width)}
+}
+
+// tableWidth returns the display width of a table with the given column
+// widths, including the borders and the padding around cells
+func tableWidth(columnWidths []int) int {
+	width := 1
+	for _, columnWidth := range columnWidths {
+		width += columnWidth + 3
+	}
+	return width
+}
+
+// fitColumns narrows the widest columns until the columns take at most the
+// available width in total, without making any column narrower than
+// minColumnWidth unless it already was
+func fitColumns(columnWidths []int, available int) {
+	total := func(limit int) int {
+		sum := 0
+		for _, width := range columnWidths {
+			sum += min(width, limit)
+		}
+		return sum
+	}
+
+	// Find the largest limit on column widths that fits
+	low, high := minColumnWidth, 0
+	for _, width := range columnWidths {
+		high = max(high, width)
+	}
+	for low < high {
+		limit := (low + high + 1) / 2
+		if total(limit) <= available {
+			low = limit
+		} else {
+			high = limit - 1
+		}
+	}
+
+	// Share the columns left over by the limit among the widest columns
+	spare := available - total(low)
+	for i, width := range columnWidths {
+		columnWidths[i] = min(width, low)
+		if width > low && spare > 0 {
+			columnWidths[i]++
+			spare--
+		}
+	}
+}
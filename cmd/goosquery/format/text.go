@@ -0,0 +1,90 @@
+package format
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/scrymastic/goosquery/sql/result"
+)
+
+// delimitedFormatter writes CSV or TSV, quoting values that contain the
+// separator, quotes or line breaks
+type delimitedFormatter struct {
+	separator rune
+}
+
+func (f delimitedFormatter) Format(w io.Writer, results *result.Results, opts Options) error {
+	out := csv.NewWriter(w)
+	out.Comma = f.separator
+
+	columns := results.GetColumns()
+	if opts.Headers && len(columns) > 0 {
+		if err := out.Write(columns); err != nil {
+			return err
+		}
+	}
+	for _, row := range *results {
+		if err := out.Write(rowValues(row, columns, opts)); err != nil {
+			return err
+		}
+	}
+
+	out.Flush()
+	return out.Error()
+}
+
+// lineFormatter writes one "column = value" line per column, with a blank
+// line between rows
+type lineFormatter struct{}
+
+func (lineFormatter) Format(w io.Writer, results *result.Results, opts Options) error {
+	columns := results.GetColumns()
+	width := 0
+	for _, column := range columns {
+		width = max(width, len(column))
+	}
+
+	out := bufio.NewWriter(w)
+	for i, row := range *results {
+		if i > 0 {
+			out.WriteString("\n")
+		}
+		for j, value := range rowValues(row, columns, opts) {
+			fmt.Fprintf(out, "%*s = %s\n", width, columns[j], value)
+		}
+	}
+	return out.Flush()
+}
+
+// markdownFormatter writes a GitHub flavored markdown table
+type markdownFormatter struct{}
+
+// markdownEscaper keeps values within their cell
+var markdownEscaper = strings.NewReplacer("|", `\|`, "\r\n", "<br>", "\n", "<br>")
+
+func (markdownFormatter) Format(w io.Writer, results *result.Results, opts Options) error {
+	columns := results.GetColumns()
+	if len(columns) == 0 {
+		return nil
+	}
+
+	out := bufio.NewWriter(w)
+	line := func(values []string) {
+		out.WriteString("|")
+		for _, value := range values {
+			out.WriteString(" " + markdownEscaper.Replace(value) + " |")
+		}
+		out.WriteString("\n")
+	}
+
+	// The header row is required by the table syntax
+	line(columns)
+	out.WriteString("|" + strings.Repeat(" --- |", len(columns)) + "\n")
+	for _, row := range *results {
+		line(rowValues(row, columns, opts))
+	}
+	return out.Flush()
+}
@@ -0,0 +1,111 @@
+package format
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/width"
+)
+
+// ellipsis marks values cut short to fit their column
+const ellipsis = "…"
+
+// runeWidth returns the number of terminal columns taken by a character:
+// 2 for East Asian wide characters, 0 for combining marks and controls
+func runeWidth(r rune) int {
+	switch {
+	case r < 0x20 || r == 0x7f:
+		return 0
+	case r < 0x300:
+		return 1
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf):
+		return 0
+	}
+	switch width.LookupRune(r).Kind() {
+	case width.EastAsianWide, width.EastAsianFullwidth:
+		return 2
+	}
+	return 1
+}
+
+// displayWidth returns the number of terminal columns taken by a string
+func displayWidth(s string) int {
+	n := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			// Only text beyond ASCII needs to be decoded
+			for _, r := range s[i:] {
+				n += runeWidth(r)
+			}
+			return n
+		}
+		n += runeWidth(rune(s[i]))
+	}
+	return n
+}
+
+// pad appends spaces to a string up to the given display width
+func pad(s string, columns int) string {
+	if n := columns - displayWidth(s); n > 0 {
+		return s + strings.Repeat(" ", n)
+	}
+	return s
+}
+
+// truncate shortens a string to the given display width, ending it with an
+// ellipsis when characters were removed
+func truncate(s string, columns int) string {
+	if displayWidth(s) <= columns {
+		return s
+	}
+	if columns <= 0 {
+		return ""
+	}
+
+	n := 0
+	for i, r := range s {
+		if n+runeWidth(r) > columns-1 {
+			return s[:i] + ellipsis
+		}
+		n += runeWidth(r)
+	}
+	return s
+}
+
+// wrap breaks a string into lines of at most the given display width, at
+// line breaks and, where possible, after spaces
+func wrap(s string, columns int) []string {
+	var lines []string
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		for displayWidth(line) > columns && columns > 0 {
+			// Find the last character that fits and the last space before it
+			n, cut, space := 0, 0, -1
+			for i, r := range line {
+				if n+runeWidth(r) > columns {
+					break
+				}
+				n += runeWidth(r)
+				cut = i + utf8.RuneLen(r)
+				if r == ' ' {
+					space = cut
+				}
+			}
+			if space > 0 {
+				cut = space
+			}
+			if cut == 0 {
+				// A single character wider than the column
+				_, cut = utf8.DecodeRuneInString(line)
+			}
+			lines = append(lines, line[:cut])
+			line = line[cut:]
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// controlEscaper shows line breaks and tabs of values on a single line
+var controlEscaper = strings.NewReplacer("\r\n", `\n`, "\n", `\n`, "\r", `\r`, "\t", " ")
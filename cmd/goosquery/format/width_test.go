@@ -0,0 +1,87 @@
+package format
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDisplayWidth(t *testing.T) {
+	testCases := []struct {
+		value    string
+		expected int
+	}{
+		{"cmd.exe", 7},
+		{"svchost.exe", 11},
+		{"café", 4},
+		{"cafe\u0301", 4},
+		{"微信.exe", 8},
+		{"ｆｕｌｌ", 8},
+		{"a\x00b", 2},
+	}
+	for _, tc := range testCases {
+		if got := displayWidth(tc.value); got != tc.expected {
+			t.Errorf("displayWidth(%q) = %d, want %d", tc.value, got, tc.expected)
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	testCases := []struct {
+		value    string
+		columns  int
+		expected string
+	}{
+		{"short", 10, "short"},
+		{"C:\\Windows\\System32\\svchost.exe", 10, "C:\\Window…"},
+		{"微信微信", 5, "微信…"},
+		{"微信微信", 6, "微信…"},
+		{"abc", 0, ""},
+	}
+	for _, tc := range testCases {
+		got := truncate(tc.value, tc.columns)
+		if got != tc.expected {
+			t.Errorf("truncate(%q, %d) = %q, want %q", tc.value, tc.columns, got, tc.expected)
+		}
+		if displayWidth(got) > tc.columns {
+			t.Errorf("truncate(%q, %d) is %d columns wide", tc.value, tc.columns, displayWidth(got))
+		}
+	}
+}
+
+func TestWrap(t *testing.T) {
+	testCases := []struct {
+		value    string
+		columns  int
+		expected []string
+	}{
+		{"short", 10, []string{"short"}},
+		{"svchost.exe -k netsvcs -p", 12, []string{"svchost.exe ", "-k netsvcs ", "-p"}},
+		{"abcdefghij", 4, []string{"abcd", "efgh", "ij"}},
+		{"line one\r\nline two", 20, []string{"line one", "line two"}},
+		{"微信微信", 5, []string{"微信", "微信"}},
+	}
+	for _, tc := range testCases {
+		if got := wrap(tc.value, tc.columns); !reflect.DeepEqual(got, tc.expected) {
+			t.Errorf("wrap(%q, %d) = %q, want %q", tc.value, tc.columns, got, tc.expected)
+		}
+	}
+}
+
+func TestFitColumns(t *testing.T) {
+	widths := []int{4, 40, 100, 10}
+	fitColumns(widths, 60)
+
+	total := 0
+	for _, width := range widths {
+		total += width
+	}
+	if total > 60 {
+		t.Errorf("Columns %v take %d columns, more than 60", widths, total)
+	}
+	if widths[0] != 4 || widths[3] != 10 {
+		t.Errorf("Expected narrow columns to keep their width, got %v", widths)
+	}
+	if widths[1] != 23 || widths[2] != 23 {
+		t.Errorf("Expected the wide columns to share the rest, got %v", widths)
+	}
+}
@@ -1,161 +1,120 @@
 package main
 
 import (
-	"bufio"
-	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 
-	"github.com/scrymastic/goosquery/sql/result"
+	"github.com/scrymastic/goosquery/cmd/goosquery/format"
+	"github.com/scrymastic/goosquery/cmd/goosquery/lineedit"
+)
+
+const (
+	historyFile = ".goosquery_history"
+	historySize = 1000
 )
 
 // readSQLInput reads input from the user, either a command or a multi-line SQL query
 // Returns the input string, whether it's a command, and any error encountered
-func readSQLInput(reader *bufio.Reader) (string, bool, error) {
-	var sqlBuffer strings.Builder
-
-	// Read first line
-	line, err := reader.ReadString('\n')
-	if err != nil {
-		return "", false, err
-	}
-
-	// Trim whitespace
-	line = strings.TrimSpace(line)
+func readSQLInput(editor *lineedit.Editor) (string, bool, error) {
+	input, err := editor.ReadLine("goosquery> ")
+	input = strings.TrimSpace(input)
 
 	// Check if this is a special command
-	if strings.HasPrefix(line, ".") {
-		return line, true, nil
-	}
+	return input, strings.HasPrefix(input, "."), err
+}
 
-	// Not a command, start collecting SQL
-	sqlBuffer.WriteString(line)
+// isCompleteInput reports whether the input is a command or a SQL query
+// terminated by a semicolon, so that the editor continues other input on a
+// new line
+func isCompleteInput(input string) bool {
+	input = strings.TrimSpace(input)
+	return input == "" || strings.HasPrefix(input, ".") || strings.HasSuffix(input, ";")
+}
 
-	// If line ends with semicolon, we're done
-	if strings.HasSuffix(line, ";") {
-		return sqlBuffer.String(), false, nil
-	}
+// newEditor creates the line editor of the interactive mode, with the
+// history saved in the home directory of the user
+func newEditor() *lineedit.Editor {
+	editor := lineedit.New(os.Stdin, os.Stdout)
+	editor.ContinuationPrompt = ".........> "
+	editor.IsComplete = isCompleteInput
+	editor.Complete = newCompleter().complete
+	editor.History = lineedit.NewHistory(historySize)
 
-	// Continue reading until semicolon is found
-	for {
-		if len(sqlBuffer.String()) > 0 {
-			fmt.Print(".........> ")
-		} else {
-			fmt.Print("goosquery> ")
-		}
-		line, err = reader.ReadString('\n')
-		if err != nil {
-			return sqlBuffer.String(), false, err
-		}
-
-		line = strings.TrimSpace(line)
-		if line == "" {
-			continue
-		}
-
-		sqlBuffer.WriteString(" " + line)
-
-		// Check if we've reached the end of the SQL statement
-		if strings.HasSuffix(line, ";") {
-			break
-		}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return editor
 	}
-
-	return sqlBuffer.String(), false, nil
-}
-
-// printOutputMode prints the current output mode
-func printOutputMode(jsonOutput bool) {
-	if jsonOutput {
-		fmt.Println("Output mode: JSON")
-	} else {
-		fmt.Println("Output mode: TABLE")
+	history, err := lineedit.LoadHistory(filepath.Join(home, historyFile), historySize)
+	if err != nil {
+		printError(fmt.Errorf("loading history: %w", err))
 	}
+	editor.History = history
+	return editor
 }
 
-// displayAsTable prints the query result in a formatted table with box-drawing characters
-func displayAsTable(queryResult *result.Results) {
-	if queryResult == nil || queryResult.Size() == 0 {
-		return
-	}
+// outputSettings holds the output mode of the shell and its options
+type outputSettings struct {
+	mode      string
+	formatter format.Formatter
+	options   format.Options
 
-	// Get column names
-	columns := (*queryResult).GetColumns()
+	interactive bool // whether the shell is interactive
+	pager       bool // whether long output is shown through a pager
+}
 
-	// Calculate column widths
-	columnWidths := make(map[string]int)
-	for _, col := range columns {
-		columnWidths[col] = len(col)
+// newOutputSettings creates the settings for an output mode with the
+// default options
+func newOutputSettings(mode string) (*outputSettings, error) {
+	settings := &outputSettings{options: format.DefaultOptions(), pager: true}
+	if err := settings.setMode(mode); err != nil {
+		return nil, err
 	}
+	return settings, nil
+}
 
-	// Find the maximum width needed for each column
-	for _, row := range *queryResult {
-		for col, value := range row {
-			strValue := fmt.Sprintf("%v", value)
-			if len(strValue) > columnWidths[col] {
-				columnWidths[col] = len(strValue)
-			}
-		}
+// setMode changes the output mode
+func (s *outputSettings) setMode(mode string) error {
+	formatter, err := format.Get(mode)
+	if err != nil {
+		return err
 	}
+	s.mode = strings.ToLower(mode)
+	s.formatter = formatter
+	return nil
+}
 
-	// Print header
-	// Top border
-	fmt.Print("┌")
-	for i, col := range columns {
-		fmt.Print(strings.Repeat("─", columnWidths[col]+2))
-		if i < len(columns)-1 {
-			fmt.Print("┬")
-		}
-	}
-	fmt.Println("┐")
+// printOutputMode prints the current output mode
+func printOutputMode(settings *outputSettings) {
+	fmt.Printf("Output mode: %s\n", strings.ToUpper(settings.mode))
+}
 
-	// Column names
-	fmt.Print("│")
-	for _, col := range columns {
-		fmt.Printf(" %-*s │", columnWidths[col], col)
-	}
-	fmt.Println()
-
-	// Separator line
-	fmt.Print("├")
-	for i, col := range columns {
-		fmt.Print(strings.Repeat("─", columnWidths[col]+2))
-		if i < len(columns)-1 {
-			fmt.Print("┼")
-		}
-	}
-	fmt.Println("┤")
-
-	// Print rows
-	for _, row := range *queryResult {
-		fmt.Print("│")
-		for _, col := range columns {
-			value, exists := row[col]
-			strValue := ""
-			if exists {
-				strValue = fmt.Sprintf("%v", value)
-			}
-			fmt.Printf(" %-*s │", columnWidths[col], strValue)
-		}
-		fmt.Println()
+// onOff returns the text of a boolean setting
+func onOff(enabled bool) string {
+	if enabled {
+		return "on"
 	}
+	return "off"
+}
 
-	// Bottom border
-	fmt.Print("└")
-	for i, col := range columns {
-		fmt.Print(strings.Repeat("─", columnWidths[col]+2))
-		if i < len(columns)-1 {
-			fmt.Print("┴")
-		}
+// setOnOff prints a boolean setting, or changes it to the on or off argument
+func setOnOff(name string, args []string, setting *bool) error {
+	switch {
+	case len(args) == 0:
+		fmt.Printf("%s: %s\n", name, onOff(*setting))
+	case strings.EqualFold(args[0], "on"), strings.EqualFold(args[0], "off"):
+		*setting = strings.EqualFold(args[0], "on")
+	default:
+		return fmt.Errorf("usage: .%s on|off", strings.ToLower(name))
 	}
-	fmt.Println("┘")
+	return nil
 }
 
-// formatResultAsJSON converts the query result to JSON format
-func formatResultAsJSON(queryResult *result.Results) (string, error) {
-	jsonData, err := json.MarshalIndent(queryResult, "", "  ")
-	if err != nil {
-		return "", err
+// unquote removes the quotes around an argument of a command
+func unquote(arg string) string {
+	if len(arg) >= 2 && (arg[0] == '"' || arg[0] == '\'') && arg[len(arg)-1] == arg[0] {
+		return arg[1 : len(arg)-1]
 	}
-	return string(jsonData), nil
+	return arg
 }
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+const libraryFile = ".goosquery_queries.json"
+
+// queryLibrary holds the queries saved with .save, stored as a JSON object
+// of names and queries in the home directory of the user
+type queryLibrary struct {
+	path    string
+	queries map[string]string
+}
+
+// loadQueryLibrary reads the saved queries, if any were saved yet
+func loadQueryLibrary() (*queryLibrary, error) {
+	library := &queryLibrary{queries: make(map[string]string)}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return library, err
+	}
+	library.path = filepath.Join(home, libraryFile)
+
+	data, err := os.ReadFile(library.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return library, nil
+	}
+	if err != nil {
+		return library, err
+	}
+	return library, json.Unmarshal(data, &library.queries)
+}
+
+// get returns the query saved under name
+func (l *queryLibrary) get(name string) (string, bool) {
+	query, ok := l.queries[name]
+	return query, ok
+}
+
+// names returns the names of the saved queries in sorted order
+func (l *queryLibrary) names() []string {
+	names := make([]string, 0, len(l.queries))
+	for name := range l.queries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// save stores a query under name, replacing any query of the same name
+func (l *queryLibrary) save(name, query string) error {
+	if l.path == "" {
+		return errors.New("no home directory to save queries in")
+	}
+	l.queries[name] = query
+
+	data, err := json.MarshalIndent(l.queries, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(l.path, append(data, '\n'), 0600)
+}
@@ -0,0 +1,646 @@
+// Package lineedit implements the line editor of the interactive shell: cursor
+// movement and editing, multi-line input, persistent history with reverse
+// search, and tab completion.
+package lineedit
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ErrInterrupted is returned by ReadLine when the user presses Ctrl-C
+var ErrInterrupted = errors.New("interrupted")
+
+// Completer returns the candidates that may replace the word ending at the
+// byte offset pos of text, and the byte offset at which that word starts
+type Completer func(text string, pos int) (start int, candidates []string)
+
+// Editor reads lines from a terminal, which it puts in raw mode while
+// editing. Input that is not a terminal is read line by line.
+type Editor struct {
+	// ContinuationPrompt is shown in front of every line but the first
+	ContinuationPrompt string
+	// IsComplete reports whether the text can be submitted when Enter is
+	// pressed. Otherwise Enter starts a new line. Any text is complete if nil.
+	IsComplete func(text string) bool
+	// Complete lists the completions of the word before the cursor on Tab
+	Complete Completer
+	// History holds the submitted lines. It may be nil.
+	History *History
+
+	in   *bufio.Reader
+	out  io.Writer
+	fd   uintptr
+	term bool
+
+	// State of the line being edited
+	prompt    string
+	buf       []rune
+	pos       int
+	width     int
+	cursorRow int    // terminal row of the cursor, relative to the prompt
+	histIndex int    // history entry shown, History.Len() for the new line
+	pending   []rune // the new line, saved while browsing the history
+	lastTab   bool   // whether the previous key was Tab
+}
+
+// New creates an editor reading keys from in and drawing on out
+func New(in io.Reader, out io.Writer) *Editor {
+	e := &Editor{in: bufio.NewReader(in), out: out}
+	if file, ok := in.(*os.File); ok && isTerminal(file.Fd()) {
+		e.fd, e.term = file.Fd(), true
+	}
+	return e
+}
+
+// IsTerminal reports whether the file is a terminal
+func IsTerminal(file *os.File) bool {
+	return isTerminal(file.Fd())
+}
+
+// TerminalSize returns the columns and rows of the terminal of the file, or
+// zeros when they are unknown
+func TerminalSize(file *os.File) (width, height int) {
+	return terminalSize(file.Fd())
+}
+
+// ReadLine shows the prompt and returns the text entered by the user, without
+// the final newline. It returns io.EOF when the input ends or the user presses
+// Ctrl-D on an empty line, and ErrInterrupted when the user presses Ctrl-C.
+func (e *Editor) ReadLine(prompt string) (string, error) {
+	if !e.term {
+		return e.readPlain(prompt)
+	}
+
+	restore, err := makeRaw(e.fd)
+	if err != nil {
+		return e.readPlain(prompt)
+	}
+	defer restore()
+
+	e.width, _ = terminalSize(e.fd)
+	return e.edit(prompt)
+}
+
+// readPlain reads lines without editing, for input that is not a terminal
+func (e *Editor) readPlain(prompt string) (string, error) {
+	var lines []string
+	for {
+		fmt.Fprint(e.out, prompt)
+		prompt = e.ContinuationPrompt
+
+		line, err := e.in.ReadString('\n')
+		line = strings.TrimRight(line, "\r\n")
+		if err != nil {
+			if line != "" {
+				lines = append(lines, line)
+			}
+			return strings.Join(lines, "\n"), err
+		}
+
+		// Blank lines do not continue a statement
+		if strings.TrimSpace(line) == "" && len(lines) > 0 {
+			continue
+		}
+		lines = append(lines, line)
+
+		text := strings.Join(lines, "\n")
+		if e.IsComplete == nil || e.IsComplete(text) {
+			return text, nil
+		}
+	}
+}
+
+// edit runs the editing loop until a line is submitted
+func (e *Editor) edit(prompt string) (string, error) {
+	if e.width <= 0 {
+		e.width = 80
+	}
+	e.prompt = prompt
+	e.buf = nil
+	e.pos = 0
+	e.cursorRow = 0
+	e.histIndex = e.History.Len()
+	e.pending = nil
+	e.lastTab = false
+	e.refresh()
+
+	var k key
+	for {
+		// A key that ends the reverse search is handled as usual
+		if k == 0 {
+			var err error
+			if k, err = e.readKey(); err != nil {
+				return "", err
+			}
+		}
+
+		next := key(0)
+		switch k {
+		case '\r', '\n':
+			text := string(e.buf)
+			if e.IsComplete == nil || e.IsComplete(text) {
+				e.finish("")
+				if err := e.History.Add(text); err != nil {
+					fmt.Fprintf(e.out, "Error saving history: %v\r\n", err)
+				}
+				return text, nil
+			}
+			e.insert('\n')
+		case ctrl('C'):
+			e.finish("^C")
+			return "", ErrInterrupted
+		case ctrl('D'):
+			if len(e.buf) == 0 {
+				e.finish("")
+				return "", io.EOF
+			}
+			e.delete(e.pos, e.pos+1)
+		case ctrl('A'), keyHome:
+			e.pos = e.lineStart()
+		case ctrl('E'), keyEnd:
+			e.pos = e.lineEnd()
+		case ctrl('B'), keyLeft:
+			e.pos = max(e.pos-1, 0)
+		case ctrl('F'), keyRight:
+			e.pos = min(e.pos+1, len(e.buf))
+		case keyWordLeft:
+			e.pos = e.wordStart()
+		case keyWordRight:
+			e.pos = e.wordEnd()
+		case 127, ctrl('H'):
+			if e.pos > 0 {
+				e.delete(e.pos-1, e.pos)
+			}
+		case keyDelete:
+			e.delete(e.pos, e.pos+1)
+		case ctrl('K'):
+			e.delete(e.pos, e.lineEnd())
+		case ctrl('U'):
+			e.delete(e.lineStart(), e.pos)
+		case ctrl('W'):
+			e.delete(e.wordStart(), e.pos)
+		case ctrl('L'):
+			fmt.Fprint(e.out, "\x1b[H\x1b[2J")
+			e.cursorRow = 0
+		case ctrl('P'):
+			e.historyPrev()
+		case ctrl('N'):
+			e.historyNext()
+		case keyUp:
+			if e.lineStart() > 0 {
+				e.moveLine(-1)
+			} else {
+				e.historyPrev()
+			}
+		case keyDown:
+			if e.lineEnd() < len(e.buf) {
+				e.moveLine(1)
+			} else {
+				e.historyNext()
+			}
+		case '\t':
+			e.complete()
+		case ctrl('R'):
+			var err error
+			if next, err = e.search(); err != nil {
+				return "", err
+			}
+		default:
+			if k >= ' ' {
+				e.insert(rune(k))
+			}
+		}
+
+		e.lastTab = k == '\t'
+		e.refresh()
+		k = next
+	}
+}
+
+// finish moves the cursor past the text so that output starts on a new line
+func (e *Editor) finish(suffix string) {
+	e.pos = len(e.buf)
+	e.refresh()
+	fmt.Fprint(e.out, suffix+"\r\n")
+}
+
+// insert adds a character at the cursor
+func (e *Editor) insert(r rune) {
+	e.buf = append(e.buf, 0)
+	copy(e.buf[e.pos+1:], e.buf[e.pos:])
+	e.buf[e.pos] = r
+	e.pos++
+}
+
+// replace replaces the characters between from and to with text
+func (e *Editor) replace(from, to int, text []rune) {
+	buf := make([]rune, 0, len(e.buf)-(to-from)+len(text))
+	buf = append(buf, e.buf[:from]...)
+	buf = append(buf, text...)
+	e.buf = append(buf, e.buf[to:]...)
+	e.pos = from + len(text)
+}
+
+// delete removes the characters between from and to
+func (e *Editor) delete(from, to int) {
+	to = min(to, len(e.buf))
+	if from >= to {
+		return
+	}
+	e.replace(from, to, nil)
+	e.pos = from
+}
+
+// lineStart returns the start of the line under the cursor
+func (e *Editor) lineStart() int {
+	i := e.pos
+	for i > 0 && e.buf[i-1] != '\n' {
+		i--
+	}
+	return i
+}
+
+// lineEnd returns the end of the line under the cursor
+func (e *Editor) lineEnd() int {
+	i := e.pos
+	for i < len(e.buf) && e.buf[i] != '\n' {
+		i++
+	}
+	return i
+}
+
+// moveLine moves the cursor to the previous or next line, keeping its column
+func (e *Editor) moveLine(delta int) {
+	column := e.pos - e.lineStart()
+	if delta < 0 {
+		e.pos = e.lineStart() - 1
+		e.pos = e.lineStart()
+	} else {
+		e.pos = e.lineEnd() + 1
+	}
+	e.pos = min(e.pos+column, e.lineEnd())
+}
+
+// wordStart returns the start of the word before the cursor
+func (e *Editor) wordStart() int {
+	i := e.pos
+	for i > 0 && !isWordRune(e.buf[i-1]) {
+		i--
+	}
+	for i > 0 && isWordRune(e.buf[i-1]) {
+		i--
+	}
+	return i
+}
+
+// wordEnd returns the end of the word after the cursor
+func (e *Editor) wordEnd() int {
+	i := e.pos
+	for i < len(e.buf) && !isWordRune(e.buf[i]) {
+		i++
+	}
+	for i < len(e.buf) && isWordRune(e.buf[i]) {
+		i++
+	}
+	return i
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// historyPrev shows the previous history entry
+func (e *Editor) historyPrev() {
+	if e.histIndex == 0 {
+		return
+	}
+	if e.histIndex == e.History.Len() {
+		e.pending = append([]rune(nil), e.buf...)
+	}
+	e.histIndex--
+	e.buf = []rune(e.History.Entry(e.histIndex))
+	e.pos = len(e.buf)
+}
+
+// historyNext shows the next history entry, or the new line after the last
+func (e *Editor) historyNext() {
+	if e.histIndex >= e.History.Len() {
+		return
+	}
+	e.histIndex++
+	if e.histIndex == e.History.Len() {
+		e.buf = e.pending
+	} else {
+		e.buf = []rune(e.History.Entry(e.histIndex))
+	}
+	e.pos = len(e.buf)
+}
+
+// search runs an incremental reverse search of the history. It returns the
+// key that ended the search, to be handled as usual, or 0 if it was cancelled.
+func (e *Editor) search() (key, error) {
+	var query []rune
+	index := e.History.Len()
+	failed := false
+
+	for {
+		// Show the match on a single line with the query under the cursor
+		match := []rune(strings.ReplaceAll(string(e.buf), "\n", " "))
+		pos := e.pos
+		if index < e.History.Len() {
+			text := e.History.Entry(index)
+			match = []rune(strings.ReplaceAll(text, "\n", " "))
+			pos = 0
+			if i := strings.Index(text, string(query)); i >= 0 {
+				pos = utf8.RuneCountInString(text[:i])
+			}
+		}
+		prompt := fmt.Sprintf("(reverse-i-search)`%s': ", string(query))
+		if failed {
+			prompt = "(failed " + prompt[1:]
+		}
+		e.render(prompt, match, pos)
+
+		k, err := e.readKey()
+		if err != nil {
+			return 0, err
+		}
+
+		from := index + 1
+		switch {
+		case k == ctrl('R'):
+			from = index
+		case k == 127 || k == ctrl('H'):
+			if len(query) > 0 {
+				query = query[:len(query)-1]
+			}
+		case k == ctrl('G') || k == ctrl('C'):
+			return 0, nil
+		case k >= ' ':
+			query = append(query, rune(k))
+		default:
+			// Any other key accepts the match
+			if index < e.History.Len() {
+				e.histIndex = index
+				e.buf = []rune(e.History.Entry(index))
+				e.pos = len(e.buf)
+			}
+			return k, nil
+		}
+
+		if len(query) == 0 {
+			index, failed = e.History.Len(), false
+			continue
+		}
+		found := e.History.Search(string(query), from)
+		failed = found < 0
+		if !failed {
+			index = found
+		}
+	}
+}
+
+// complete replaces the word before the cursor with its completion. When
+// there are several, it completes their common prefix, and lists them when
+// Tab is pressed twice.
+func (e *Editor) complete() {
+	if e.Complete == nil {
+		return
+	}
+	text := string(e.buf)
+	pos := len(string(e.buf[:e.pos]))
+	start, candidates := e.Complete(text, pos)
+	start = utf8.RuneCountInString(text[:min(max(start, 0), pos)])
+
+	switch {
+	case len(candidates) == 0:
+		fmt.Fprint(e.out, "\a")
+	case len(candidates) == 1:
+		e.replace(start, e.pos, []rune(candidates[0]))
+	default:
+		prefix := commonPrefix(candidates)
+		if utf8.RuneCountInString(prefix) > e.pos-start {
+			e.replace(start, e.pos, []rune(prefix))
+		} else if e.lastTab {
+			e.listCandidates(candidates)
+		} else {
+			fmt.Fprint(e.out, "\a")
+		}
+	}
+}
+
+// listCandidates prints completions in columns below the text
+func (e *Editor) listCandidates(candidates []string) {
+	pos := e.pos
+	e.finish("")
+	e.pos = pos
+
+	columnWidth := 0
+	for _, candidate := range candidates {
+		columnWidth = max(columnWidth, utf8.RuneCountInString(candidate)+2)
+	}
+	columns := max(e.width/columnWidth, 1)
+
+	var sb strings.Builder
+	for i, candidate := range candidates {
+		sb.WriteString(candidate)
+		if (i+1)%columns == 0 || i == len(candidates)-1 {
+			sb.WriteString("\r\n")
+		} else {
+			sb.WriteString(strings.Repeat(" ", columnWidth-utf8.RuneCountInString(candidate)))
+		}
+	}
+	fmt.Fprint(e.out, sb.String())
+	e.cursorRow = 0
+}
+
+// commonPrefix returns the longest prefix shared by all strings
+func commonPrefix(values []string) string {
+	prefix := values[0]
+	for _, value := range values[1:] {
+		for !strings.HasPrefix(value, prefix) {
+			_, size := utf8.DecodeLastRuneInString(prefix)
+			prefix = prefix[:len(prefix)-size]
+		}
+	}
+	return prefix
+}
+
+// refresh redraws the prompt and the text being edited
+func (e *Editor) refresh() {
+	e.render(e.prompt, e.buf, e.pos)
+}
+
+// render redraws the prompt and text, each line of the text after the first
+// following a continuation prompt, and puts the cursor at pos. Lines longer
+// than the terminal wrap over several rows.
+func (e *Editor) render(prompt string, text []rune, pos int) {
+	var sb strings.Builder
+
+	// Go back to the first row of the previous rendering and clear it all
+	if e.cursorRow > 0 {
+		fmt.Fprintf(&sb, "\x1b[%dA", e.cursorRow)
+	}
+	sb.WriteString("\r\x1b[J")
+
+	row, start := 0, 0
+	cursorRow, cursorColumn := 0, 0
+	endRow, endColumn := 0, 0
+	for i := 0; start <= len(text); i++ {
+		end := start
+		for end < len(text) && text[end] != '\n' {
+			end++
+		}
+		last := end == len(text)
+
+		linePrompt := prompt
+		if i > 0 {
+			linePrompt = e.ContinuationPrompt
+		}
+		sb.WriteString(linePrompt)
+		sb.WriteString(string(text[start:end]))
+
+		// Columns of the prompt and line, then where the terminal leaves the
+		// cursor, moving it to the next row explicitly after an exact fit
+		promptWidth := utf8.RuneCountInString(linePrompt)
+		width := promptWidth + end - start
+		exactFit := width > 0 && width%e.width == 0
+		if exactFit {
+			sb.WriteString("\r\n")
+		}
+		endRow, endColumn = row+width/e.width, width%e.width
+
+		if pos >= start && pos <= end {
+			column := promptWidth + pos - start
+			cursorRow, cursorColumn = row+column/e.width, column%e.width
+			if exactFit && pos == end && !last {
+				cursorRow, cursorColumn = endRow-1, e.width-1
+			}
+		}
+
+		if last {
+			break
+		}
+		if !exactFit {
+			sb.WriteString("\r\n")
+		}
+		row = row + width/e.width
+		if !exactFit {
+			row++
+		}
+		start = end + 1
+	}
+
+	// Move from the end of the text to the cursor
+	if endRow > cursorRow {
+		fmt.Fprintf(&sb, "\x1b[%dA", endRow-cursorRow)
+	}
+	if cursorColumn != endColumn {
+		sb.WriteString("\r")
+		if cursorColumn > 0 {
+			fmt.Fprintf(&sb, "\x1b[%dC", cursorColumn)
+		}
+	}
+	e.cursorRow = cursorRow
+
+	fmt.Fprint(e.out, sb.String())
+}
+
+// key is a character typed, or one of the special keys below
+type key rune
+
+const (
+	keyUnknown key = -(iota + 1)
+	keyUp
+	keyDown
+	keyLeft
+	keyRight
+	keyHome
+	keyEnd
+	keyDelete
+	keyWordLeft
+	keyWordRight
+)
+
+// ctrl returns the key typed with Ctrl and a letter
+func ctrl(c byte) key {
+	return key(c & 0x1f)
+}
+
+// readKey reads a character, or decodes the escape sequence of a special key
+func (e *Editor) readKey() (key, error) {
+	r, _, err := e.in.ReadRune()
+	if err != nil {
+		return 0, err
+	}
+	if r != 0x1b {
+		return key(r), nil
+	}
+
+	r, _, err = e.in.ReadRune()
+	if err != nil {
+		return 0, err
+	}
+	switch r {
+	case '[', 'O':
+	case 'b', 'B':
+		return keyWordLeft, nil
+	case 'f', 'F':
+		return keyWordRight, nil
+	default:
+		return keyUnknown, nil
+	}
+
+	// Read the parameters and final byte of a CSI or SS3 sequence
+	var params strings.Builder
+	for {
+		b, err := e.in.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		if b < 0x40 || b > 0x7e {
+			params.WriteByte(b)
+			continue
+		}
+
+		// Ctrl or Alt with the arrows move by words
+		modified := strings.HasSuffix(params.String(), ";5") || strings.HasSuffix(params.String(), ";3")
+		switch b {
+		case 'A':
+			return keyUp, nil
+		case 'B':
+			return keyDown, nil
+		case 'C':
+			if modified {
+				return keyWordRight, nil
+			}
+			return keyRight, nil
+		case 'D':
+			if modified {
+				return keyWordLeft, nil
+			}
+			return keyLeft, nil
+		case 'H':
+			return keyHome, nil
+		case 'F':
+			return keyEnd, nil
+		case '~':
+			switch params.String() {
+			case "1", "7":
+				return keyHome, nil
+			case "4", "8":
+				return keyEnd, nil
+			case "3":
+				return keyDelete, nil
+			}
+		}
+		return keyUnknown, nil
+	}
+}
@@ -0,0 +1,215 @@
+package lineedit
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+// editLine feeds keys to the editing loop and returns the submitted text
+func editLine(t *testing.T, e *Editor, keys string) string {
+	t.Helper()
+	e.in.Reset(strings.NewReader(keys))
+	text, err := e.edit("> ")
+	if err != nil {
+		t.Fatalf("edit(%q) failed: %v", keys, err)
+	}
+	return text
+}
+
+func newTestEditor() *Editor {
+	e := New(strings.NewReader(""), &bytes.Buffer{})
+	e.ContinuationPrompt = "> "
+	e.IsComplete = func(text string) bool {
+		return strings.HasSuffix(strings.TrimSpace(text), ";")
+	}
+	return e
+}
+
+func TestEditing(t *testing.T) {
+	testCases := []struct {
+		name     string
+		keys     string
+		expected string
+	}{
+		{"insert", "select 1;\r", "select 1;"},
+		{"insert before cursor", "selct 1;\x02\x02\x02\x02\x02e\r", "select 1;"},
+		{"backspace", "selectt\x7f 1;\r", "select 1;"},
+		{"home and end", "elect\x01s\x05 1;\r", "select 1;"},
+		{"arrow keys", "elect 1;\x1b[H\x1b[Cs\x1b[F\r", "eslect 1;"},
+		{"delete key", "sselect 1;\x1b[H\x1b[3~\r", "select 1;"},
+		{"kill to end", "select 1; junk\x1b[D\x1b[D\x1b[D\x1b[D\x1b[D\x0b\r", "select 1;"},
+		{"kill to start", "junk select 1;\x1b[1;5D\x1b[1;5D\x15\r", "select 1;"},
+		{"delete word", "select junk\x17 1;\r", "select  1;"},
+		{"utf-8", "select 'héllo';\r", "select 'héllo';"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := editLine(t, newTestEditor(), tc.keys); got != tc.expected {
+				t.Errorf("Expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestMultiLineEditing(t *testing.T) {
+	e := newTestEditor()
+
+	// Enter continues an incomplete statement on a new line
+	if got := editLine(t, e, "select name\rfrom processes;\r"); got != "select name\nfrom processes;" {
+		t.Errorf("Unexpected text: %q", got)
+	}
+
+	// Up moves to the same column of the previous line before it recalls history
+	if got := editLine(t, e, "select\rpid;\x1b[Ax\r"); got != "selexct\npid;" {
+		t.Errorf("Unexpected text: %q", got)
+	}
+}
+
+func TestHistoryNavigation(t *testing.T) {
+	e := newTestEditor()
+	e.History = NewHistory(10)
+	_ = e.History.Add("select 1;")
+	_ = e.History.Add("select 2;")
+
+	testCases := []struct {
+		keys     string
+		expected string
+	}{
+		{"\x1b[A\r", "select 2;"},
+		{"\x1b[A\x1b[A\r", "select 1;"},
+		{"\x10\x10\x10\x0e\r", "select 2;"},
+		{"new\x1b[A\x1b[B;\r", "new;"},
+	}
+	for _, tc := range testCases {
+		if got := editLine(t, e, tc.keys); got != tc.expected {
+			t.Errorf("%q: expected %q, got %q", tc.keys, tc.expected, got)
+		}
+	}
+
+	// Submitted lines are added to the history
+	if got := e.History.Entry(e.History.Len() - 1); got != "new;" {
+		t.Errorf("Expected the last entry to be %q, got %q", "new;", got)
+	}
+}
+
+func TestReverseSearch(t *testing.T) {
+	testCases := []struct {
+		keys     string
+		expected string
+	}{
+		{"\x12proc\r", "select pid from processes;"},
+		{"\x12proc\x12\r", "select * from processes;"},
+		{"\x12usersx\x7f\r", "select * from users;"},
+		{"\x12proc\x05 \x7f\r", "select pid from processes;"},
+		{"select 1;\x12proc\x07\r", "select 1;"},
+	}
+	for _, tc := range testCases {
+		e := newTestEditor()
+		e.History = NewHistory(10)
+		_ = e.History.Add("select * from processes;")
+		_ = e.History.Add("select * from users;")
+		_ = e.History.Add("select pid from processes;")
+
+		if got := editLine(t, e, tc.keys); got != tc.expected {
+			t.Errorf("%q: expected %q, got %q", tc.keys, tc.expected, got)
+		}
+	}
+}
+
+func TestCompletion(t *testing.T) {
+	e := newTestEditor()
+	words := []string{"processes", "process_open_sockets", "users"}
+	e.Complete = func(text string, pos int) (int, []string) {
+		start := strings.LastIndex(text[:pos], " ") + 1
+		var candidates []string
+		for _, word := range words {
+			if strings.HasPrefix(word, text[start:pos]) {
+				candidates = append(candidates, word)
+			}
+		}
+		return start, candidates
+	}
+
+	testCases := []struct {
+		keys     string
+		expected string
+	}{
+		{"select * from us\t;\r", "select * from users;"},
+		{"select * from pro\t;\r", "select * from process;"},
+		{"select * from pro\t\t\tes;\r", "select * from processes;"},
+		{"select * from x\t;\r", "select * from x;"},
+	}
+	for _, tc := range testCases {
+		if got := editLine(t, e, tc.keys); got != tc.expected {
+			t.Errorf("%q: expected %q, got %q", tc.keys, tc.expected, got)
+		}
+	}
+
+	// Tab pressed twice lists the candidates
+	out := e.out.(*bytes.Buffer)
+	out.Reset()
+	editLine(t, e, "select * from process\t\t;\r")
+	if !strings.Contains(out.String(), "\r\nprocesses             process_open_sockets\r\n") {
+		t.Errorf("Expected the candidates to be listed, got %q", out.String())
+	}
+}
+
+func TestInterrupt(t *testing.T) {
+	e := newTestEditor()
+	e.in.Reset(strings.NewReader("select\x03"))
+	if _, err := e.edit("> "); !errors.Is(err, ErrInterrupted) {
+		t.Errorf("Expected ErrInterrupted, got %v", err)
+	}
+
+	e.in.Reset(strings.NewReader("\x04"))
+	if _, err := e.edit("> "); err != io.EOF {
+		t.Errorf("Expected io.EOF, got %v", err)
+	}
+}
+
+func TestReadPlain(t *testing.T) {
+	var out bytes.Buffer
+	e := New(strings.NewReader("select name\n\nfrom processes;\n.help\nselect"), &out)
+	e.ContinuationPrompt = "...> "
+	e.IsComplete = func(text string) bool {
+		return strings.HasPrefix(text, ".") || strings.HasSuffix(text, ";")
+	}
+
+	expected := []string{"select name\nfrom processes;", ".help"}
+	for _, want := range expected {
+		got, err := e.ReadLine("> ")
+		if err != nil || got != want {
+			t.Errorf("Expected %q, got %q (%v)", want, got, err)
+		}
+	}
+	if got, err := e.ReadLine("> "); got != "select" || err != io.EOF {
+		t.Errorf("Expected the partial statement and io.EOF, got %q (%v)", got, err)
+	}
+	if !strings.HasPrefix(out.String(), "> ...> ...> > ") {
+		t.Errorf("Unexpected prompts: %q", out.String())
+	}
+}
+
+func TestRenderWrapsLongLines(t *testing.T) {
+	var out bytes.Buffer
+	e := New(strings.NewReader(""), &out)
+	e.width = 10
+	e.ContinuationPrompt = ". "
+
+	// "> " and 13 characters wrap once, the cursor is on the second row
+	e.render("> ", []rune("select 1 from\nt"), 12)
+	if e.cursorRow != 1 {
+		t.Errorf("Expected the cursor on row 1, got %d", e.cursorRow)
+	}
+
+	// The next rendering goes back to the first row
+	out.Reset()
+	e.render("> ", []rune("x"), 1)
+	if !strings.HasPrefix(out.String(), "\x1b[1A\r\x1b[J") {
+		t.Errorf("Expected the rendering to start on the first row, got %q", out.String())
+	}
+}
@@ -0,0 +1,136 @@
+package lineedit
+
+import (
+	"bufio"
+	"errors"
+	"io/fs"
+	"os"
+	"strings"
+)
+
+// History keeps the entries submitted to an editor, oldest first, and
+// appends them to a file when it was loaded from one
+type History struct {
+	entries []string
+	path    string
+	max     int
+}
+
+// NewHistory creates an in-memory history holding up to max entries
+func NewHistory(max int) *History {
+	return &History{max: max}
+}
+
+// LoadHistory reads the history saved in path, which does not need to exist
+// yet. Entries added later are appended to the file.
+func LoadHistory(path string, max int) (*History, error) {
+	h := &History{path: path, max: max}
+
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return h, nil
+	}
+	if err != nil {
+		return h, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		if line := scanner.Text(); line != "" {
+			h.entries = append(h.entries, unescapeEntry(line))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return h, err
+	}
+
+	// Drop the oldest entries once the file has grown past the limit
+	if len(h.entries) > max {
+		h.entries = h.entries[len(h.entries)-max:]
+		return h, h.save()
+	}
+	return h, nil
+}
+
+// Len returns the number of entries
+func (h *History) Len() int {
+	if h == nil {
+		return 0
+	}
+	return len(h.entries)
+}
+
+// Entry returns the entry at index i, 0 being the oldest
+func (h *History) Entry(i int) string {
+	return h.entries[i]
+}
+
+// Add appends an entry, unless it is blank or repeats the last entry
+func (h *History) Add(entry string) error {
+	if h == nil || strings.TrimSpace(entry) == "" {
+		return nil
+	}
+	if n := len(h.entries); n > 0 && h.entries[n-1] == entry {
+		return nil
+	}
+
+	h.entries = append(h.entries, entry)
+	if len(h.entries) > h.max {
+		h.entries = h.entries[len(h.entries)-h.max:]
+	}
+	if h.path == "" {
+		return nil
+	}
+
+	file, err := os.OpenFile(h.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = file.WriteString(escapeEntry(entry) + "\n")
+	return err
+}
+
+// Search returns the index of the newest entry before index from that
+// contains query, or -1 if there is none
+func (h *History) Search(query string, from int) int {
+	for i := min(from, h.Len()) - 1; i >= 0; i-- {
+		if strings.Contains(h.entries[i], query) {
+			return i
+		}
+	}
+	return -1
+}
+
+// save rewrites the history file with the current entries
+func (h *History) save() error {
+	var sb strings.Builder
+	for _, entry := range h.entries {
+		sb.WriteString(escapeEntry(entry))
+		sb.WriteByte('\n')
+	}
+	return os.WriteFile(h.path, []byte(sb.String()), 0600)
+}
+
+// escapeEntry stores a multi-line entry on a single line of the history file
+func escapeEntry(entry string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(entry)
+}
+
+// unescapeEntry reverses escapeEntry
+func unescapeEntry(line string) string {
+	var sb strings.Builder
+	for i := 0; i < len(line); i++ {
+		if line[i] == '\\' && i+1 < len(line) {
+			i++
+			if line[i] == 'n' {
+				sb.WriteByte('\n')
+				continue
+			}
+		}
+		sb.WriteByte(line[i])
+	}
+	return sb.String()
+}
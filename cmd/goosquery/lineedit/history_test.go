@@ -0,0 +1,68 @@
+package lineedit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestHistoryPersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history")
+
+	h, err := LoadHistory(path, 3)
+	if err != nil {
+		t.Fatalf("Failed to load missing history: %v", err)
+	}
+	entries := []string{"select 1;", "select 1;", "  ", "select\n  name\nfrom processes;", `select '\n';`}
+	for _, entry := range entries {
+		if err := h.Add(entry); err != nil {
+			t.Fatalf("Failed to add entry: %v", err)
+		}
+	}
+
+	h, err = LoadHistory(path, 3)
+	if err != nil {
+		t.Fatalf("Failed to load history: %v", err)
+	}
+	expected := []string{"select 1;", "select\n  name\nfrom processes;", `select '\n';`}
+	if h.Len() != len(expected) {
+		t.Fatalf("Expected %d entries, got %d", len(expected), h.Len())
+	}
+	for i, want := range expected {
+		if got := h.Entry(i); got != want {
+			t.Errorf("Entry %d: expected %q, got %q", i, want, got)
+		}
+	}
+
+	// Loading with a lower limit drops the oldest entries from the file
+	if _, err := LoadHistory(path, 1); err != nil {
+		t.Fatalf("Failed to load history: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(data); got != "select '\\\\n';\n" {
+		t.Errorf("Unexpected history file: %q", got)
+	}
+}
+
+func TestHistorySearch(t *testing.T) {
+	h := NewHistory(10)
+	for _, entry := range []string{"select * from users;", "select * from processes;", "select 1;"} {
+		_ = h.Add(entry)
+	}
+
+	if got := h.Search("from", h.Len()); got != 1 {
+		t.Errorf("Expected entry 1, got %d", got)
+	}
+	if got := h.Search("from", 1); got != 0 {
+		t.Errorf("Expected entry 0, got %d", got)
+	}
+	if got := h.Search("groups", h.Len()); got != -1 {
+		t.Errorf("Expected no match, got %d", got)
+	}
+	if got := (*History)(nil).Search("x", 5); got != -1 {
+		t.Errorf("Expected no match in a nil history, got %d", got)
+	}
+}
@@ -0,0 +1,21 @@
+//go:build !linux && !windows
+
+package lineedit
+
+import "errors"
+
+// isTerminal reports whether fd refers to a terminal. Line editing is only
+// supported on Linux and Windows, so input is read line by line elsewhere.
+func isTerminal(fd uintptr) bool {
+	return false
+}
+
+// makeRaw is not supported on this platform
+func makeRaw(fd uintptr) (func(), error) {
+	return nil, errors.New("raw terminal mode is not supported")
+}
+
+// terminalSize returns zeros as the size is unknown
+func terminalSize(fd uintptr) (int, int) {
+	return 0, 0
+}
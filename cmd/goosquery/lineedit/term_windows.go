@@ -0,0 +1,54 @@
+//go:build windows
+
+package lineedit
+
+import "golang.org/x/sys/windows"
+
+// isTerminal reports whether fd refers to a console
+func isTerminal(fd uintptr) bool {
+	var mode uint32
+	return windows.GetConsoleMode(windows.Handle(fd), &mode) == nil
+}
+
+// makeRaw disables line input and echo on the console, and enables virtual
+// terminal sequences for the arrow keys and for redrawing. It returns a
+// function restoring the previous modes.
+func makeRaw(fd uintptr) (func(), error) {
+	in := windows.Handle(fd)
+	var inMode uint32
+	if err := windows.GetConsoleMode(in, &inMode); err != nil {
+		return nil, err
+	}
+	raw := inMode &^ (windows.ENABLE_ECHO_INPUT | windows.ENABLE_LINE_INPUT | windows.ENABLE_PROCESSED_INPUT)
+	raw |= windows.ENABLE_VIRTUAL_TERMINAL_INPUT
+	if err := windows.SetConsoleMode(in, raw); err != nil {
+		return nil, err
+	}
+
+	out := windows.Stdout
+	var outMode uint32
+	outErr := windows.GetConsoleMode(out, &outMode)
+	if outErr == nil {
+		_ = windows.SetConsoleMode(out, outMode|windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING)
+	}
+
+	return func() {
+		_ = windows.SetConsoleMode(in, inMode)
+		if outErr == nil {
+			_ = windows.SetConsoleMode(out, outMode)
+		}
+	}, nil
+}
+
+// terminalSize returns the columns and rows of the console window, or
+// zeros. Only output handles have a window, so standard output is used for
+// the console input.
+func terminalSize(fd uintptr) (int, int) {
+	var info windows.ConsoleScreenBufferInfo
+	if err := windows.GetConsoleScreenBufferInfo(windows.Handle(fd), &info); err != nil {
+		if err := windows.GetConsoleScreenBufferInfo(windows.Stdout, &info); err != nil {
+			return 0, 0
+		}
+	}
+	return int(info.Window.Right-info.Window.Left) + 1, int(info.Window.Bottom-info.Window.Top) + 1
+}
@@ -1,116 +1,163 @@
 package main
 
 import (
-	"bufio"
 	"flag"
 	"fmt"
 	"os"
 	"strings"
 
+	"github.com/scrymastic/goosquery/cmd/goosquery/format"
+	"github.com/scrymastic/goosquery/cmd/goosquery/lineedit"
 	"github.com/scrymastic/goosquery/sql/engine"
 )
 
-func main() {
-	// Parse command-line flags
-	queryFlag := flag.String("q", "", "SQL query to execute")
-	interactiveFlag := flag.Bool("i", false, "Run in interactive mode")
-	jsonFlag := flag.Bool("json", false, "Output results in JSON format")
-	flag.Parse()
-
-	// Create SQL engine
-	sqlEngine := engine.NewEngine()
-
-	// If interactive mode specified or no query provided, start interactive mode
-	if *interactiveFlag || *queryFlag == "" {
-		runInteractiveMode(sqlEngine, *jsonFlag)
-		return
-	}
-
-	// Execute a single query in non-interactive mode
-	executeQuery(sqlEngine, *queryFlag, *jsonFlag)
+// shellCommand is a special command of the interactive mode
+type shellCommand struct {
+	usage       string
+	description string
+	arguments   []string // values completed for the first argument
 }
 
-// runInteractiveMode starts an interactive REPL for executing SQL queries
-func runInteractiveMode(sqlEngine *engine.Engine, jsonOutput bool) {
-	displayBanner()
-
-	reader := bufio.NewReader(os.Stdin)
-
-	// Show current output mode
-	printOutputMode(jsonOutput)
+// name returns the command without its arguments
+func (c shellCommand) name() string {
+	return strings.Fields(c.usage)[0]
+}
 
-	for {
-		fmt.Print("goosquery> ")
+// shellCommands lists the special commands for .help and tab completion
+var shellCommands = []shellCommand{
+	{".quit", "Exit the program", nil},
+	{".json", "Switch to JSON output mode", nil},
+	{".table", "Switch to table output mode", nil},
+	{".mode [name]", "Show or set the output mode: " + strings.Join(format.Names(), ", "), format.Names()},
+	{".headers on|off", "Show or hide column headers", []string{"on", "off"}},
+	{".nullvalue [text]", "Text shown for NULL values", nil},
+	{".maxwidth [n]", "Limit the width of table cells, 0 for no limit", nil},
+	{".wrap on|off", "Wrap long table cells instead of truncating them", []string{"on", "off"}},
+	{".expanded on|off|auto", "Show tables with one line per column, auto for wide rows", []string{"on", "off", "auto"}},
+	{".pager on|off", "Show long output through a pager", []string{"on", "off"}},
+	{".tables [pattern]", "List the tables, or those matching a LIKE pattern", nil},
+	{".schema <table>", "Show the columns of a table with their types and descriptions", nil},
+	{".explain <query>", "Show how a query would be executed, without running it", nil},
+	{".timer on|off", "Show the time spent generating and processing rows", []string{"on", "off"}},
+	{".read <file>", "Execute the statements and commands of a file", nil},
+	{".output [file]", "Write results to a file, or to the screen again without file", nil},
+	{".once <file>", "Write the results of the next query to a file", nil},
+	{".show", "Show the current settings", nil},
+	{".save <name> [query]", "Save a query, or the last one executed, to the library", nil},
+	{".run [name]", "Run a saved query, or list the saved queries", nil},
+	{".watch <interval> <query>", "Run a query on an interval showing changed rows, until Ctrl-C; key=<columns> before the query identifies rows", nil},
+	{".help", "Show this help message", nil},
+}
 
-		// Read user input (command or SQL)
-		input, isCommand, err := readSQLInput(reader)
-		if err != nil {
-			fmt.Printf("Error reading input: %v\n", err)
-			continue
-		}
+// Exit codes of the command line mode
+const (
+	exitOK    = 0
+	exitError = 1 // a query or command failed
+	exitUsage = 2 // invalid arguments or unreadable script
+	exitEmpty = 3 // a query returned no rows with -fail-on-empty
+)
 
-		// Skip if input is empty
-		if input == "" {
-			continue
-		}
+func main() {
+	os.Exit(run())
+}
 
-		// Handle commands
-		if isCommand {
-			// Handle special commands
-			switch strings.ToLower(input) {
-			case ".quit":
-				fmt.Println("Exiting...")
-				return
-			case ".json":
-				jsonOutput = true
-				fmt.Println("Output mode set to JSON")
-				continue
-			case ".table":
-				jsonOutput = false
-				fmt.Println("Output mode set to TABLE")
-				continue
-			case ".mode":
-				printOutputMode(jsonOutput)
-				continue
-			case ".help":
-				fmt.Println("Commands:")
-				fmt.Println("  .quit        - Exit the program")
-				fmt.Println("  .json        - Switch to JSON output mode")
-				fmt.Println("  .table       - Switch to table output mode")
-				fmt.Println("  .mode        - Show current output mode")
-				fmt.Println("  .help        - Show this help message")
-				continue
-			default:
-				fmt.Printf("Unknown command: %s\n", input)
-				continue
-			}
+// run executes the queries given on the command line, in a script or on the
+// standard input, or starts the interactive mode, and returns the exit code
+func run() int {
+	// Subcommands come before any flag of the shell
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "schema":
+			return runSchema(os.Args[2:])
+		case "doctor":
+			return runDoctor(os.Args[2:])
 		}
+	}
 
-		// Execute the query
-		executeQuery(sqlEngine, input, jsonOutput)
+	// Parse command-line flags
+	queryFlag := flag.String("q", "", "SQL statements to execute, separated by semicolons")
+	fileFlag := flag.String("f", "", "File of SQL statements and commands to execute, - for standard input")
+	interactiveFlag := flag.Bool("i", false, "Run in interactive mode")
+	jsonFlag := flag.Bool("json", false, "Output results in JSON format (same as -format json)")
+	formatFlag := flag.String("format", "table", "Output format: "+strings.Join(format.Names(), ", "))
+	maxWidthFlag := flag.Int("maxwidth", 0, "Limit the width of table cells, 0 for no limit")
+	wrapFlag := flag.Bool("wrap", false, "Wrap long table cells instead of truncating them")
+	expandedFlag := flag.String("expanded", "off", "Show tables with one line per column: on, off or auto")
+	watchFlag := flag.Duration("watch", 0, "Run the -q query on this interval, e.g. 5s, showing changed rows until interrupted")
+	watchKeyFlag := flag.String("watch-key", "", "Comma-separated columns identifying rows in -watch mode, all columns by default")
+	failOnEmptyFlag := flag.Bool("fail-on-empty", false, "Exit with code 3 when a query returns no rows")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "Usage: goosquery [flags]")
+		fmt.Fprintln(flag.CommandLine.Output(), "       goosquery schema [-format name] [-o path] [table ...]")
+		fmt.Fprintln(flag.CommandLine.Output(), "       goosquery doctor [-format name] [-timeout duration] [table ...]")
+		flag.PrintDefaults()
 	}
-}
+	flag.Parse()
 
-// executeQuery runs a SQL query and displays the result
-func executeQuery(sqlEngine *engine.Engine, query string, jsonOutput bool) {
-	queryResult, err := sqlEngine.Execute(query)
+	if flag.NArg() > 0 {
+		printError(fmt.Errorf("unexpected argument: %s", flag.Arg(0)))
+		flag.Usage()
+		return exitUsage
+	}
+	if *jsonFlag {
+		*formatFlag = "json"
+	}
+	settings, err := newOutputSettings(*formatFlag)
 	if err != nil {
-		fmt.Printf("Error executing query: %v\n", err)
-		return
+		printError(err)
+		return exitUsage
+	}
+	settings.options.MaxWidth = *maxWidthFlag
+	settings.options.Wrap = *wrapFlag
+	if settings.options.Expanded, err = format.ParseExpanded(*expandedFlag); err != nil {
+		printError(err)
+		return exitUsage
 	}
 
-	if jsonOutput {
-		// Convert result to JSON and print
-		jsonData, err := formatResultAsJSON(queryResult)
-		if err != nil {
-			fmt.Printf("Error formatting result: %v\n", err)
-			return
+	// Create SQL engine
+	sh := &shell{engine: engine.NewEngine(), settings: settings}
+
+	switch {
+	case *interactiveFlag:
+		sh.runInteractive()
+		return exitOK
+	case *watchFlag != 0:
+		if *queryFlag == "" {
+			printError(fmt.Errorf("-watch needs a query given with -q"))
+			return exitUsage
+		}
+		err = sh.watch(*queryFlag, *watchFlag, splitColumns(*watchKeyFlag))
+	case *queryFlag != "":
+		err = sh.runStatements(*queryFlag)
+	case *fileFlag == "-":
+		err = sh.runScript(os.Stdin)
+	case *fileFlag != "":
+		file, openErr := os.Open(*fileFlag)
+		if openErr != nil {
+			printError(openErr)
+			return exitUsage
 		}
-		fmt.Println(jsonData)
-		fmt.Printf("Total rows: %d\n", queryResult.Size())
-	} else {
-		// Display the result as a formatted table
-		displayAsTable(queryResult)
-		fmt.Printf("Total rows: %d\n", queryResult.Size())
+		defer file.Close()
+		err = sh.runScript(file)
+	case lineedit.IsTerminal(os.Stdin):
+		// Start interactive mode when no query is provided
+		sh.runInteractive()
+		return exitOK
+	default:
+		// Execute the statements piped to the standard input
+		err = sh.runScript(os.Stdin)
+	}
+
+	// The output of the statements run before an error is kept
+	if batchErr := sh.endBatch(); err == nil {
+		err = batchErr
+	}
+	if err != nil && err != errQuit {
+		printError(err)
+		return exitError
+	}
+	if *failOnEmptyFlag && sh.empty {
+		return exitEmpty
 	}
+	return exitOK
 }
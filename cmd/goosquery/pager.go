@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
+	"strings"
+
+	"github.com/scrymastic/goosquery/cmd/goosquery/lineedit"
+)
+
+// writeOutput prints the output of a query, through a pager in interactive
+// mode when it does not fit on the screen
+func writeOutput(output []byte, settings *outputSettings) {
+	if settings.interactive && settings.pager && lineedit.IsTerminal(os.Stdout) {
+		_, height := lineedit.TerminalSize(os.Stdout)
+		if height > 0 && bytes.Count(output, []byte("\n")) >= height && page(output) {
+			return
+		}
+	}
+	os.Stdout.Write(output)
+}
+
+// pagerCommand returns the pager from $PAGER, or the default of the platform
+func pagerCommand() []string {
+	if pager := strings.Fields(os.Getenv("PAGER")); len(pager) > 0 {
+		return pager
+	}
+	if _, err := exec.LookPath("less"); err == nil {
+		// Quit when the output fits on the screen and keep the box drawing
+		return []string{"less", "-FRX"}
+	}
+	if runtime.GOOS == "windows" {
+		return []string{"more"}
+	}
+	return nil
+}
+
+// page shows the output through the pager and reports whether it could run
+func page(output []byte) bool {
+	pager := pagerCommand()
+	if len(pager) == 0 {
+		return false
+	}
+
+	cmd := exec.Command(pager[0], pager[1:]...)
+	cmd.Stdin = bytes.NewReader(output)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	// Ctrl-C is meant for the pager rather than the shell
+	signal.Ignore(os.Interrupt)
+	defer signal.Reset(os.Interrupt)
+
+	if err := cmd.Start(); err != nil {
+		return false
+	}
+	_ = cmd.Wait()
+	return true
+}
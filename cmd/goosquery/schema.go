@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/scrymastic/goosquery/cmd/goosquery/schemadoc"
+	execintf "github.com/scrymastic/goosquery/sql/executor/interface"
+	"github.com/scrymastic/goosquery/sql/result"
+)
+
+// runSchema implements the schema subcommand, which documents the tables
+// as JSON, markdown or osquery table specs
+func runSchema(args []string) int {
+	flags := flag.NewFlagSet("schema", flag.ContinueOnError)
+	formatFlag := flags.String("format", "json", "Output format: "+strings.Join(schemadoc.Formats, ", "))
+	outputFlag := flags.String("o", "", "Write to a file, or with -format spec to a directory of <platform>/<table>.table files")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: goosquery schema [-format name] [-o path] [table ...]")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+
+	tables, err := selectTables(flags.Args(), execintf.AllTables())
+	if err != nil {
+		printError(err)
+		return exitUsage
+	}
+
+	if *outputFlag != "" && strings.EqualFold(*formatFlag, "spec") {
+		err = writeSpecFiles(*outputFlag, tables)
+	} else {
+		var output bytes.Buffer
+		if err = schemadoc.Write(&output, tables, *formatFlag); err != nil {
+			printError(err)
+			return exitUsage
+		}
+		if *outputFlag != "" {
+			err = os.WriteFile(*outputFlag, output.Bytes(), 0644)
+		} else {
+			_, err = os.Stdout.Write(output.Bytes())
+		}
+	}
+	if err != nil {
+		printError(err)
+		return exitError
+	}
+	return exitOK
+}
+
+// selectTables returns the tables with the given names among the tables
+// of every platform, or the given default tables
+func selectTables(names []string, defaults []result.Table) ([]result.Table, error) {
+	if len(names) == 0 {
+		return defaults, nil
+	}
+
+	tables := execintf.AllTables()
+	byName := make(map[string]result.Table, len(tables))
+	for _, table := range tables {
+		byName[table.Name] = table
+	}
+	selected := make([]result.Table, 0, len(names))
+	for _, name := range names {
+		table, ok := byName[strings.ToLower(name)]
+		if !ok {
+			return nil, fmt.Errorf("unsupported table: %s", name)
+		}
+		selected = append(selected, table)
+	}
+	return selected, nil
+}
+
+// writeSpecFiles writes the spec of each table to <dir>/<platform>/<table>.table,
+// as laid out in the specs directory of osquery
+func writeSpecFiles(dir string, tables []result.Table) error {
+	for _, table := range tables {
+		var spec bytes.Buffer
+		if err := schemadoc.WriteSpec(&spec, table); err != nil {
+			return err
+		}
+		for _, platform := range table.Platforms {
+			platformDir := filepath.Join(dir, platform)
+			if err := os.MkdirAll(platformDir, 0755); err != nil {
+				return err
+			}
+			if err := os.WriteFile(filepath.Join(platformDir, table.Name+".table"), spec.Bytes(), 0644); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+// Package schemadoc documents the tables and their columns as JSON, as a
+// markdown reference or as osquery table spec files.
+package schemadoc
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/scrymastic/goosquery/sql/result"
+)
+
+// Formats lists the names of the formats accepted by Write
+var Formats = []string{"json", "markdown", "spec"}
+
+// Write documents the tables in the named format
+func Write(w io.Writer, tables []result.Table, format string) error {
+	switch strings.ToLower(format) {
+	case "json":
+		return WriteJSON(w, tables)
+	case "markdown", "md":
+		return WriteMarkdown(w, tables)
+	case "spec":
+		for i, table := range tables {
+			if i > 0 {
+				if _, err := io.WriteString(w, "\n"); err != nil {
+					return err
+				}
+			}
+			if err := WriteSpec(w, table); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown schema format: %s (available: %s)", format, strings.Join(Formats, ", "))
+}
+
+type jsonTable struct {
+	Name        string             `json:"name"`
+	Description string             `json:"description"`
+	Platforms   []string           `json:"platforms"`
+	Status      result.TableStatus `json:"status"`
+	// Platforms where the table is not implemented although it is elsewhere
+	NotImplementedOn []string     `json:"not_implemented_on,omitempty"`
+	Columns          []jsonColumn `json:"columns"`
+}
+
+type jsonColumn struct {
+	Name        string   `json:"name"`
+	Type        string   `json:"type"`
+	Description string   `json:"description"`
+	Options     []string `json:"options,omitempty"`
+}
+
+// WriteJSON writes the tables as a JSON array
+func WriteJSON(w io.Writer, tables []result.Table) error {
+	doc := make([]jsonTable, 0, len(tables))
+	for _, table := range tables {
+		entry := jsonTable{
+			Name:             table.Name,
+			Description:      table.Description,
+			Platforms:        table.Platforms,
+			Status:           table.Status,
+			NotImplementedOn: table.NotImplementedOn,
+			Columns:          make([]jsonColumn, 0, len(table.Schema)),
+		}
+		for _, column := range table.Schema {
+			entry.Columns = append(entry.Columns, jsonColumn{
+				Name:        column.Name,
+				Type:        column.Type,
+				Description: column.Description,
+				Options:     column.Options.Names(),
+			})
+		}
+		doc = append(doc, entry)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(doc)
+}
+
+// WriteMarkdown writes a reference of the tables: a summary followed by a
+// section with the columns of each table
+func WriteMarkdown(w io.Writer, tables []result.Table) error {
+	var sb strings.Builder
+	sb.WriteString("# Tables\n\n")
+	sb.WriteString("| Table | Platforms | Status | Description |\n")
+	sb.WriteString("|-------|-----------|--------|-------------|\n")
+	for _, table := range tables {
+		fmt.Fprintf(&sb, "| [%s](#%s) | %s | %s | %s |\n", table.Name, table.Name,
+			strings.Join(table.Platforms, ", "), statusText(table), escapeMarkdown(table.Description))
+	}
+
+	for _, table := range tables {
+		fmt.Fprintf(&sb, "\n## %s\n\n", table.Name)
+		if table.Description != "" {
+			fmt.Fprintf(&sb, "%s\n\n", escapeMarkdown(table.Description))
+		}
+		if table.Status == result.NotImplemented {
+			sb.WriteString("**Not implemented:** queries of this table return an error.\n\n")
+		} else if len(table.NotImplementedOn) > 0 {
+			fmt.Fprintf(&sb, "**Not implemented on %s:** queries of this table return an error there.\n\n", strings.Join(table.NotImplementedOn, ", "))
+		}
+		fmt.Fprintf(&sb, "Platforms: %s\n\n", strings.Join(table.Platforms, ", "))
+
+		sb.WriteString("| Column | Type | Description |\n")
+		sb.WriteString("|--------|------|-------------|\n")
+		for _, column := range table.Schema {
+			description := escapeMarkdown(column.Description)
+			if options := column.Options.Names(); len(options) > 0 {
+				description = strings.TrimSpace(fmt.Sprintf("%s (%s)", description, strings.Join(options, ", ")))
+			}
+			fmt.Fprintf(&sb, "| `%s` | %s | %s |\n", column.Name, column.Type, description)
+		}
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// WriteSpec writes the osquery table spec of a table. Tables that are not
+// implemented are marked by a comment.
+func WriteSpec(w io.Writer, table result.Table) error {
+	var sb strings.Builder
+	if table.Status == result.NotImplemented {
+		sb.WriteString("# Not implemented: queries of this table return an error\n")
+	} else if len(table.NotImplementedOn) > 0 {
+		fmt.Fprintf(&sb, "# Not implemented on %s: queries of this table return an error there\n", strings.Join(table.NotImplementedOn, ", "))
+	}
+	fmt.Fprintf(&sb, "table_name(%s)\n", strconv.Quote(table.Name))
+	fmt.Fprintf(&sb, "description(%s)\n", strconv.Quote(table.Description))
+	sb.WriteString("schema([\n")
+	for _, column := range table.Schema {
+		fmt.Fprintf(&sb, "    Column(%s, %s, %s", strconv.Quote(column.Name), column.Type, strconv.Quote(column.Description))
+		for _, option := range column.Options.Names() {
+			fmt.Fprintf(&sb, ", %s=True", option)
+		}
+		sb.WriteString("),\n")
+	}
+	sb.WriteString("])\n")
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// statusText returns the status of a table with the icon of the README
+func statusText(table result.Table) string {
+	if table.Status == result.NotImplemented {
+		return "⏳ " + table.Status.String()
+	}
+	if len(table.NotImplementedOn) > 0 {
+		return fmt.Sprintf("✅ %s, ⏳ not on %s", table.Status, strings.Join(table.NotImplementedOn, ", "))
+	}
+	return "✅ " + table.Status.String()
+}
+
+// escapeMarkdown keeps text on a single line of a markdown table
+func escapeMarkdown(text string) string {
+	return strings.NewReplacer("|", `\|`, "\r\n", " ", "\n", " ").Replace(text)
+}
@@ -0,0 +1,144 @@
+package schemadoc
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/scrymastic/goosquery/sql/result"
+)
+
+var testTables = []result.Table{
+	{
+		Name:        "file",
+		Description: "Interactive filesystem attributes and metadata.",
+		Schema: result.Schema{
+			{Name: "path", Type: "TEXT", Description: "Absolute file path", Options: result.Required},
+			{Name: "size", Type: "BIGINT", Description: "Size of file in bytes"},
+		},
+		Platforms: []string{"windows"},
+	},
+	{
+		Name:        "prefetch",
+		Description: "Prefetch files show metadata related to file execution.",
+		Schema: result.Schema{
+			{Name: "path", Type: "TEXT", Description: "Prefetch file path."},
+		},
+		Platforms: []string{"windows"},
+		Status:    result.NotImplemented,
+	},
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, testTables, "json"); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	var doc []map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("Invalid JSON: %v\n%s", err, buf.String())
+	}
+	if len(doc) != 2 || doc[0]["status"] != "implemented" || doc[1]["status"] != "not implemented" {
+		t.Errorf("Unexpected tables: %v", doc)
+	}
+	columns := doc[0]["columns"].([]interface{})
+	if options := columns[0].(map[string]interface{})["options"]; len(options.([]interface{})) != 1 {
+		t.Errorf("Expected the required option of file.path, got %v", options)
+	}
+	if _, ok := columns[1].(map[string]interface{})["options"]; ok {
+		t.Errorf("Expected no options for file.size")
+	}
+}
+
+func TestWriteSpec(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, testTables, "spec"); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	expected := `table_name("file")
+description("Interactive filesystem attributes and metadata.")
+schema([
+    Column("path", TEXT, "Absolute file path", required=True),
+    Column("size", BIGINT, "Size of file in bytes"),
+])
+
+# Not implemented: queries of this table return an error
+table_name("prefetch")
+description("Prefetch files show metadata related to file execution.")
+schema([
+    Column("path", TEXT, "Prefetch file path."),
+])
+`
+	if buf.String() != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, buf.String())
+	}
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, testTables, "markdown"); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	output := buf.String()
+	for _, expected := range []string{
+		"| [file](#file) | windows | ✅ implemented | Interactive filesystem attributes and metadata. |\n",
+		"| [prefetch](#prefetch) | windows | ⏳ not implemented |",
+		"## prefetch\n\nPrefetch files show metadata related to file execution.\n\n**Not implemented:**",
+		"| `path` | TEXT | Absolute file path (required) |\n",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, output)
+		}
+	}
+}
+
+func TestWriteUnknownFormat(t *testing.T) {
+	if err := Write(&bytes.Buffer{}, testTables, "yaml"); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+}
+
+func TestWritePartlyImplemented(t *testing.T) {
+	tables := []result.Table{{
+		Name:             "startup_items",
+		Description:      "Applications and binaries set as user/login startup items.",
+		Schema:           result.Schema{{Name: "name", Type: "TEXT", Description: "Name of startup item"}},
+		Platforms:        []string{"windows", "linux"},
+		NotImplementedOn: []string{"windows"},
+	}}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, tables, "json"); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	var doc []map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("Invalid JSON: %v\n%s", err, buf.String())
+	}
+	if doc[0]["status"] != "implemented" || len(doc[0]["not_implemented_on"].([]interface{})) != 1 {
+		t.Errorf("Unexpected table: %v", doc[0])
+	}
+
+	buf.Reset()
+	if err := Write(&buf, tables, "markdown"); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	for _, expected := range []string{
+		"| windows, linux | ✅ implemented, ⏳ not on windows |",
+		"**Not implemented on windows:**",
+	} {
+		if !strings.Contains(buf.String(), expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, buf.String())
+		}
+	}
+
+	buf.Reset()
+	if err := Write(&buf, tables, "spec"); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if !strings.HasPrefix(buf.String(), "# Not implemented on windows: ") {
+		t.Errorf("Expected the spec to start with a comment, got:\n%s", buf.String())
+	}
+}
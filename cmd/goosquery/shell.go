@@ -0,0 +1,698 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/scrymastic/goosquery/cmd/goosquery/format"
+	"github.com/scrymastic/goosquery/cmd/goosquery/lineedit"
+	"github.com/scrymastic/goosquery/cmd/goosquery/watch"
+	"github.com/scrymastic/goosquery/sql/engine"
+	execintf "github.com/scrymastic/goosquery/sql/executor/interface"
+	"github.com/scrymastic/goosquery/sql/executor/operations"
+	"github.com/scrymastic/goosquery/sql/parser"
+	"github.com/scrymastic/goosquery/sql/result"
+)
+
+// errQuit is returned by runCommand when the user asks to exit
+var errQuit = errors.New("quit")
+
+// shell executes queries and special commands, typed in interactive mode or
+// read from scripts
+type shell struct {
+	engine   *engine.Engine
+	settings *outputSettings
+
+	// empty is set once a query returned no rows
+	empty bool
+
+	timer     bool          // whether the time of queries is shown
+	output    *os.File      // file receiving the results, nil for the screen
+	once      bool          // whether output is reset after the next query
+	lastQuery string        // last query executed successfully, for .save
+	library   *queryLibrary // saved queries, loaded on first use
+	depth     int           // nesting of .read commands
+
+	// JSON documents of the statements run in a row, outside interactive
+	// mode, which are written as one array, see addToBatch
+	batched int    // number of documents in the batch
+	pending []byte // first document, held back until a second one comes
+}
+
+// maxReadDepth limits .read commands reading each other
+const maxReadDepth = 16
+
+// runInteractive starts an interactive REPL for executing SQL queries
+func (s *shell) runInteractive() {
+	displayBanner()
+
+	editor := newEditor()
+	s.settings.interactive = true
+
+	// Show current output mode
+	printOutputMode(s.settings)
+
+	for {
+		// Read user input (command or SQL)
+		input, isCommand, err := readSQLInput(editor)
+		if errors.Is(err, lineedit.ErrInterrupted) {
+			continue
+		}
+		if err == io.EOF && input == "" {
+			fmt.Println("Exiting...")
+			return
+		}
+		if err != nil && err != io.EOF {
+			printError(fmt.Errorf("reading input: %w", err))
+			continue
+		}
+
+		// Skip if input is empty
+		if input == "" {
+			continue
+		}
+
+		if isCommand {
+			err = s.runCommand(input)
+		} else {
+			err = s.runStatements(input)
+		}
+		if err == errQuit {
+			fmt.Println("Exiting...")
+			return
+		}
+		if err != nil {
+			printError(err)
+		}
+	}
+}
+
+// runScript executes the SQL statements and special commands read from a
+// file or pipe, stopping at the first error. Commands take a line of their
+// own between statements.
+func (s *shell) runScript(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 16*1024*1024)
+
+	var pending string
+	for scanner.Scan() {
+		line := scanner.Text()
+		if trimmed := strings.TrimSpace(line); strings.HasPrefix(trimmed, ".") && parser.IsBlank(pending) {
+			pending = ""
+			if err := s.runCommand(trimmed); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// Execute the statements completed by this line
+		statements, rest := parser.SplitStatements(pending + line + "\n")
+		for _, statement := range statements {
+			if err := s.executeQuery(statement); err != nil {
+				return err
+			}
+		}
+		pending = rest
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("reading input: %w", err)
+	}
+
+	// The last statement does not need a semicolon
+	return s.runStatements(pending)
+}
+
+// runStatements executes the semicolon-separated statements of the input,
+// stopping at the first error
+func (s *shell) runStatements(input string) error {
+	statements, rest := parser.SplitStatements(input)
+	if !parser.IsBlank(rest) {
+		statements = append(statements, rest)
+	}
+	for _, statement := range statements {
+		if err := s.executeQuery(statement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// executeQuery runs a SQL query and displays the result
+func (s *shell) executeQuery(query string) error {
+	if s.once {
+		defer s.closeOutput()
+	}
+
+	queryResult, stats, err := s.engine.ExecuteWithStats(query)
+	if err != nil {
+		return err
+	}
+	s.lastQuery = strings.TrimSpace(query)
+	if queryResult.Size() == 0 {
+		s.empty = true
+	}
+
+	output, err := s.formatResults(queryResult, true)
+	if err != nil {
+		return err
+	}
+	if s.settings.mode == "json" && !s.settings.interactive {
+		err = s.addToBatch(output)
+	} else {
+		err = s.write(output)
+	}
+	if err != nil {
+		return err
+	}
+	if s.timer {
+		fmt.Fprintf(os.Stderr, "Run time: generator %v, processing %v, %d rows generated\n",
+			stats.GeneratorTime.Round(time.Microsecond), stats.ProcessingTime.Round(time.Microsecond), stats.GeneratedRows)
+	}
+	return nil
+}
+
+// writeResults writes results in the output mode, followed by the row count
+// in the formats meant to be read by people
+func (s *shell) writeResults(results *result.Results, rowCount bool) error {
+	output, err := s.formatResults(results, rowCount)
+	if err != nil {
+		return err
+	}
+	return s.write(output)
+}
+
+// formatResults formats results in the output mode, followed by the row
+// count in the formats meant to be read by people
+func (s *shell) formatResults(results *result.Results, rowCount bool) ([]byte, error) {
+	// Tables are fitted to the terminal, but not when written to a file. The
+	// settings are copied to leave the width of the shell unchanged.
+	settings := *s.settings
+	settings.options.TerminalWidth = 0
+	if s.output == nil && lineedit.IsTerminal(os.Stdout) {
+		settings.options.TerminalWidth, _ = lineedit.TerminalSize(os.Stdout)
+	}
+
+	var output bytes.Buffer
+	if err := settings.formatter.Format(&output, results, settings.options); err != nil {
+		return nil, fmt.Errorf("formatting result: %w", err)
+	}
+
+	// Formats read by other programs contain only the data
+	switch settings.mode {
+	case "table", "line":
+		if rowCount {
+			fmt.Fprintf(&output, "Total rows: %d\n", results.Size())
+		}
+	}
+
+	return output.Bytes(), nil
+}
+
+// write sends formatted output to the output file or the screen
+func (s *shell) write(output []byte) error {
+	if s.output != nil {
+		_, err := s.output.Write(output)
+		return err
+	}
+	writeOutput(output, s.settings)
+	return nil
+}
+
+// addToBatch writes the JSON document of a statement. The documents of the
+// statements run in a row are written as an array holding one array of rows
+// per statement, so that the output of several statements is one valid
+// document, while a single statement writes its rows alone. The first
+// document is held back until it is known whether another one follows.
+func (s *shell) addToBatch(document []byte) error {
+	s.batched++
+	switch s.batched {
+	case 1:
+		s.pending = document
+		return nil
+	case 2:
+		output := append([]byte("[\n"), indentJSON(s.pending)...)
+		s.pending = nil
+		output = append(output, ",\n"...)
+		return s.write(append(output, indentJSON(document)...))
+	default:
+		return s.write(append([]byte(",\n"), indentJSON(document)...))
+	}
+}
+
+// endBatch completes the JSON documents of the statements run in a row. It is
+// called before commands, which may change the output, and at the end of the
+// input.
+func (s *shell) endBatch() error {
+	batched, pending := s.batched, s.pending
+	s.batched, s.pending = 0, nil
+	switch {
+	case batched == 1:
+		return s.write(pending)
+	case batched > 1:
+		return s.write([]byte("\n]\n"))
+	}
+	return nil
+}
+
+// indentJSON indents a JSON document to nest it in an array, without its
+// final line break
+func indentJSON(document []byte) []byte {
+	var output bytes.Buffer
+	for i, line := range strings.Split(strings.TrimSuffix(string(document), "\n"), "\n") {
+		if i > 0 {
+			output.WriteByte('\n')
+		}
+		output.WriteString("  " + line)
+	}
+	return output.Bytes()
+}
+
+// writer returns where the output of queries and commands goes, the output
+// file or the screen
+func (s *shell) writer() io.Writer {
+	if s.output != nil {
+		return s.output
+	}
+	return os.Stdout
+}
+
+// setOutput sends the results to a file, or back to the screen when path is
+// empty or stdout
+func (s *shell) setOutput(path string, once bool) error {
+	if err := s.closeOutput(); err != nil {
+		return err
+	}
+	if path == "" || path == "stdout" {
+		return nil
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	s.output, s.once = file, once
+	return nil
+}
+
+// closeOutput closes the output file and sends results to the screen again
+func (s *shell) closeOutput() error {
+	if s.output == nil {
+		return nil
+	}
+	if err := s.endBatch(); err != nil {
+		return err
+	}
+	err := s.output.Close()
+	s.output, s.once = nil, false
+	return err
+}
+
+// watch runs a query on an interval until interrupted, showing the rows
+// added, removed or changed since the previous run. Rows are identified by
+// the key columns, or by all their values when keys is empty.
+func (s *shell) watch(input string, interval time.Duration, keys []string) error {
+	if interval <= 0 {
+		return errors.New("the watch interval must be positive")
+	}
+	statements, rest := parser.SplitStatements(input)
+	if !parser.IsBlank(rest) {
+		statements = append(statements, rest)
+	}
+	if len(statements) != 1 {
+		return errors.New("watch needs exactly one query")
+	}
+	query := statements[0]
+
+	// Refreshed output is not held back by the pager
+	pager := s.settings.pager
+	s.settings.pager = false
+	defer func() { s.settings.pager = pager }()
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	tracker := watch.NewTracker(keys)
+	for run := 1; ; run++ {
+		queryResult, err := s.engine.Execute(query)
+		if err != nil {
+			return err
+		}
+		diff, err := tracker.Update(queryResult)
+		if err != nil {
+			return err
+		}
+		s.lastQuery = strings.TrimSpace(query)
+		if queryResult.Size() == 0 {
+			s.empty = true
+		}
+
+		// The summary goes to the standard error stream, leaving only rows
+		// in machine formats
+		fmt.Fprintf(os.Stderr, "[%s] run %d: %d rows, %s\n",
+			time.Now().Format("15:04:05"), run, queryResult.Size(), diff)
+		if !diff.Empty() {
+			if err := s.writeResults(diff.Results(), false); err != nil {
+				return err
+			}
+		}
+
+		select {
+		case <-interrupt:
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// parseWatch parses the arguments of .watch: an interval, optionally key
+// columns, and a query
+func parseWatch(args string) (time.Duration, []string, string, error) {
+	usage := errors.New("usage: .watch <interval> [key=<column,...>] <query>")
+	fields := strings.Fields(args)
+	if len(fields) < 2 {
+		return 0, nil, "", usage
+	}
+	interval, err := time.ParseDuration(fields[0])
+	if err != nil {
+		return 0, nil, "", usage
+	}
+	query := strings.TrimSpace(args[strings.Index(args, fields[0])+len(fields[0]):])
+
+	var keys []string
+	if strings.HasPrefix(strings.ToLower(fields[1]), "key=") {
+		keys = splitColumns(fields[1][len("key="):])
+		query = strings.TrimSpace(query[len(fields[1]):])
+	}
+	if query == "" {
+		return 0, nil, "", usage
+	}
+	return interval, keys, query, nil
+}
+
+// splitColumns splits a comma-separated list of column names
+func splitColumns(list string) []string {
+	var columns []string
+	for _, column := range strings.Split(list, ",") {
+		if column = strings.TrimSpace(column); column != "" {
+			columns = append(columns, column)
+		}
+	}
+	return columns
+}
+
+// readFile executes the statements and commands of a script file
+func (s *shell) readFile(path string) error {
+	if s.depth >= maxReadDepth {
+		return fmt.Errorf("%s: too many nested .read commands", path)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	s.depth++
+	defer func() { s.depth-- }()
+	return s.runScript(file)
+}
+
+// queries returns the library of saved queries, loading it on first use
+func (s *shell) queries() (*queryLibrary, error) {
+	if s.library != nil {
+		return s.library, nil
+	}
+	library, err := loadQueryLibrary()
+	if err != nil {
+		return nil, fmt.Errorf("loading saved queries: %w", err)
+	}
+	s.library = library
+	return library, nil
+}
+
+// runCommand executes a special command
+func (s *shell) runCommand(input string) error {
+	if err := s.endBatch(); err != nil {
+		return err
+	}
+	settings := s.settings
+	fields := strings.Fields(input)
+	args := fields[1:]
+	rest := strings.TrimSpace(input[len(fields[0]):])
+
+	// Handle special commands
+	switch strings.ToLower(fields[0]) {
+	case ".quit":
+		return errQuit
+	case ".json", ".table":
+		_ = settings.setMode(fields[0][1:])
+		fmt.Printf("Output mode set to %s\n", strings.ToUpper(settings.mode))
+	case ".mode":
+		if len(args) == 0 {
+			printOutputMode(settings)
+			return nil
+		}
+		if err := settings.setMode(args[0]); err != nil {
+			return err
+		}
+		fmt.Printf("Output mode set to %s\n", strings.ToUpper(settings.mode))
+	case ".headers":
+		return setOnOff("Headers", args, &settings.options.Headers)
+	case ".nullvalue":
+		settings.options.NullValue = unquote(rest)
+	case ".maxwidth":
+		if len(args) == 0 {
+			fmt.Printf("Max width: %d\n", settings.options.MaxWidth)
+			return nil
+		}
+		width, err := strconv.Atoi(args[0])
+		if err != nil || width < 0 {
+			return errors.New("usage: .maxwidth <n>")
+		}
+		settings.options.MaxWidth = width
+	case ".wrap":
+		return setOnOff("Wrap", args, &settings.options.Wrap)
+	case ".expanded":
+		if len(args) == 0 {
+			fmt.Printf("Expanded: %s\n", settings.options.Expanded)
+			return nil
+		}
+		expanded, err := format.ParseExpanded(args[0])
+		if err != nil {
+			return err
+		}
+		settings.options.Expanded = expanded
+	case ".pager":
+		return setOnOff("Pager", args, &settings.pager)
+	case ".tables":
+		return s.listTables(unquote(rest))
+	case ".schema":
+		if len(args) == 0 {
+			return errors.New("usage: .schema <table>")
+		}
+		return s.showSchema(strings.ToLower(args[0]))
+	case ".explain":
+		if rest == "" {
+			return errors.New("usage: .explain <query>")
+		}
+		plan, err := s.engine.Explain(rest)
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(s.writer(), plan)
+	case ".timer":
+		return setOnOff("Timer", args, &s.timer)
+	case ".read":
+		if rest == "" {
+			return errors.New("usage: .read <file>")
+		}
+		return s.readFile(unquote(rest))
+	case ".output":
+		return s.setOutput(unquote(rest), false)
+	case ".once":
+		if rest == "" {
+			return errors.New("usage: .once <file>")
+		}
+		return s.setOutput(unquote(rest), true)
+	case ".show":
+		s.showSettings()
+	case ".save":
+		if len(args) == 0 {
+			return errors.New("usage: .save <name> [query]")
+		}
+		return s.saveQuery(args[0], strings.TrimSpace(rest[len(args[0]):]))
+	case ".run":
+		if len(args) == 0 {
+			return s.listSavedQueries()
+		}
+		library, err := s.queries()
+		if err != nil {
+			return err
+		}
+		query, ok := library.get(args[0])
+		if !ok {
+			return fmt.Errorf("no saved query named %s", args[0])
+		}
+		return s.runStatements(query)
+	case ".watch":
+		interval, keys, query, err := parseWatch(rest)
+		if err != nil {
+			return err
+		}
+		return s.watch(query, interval, keys)
+	case ".help":
+		fmt.Println("Commands:")
+		for _, command := range shellCommands {
+			fmt.Printf("  %-25s - %s\n", command.usage, command.description)
+		}
+	default:
+		return fmt.Errorf("unknown command: %s", input)
+	}
+	return nil
+}
+
+// listTables prints the names of the tables matching a LIKE pattern, or of
+// all tables when the pattern is empty
+func (s *shell) listTables(pattern string) error {
+	var names []string
+	for _, table := range execintf.GetTables() {
+		if pattern == "" || operations.MatchesLike(table.Name, pattern) {
+			names = append(names, table.Name)
+		}
+	}
+	width := 0
+	if s.output == nil && lineedit.IsTerminal(os.Stdout) {
+		width, _ = lineedit.TerminalSize(os.Stdout)
+	}
+	printColumns(s.writer(), names, width)
+	return nil
+}
+
+// showSchema prints the columns of a table in the output mode
+func (s *shell) showSchema(tableName string) error {
+	schema, err := execintf.GetSchema(tableName)
+	if err != nil {
+		return err
+	}
+	for _, table := range execintf.GetTables() {
+		if table.Name == tableName && table.Description != "" {
+			fmt.Fprintf(s.writer(), "%s: %s\n", table.Name, table.Description)
+		}
+	}
+
+	index := result.NewColumnIndex("name", "type", "description")
+	columns := result.NewQueryResult()
+	for _, column := range schema {
+		row := index.NewRow()
+		row.Add("name", column.Name)
+		row.Add("type", column.Type)
+		row.Add("description", column.Description)
+		columns.AppendResult(row)
+	}
+	return s.writeResults(columns, false)
+}
+
+// showSettings prints the current settings of the shell
+func (s *shell) showSettings() {
+	output := "stdout"
+	if s.output != nil {
+		output = s.output.Name()
+		if s.once {
+			output += " (once)"
+		}
+	}
+
+	settings := s.settings
+	for _, setting := range [][2]string{
+		{"mode", settings.mode},
+		{"headers", onOff(settings.options.Headers)},
+		{"nullvalue", strconv.Quote(settings.options.NullValue)},
+		{"maxwidth", strconv.Itoa(settings.options.MaxWidth)},
+		{"wrap", onOff(settings.options.Wrap)},
+		{"expanded", settings.options.Expanded.String()},
+		{"pager", onOff(settings.pager)},
+		{"timer", onOff(s.timer)},
+		{"output", output},
+	} {
+		fmt.Fprintf(s.writer(), "%10s: %s\n", setting[0], setting[1])
+	}
+}
+
+// saveQuery saves a query to the library, or the last query executed when
+// query is empty
+func (s *shell) saveQuery(name, query string) error {
+	for i := 0; i < len(name); i++ {
+		if !isIdentifierByte(name[i]) {
+			return fmt.Errorf("invalid query name: %s", name)
+		}
+	}
+	if query == "" {
+		query = s.lastQuery
+	}
+	if query == "" {
+		return errors.New("no query to save")
+	}
+
+	library, err := s.queries()
+	if err != nil {
+		return err
+	}
+	if err := library.save(name, query); err != nil {
+		return fmt.Errorf("saving query: %w", err)
+	}
+	fmt.Printf("Saved query %s\n", name)
+	return nil
+}
+
+// listSavedQueries prints the saved queries with their names
+func (s *shell) listSavedQueries() error {
+	library, err := s.queries()
+	if err != nil {
+		return err
+	}
+	if len(library.names()) == 0 {
+		fmt.Println("No saved queries, save one with .save <name> [query]")
+	}
+	for _, name := range library.names() {
+		query, _ := library.get(name)
+		fmt.Printf("%s: %s\n", name, strings.ReplaceAll(query, "\n", " "))
+	}
+	return nil
+}
+
+// printColumns writes names in columns filling the given width, or one per
+// line when the width is unknown
+func printColumns(w io.Writer, names []string, width int) {
+	columnWidth := 0
+	for _, name := range names {
+		columnWidth = max(columnWidth, len(name)+2)
+	}
+	perLine := 1
+	if width > 0 && columnWidth > 0 {
+		perLine = max(1, width/columnWidth)
+	}
+
+	// Names run down the columns, as in ls
+	lines := (len(names) + perLine - 1) / perLine
+	for line := 0; line < lines; line++ {
+		var sb strings.Builder
+		for i := line; i < len(names); i += lines {
+			sb.WriteString(fmt.Sprintf("%-*s", columnWidth, names[i]))
+		}
+		fmt.Fprintln(w, strings.TrimRight(sb.String(), " "))
+	}
+}
+
+// printError reports an error on the standard error stream
+func printError(err error) {
+	fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scrymastic/goosquery/cmd/goosquery/format"
+	"github.com/scrymastic/goosquery/sql/engine"
+)
+
+func newTestShell(t *testing.T) *shell {
+	t.Helper()
+	settings, err := newOutputSettings("table")
+	if err != nil {
+		t.Fatalf("Failed to create the settings: %v", err)
+	}
+	sh := &shell{engine: engine.NewEngine(), settings: settings}
+	t.Cleanup(func() { sh.closeOutput() })
+	return sh
+}
+
+func readOutput(t *testing.T, path string) string {
+	t.Helper()
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read the output: %v", err)
+	}
+	return string(content)
+}
+
+func TestRunCommandSettings(t *testing.T) {
+	sh := newTestShell(t)
+	for _, command := range []string{
+		".mode csv",
+		".HEADERS off",
+		".nullvalue 'NULL'",
+		".maxwidth 12",
+		".wrap on",
+		".expanded on",
+		".pager off",
+		".timer on",
+	} {
+		if err := sh.runCommand(command); err != nil {
+			t.Fatalf("%s: %v", command, err)
+		}
+	}
+
+	settings := sh.settings
+	if settings.mode != "csv" || settings.options.Headers || settings.options.NullValue != "NULL" ||
+		settings.options.MaxWidth != 12 || !settings.options.Wrap || settings.options.Expanded != format.ExpandedOn ||
+		settings.pager || !sh.timer {
+		t.Errorf("Unexpected settings: mode %s, %+v, pager %v, timer %v", settings.mode, settings.options, settings.pager, sh.timer)
+	}
+}
+
+func TestRunCommandErrors(t *testing.T) {
+	sh := newTestShell(t)
+	for _, command := range []string{
+		".unknown",
+		".mode nosuchmode",
+		".headers maybe",
+		".maxwidth -1",
+		".maxwidth wide",
+		".expanded sometimes",
+		".schema",
+		".schema no_such_table",
+		".explain",
+		".read",
+		".once",
+		".save",
+		".watch 1s",
+		".watch soon SELECT 1",
+	} {
+		if err := sh.runCommand(command); err == nil || err == errQuit {
+			t.Errorf("%s: expected an error, got %v", command, err)
+		}
+	}
+	if err := sh.runCommand(".quit"); err != errQuit {
+		t.Errorf("Expected .quit to quit, got %v", err)
+	}
+}
+
+func TestParseWatch(t *testing.T) {
+	tests := []struct {
+		args     string
+		interval time.Duration
+		keys     []string
+		query    string
+	}{
+		{"2s SELECT pid FROM processes", 2 * time.Second, nil, "SELECT pid FROM processes"},
+		{"500ms key=pid,name SELECT pid, name FROM processes", 500 * time.Millisecond, []string{"pid", "name"}, "SELECT pid, name FROM processes"},
+		{"1m KEY= pid SELECT 1", time.Minute, nil, "pid SELECT 1"},
+	}
+	for _, test := range tests {
+		interval, keys, query, err := parseWatch(test.args)
+		if err != nil {
+			t.Errorf("%s: %v", test.args, err)
+			continue
+		}
+		if interval != test.interval || strings.Join(keys, ",") != strings.Join(test.keys, ",") || query != test.query {
+			t.Errorf("%s: got %v, %q, %q", test.args, interval, keys, query)
+		}
+	}
+	if _, _, _, err := parseWatch("1s key=pid"); err == nil {
+		t.Error("Expected an error for a watch without a query")
+	}
+}
+
+func TestOutputRedirection(t *testing.T) {
+	sh := newTestShell(t)
+	path := filepath.Join(t.TempDir(), "output.txt")
+
+	// The output of the commands follows the results into the file
+	script := strings.Join([]string{
+		".output " + path,
+		".mode csv",
+		"SELECT 1 AS one;",
+		".tables uptime",
+		".schema uptime",
+		".show",
+		".explain SELECT * FROM uptime",
+		".output stdout",
+	}, "\n")
+	if err := sh.runScript(strings.NewReader(script)); err != nil {
+		t.Fatalf("Failed to run the script: %v", err)
+	}
+	if sh.output != nil {
+		t.Error("Expected .output stdout to close the file")
+	}
+
+	output := readOutput(t, path)
+	for _, expected := range []string{
+		"one\n1\n",
+		"\nuptime\n",
+		"uptime: ",
+		"total_seconds,BIGINT,",
+		"    output: " + path + "\n",
+		"SCAN uptime",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected the output to contain %q, got:\n%s", expected, output)
+		}
+	}
+}
+
+func TestOutputOnce(t *testing.T) {
+	sh := newTestShell(t)
+	path := filepath.Join(t.TempDir(), "once.txt")
+
+	if err := sh.runCommand(".once " + path); err != nil {
+		t.Fatalf("Failed to set the output: %v", err)
+	}
+	if err := sh.runStatements("SELECT 'first' AS value"); err != nil {
+		t.Fatalf("Failed to run the query: %v", err)
+	}
+	if sh.output != nil {
+		t.Fatal("Expected .once to end after a query")
+	}
+	if output := readOutput(t, path); !strings.Contains(output, "first") {
+		t.Errorf("Expected the result in the file, got:\n%s", output)
+	}
+}
+
+func TestWriteResultsKeepsSettings(t *testing.T) {
+	sh := newTestShell(t)
+	sh.settings.options.TerminalWidth = 40
+	if err := sh.runCommand(".output " + filepath.Join(t.TempDir(), "output.txt")); err != nil {
+		t.Fatalf("Failed to set the output: %v", err)
+	}
+	if err := sh.runStatements("SELECT 1 AS one"); err != nil {
+		t.Fatalf("Failed to run the query: %v", err)
+	}
+	if sh.settings.options.TerminalWidth != 40 {
+		t.Errorf("Expected the terminal width of the shell to be kept, got %d", sh.settings.options.TerminalWidth)
+	}
+}
+
+func TestJSONStatements(t *testing.T) {
+	tests := []struct {
+		statements string
+		expected   interface{}
+	}{
+		// A single statement writes its rows alone
+		{"SELECT 1 AS one", []interface{}{
+			map[string]interface{}{"one": 1.0},
+		}},
+		// Several statements write an array of rows per statement
+		{"SELECT 1 AS one; SELECT 'b' AS two WHERE 1 = 0; SELECT 3 AS three", []interface{}{
+			[]interface{}{map[string]interface{}{"one": 1.0}},
+			[]interface{}{},
+			[]interface{}{map[string]interface{}{"three": 3.0}},
+		}},
+	}
+	for _, test := range tests {
+		sh := newTestShell(t)
+		if err := sh.settings.setMode("json"); err != nil {
+			t.Fatalf("Failed to set the mode: %v", err)
+		}
+		path := filepath.Join(t.TempDir(), "output.json")
+		if err := sh.runCommand(".output " + path); err != nil {
+			t.Fatalf("Failed to set the output: %v", err)
+		}
+		if err := sh.runStatements(test.statements); err != nil {
+			t.Fatalf("%s: %v", test.statements, err)
+		}
+		if err := sh.closeOutput(); err != nil {
+			t.Fatalf("Failed to close the output: %v", err)
+		}
+
+		output := readOutput(t, path)
+		var document interface{}
+		if err := json.Unmarshal([]byte(output), &document); err != nil {
+			t.Fatalf("%s: invalid JSON %v:\n%s", test.statements, err, output)
+		}
+		if fmt.Sprint(document) != fmt.Sprint(test.expected) {
+			t.Errorf("%s: expected %v, got %v", test.statements, test.expected, document)
+		}
+	}
+}
@@ -0,0 +1,197 @@
+// Package watch compares the results of a query run repeatedly, to show the
+// rows added, removed and changed between runs.
+package watch
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+
+	"github.com/scrymastic/goosquery/sql/executor/operations"
+	"github.com/scrymastic/goosquery/sql/result"
+)
+
+// ChangeColumn is the column of Diff.Results marking each row as added (+),
+// removed (-) or changed (~)
+const ChangeColumn = "change"
+
+// Diff holds the rows that differ between two runs of a query
+type Diff struct {
+	Added   []result.Result
+	Removed []result.Result
+	Changed []result.Result // rows of the same key with other values
+}
+
+// Empty reports whether the runs returned the same rows
+func (d Diff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// String summarizes the number of rows of each kind
+func (d Diff) String() string {
+	return fmt.Sprintf("+%d -%d ~%d", len(d.Added), len(d.Removed), len(d.Changed))
+}
+
+// Results returns the differing rows preceded by the change column, removed
+// rows first
+func (d Diff) Results() *result.Results {
+	rows := result.NewQueryResult()
+	for _, group := range []struct {
+		change string
+		rows   []result.Result
+	}{{"-", d.Removed}, {"~", d.Changed}, {"+", d.Added}} {
+		for _, row := range group.rows {
+			marked := result.Result{}
+			marked.Add(ChangeColumn, group.change)
+			row.Range(func(key string, value interface{}) bool {
+				marked.Add(key, value)
+				return true
+			})
+			rows.AppendResult(marked)
+		}
+	}
+	return rows
+}
+
+// entry is a row of the previous run with the hash of its values
+type entry struct {
+	row  result.Result
+	hash uint64
+}
+
+// Tracker remembers the rows of the previous run of a query. Rows are
+// identified by the values of key columns, or by a hash of the whole row
+// when there are no key columns, in which case changed rows show up as
+// removed and added.
+type Tracker struct {
+	keys     []string
+	previous map[string]entry
+	order    []string
+}
+
+// NewTracker creates a tracker identifying rows by the given key columns
+func NewTracker(keys []string) *Tracker {
+	return &Tracker{keys: keys, previous: make(map[string]entry)}
+}
+
+// Update compares the rows of a run with those of the previous run. All rows
+// of the first run are added.
+func (t *Tracker) Update(results *result.Results) (Diff, error) {
+	if results.Size() > 0 {
+		columns := results.GetColumns()
+		for _, key := range t.keys {
+			if !containsFold(columns, key) {
+				return Diff{}, fmt.Errorf("unknown key column: %s", key)
+			}
+		}
+	}
+
+	var diff Diff
+	current := make(map[string]entry, results.Size())
+	order := make([]string, 0, results.Size())
+	occurrences := make(map[string]int)
+	for _, row := range *results {
+		hash := rowHash(row)
+		id := t.identity(row, hash)
+
+		// Rows sharing an identity are told apart by their position
+		if occurrences[id]++; occurrences[id] > 1 {
+			id += "#" + strconv.Itoa(occurrences[id])
+		}
+		current[id] = entry{row: row, hash: hash}
+		order = append(order, id)
+
+		previous, ok := t.previous[id]
+		switch {
+		case !ok:
+			diff.Added = append(diff.Added, row)
+		case previous.hash != hash:
+			diff.Changed = append(diff.Changed, row)
+		}
+	}
+	for _, id := range t.order {
+		if _, ok := current[id]; !ok {
+			diff.Removed = append(diff.Removed, t.previous[id].row)
+		}
+	}
+
+	t.previous, t.order = current, order
+	return diff, nil
+}
+
+// identity returns the key column values of a row, or its hash
+func (t *Tracker) identity(row result.Result, hash uint64) string {
+	if len(t.keys) == 0 {
+		return strconv.FormatUint(hash, 16)
+	}
+	values := make([]string, len(t.keys))
+	for i, key := range t.keys {
+		// NULL is left unquoted to tell it from the text "NULL"
+		value := lookupFold(row, key)
+		if value == nil {
+			values[i] = "NULL"
+		} else {
+			values[i] = strconv.Quote(operations.FormatValue(value))
+		}
+	}
+	return strings.Join(values, ",")
+}
+
+// Tags written before each value hashed, so that values of different types
+// never hash the same, e.g. NULL and the text "\x01"
+const (
+	nullTag  = 0
+	textTag  = 1
+	valueTag = 2
+)
+
+// rowHash hashes the names and values of the columns of a row. Names and
+// values are prefixed with their length, and values with their type.
+func rowHash(row result.Result) uint64 {
+	h := fnv.New64a()
+	writeString := func(s string) {
+		var length [binary.MaxVarintLen64]byte
+		h.Write(length[:binary.PutUvarint(length[:], uint64(len(s)))])
+		h.Write([]byte(s))
+	}
+	row.Range(func(key string, value interface{}) bool {
+		writeString(key)
+		switch value := value.(type) {
+		case nil:
+			h.Write([]byte{nullTag})
+		case string:
+			h.Write([]byte{textTag})
+			writeString(value)
+		default:
+			h.Write([]byte{valueTag})
+			writeString(operations.FormatValue(value))
+		}
+		return true
+	})
+	return h.Sum64()
+}
+
+// lookupFold returns the value of a column, ignoring the case of its name
+func lookupFold(row result.Result, column string) interface{} {
+	var found interface{}
+	row.Range(func(key string, value interface{}) bool {
+		if strings.EqualFold(key, column) {
+			found = value
+			return false
+		}
+		return true
+	})
+	return found
+}
+
+// containsFold reports whether a column is in the list, ignoring case
+func containsFold(columns []string, column string) bool {
+	for _, c := range columns {
+		if strings.EqualFold(c, column) {
+			return true
+		}
+	}
+	return false
+}
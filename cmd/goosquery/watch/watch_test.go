@@ -0,0 +1,126 @@
+package watch
+
+import (
+	"testing"
+
+	"github.com/scrymastic/goosquery/sql/result"
+)
+
+// sockets builds rows shaped like process_open_sockets
+func sockets(rows ...[3]interface{}) *result.Results {
+	index := result.NewColumnIndex("pid", "remote_address", "state")
+	results := result.NewQueryResult()
+	for _, values := range rows {
+		row := index.NewRow()
+		row.Add("pid", values[0])
+		row.Add("remote_address", values[1])
+		row.Add("state", values[2])
+		results.AppendResult(row)
+	}
+	return results
+}
+
+func TestTrackerWholeRow(t *testing.T) {
+	tracker := NewTracker(nil)
+	diff, err := tracker.Update(sockets(
+		[3]interface{}{int64(4), "10.0.0.1", "ESTABLISHED"},
+		[3]interface{}{int64(8), "10.0.0.2", "ESTABLISHED"},
+	))
+	if err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if diff.String() != "+2 -0 ~0" {
+		t.Errorf("Expected all rows added on the first run, got %s", diff)
+	}
+
+	diff, _ = tracker.Update(sockets(
+		[3]interface{}{int64(8), "10.0.0.2", "ESTABLISHED"},
+		[3]interface{}{int64(4), "10.0.0.1", "ESTABLISHED"},
+	))
+	if !diff.Empty() {
+		t.Errorf("Expected no changes when rows are reordered, got %s", diff)
+	}
+
+	// Without key columns a changed row is removed and added
+	diff, _ = tracker.Update(sockets(
+		[3]interface{}{int64(8), "10.0.0.2", "CLOSE_WAIT"},
+		[3]interface{}{int64(4), "10.0.0.1", "ESTABLISHED"},
+	))
+	if diff.String() != "+1 -1 ~0" {
+		t.Errorf("Expected one row added and one removed, got %s", diff)
+	}
+	if diff.Removed[0].Get("state") != "ESTABLISHED" || diff.Added[0].Get("state") != "CLOSE_WAIT" {
+		t.Errorf("Unexpected rows: removed %v, added %v", diff.Removed[0].ToMap(), diff.Added[0].ToMap())
+	}
+}
+
+func TestTrackerDuplicateRows(t *testing.T) {
+	row := [3]interface{}{int64(4), "10.0.0.1", "ESTABLISHED"}
+	tracker := NewTracker(nil)
+	tracker.Update(sockets(row, row))
+
+	diff, _ := tracker.Update(sockets(row))
+	if diff.String() != "+0 -1 ~0" {
+		t.Errorf("Expected one of the duplicate rows removed, got %s", diff)
+	}
+}
+
+func TestTrackerKeys(t *testing.T) {
+	tracker := NewTracker([]string{"PID"})
+	tracker.Update(sockets(
+		[3]interface{}{int64(4), "10.0.0.1", "ESTABLISHED"},
+		[3]interface{}{int64(8), "10.0.0.2", "ESTABLISHED"},
+	))
+
+	diff, err := tracker.Update(sockets(
+		[3]interface{}{int64(4), "10.0.0.1", "CLOSE_WAIT"},
+		[3]interface{}{int64(12), "10.0.0.3", "ESTABLISHED"},
+	))
+	if err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if diff.String() != "+1 -1 ~1" {
+		t.Fatalf("Expected one row of each kind, got %s", diff)
+	}
+
+	results := diff.Results()
+	expected := []string{"-", "~", "+"}
+	for i, row := range *results {
+		if row.Get(ChangeColumn) != expected[i] {
+			t.Errorf("Row %d: expected change %s, got %v", i, expected[i], row.Get(ChangeColumn))
+		}
+	}
+	if columns := results.GetColumns(); len(columns) != 4 || columns[0] != ChangeColumn {
+		t.Errorf("Expected the change column first, got %v", columns)
+	}
+
+	if _, err := NewTracker([]string{"fd"}).Update(sockets([3]interface{}{int64(4), "", ""})); err == nil {
+		t.Error("Expected an error for an unknown key column")
+	}
+}
+
+func TestRowHashTypes(t *testing.T) {
+	rows := sockets(
+		[3]interface{}{int64(4), nil, "ESTABLISHED"},
+		[3]interface{}{int64(4), "\x01", "ESTABLISHED"},
+		[3]interface{}{int64(4), "", "ESTABLISHED"},
+		[3]interface{}{int64(4), "NULL", "ESTABLISHED"},
+		[3]interface{}{"4", "", "ESTABLISHED"},
+	)
+	hashes := make(map[uint64]int)
+	for i, row := range *rows {
+		hash := rowHash(row)
+		if previous, ok := hashes[hash]; ok {
+			t.Errorf("Expected rows %d and %d to hash differently", previous, i)
+		}
+		hashes[hash] = i
+	}
+
+	// A NULL key is not the text NULL
+	tracker := NewTracker([]string{"remote_address"})
+	tracker.Update(sockets([3]interface{}{int64(4), nil, "ESTABLISHED"}))
+	diff, _ := tracker.Update(sockets([3]interface{}{int64(4), "NULL", "ESTABLISHED"}))
+	if diff.String() != "+1 -1 ~0" {
+		t.Errorf("Expected the NULL row replaced, got %s", diff)
+	}
+}
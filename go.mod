@@ -9,9 +9,7 @@ require (
 	github.com/blastrain/vitess-sqlparser v0.0.0-20201030050434-a139afbb1aba
 	github.com/go-ole/go-ole v1.3.0
 	golang.org/x/sys v0.31.0
+	golang.org/x/text v0.0.0-20180302201248-b7ef84aaf62a
 )
 
-require (
-	github.com/juju/errors v0.0.0-20170703010042-c7d06af17c68 // indirect
-	golang.org/x/text v0.0.0-20180302201248-b7ef84aaf62a // indirect
-)
+require github.com/juju/errors v0.0.0-20170703010042-c7d06af17c68 // indirect
@@ -0,0 +1,106 @@
+package engine
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/scrymastic/goosquery/sql/executor/impl"
+	execintf "github.com/scrymastic/goosquery/sql/executor/interface"
+	"github.com/scrymastic/goosquery/sql/parser"
+	"github.com/scrymastic/goosquery/sql/result"
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+)
+
+// stubGenerator returns two rows of a table with a value in every column, so
+// that queries run the same on every platform and without privileges
+func stubGenerator(schema result.Schema) impl.DataGenerator {
+	return func(ctx *sqlctx.Context) (*result.Results, error) {
+		rows := result.NewQueryResult()
+		for i := 1; i <= 2; i++ {
+			row := result.NewResult(ctx, schema)
+			for _, column := range schema {
+				switch {
+				case column.Type == "INTEGER":
+					row.Set(column.Name, int32(i))
+				case column.Type == "BIGINT":
+					row.Set(column.Name, int64(i))
+				case column.Type == "DOUBLE":
+					row.Set(column.Name, float64(i))
+				case strings.HasSuffix(column.Name, "_json"):
+					row.Set(column.Name, `["nativeMessaging"]`)
+				default:
+					row.Set(column.Name, fmt.Sprintf("%s %d", column.Name, i))
+				}
+			}
+			rows.AppendResult(*row)
+		}
+		return rows, nil
+	}
+}
+
+// TestOsqueryPackCorpus executes the queries of the osquery packs against
+// stubs of their tables, which must be provided on at least one platform
+func TestOsqueryPackCorpus(t *testing.T) {
+	data, err := os.ReadFile("../parser/testdata/osquery_packs.sql")
+	if err != nil {
+		t.Fatalf("Failed to read corpus: %v", err)
+	}
+
+	count := 0
+	for _, query := range strings.Split(string(data), ";\n") {
+		var lines []string
+		for _, line := range strings.Split(query, "\n") {
+			if !strings.HasPrefix(strings.TrimSpace(line), "--") {
+				lines = append(lines, line)
+			}
+		}
+		query = strings.TrimSpace(strings.Join(lines, "\n"))
+		if query == "" {
+			continue
+		}
+		count++
+
+		parsedQuery, err := parser.Parse(query)
+		if err != nil {
+			t.Errorf("Parse(%q) returned error: %v", query, err)
+			continue
+		}
+		tableName, err := parser.GetTableName(parsedQuery.Statement)
+		if err != nil {
+			t.Errorf("%q: %v", query, err)
+			continue
+		}
+		executor := &impl.TableExecutor{TableName: tableName, Generator: impl.GenDual}
+		if tableName != "dual" {
+			var table *result.Table
+			for _, candidate := range execintf.AllTables() {
+				if candidate.Name == tableName {
+					table = &candidate
+					break
+				}
+			}
+			if table == nil {
+				t.Errorf("%q: unknown table %s", query, tableName)
+				continue
+			}
+			executor.Generator = stubGenerator(table.Schema)
+		}
+		if _, err := executor.Execute(parsedQuery); err != nil {
+			t.Errorf("Execute(%q) returned error: %v", query, err)
+		}
+	}
+	if count == 0 {
+		t.Fatal("Corpus contains no queries")
+	}
+}
+
+// Test that joins between tables are reported rather than misread
+func TestExecuteJoin(t *testing.T) {
+	engine := NewEngine()
+	_, err := engine.Execute("SELECT p.name, l.port FROM listening_ports AS l JOIN processes AS p USING (pid)")
+	if err == nil || !strings.Contains(err.Error(), "joins between tables are not supported") {
+		t.Fatalf("Expected joins to be unsupported, got %v", err)
+	}
+}
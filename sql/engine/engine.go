@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/blastrain/vitess-sqlparser/sqlparser"
+	"github.com/scrymastic/goosquery/sql/executor/impl"
 	execintf "github.com/scrymastic/goosquery/sql/executor/interface"
 	"github.com/scrymastic/goosquery/sql/parser"
 	"github.com/scrymastic/goosquery/sql/result"
@@ -19,29 +20,62 @@ func NewEngine() *Engine {
 
 // Execute executes a SQL query and returns the result
 func (e *Engine) Execute(query string) (*result.Results, error) {
+	results, _, err := e.ExecuteWithStats(query)
+	return results, err
+}
+
+// ExecuteWithStats executes a SQL query and returns the result with the
+// time spent generating and processing the rows
+func (e *Engine) ExecuteWithStats(query string) (*result.Results, impl.Stats, error) {
+	parsedQuery, exec, err := e.prepare(query)
+	if err != nil {
+		return nil, impl.Stats{}, err
+	}
+
+	results, err := exec.Execute(parsedQuery)
+	if tableExec, ok := exec.(*impl.TableExecutor); ok {
+		return results, tableExec.Stats, err
+	}
+	return results, impl.Stats{}, err
+}
+
+// Explain returns the plan of a SQL query without executing it
+func (e *Engine) Explain(query string) (*impl.Plan, error) {
+	parsedQuery, exec, err := e.prepare(query)
+	if err != nil {
+		return nil, err
+	}
+
+	tableExec, ok := exec.(*impl.TableExecutor)
+	if !ok {
+		return nil, fmt.Errorf("no plan available")
+	}
+	return tableExec.Plan(parsedQuery)
+}
+
+// prepare parses a SQL query and finds the executor of its table
+func (e *Engine) prepare(query string) (*parser.ParsedQuery, execintf.Executor, error) {
 	// Parse the SQL query
 	parsedQuery, err := parser.Parse(query)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// Get the table name
 	tableName, err := parser.GetTableName(parsedQuery.Statement)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// Get the executor for this table
 	exec, err := execintf.GetExecutor(tableName)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	// Execute the query
-	selectStmt, ok := parsedQuery.Statement.(*sqlparser.Select)
-	if !ok {
-		return nil, fmt.Errorf("only SELECT statements are supported")
+	// Only SELECT statements can be executed
+	if _, ok := parsedQuery.Statement.(*sqlparser.Select); !ok {
+		return nil, nil, fmt.Errorf("only SELECT statements are supported")
 	}
-
-	return exec.Execute(selectStmt)
+	return parsedQuery, exec, nil
 }
@@ -1,20 +1,61 @@
 package engine
 
 import (
+	"runtime"
+	"strings"
 	"testing"
 )
 
-// Test query execution
-func TestExecute(t *testing.T) {
+// Test a query on a table provided on every platform
+func TestExecutePortable(t *testing.T) {
 	engine := NewEngine()
-	query := "select count(name) from processes where name = 'Cursor.exe';"
-	result, err := engine.Execute(query)
-
+	result, err := engine.Execute("select weekday, year from time;")
 	if err != nil {
 		t.Fatalf("Failed to execute query: %v", err)
 	}
+	if result.Size() != 1 {
+		t.Fatalf("Expected 1 row, got %d", result.Size())
+	}
+}
+
+// Test that the tables of other platforms are reported as unavailable
+func TestExecuteUnavailable(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("every table is provided on Windows")
+	}
+	engine := NewEngine()
+	_, err := engine.Execute("select name from windows_firewall_rules;")
+	if err == nil || !strings.Contains(err.Error(), "unavailable on this platform") {
+		t.Fatalf("Expected the table to be unavailable, got %v", err)
+	}
 
-	if result == nil {
-		t.Fatalf("Expected non-nil result, got nil")
+	_, err = engine.Execute("select * from no_such_table;")
+	if err == nil || !strings.Contains(err.Error(), "unsupported table") {
+		t.Fatalf("Expected an unsupported table, got %v", err)
+	}
+}
+
+// Test that conditions in the SELECT list are computed like the WHERE clause
+func TestExecuteSelectedConditions(t *testing.T) {
+	engine := NewEngine()
+	result, err := engine.Execute("SELECT datetime('now') IS NOT NULL AS known, NULL IS NULL AS null_is_null, " +
+		"5 BETWEEN 1 AND 10 AS in_range, 2 > 3 AS greater, 'a' = 'a' AND NOT 1 = 2 AS both;")
+	if err != nil {
+		t.Fatalf("Failed to execute query: %v", err)
+	}
+	if result.Size() != 1 {
+		t.Fatalf("Expected 1 row, got %d", result.Size())
+	}
+	row := (*result)[0]
+	for column, expected := range map[string]int64{
+		"known":        1,
+		"null_is_null": 1,
+		"in_range":     1,
+		"greater":      0,
+		"both":         1,
+	} {
+		if value := row.Get(column); value != expected {
+			t.Errorf("%s: expected %d, got %v", column, expected, value)
+		}
 	}
 }
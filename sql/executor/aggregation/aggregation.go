@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/blastrain/vitess-sqlparser/sqlparser"
+	"github.com/scrymastic/goosquery/sql/executor/functions"
 	"github.com/scrymastic/goosquery/sql/executor/operations"
 	"github.com/scrymastic/goosquery/sql/result"
 )
@@ -14,17 +15,19 @@ func ApplyAggregations(results *result.Results, aggregations []AggregationInfo,
 	// If there are no results, return empty result with aggregation columns
 	if len(*results) == 0 {
 		emptyResult := result.NewQueryResult()
-		emptyRow := make(map[string]interface{})
+		emptyRow := aggregatedColumns(nil, aggregations).NewRow()
 
 		// Add zero values for all aggregations
 		for _, agg := range aggregations {
 			switch agg.Type {
 			case Count:
-				emptyRow[agg.Alias] = 0
+				emptyRow.Add(agg.Alias, 0)
 			case Sum, Avg:
-				emptyRow[agg.Alias] = 0.0
+				emptyRow.Add(agg.Alias, 0.0)
 			case Min, Max:
-				emptyRow[agg.Alias] = nil
+				emptyRow.Add(agg.Alias, nil)
+			case JSONGroupArray:
+				emptyRow.Add(agg.Alias, "[]")
 			}
 		}
 
@@ -54,10 +57,20 @@ func ApplyAggregations(results *result.Results, aggregations []AggregationInfo,
 	return aggregateByGroups(results, aggregations, groupByColumns)
 }
 
+// aggregatedColumns creates the column index shared by all aggregated rows:
+// the group by columns followed by the aggregation results
+func aggregatedColumns(groupByColumns []string, aggregations []AggregationInfo) *result.ColumnIndex {
+	columns := append([]string{}, groupByColumns...)
+	for _, agg := range aggregations {
+		columns = append(columns, agg.Alias)
+	}
+	return result.NewColumnIndex(columns...)
+}
+
 // aggregateAll applies aggregations to the entire result set (no GROUP BY)
 func aggregateAll(results *result.Results, aggregations []AggregationInfo) (*result.Results, error) {
 	// Create a single aggregated row
-	aggregatedRow := result.NewQueryResult()
+	aggregatedRow := aggregatedColumns(nil, aggregations).NewRow()
 
 	// Apply each aggregation function
 	for _, agg := range aggregations {
@@ -65,14 +78,12 @@ func aggregateAll(results *result.Results, aggregations []AggregationInfo) (*res
 		if err != nil {
 			return nil, err
 		}
-		res := result.NewEmptyResult()
-		res.Add(agg.Alias, value)
-		aggregatedRow.AppendResult(*res)
+		aggregatedRow.Add(agg.Alias, value)
 	}
 
 	// Create a new result with just the aggregated row
 	aggregatedResult := result.NewQueryResult()
-	aggregatedResult.AppendResults(*aggregatedRow)
+	aggregatedResult.AppendResult(aggregatedRow)
 
 	return aggregatedResult, nil
 }
@@ -83,65 +94,62 @@ func aggregateByGroups(results *result.Results, aggregations []AggregationInfo,
 	if len(*results) > 0 {
 		firstRow := (*results)[0]
 		for _, col := range groupByColumns {
-			if _, exists := firstRow[col]; !exists {
+			if _, exists := firstRow.Lookup(col); !exists {
 				return nil, fmt.Errorf("group by column %s not found in result set", col)
 			}
 		}
 	}
 
-	// Map to store groups: groupKey -> []rowIndex
-	groups := make(map[string][]int)
+	// Map to store groups: groupKey -> position in groups, in order of first appearance
+	groupPositions := make(map[string]int)
+	var groups []result.Results
 
 	// Group the rows
-	for i, row := range *results {
+	keyParts := make([]string, len(groupByColumns))
+	for _, row := range *results {
 		// Create a key for this group (combination of values of group by columns)
-		var keyParts []string
-		for _, col := range groupByColumns {
-			keyParts = append(keyParts, fmt.Sprintf("%v", row[col]))
+		for i, col := range groupByColumns {
+			keyParts[i] = operations.FormatValue(row.Get(col))
 		}
-		// Join the key parts with a colon, if there are multiple group by columns
-		groupKey := strings.Join(keyParts, ":")
+		// Join the key parts with a NUL byte, if there are multiple group by columns
+		groupKey := strings.Join(keyParts, "\x00")
 
-		// Add this row's index to the appropriate group
-		groups[groupKey] = append(groups[groupKey], i)
+		// Add this row to the appropriate group
+		pos, ok := groupPositions[groupKey]
+		if !ok {
+			pos = len(groups)
+			groupPositions[groupKey] = pos
+			groups = append(groups, nil)
+		}
+		groups[pos] = append(groups[pos], row)
 	}
 
-	// Create a new result with one row per group
-	aggregatedResult := result.NewQueryResult()
+	// Create a new result with one row per group, sharing one column index
+	aggregatedResult := make(result.Results, 0, len(groups))
+	index := aggregatedColumns(groupByColumns, aggregations)
 
 	// Process each group
-	for _, rowIndices := range groups {
-		// Create a subset of results containing only rows in this group
-		groupResults := result.NewQueryResult()
-		for _, idx := range rowIndices {
-			groupResults.AppendResult((*results)[idx])
-		}
-
-		// Create a row with group by columns and aggregated values
-		aggregatedRow := result.NewQueryResult()
+	for _, groupResults := range groups {
+		aggregatedRow := index.NewRow()
 
 		// First, add the group by columns
 		for _, col := range groupByColumns {
-			res := result.NewEmptyResult()
-			res.Add(col, (*results)[rowIndices[0]][col])
-			aggregatedRow.AppendResult(*res)
+			aggregatedRow.Add(col, groupResults[0].Get(col))
 		}
 
 		// Then add the aggregated values
 		for _, agg := range aggregations {
-			value, err := calculateAggregation(groupResults, agg)
+			value, err := calculateAggregation(&groupResults, agg)
 			if err != nil {
 				return nil, err
 			}
-			res := result.NewEmptyResult()
-			res.Add(agg.Alias, value)
-			aggregatedRow.AppendResult(*res)
+			aggregatedRow.Add(agg.Alias, value)
 		}
 
-		aggregatedResult.AppendResults(*aggregatedRow)
+		aggregatedResult = append(aggregatedResult, aggregatedRow)
 	}
 
-	return aggregatedResult, nil
+	return &aggregatedResult, nil
 }
 
 // calculateAggregation applies a single aggregation function to a set of results
@@ -150,26 +158,32 @@ func calculateAggregation(results *result.Results, agg AggregationInfo) (interfa
 		return nil, nil
 	}
 
+	// COUNT(*) counts rows rather than values
+	if agg.Type == Count && agg.Column == "*" {
+		return len(*results), nil
+	}
+
 	// For other aggregations, collect the values to aggregate
 	var values []interface{}
 
 	// If distinct, collect unique values
 	if agg.IsDistinct {
-		uniqueValues := result.NewQueryResult()
+		seen := make(map[string]bool)
 		for _, row := range *results {
-			if val, exists := row[agg.Column]; exists && val != nil {
+			if val, exists := row.Lookup(agg.Column); exists && val != nil {
 				// Use string representation as map key
-				uniqueValues.AppendResult(row)
+				key := operations.FormatValue(val)
+				if !seen[key] {
+					seen[key] = true
+					values = append(values, val)
+				}
 			}
 		}
-
-		for _, val := range *uniqueValues {
-			values = append(values, val)
-		}
 	} else {
 		// Otherwise collect all values
 		for _, row := range *results {
-			if val, exists := row[agg.Column]; exists && val != nil {
+			if val, exists := row.Lookup(agg.Column); exists && (val != nil || agg.Type == JSONGroupArray) {
+				// JSON_GROUP_ARRAY keeps NULLs as JSON null elements
 				values = append(values, val)
 			}
 		}
@@ -182,6 +196,8 @@ func calculateAggregation(results *result.Results, agg AggregationInfo) (interfa
 			return 0, nil
 		case Sum, Avg:
 			return 0.0, nil
+		case JSONGroupArray:
+			return "[]", nil
 		default:
 			return nil, nil
 		}
@@ -212,6 +228,9 @@ func calculateAggregation(results *result.Results, agg AggregationInfo) (interfa
 	case Max:
 		return operations.CalculateMax(values)
 
+	case JSONGroupArray:
+		return functions.EncodeJSONArray(values), nil
+
 	default:
 		return nil, fmt.Errorf("unsupported aggregation type: %v", agg.Type)
 	}
@@ -233,19 +252,8 @@ func ExtractAggregations(selectExprs sqlparser.SelectExprs) []AggregationInfo {
 		}
 
 		funcName := strings.ToUpper(funcExpr.Name.String())
-		var aggType AggregationType
-		switch funcName {
-		case "COUNT":
-			aggType = Count
-		case "SUM":
-			aggType = Sum
-		case "AVG":
-			aggType = Avg
-		case "MIN":
-			aggType = Min
-		case "MAX":
-			aggType = Max
-		default:
+		aggType, ok := aggregateFunctions[funcName]
+		if !ok {
 			continue // Not an aggregation function
 		}
 
@@ -256,15 +264,19 @@ func ExtractAggregations(selectExprs sqlparser.SelectExprs) []AggregationInfo {
 		if funcName != "COUNT" || len(funcExpr.Exprs) > 0 {
 			// For non-COUNT(*) functions, get the column name
 			if len(funcExpr.Exprs) > 0 {
-				argExpr, ok := funcExpr.Exprs[0].(*sqlparser.AliasedExpr)
-				if !ok {
+				switch argExpr := funcExpr.Exprs[0].(type) {
+				case *sqlparser.AliasedExpr:
+					if colName, ok := argExpr.Expr.(*sqlparser.ColName); ok {
+						columnName = colName.Name.String()
+					}
+				case *sqlparser.StarExpr:
+					// COUNT(*) is parsed with a star argument
+					if funcName != "COUNT" {
+						continue
+					}
+				default:
 					continue
 				}
-
-				colName, ok := argExpr.Expr.(*sqlparser.ColName)
-				if ok {
-					columnName = colName.Name.String()
-				}
 			}
 		}
 
@@ -304,9 +316,7 @@ func HasAggregations(selectExprs sqlparser.SelectExprs) bool {
 			continue
 		}
 
-		funcName := strings.ToUpper(funcExpr.Name.String())
-		switch funcName {
-		case "COUNT", "SUM", "AVG", "MIN", "MAX":
+		if IsAggregateFunction(funcExpr.Name.String()) {
 			return true
 		}
 	}
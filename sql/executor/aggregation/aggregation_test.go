@@ -0,0 +1,83 @@
+package aggregation
+
+import (
+	"testing"
+
+	"github.com/blastrain/vitess-sqlparser/sqlparser"
+	"github.com/scrymastic/goosquery/sql/result"
+)
+
+// aggregate runs the aggregations of a query over the rows
+func aggregate(t *testing.T, query string, rows []map[string]interface{}) *result.Results {
+	t.Helper()
+	stmt, err := sqlparser.Parse(query)
+	if err != nil {
+		t.Fatalf("Failed to parse %q: %v", query, err)
+	}
+	selectStmt := stmt.(*sqlparser.Select)
+
+	results := result.NewQueryResult()
+	for _, row := range rows {
+		results.AppendResult(result.FromMap(row))
+	}
+	aggregated, err := ApplyAggregations(results, ExtractAggregations(selectStmt.SelectExprs), selectStmt.GroupBy)
+	if err != nil {
+		t.Fatalf("Failed to aggregate %q: %v", query, err)
+	}
+	return aggregated
+}
+
+var processRows = []map[string]interface{}{
+	{"name": "svchost.exe", "pid": int64(100), "parent": int64(4)},
+	{"name": "svchost.exe", "pid": int64(200), "parent": int64(4)},
+	{"name": "cmd.exe", "pid": int64(300), "parent": nil},
+	{"name": "svchost.exe", "pid": int64(400), "parent": int64(8)},
+}
+
+func TestAggregatesOfAGroupShareARow(t *testing.T) {
+	results := aggregate(t, "SELECT name, count(pid), min(pid), max(pid) FROM t GROUP BY name", processRows)
+	if results.Size() != 2 {
+		t.Fatalf("Expected one row per group, got %v", *results)
+	}
+
+	expected := []map[string]interface{}{
+		{"name": "svchost.exe", "COUNT(pid)": 3, "MIN(pid)": int64(100), "MAX(pid)": int64(400)},
+		{"name": "cmd.exe", "COUNT(pid)": 1, "MIN(pid)": int64(300), "MAX(pid)": int64(300)},
+	}
+	for i, columns := range expected {
+		for column, value := range columns {
+			if (*results)[i].Get(column) != value {
+				t.Errorf("Expected %s = %v in group %d, got %v", column, value, i, (*results)[i].ToMap())
+			}
+		}
+	}
+}
+
+func TestCountStar(t *testing.T) {
+	// COUNT(*) counts the rows, including those with NULL columns
+	results := aggregate(t, "SELECT count(*), count(parent) FROM t", processRows)
+	row := (*results)[0]
+	if row.Get("COUNT(*)") != 4 || row.Get("COUNT(parent)") != 3 {
+		t.Errorf("Unexpected counts: %v", row.ToMap())
+	}
+
+	results = aggregate(t, "SELECT name, count(*) AS n FROM t GROUP BY name", processRows)
+	if (*results)[0].Get("n") != 3 || (*results)[1].Get("n") != 1 {
+		t.Errorf("Unexpected counts per group: %v", *results)
+	}
+}
+
+func TestCountDistinct(t *testing.T) {
+	results := aggregate(t, "SELECT count(DISTINCT parent), count(DISTINCT name) FROM t", processRows)
+	row := (*results)[0]
+	if row.Get("COUNT(parent)") != 2 || row.Get("COUNT(name)") != 2 {
+		t.Errorf("Unexpected distinct counts: %v", row.ToMap())
+	}
+}
+
+func TestAggregateNoRows(t *testing.T) {
+	results := aggregate(t, "SELECT count(*), max(pid) FROM t", nil)
+	if results.Size() != 1 || (*results)[0].Get("COUNT(*)") != 0 || (*results)[0].Get("MAX(pid)") != nil {
+		t.Errorf("Expected a single row of empty aggregates, got %v", *results)
+	}
+}
@@ -1,5 +1,7 @@
 package aggregation
 
+import "strings"
+
 // AggregationType represents the type of aggregation function
 type AggregationType int
 
@@ -14,8 +16,26 @@ const (
 	Min
 	// Max represents MAX aggregation function
 	Max
+	// JSONGroupArray represents JSON_GROUP_ARRAY aggregation function
+	JSONGroupArray
 )
 
+// aggregateFunctions maps aggregation function names to their types
+var aggregateFunctions = map[string]AggregationType{
+	"COUNT":            Count,
+	"SUM":              Sum,
+	"AVG":              Avg,
+	"MIN":              Min,
+	"MAX":              Max,
+	"JSON_GROUP_ARRAY": JSONGroupArray,
+}
+
+// IsAggregateFunction checks if name refers to an aggregation function
+func IsAggregateFunction(name string) bool {
+	_, ok := aggregateFunctions[strings.ToUpper(name)]
+	return ok
+}
+
 // AggregationInfo represents an aggregation operation
 type AggregationInfo struct {
 	Type       AggregationType
@@ -0,0 +1,252 @@
+package functions
+
+import (
+	"fmt"
+
+	"github.com/blastrain/vitess-sqlparser/sqlparser"
+	"github.com/scrymastic/goosquery/sql/executor/operations"
+	"github.com/scrymastic/goosquery/sql/result"
+)
+
+// Evaluator computes the value of a compiled expression against a single row
+type Evaluator func(row result.Result) (interface{}, error)
+
+// CompiledExpr is an expression compiled once per query into a closure.
+// Constant is set when the expression does not depend on the row, in which
+// case Value holds its precomputed value.
+type CompiledExpr struct {
+	Eval     Evaluator
+	Constant bool
+	Value    interface{}
+}
+
+// Compile turns an expression into a closure. Literals are converted once,
+// function names are resolved once and subexpressions without column
+// references are folded into constants.
+func Compile(expr sqlparser.Expr) (*CompiledExpr, error) {
+	eval, constant, err := compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	if !constant {
+		return &CompiledExpr{Eval: eval}, nil
+	}
+	return constantExpr(eval)
+}
+
+// constantExpr evaluates a constant expression once and wraps its value
+func constantExpr(eval Evaluator) (*CompiledExpr, error) {
+	value, err := eval(result.Result{})
+	if err != nil {
+		return nil, err
+	}
+	return &CompiledExpr{
+		Eval:     func(result.Result) (interface{}, error) { return value, nil },
+		Constant: true,
+		Value:    value,
+	}, nil
+}
+
+// compile returns the closure of an expression and whether it is constant
+func compile(expr sqlparser.Expr) (Evaluator, bool, error) {
+	switch expr := expr.(type) {
+	case *sqlparser.ColName:
+		column := expr.Name.String()
+		return func(row result.Result) (interface{}, error) {
+			return row.Get(column), nil
+		}, false, nil
+	case *sqlparser.SQLVal:
+		return constant(operations.ExtractLiteralValue(expr)), true, nil
+	case *sqlparser.NullVal:
+		return constant(nil), true, nil
+	case sqlparser.BoolVal:
+		if expr {
+			return constant(int64(1)), true, nil
+		}
+		return constant(int64(0)), true, nil
+	case *sqlparser.ParenExpr:
+		return compile(expr.Expr)
+	case *sqlparser.ConvertExpr:
+		inner, isConstant, err := compile(expr.Expr)
+		if err != nil {
+			return nil, false, err
+		}
+		castType := expr.Type.Type
+		return func(row result.Result) (interface{}, error) {
+			value, err := inner(row)
+			if err != nil {
+				return nil, err
+			}
+			return evaluateCast(value, castType)
+		}, isConstant, nil
+	case *sqlparser.BinaryExpr:
+		left, leftConstant, err := compile(expr.Left)
+		if err != nil {
+			return nil, false, err
+		}
+		right, rightConstant, err := compile(expr.Right)
+		if err != nil {
+			return nil, false, err
+		}
+		operator := expr.Operator
+		return func(row result.Result) (interface{}, error) {
+			leftValue, err := left(row)
+			if err != nil {
+				return nil, err
+			}
+			rightValue, err := right(row)
+			if err != nil {
+				return nil, err
+			}
+			return evaluateArithmetic(operator, leftValue, rightValue)
+		}, leftConstant && rightConstant, nil
+	case *sqlparser.UnaryExpr:
+		inner, isConstant, err := compile(expr.Expr)
+		if err != nil {
+			return nil, false, err
+		}
+		operator := expr.Operator
+		return func(row result.Result) (interface{}, error) {
+			value, err := inner(row)
+			if err != nil {
+				return nil, err
+			}
+			return evaluateUnary(operator, value)
+		}, isConstant, nil
+	case *sqlparser.FuncExpr:
+		return compileFunction(expr)
+	case *sqlparser.CaseExpr:
+		return compileCase(expr)
+	case *sqlparser.ComparisonExpr, *sqlparser.IsExpr, *sqlparser.RangeCond,
+		*sqlparser.AndExpr, *sqlparser.OrExpr, *sqlparser.NotExpr:
+		return compileCondition(expr)
+	}
+	return nil, false, fmt.Errorf("unsupported expression: %s", sqlparser.String(expr))
+}
+
+// compileCondition compiles a condition selected as a value, such as
+// datetime('now') IS NOT NULL, like the WHERE clause and yields 1 or 0.
+// Conditions without column references are constant.
+func compileCondition(expr sqlparser.Expr) (Evaluator, bool, error) {
+	if conditionCompiler == nil {
+		return nil, false, fmt.Errorf("unsupported expression: %s", sqlparser.String(expr))
+	}
+	cond, err := conditionCompiler(expr)
+	if err != nil {
+		return nil, false, err
+	}
+
+	isConstant := true
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		if _, ok := node.(*sqlparser.ColName); ok {
+			isConstant = false
+		}
+		return isConstant, nil
+	}, expr)
+
+	return func(row result.Result) (interface{}, error) {
+		if cond(row) {
+			return int64(1), nil
+		}
+		return int64(0), nil
+	}, isConstant, nil
+}
+
+// compileFunction resolves a scalar function and compiles its arguments
+func compileFunction(expr *sqlparser.FuncExpr) (Evaluator, bool, error) {
+	fn, ok := scalarFunctions[expr.Name.Lowered()]
+	if !ok {
+		return nil, false, fmt.Errorf("unsupported function: %s", expr.Name.String())
+	}
+
+	isConstant := true
+	argEvals := make([]Evaluator, 0, len(expr.Exprs))
+	for _, arg := range expr.Exprs {
+		aliasedExpr, ok := arg.(*sqlparser.AliasedExpr)
+		if !ok {
+			return nil, false, fmt.Errorf("unsupported argument to %s: %s", expr.Name.String(), sqlparser.String(arg))
+		}
+		argEval, argConstant, err := compile(aliasedExpr.Expr)
+		if err != nil {
+			return nil, false, err
+		}
+		argEvals = append(argEvals, argEval)
+		isConstant = isConstant && argConstant
+	}
+
+	eval := func(row result.Result) (interface{}, error) {
+		args := make([]interface{}, len(argEvals))
+		for i, argEval := range argEvals {
+			value, err := argEval(row)
+			if err != nil {
+				return nil, err
+			}
+			args[i] = value
+		}
+		return fn(args)
+	}
+	if !isConstant {
+		return eval, false, nil
+	}
+
+	// Fold calls with constant arguments, such as glob_to_regexp('*.exe')
+	// or datetime('now'), which is then the same for every row as in SQLite
+	folded, err := constantExpr(eval)
+	if err != nil {
+		return nil, false, err
+	}
+	return folded.Eval, true, nil
+}
+
+// compileCase compiles CASE WHEN cond THEN value ... ELSE value END, and the
+// CASE operand WHEN value form comparing the operand to each value. Without
+// ELSE, a CASE with no matching WHEN is NULL.
+func compileCase(expr *sqlparser.CaseExpr) (Evaluator, bool, error) {
+	if conditionCompiler == nil {
+		return nil, false, fmt.Errorf("unsupported expression: %s", sqlparser.String(expr))
+	}
+
+	type branch struct {
+		cond  func(row result.Result) bool
+		value Evaluator
+	}
+	branches := make([]branch, 0, len(expr.Whens))
+	for _, when := range expr.Whens {
+		cond := when.Cond
+		if expr.Expr != nil {
+			cond = &sqlparser.ComparisonExpr{Operator: sqlparser.EqualStr, Left: expr.Expr, Right: when.Cond}
+		}
+		compiledCond, err := conditionCompiler(cond)
+		if err != nil {
+			return nil, false, err
+		}
+		value, _, err := compile(when.Val)
+		if err != nil {
+			return nil, false, err
+		}
+		branches = append(branches, branch{cond: compiledCond, value: value})
+	}
+	otherwise := constant(nil)
+	if expr.Else != nil {
+		var err error
+		if otherwise, _, err = compile(expr.Else); err != nil {
+			return nil, false, err
+		}
+	}
+
+	return func(row result.Result) (interface{}, error) {
+		for _, branch := range branches {
+			if branch.cond(row) {
+				return branch.value(row)
+			}
+		}
+		return otherwise(row)
+	}, false, nil
+}
+
+// constant returns an evaluator that always yields value
+func constant(value interface{}) Evaluator {
+	return func(result.Result) (interface{}, error) {
+		return value, nil
+	}
+}
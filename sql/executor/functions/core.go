@@ -0,0 +1,277 @@
+package functions
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/scrymastic/goosquery/sql/executor/operations"
+)
+
+// Core functions of SQLite that osquery queries commonly rely on
+func init() {
+	RegisterScalar("concat", concat)
+	RegisterScalar("ifnull", ifnull)
+	RegisterScalar("coalesce", coalesce)
+	RegisterScalar("nullif", nullif)
+	RegisterScalar("lower", lower)
+	RegisterScalar("upper", upper)
+	RegisterScalar("length", length)
+	RegisterScalar("substr", substr)
+	RegisterScalar("substring", substr)
+	RegisterScalar("replace", replace)
+	RegisterScalar("trim", func(args []interface{}) (interface{}, error) {
+		return trim("trim", args, strings.Trim)
+	})
+	RegisterScalar("ltrim", func(args []interface{}) (interface{}, error) {
+		return trim("ltrim", args, strings.TrimLeft)
+	})
+	RegisterScalar("rtrim", func(args []interface{}) (interface{}, error) {
+		return trim("rtrim", args, strings.TrimRight)
+	})
+	RegisterScalar("instr", instr)
+	RegisterScalar("abs", abs)
+	RegisterScalar("glob_to_regexp", globToRegexp)
+}
+
+// concat implements concat(a, b, ...) and the SQLite || operator:
+// the result is NULL if any argument is NULL
+func concat(args []interface{}) (interface{}, error) {
+	var sb strings.Builder
+	for _, arg := range args {
+		text, ok := argText(arg)
+		if !ok {
+			return nil, nil
+		}
+		sb.WriteString(text)
+	}
+	return sb.String(), nil
+}
+
+// ifnull implements ifnull(x, y)
+func ifnull(args []interface{}) (interface{}, error) {
+	if err := checkArgCount("ifnull", args, 2, 2); err != nil {
+		return nil, err
+	}
+	return coalesce(args)
+}
+
+// coalesce implements coalesce(x, y, ...), returning the first non-NULL argument
+func coalesce(args []interface{}) (interface{}, error) {
+	for _, arg := range args {
+		if arg != nil {
+			return arg, nil
+		}
+	}
+	return nil, nil
+}
+
+// nullif implements nullif(x, y)
+func nullif(args []interface{}) (interface{}, error) {
+	if err := checkArgCount("nullif", args, 2, 2); err != nil {
+		return nil, err
+	}
+	if args[0] != nil && args[1] != nil && operations.Compare(args[0], args[1]) == 0 {
+		return nil, nil
+	}
+	return args[0], nil
+}
+
+// lower implements lower(x)
+func lower(args []interface{}) (interface{}, error) {
+	return textFunc("lower", args, func(s string) (string, bool) {
+		return strings.ToLower(s), true
+	})
+}
+
+// upper implements upper(x)
+func upper(args []interface{}) (interface{}, error) {
+	return textFunc("upper", args, func(s string) (string, bool) {
+		return strings.ToUpper(s), true
+	})
+}
+
+// length implements length(x), counting characters rather than bytes
+func length(args []interface{}) (interface{}, error) {
+	if err := checkArgCount("length", args, 1, 1); err != nil {
+		return nil, err
+	}
+	text, ok := argText(args[0])
+	if !ok {
+		return nil, nil
+	}
+	return int64(utf8.RuneCountInString(text)), nil
+}
+
+// substr implements substr(x, start[, length]) with SQLite's 1-based and
+// negative start positions
+func substr(args []interface{}) (interface{}, error) {
+	if err := checkArgCount("substr", args, 2, 3); err != nil {
+		return nil, err
+	}
+	text, ok := argText(args[0])
+	start, startOK := argInt64(args[1])
+	if !ok || !startOK {
+		return nil, nil
+	}
+
+	runes := []rune(text)
+	count := int64(len(runes))
+	if len(args) == 3 {
+		if count, ok = argInt64(args[2]); !ok {
+			return nil, nil
+		}
+	}
+
+	// Convert to a 0-based half-open range [from, to)
+	var from int64
+	switch {
+	case start > 0:
+		from = start - 1
+	case start < 0:
+		from = int64(len(runes)) + start
+	default:
+		// Position 0 is just before the first character and consumes one of the count
+		from = 0
+		count--
+	}
+	to := from + count
+	if count < 0 {
+		from, to = from+count, from
+	}
+
+	from = max(from, 0)
+	to = min(to, int64(len(runes)))
+	if from >= to {
+		return "", nil
+	}
+	return string(runes[from:to]), nil
+}
+
+// replace implements replace(x, from, to)
+func replace(args []interface{}) (interface{}, error) {
+	if err := checkArgCount("replace", args, 3, 3); err != nil {
+		return nil, err
+	}
+	text, ok := argText(args[0])
+	from, fromOK := argText(args[1])
+	to, toOK := argText(args[2])
+	if !ok || !fromOK || !toOK {
+		return nil, nil
+	}
+	if from == "" {
+		return text, nil
+	}
+	return strings.ReplaceAll(text, from, to), nil
+}
+
+// trim implements trim(x[, chars]) and its left and right variants
+func trim(name string, args []interface{}, fn func(string, string) string) (interface{}, error) {
+	if err := checkArgCount(name, args, 1, 2); err != nil {
+		return nil, err
+	}
+	text, ok := argText(args[0])
+	if !ok {
+		return nil, nil
+	}
+	chars := " "
+	if len(args) == 2 {
+		if chars, ok = argText(args[1]); !ok {
+			return nil, nil
+		}
+	}
+	return fn(text, chars), nil
+}
+
+// instr implements instr(x, y), the 1-based character position of y in x or 0
+func instr(args []interface{}) (interface{}, error) {
+	if err := checkArgCount("instr", args, 2, 2); err != nil {
+		return nil, err
+	}
+	text, ok := argText(args[0])
+	needle, needleOK := argText(args[1])
+	if !ok || !needleOK {
+		return nil, nil
+	}
+	index := strings.Index(text, needle)
+	if index < 0 {
+		return int64(0), nil
+	}
+	return int64(utf8.RuneCountInString(text[:index]) + 1), nil
+}
+
+// abs implements abs(x)
+func abs(args []interface{}) (interface{}, error) {
+	if err := checkArgCount("abs", args, 1, 1); err != nil {
+		return nil, err
+	}
+	if args[0] == nil {
+		return nil, nil
+	}
+	if i, ok := args[0].(int64); ok {
+		if i < 0 {
+			return -i, nil
+		}
+		return i, nil
+	}
+	f, ok := operations.ToFloat64(args[0])
+	if !ok {
+		return float64(0), nil
+	}
+	if f == math.Trunc(f) && math.Abs(f) < math.MaxInt64 {
+		return int64(math.Abs(f)), nil
+	}
+	return math.Abs(f), nil
+}
+
+// globToRegexp implements glob_to_regexp(pattern), which translates a SQLite
+// GLOB pattern (*, ?, [...]) into an anchored regular expression. The parser
+// rewrites x GLOB pattern into x REGEXP glob_to_regexp(pattern).
+func globToRegexp(args []interface{}) (interface{}, error) {
+	if err := checkArgCount("glob_to_regexp", args, 1, 1); err != nil {
+		return nil, err
+	}
+	pattern, ok := argText(args[0])
+	if !ok {
+		return nil, nil
+	}
+	return GlobToRegexp(pattern), nil
+}
+
+// GlobToRegexp translates a GLOB pattern into an anchored regular expression
+func GlobToRegexp(pattern string) string {
+	var sb strings.Builder
+	sb.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*':
+			sb.WriteString("(?s:.*)")
+		case '?':
+			sb.WriteString("(?s:.)")
+		case '[':
+			end := strings.IndexByte(pattern[i+1:], ']')
+			if end == 0 && i+2 < len(pattern) {
+				// A leading ] is part of the set
+				end = strings.IndexByte(pattern[i+2:], ']') + 1
+			}
+			if end <= 0 {
+				sb.WriteString(`\[`)
+				continue
+			}
+			set := pattern[i+1 : i+1+end]
+			if strings.HasPrefix(set, "^") {
+				set = `\^` + set[1:]
+			} else if strings.HasPrefix(set, "!") {
+				set = "^" + set[1:]
+			}
+			fmt.Fprintf(&sb, "[%s]", strings.ReplaceAll(set, `\`, `\\`))
+			i += end + 1
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	sb.WriteString("$")
+	return sb.String()
+}
@@ -0,0 +1,96 @@
+package functions
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestCoreFunctions(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   ScalarFunction
+		args []interface{}
+		want interface{}
+	}{
+		{"concat", concat, []interface{}{"pid ", int64(4)}, "pid 4"},
+		{"concat null", concat, []interface{}{"a", nil}, nil},
+		{"ifnull", ifnull, []interface{}{nil, "-"}, "-"},
+		{"coalesce", coalesce, []interface{}{nil, nil, int64(3)}, int64(3)},
+		{"nullif", nullif, []interface{}{"", ""}, nil},
+		{"length", length, []interface{}{"héllo"}, int64(5)},
+		{"substr", substr, []interface{}{"C:\\Windows", int64(1), int64(2)}, "C:"},
+		{"substr negative", substr, []interface{}{"cmd.exe", int64(-3)}, "exe"},
+		{"substr zero", substr, []interface{}{"abc", int64(0), int64(2)}, "a"},
+		{"replace", replace, []interface{}{"a/b/c", "/", "\\"}, "a\\b\\c"},
+		{"trim", scalarFunctions["trim"], []interface{}{"  x  "}, "x"},
+		{"rtrim chars", scalarFunctions["rtrim"], []interface{}{"path\\", "\\"}, "path"},
+		{"instr", instr, []interface{}{"hello", "ll"}, int64(3)},
+		{"instr missing", instr, []interface{}{"hello", "z"}, int64(0)},
+		{"abs", abs, []interface{}{int64(-5)}, int64(5)},
+	}
+
+	for _, test := range tests {
+		got, err := test.fn(test.args)
+		if err != nil {
+			t.Errorf("%s returned error: %v", test.name, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("%s = %#v, want %#v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestGlobToRegexp(t *testing.T) {
+	tests := []struct {
+		pattern string
+		value   string
+		want    bool
+	}{
+		{"*.exe", "cmd.exe", true},
+		{"*.exe", "cmd.exe.bak", false},
+		{"*.exe", "CMD.EXE", false},
+		{"C:\\Windows\\*", "C:\\Windows\\notepad.exe", true},
+		{"file?.txt", "file1.txt", true},
+		{"[a-c]*", "bash", true},
+		{"[!a-c]*", "bash", false},
+		{"[^]x", "^x", true},
+	}
+
+	for _, test := range tests {
+		got := regexp.MustCompile(GlobToRegexp(test.pattern)).MatchString(test.value)
+		if got != test.want {
+			t.Errorf("%q GLOB %q = %v, want %v", test.value, test.pattern, got, test.want)
+		}
+	}
+}
+
+func TestCastAndArithmetic(t *testing.T) {
+	tests := []struct {
+		name string
+		got  func() (interface{}, error)
+		want interface{}
+	}{
+		{"cast integer prefix", func() (interface{}, error) { return evaluateCast("12abc", "signed") }, int64(12)},
+		{"cast integer text", func() (interface{}, error) { return evaluateCast("abc", "signed") }, int64(0)},
+		{"cast integer real", func() (interface{}, error) { return evaluateCast(3.7, "signed") }, int64(3)},
+		{"cast text", func() (interface{}, error) { return evaluateCast(int64(42), "char") }, "42"},
+		{"cast real", func() (interface{}, error) { return evaluateCast("2.5", "decimal") }, 2.5},
+		{"integer division", func() (interface{}, error) { return evaluateArithmetic("/", int64(7), int64(2)) }, int64(3)},
+		{"real division", func() (interface{}, error) { return evaluateArithmetic("/", 7.0, int64(2)) }, 3.5},
+		{"division by zero", func() (interface{}, error) { return evaluateArithmetic("/", int64(7), int64(0)) }, nil},
+		{"null operand", func() (interface{}, error) { return evaluateArithmetic("+", nil, int64(1)) }, nil},
+		{"negate", func() (interface{}, error) { return evaluateUnary("-", "5") }, int64(-5)},
+	}
+
+	for _, test := range tests {
+		got, err := test.got()
+		if err != nil {
+			t.Errorf("%s returned error: %v", test.name, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("%s = %#v, want %#v", test.name, got, test.want)
+		}
+	}
+}
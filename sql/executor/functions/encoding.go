@@ -0,0 +1,104 @@
+package functions
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"strings"
+	"unicode/utf16"
+)
+
+func init() {
+	RegisterScalar("hex", hexFunc)
+	RegisterScalar("unhex", unhexFunc)
+	RegisterScalar("to_base64", toBase64)
+	RegisterScalar("from_base64", fromBase64)
+	RegisterScalar("utf16le_decode", utf16leDecode)
+	RegisterScalar("rot13", rot13Func)
+}
+
+// textFunc adapts a string transformation into a single-argument scalar
+// function that returns NULL for NULL input
+func textFunc(name string, args []interface{}, fn func(string) (string, bool)) (interface{}, error) {
+	if err := checkArgCount(name, args, 1, 1); err != nil {
+		return nil, err
+	}
+
+	text, ok := argText(args[0])
+	if !ok {
+		return nil, nil
+	}
+	converted, ok := fn(text)
+	if !ok {
+		return nil, nil
+	}
+	return converted, nil
+}
+
+// hexFunc implements hex(x): the upper-case hex encoding of the bytes of x
+func hexFunc(args []interface{}) (interface{}, error) {
+	return textFunc("hex", args, func(s string) (string, bool) {
+		return strings.ToUpper(hex.EncodeToString([]byte(s))), true
+	})
+}
+
+// unhexFunc implements unhex(x), returning NULL when x is not valid hex
+func unhexFunc(args []interface{}) (interface{}, error) {
+	return textFunc("unhex", args, func(s string) (string, bool) {
+		decoded, err := hex.DecodeString(strings.TrimSpace(s))
+		return string(decoded), err == nil
+	})
+}
+
+// toBase64 implements to_base64(x)
+func toBase64(args []interface{}) (interface{}, error) {
+	return textFunc("to_base64", args, func(s string) (string, bool) {
+		return base64.StdEncoding.EncodeToString([]byte(s)), true
+	})
+}
+
+// fromBase64 implements from_base64(x), accepting padded and unpadded input
+// and returning NULL when x is not valid base64
+func fromBase64(args []interface{}) (interface{}, error) {
+	return textFunc("from_base64", args, func(s string) (string, bool) {
+		s = strings.Join(strings.Fields(s), "")
+		decoded, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			decoded, err = base64.RawStdEncoding.DecodeString(strings.TrimRight(s, "="))
+		}
+		return string(decoded), err == nil
+	})
+}
+
+// utf16leDecode implements utf16le_decode(x), decoding little-endian UTF-16
+// bytes such as REG_BINARY registry data. Combine with unhex() for hex input.
+// Decoding stops at the first NUL character.
+func utf16leDecode(args []interface{}) (interface{}, error) {
+	return textFunc("utf16le_decode", args, func(s string) (string, bool) {
+		data := []byte(s)
+		units := make([]uint16, 0, len(data)/2)
+		for i := 0; i+1 < len(data); i += 2 {
+			unit := binary.LittleEndian.Uint16(data[i:])
+			if unit == 0 {
+				break
+			}
+			units = append(units, unit)
+		}
+		return string(utf16.Decode(units)), true
+	})
+}
+
+// rot13Func implements rot13(x), as used to obscure UserAssist value names
+func rot13Func(args []interface{}) (interface{}, error) {
+	return textFunc("rot13", args, func(s string) (string, bool) {
+		return strings.Map(func(r rune) rune {
+			switch {
+			case r >= 'a' && r <= 'z':
+				return 'a' + (r-'a'+13)%26
+			case r >= 'A' && r <= 'Z':
+				return 'A' + (r-'A'+13)%26
+			}
+			return r
+		}, s), true
+	})
+}
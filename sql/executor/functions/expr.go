@@ -0,0 +1,183 @@
+package functions
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/blastrain/vitess-sqlparser/sqlparser"
+)
+
+// evaluateCast implements CAST(x AS type). The SQLite types INTEGER, TEXT and
+// REAL reach this point as the parser's signed, char and decimal types.
+func evaluateCast(value interface{}, castType string) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	switch strings.ToLower(castType) {
+	case "signed", "unsigned", "signed integer", "unsigned integer":
+		switch v := numericValue(value).(type) {
+		case int64:
+			return v, nil
+		case float64:
+			return int64(v), nil
+		}
+	case "char", "binary", "nchar":
+		text, _ := argText(value)
+		return text, nil
+	case "decimal":
+		switch v := numericValue(value).(type) {
+		case int64:
+			return float64(v), nil
+		case float64:
+			return v, nil
+		}
+	}
+	return nil, fmt.Errorf("unsupported cast type: %s", castType)
+}
+
+// evaluateArithmetic implements the binary arithmetic operators. As in SQLite,
+// integer operands give an integer result, division by zero gives NULL and any
+// NULL operand gives NULL.
+func evaluateArithmetic(operator string, left, right interface{}) (interface{}, error) {
+	if left == nil || right == nil {
+		return nil, nil
+	}
+	l, r := numericValue(left), numericValue(right)
+
+	li, lInt := l.(int64)
+	ri, rInt := r.(int64)
+	if lInt && rInt {
+		switch operator {
+		case sqlparser.PlusStr:
+			return li + ri, nil
+		case sqlparser.MinusStr:
+			return li - ri, nil
+		case sqlparser.MultStr:
+			return li * ri, nil
+		case sqlparser.DivStr:
+			if ri == 0 {
+				return nil, nil
+			}
+			return li / ri, nil
+		case sqlparser.ModStr:
+			if ri == 0 {
+				return nil, nil
+			}
+			return li % ri, nil
+		case sqlparser.BitAndStr:
+			return li & ri, nil
+		case sqlparser.BitOrStr:
+			return li | ri, nil
+		case sqlparser.ShiftLeftStr:
+			return li << uint64(ri), nil
+		case sqlparser.ShiftRightStr:
+			return li >> uint64(ri), nil
+		}
+		return nil, fmt.Errorf("unsupported operator: %s", operator)
+	}
+
+	lf, rf := toFloat(l), toFloat(r)
+	switch operator {
+	case sqlparser.PlusStr:
+		return lf + rf, nil
+	case sqlparser.MinusStr:
+		return lf - rf, nil
+	case sqlparser.MultStr:
+		return lf * rf, nil
+	case sqlparser.DivStr:
+		if rf == 0 {
+			return nil, nil
+		}
+		return lf / rf, nil
+	case sqlparser.ModStr:
+		if int64(rf) == 0 {
+			return nil, nil
+		}
+		return float64(int64(lf) % int64(rf)), nil
+	}
+	return nil, fmt.Errorf("unsupported operator: %s", operator)
+}
+
+// evaluateUnary implements unary minus and plus
+func evaluateUnary(operator string, value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	switch operator {
+	case sqlparser.UPlusStr:
+		return numericValue(value), nil
+	case sqlparser.UMinusStr:
+		switch v := numericValue(value).(type) {
+		case int64:
+			return -v, nil
+		case float64:
+			return -v, nil
+		}
+	}
+	return nil, fmt.Errorf("unsupported operator: %s", operator)
+}
+
+// numericValue converts a value to int64 or float64. Text is converted by its
+// longest numeric prefix as SQLite does, so '12abc' is 12 and 'abc' is 0.
+func numericValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case float64:
+		return v
+	case float32:
+		return float64(v)
+	case string, []byte:
+	default:
+		if i, ok := argInt64(value); ok {
+			return i
+		}
+	}
+
+	text, _ := argText(value)
+	text = strings.TrimSpace(text)
+	if i, err := strconv.ParseInt(text, 10, 64); err == nil {
+		return i
+	}
+	if f, err := strconv.ParseFloat(text, 64); err == nil {
+		return f
+	}
+
+	// Longest numeric prefix
+	end := 0
+	seenDigit, seenDot := false, false
+scan:
+	for ; end < len(text); end++ {
+		c := text[end]
+		switch {
+		case c >= '0' && c <= '9':
+			seenDigit = true
+		case c == '.' && !seenDot:
+			seenDot = true
+		case (c == '-' || c == '+') && end == 0:
+		default:
+			break scan
+		}
+	}
+	if !seenDigit {
+		return int64(0)
+	}
+	prefix := text[:end]
+	if i, err := strconv.ParseInt(prefix, 10, 64); err == nil {
+		return i
+	}
+	if f, err := strconv.ParseFloat(prefix, 64); err == nil {
+		return f
+	}
+	return int64(0)
+}
+
+// toFloat converts the result of numericValue to float64
+func toFloat(value interface{}) float64 {
+	switch v := value.(type) {
+	case int64:
+		return float64(v)
+	case float64:
+		return v
+	}
+	return 0
+}
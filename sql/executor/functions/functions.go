@@ -0,0 +1,177 @@
+package functions
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/blastrain/vitess-sqlparser/sqlparser"
+	"github.com/scrymastic/goosquery/sql/executor/operations"
+	"github.com/scrymastic/goosquery/sql/result"
+)
+
+// ScalarFunction computes the value of a scalar SQL function from its evaluated arguments
+type ScalarFunction func(args []interface{}) (interface{}, error)
+
+// TableFunction expands its evaluated arguments into a set of rows
+type TableFunction func(args []interface{}) (*result.Results, error)
+
+// ConditionCompiler compiles a boolean condition, such as the WHEN of a CASE
+// or a comparison in the SELECT list
+type ConditionCompiler func(expr sqlparser.Expr) (func(row result.Result) bool, error)
+
+var scalarFunctions = map[string]ScalarFunction{}
+var tableFunctions = map[string]TableFunction{}
+var conditionCompiler ConditionCompiler
+
+// RegisterScalar makes a scalar function available to queries under the given name
+func RegisterScalar(name string, fn ScalarFunction) {
+	scalarFunctions[strings.ToLower(name)] = fn
+}
+
+// RegisterTable makes a table-valued function available to queries under the given name
+func RegisterTable(name string, fn TableFunction) {
+	tableFunctions[strings.ToLower(name)] = fn
+}
+
+// RegisterConditionCompiler sets the compiler of the conditions of CASE
+// expressions and of the SELECT list, which are compiled like WHERE clauses
+func RegisterConditionCompiler(compiler ConditionCompiler) {
+	conditionCompiler = compiler
+}
+
+// IsScalar checks if name refers to a registered scalar function
+func IsScalar(name string) bool {
+	_, ok := scalarFunctions[strings.ToLower(name)]
+	return ok
+}
+
+// Evaluate computes the value of an expression against a single row.
+// Expressions evaluated against many rows should be compiled once instead.
+func Evaluate(row result.Result, expr sqlparser.Expr) (interface{}, error) {
+	compiled, err := Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	return compiled.Eval(row)
+}
+
+// ApplyTableFunction joins every row with the rows produced by a table-valued
+// function whose arguments are evaluated against that row. Columns of the
+// original row take precedence over function columns of the same name.
+func ApplyTableFunction(rows *result.Results, name string, argExprs []sqlparser.Expr) (*result.Results, error) {
+	fn, ok := tableFunctions[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("unsupported table-valued function: %s", name)
+	}
+
+	argEvals := make([]*CompiledExpr, 0, len(argExprs))
+	for _, argExpr := range argExprs {
+		argEval, err := Compile(argExpr)
+		if err != nil {
+			return nil, err
+		}
+		argEvals = append(argEvals, argEval)
+	}
+
+	joined := result.NewQueryResult()
+	index := result.NewColumnIndex()
+	for _, row := range *rows {
+		args := make([]interface{}, 0, len(argExprs))
+		for _, argEval := range argEvals {
+			value, err := argEval.Eval(row)
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, value)
+		}
+
+		generated, err := fn(args)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+
+		for _, generatedRow := range *generated {
+			joinedRow := index.NewRow()
+			row.Range(func(k string, v interface{}) bool {
+				joinedRow.Add(k, v)
+				return true
+			})
+			generatedRow.Range(func(k string, v interface{}) bool {
+				if _, exists := row.Lookup(k); !exists {
+					joinedRow.Add(k, v)
+				}
+				return true
+			})
+			joined.AppendResult(joinedRow)
+		}
+	}
+	return joined, nil
+}
+
+// IsTruthy reports whether a value counts as true in a boolean context
+func IsTruthy(value interface{}) bool {
+	if value == nil {
+		return false
+	}
+	if f, ok := operations.ToFloat64(value); ok {
+		return f != 0
+	}
+	return false
+}
+
+// argText converts a function argument to text. A NULL argument yields ok == false.
+func argText(arg interface{}) (string, bool) {
+	switch v := arg.(type) {
+	case nil:
+		return "", false
+	case string:
+		return v, true
+	case []byte:
+		return string(v), true
+	}
+	return fmt.Sprintf("%v", arg), true
+}
+
+// argInt64 converts a function argument to an integer without going through
+// float64, so that large values such as FILETIMEs keep full precision
+func argInt64(arg interface{}) (int64, bool) {
+	switch v := arg.(type) {
+	case int:
+		return int64(v), true
+	case int8:
+		return int64(v), true
+	case int16:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case int64:
+		return v, true
+	case uint:
+		return int64(v), true
+	case uint8:
+		return int64(v), true
+	case uint16:
+		return int64(v), true
+	case uint32:
+		return int64(v), true
+	case uint64:
+		return int64(v), true
+	case string:
+		if i, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64); err == nil {
+			return i, true
+		}
+	}
+	if f, ok := operations.ToFloat64(arg); ok {
+		return int64(f), true
+	}
+	return 0, false
+}
+
+// checkArgCount validates the number of arguments passed to a function
+func checkArgCount(name string, args []interface{}, min, max int) error {
+	if len(args) < min || max >= 0 && len(args) > max {
+		return fmt.Errorf("wrong number of arguments to function %s()", name)
+	}
+	return nil
+}
@@ -0,0 +1,455 @@
+package functions
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// jsonMember is a single key/value pair of a JSON object
+type jsonMember struct {
+	Key   string
+	Value interface{}
+}
+
+// jsonObject is a decoded JSON object whose members are kept in document order,
+// so that json_each and re-encoded values match the original text
+type jsonObject []jsonMember
+
+func init() {
+	RegisterScalar("json_extract", jsonExtract)
+	RegisterScalar("json_array_length", jsonArrayLength)
+	RegisterScalar("json_type", jsonType)
+	RegisterScalar("json_valid", jsonValid)
+	RegisterScalar("json_object", jsonObjectFunc)
+}
+
+// parseJSON decodes JSON text into nil, bool, json.Number, string,
+// []interface{} or jsonObject values
+func parseJSON(text string) (interface{}, error) {
+	dec := json.NewDecoder(strings.NewReader(text))
+	dec.UseNumber()
+
+	value, err := decodeJSONValue(dec)
+	if err != nil {
+		return nil, fmt.Errorf("malformed JSON")
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("malformed JSON")
+	}
+	return value, nil
+}
+
+func decodeJSONValue(dec *json.Decoder) (interface{}, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	delim, ok := tok.(json.Delim)
+	if !ok {
+		return tok, nil
+	}
+
+	switch delim {
+	case '[':
+		array := []interface{}{}
+		for dec.More() {
+			value, err := decodeJSONValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			array = append(array, value)
+		}
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+		return array, nil
+	case '{':
+		object := jsonObject{}
+		for dec.More() {
+			keyTok, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			key, ok := keyTok.(string)
+			if !ok {
+				return nil, fmt.Errorf("object key is not a string")
+			}
+			value, err := decodeJSONValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			object = append(object, jsonMember{Key: key, Value: value})
+		}
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+		return object, nil
+	}
+	return nil, fmt.Errorf("unexpected delimiter %v", delim)
+}
+
+// encodeJSON renders a decoded JSON value as minified JSON text
+func encodeJSON(value interface{}) string {
+	var buf bytes.Buffer
+	writeJSON(&buf, value)
+	return buf.String()
+}
+
+func writeJSON(buf *bytes.Buffer, value interface{}) {
+	switch v := value.(type) {
+	case nil:
+		buf.WriteString("null")
+	case bool:
+		buf.WriteString(strconv.FormatBool(v))
+	case json.Number:
+		buf.WriteString(v.String())
+	case string:
+		buf.WriteString(quoteJSONString(v))
+	case []interface{}:
+		buf.WriteByte('[')
+		for i, elem := range v {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			writeJSON(buf, elem)
+		}
+		buf.WriteByte(']')
+	case jsonObject:
+		buf.WriteByte('{')
+		for i, member := range v {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			buf.WriteString(quoteJSONString(member.Key))
+			buf.WriteByte(':')
+			writeJSON(buf, member.Value)
+		}
+		buf.WriteByte('}')
+	default:
+		// SQL values stored in rows (ints, floats) from json_object and friends
+		writeJSON(buf, sqlToJSON(v))
+	}
+}
+
+// quoteJSONString quotes a string without the HTML escaping done by json.Marshal
+func quoteJSONString(s string) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// sqlToJSON converts a SQL value from a row into a JSON value
+func sqlToJSON(value interface{}) interface{} {
+	switch v := value.(type) {
+	case nil, string, bool, json.Number, []interface{}, jsonObject:
+		return v
+	case float32:
+		return json.Number(strconv.FormatFloat(float64(v), 'g', -1, 64))
+	case float64:
+		return json.Number(strconv.FormatFloat(v, 'g', -1, 64))
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return json.Number(fmt.Sprintf("%d", v))
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// jsonToSQL converts a JSON value into the SQL value returned to queries:
+// containers become JSON text, booleans become 1 or 0
+func jsonToSQL(value interface{}) interface{} {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case bool:
+		if v {
+			return int64(1)
+		}
+		return int64(0)
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+		return v.String()
+	case string:
+		return v
+	}
+	return encodeJSON(value)
+}
+
+// jsonTypeName returns the SQLite json_type() name of a JSON value
+func jsonTypeName(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		if v {
+			return "true"
+		}
+		return "false"
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			return "integer"
+		}
+		return "real"
+	case string:
+		return "text"
+	case []interface{}:
+		return "array"
+	case jsonObject:
+		return "object"
+	}
+	return "null"
+}
+
+// jsonArg decodes a JSON function argument. A NULL argument yields ok == false.
+func jsonArg(arg interface{}) (value interface{}, ok bool, err error) {
+	if arg == nil {
+		return nil, false, nil
+	}
+	text, isString := arg.(string)
+	if !isString {
+		text = fmt.Sprintf("%v", arg)
+	}
+	value, err = parseJSON(text)
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+// jsonPathStep is one component of a JSON path: an object key or an array index
+type jsonPathStep struct {
+	key     string
+	index   int
+	isIndex bool
+	fromEnd bool
+}
+
+// parseJSONPath parses a path such as $.permissions[0] or $."key.with.dots"[#-1]
+func parseJSONPath(path string) ([]jsonPathStep, error) {
+	if !strings.HasPrefix(path, "$") {
+		return nil, fmt.Errorf("JSON path error near '%s'", path)
+	}
+
+	var steps []jsonPathStep
+	rest := path[1:]
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			if strings.HasPrefix(rest, `"`) {
+				end := strings.IndexByte(rest[1:], '"')
+				if end < 0 {
+					return nil, fmt.Errorf("JSON path error near '%s'", rest)
+				}
+				steps = append(steps, jsonPathStep{key: rest[1 : end+1]})
+				rest = rest[end+2:]
+				continue
+			}
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("JSON path error near '%s'", rest)
+			}
+			steps = append(steps, jsonPathStep{key: rest[:end]})
+			rest = rest[end:]
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("JSON path error near '%s'", rest)
+			}
+			step := jsonPathStep{isIndex: true}
+			index := rest[1:end]
+			if strings.HasPrefix(index, "#-") {
+				step.fromEnd = true
+				index = index[2:]
+			}
+			n, err := strconv.Atoi(index)
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("JSON path error near '%s'", rest)
+			}
+			step.index = n
+			steps = append(steps, step)
+			rest = rest[end+1:]
+		default:
+			return nil, fmt.Errorf("JSON path error near '%s'", rest)
+		}
+	}
+	return steps, nil
+}
+
+// lookupJSONPath follows a parsed path, reporting whether it exists
+func lookupJSONPath(value interface{}, steps []jsonPathStep) (interface{}, bool) {
+	for _, step := range steps {
+		if step.isIndex {
+			array, ok := value.([]interface{})
+			if !ok {
+				return nil, false
+			}
+			index := step.index
+			if step.fromEnd {
+				index = len(array) - step.index
+			}
+			if index < 0 || index >= len(array) {
+				return nil, false
+			}
+			value = array[index]
+			continue
+		}
+
+		object, ok := value.(jsonObject)
+		if !ok {
+			return nil, false
+		}
+		found := false
+		for _, member := range object {
+			if member.Key == step.key {
+				value = member.Value
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// jsonAtPath decodes a JSON argument and resolves an optional path argument
+func jsonAtPath(jsonArgValue interface{}, pathArg interface{}) (interface{}, bool, error) {
+	value, ok, err := jsonArg(jsonArgValue)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+	if pathArg == nil {
+		return value, true, nil
+	}
+
+	steps, err := parseJSONPath(fmt.Sprintf("%v", pathArg))
+	if err != nil {
+		return nil, false, err
+	}
+	value, ok = lookupJSONPath(value, steps)
+	return value, ok, nil
+}
+
+// jsonExtract implements json_extract(json, path, ...). With several paths
+// the results are returned as a JSON array.
+func jsonExtract(args []interface{}) (interface{}, error) {
+	if err := checkArgCount("json_extract", args, 2, -1); err != nil {
+		return nil, err
+	}
+
+	if len(args) == 2 {
+		value, ok, err := jsonAtPath(args[0], args[1])
+		if err != nil || !ok {
+			return nil, err
+		}
+		return jsonToSQL(value), nil
+	}
+
+	values := make([]interface{}, 0, len(args)-1)
+	for _, path := range args[1:] {
+		value, ok, err := jsonAtPath(args[0], path)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			value = nil
+		}
+		values = append(values, value)
+	}
+	if args[0] == nil {
+		return nil, nil
+	}
+	return encodeJSON(values), nil
+}
+
+// jsonArrayLength implements json_array_length(json[, path])
+func jsonArrayLength(args []interface{}) (interface{}, error) {
+	if err := checkArgCount("json_array_length", args, 1, 2); err != nil {
+		return nil, err
+	}
+
+	var path interface{}
+	if len(args) == 2 {
+		path = args[1]
+	}
+	value, ok, err := jsonAtPath(args[0], path)
+	if err != nil || !ok {
+		return nil, err
+	}
+	if array, isArray := value.([]interface{}); isArray {
+		return int64(len(array)), nil
+	}
+	return int64(0), nil
+}
+
+// jsonType implements json_type(json[, path])
+func jsonType(args []interface{}) (interface{}, error) {
+	if err := checkArgCount("json_type", args, 1, 2); err != nil {
+		return nil, err
+	}
+
+	var path interface{}
+	if len(args) == 2 {
+		path = args[1]
+	}
+	value, ok, err := jsonAtPath(args[0], path)
+	if err != nil || !ok {
+		return nil, err
+	}
+	return jsonTypeName(value), nil
+}
+
+// jsonValid implements json_valid(json)
+func jsonValid(args []interface{}) (interface{}, error) {
+	if err := checkArgCount("json_valid", args, 1, 1); err != nil {
+		return nil, err
+	}
+	if args[0] == nil {
+		return nil, nil
+	}
+	if _, _, err := jsonArg(args[0]); err != nil {
+		return int64(0), nil
+	}
+	return int64(1), nil
+}
+
+// jsonObjectFunc implements json_object(label1, value1, ...)
+func jsonObjectFunc(args []interface{}) (interface{}, error) {
+	if len(args)%2 != 0 {
+		return nil, fmt.Errorf("json_object() requires an even number of arguments")
+	}
+
+	object := make(jsonObject, 0, len(args)/2)
+	for i := 0; i < len(args); i += 2 {
+		key, ok := args[i].(string)
+		if !ok {
+			return nil, fmt.Errorf("json_object() labels must be TEXT")
+		}
+		object = append(object, jsonMember{Key: key, Value: sqlToJSON(args[i+1])})
+	}
+	return encodeJSON(object), nil
+}
+
+// EncodeJSONArray renders SQL values as a JSON array, as json_group_array does
+func EncodeJSONArray(values []interface{}) string {
+	array := make([]interface{}, 0, len(values))
+	for _, value := range values {
+		array = append(array, sqlToJSON(value))
+	}
+	return encodeJSON(array)
+}
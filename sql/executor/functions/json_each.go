@@ -0,0 +1,122 @@
+package functions
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/scrymastic/goosquery/sql/result"
+)
+
+func init() {
+	RegisterTable("json_each", func(args []interface{}) (*result.Results, error) {
+		return jsonWalk("json_each", args, false)
+	})
+	RegisterTable("json_tree", func(args []interface{}) (*result.Results, error) {
+		return jsonWalk("json_tree", args, true)
+	})
+}
+
+// jsonWalker accumulates the rows produced by json_each and json_tree
+type jsonWalker struct {
+	rows   *result.Results
+	index  *result.ColumnIndex
+	nextID int64
+}
+
+// jsonEachColumns are the columns of json_each and json_tree, in SQLite order
+var jsonEachColumns = []string{"key", "value", "type", "atom", "id", "parent", "fullkey", "path"}
+
+// jsonWalk implements json_each(json[, path]) and json_tree(json[, path]).
+// json_each returns the immediate children of the top-level element;
+// json_tree returns the element itself and every descendant.
+func jsonWalk(name string, args []interface{}, recursive bool) (*result.Results, error) {
+	if err := checkArgCount(name, args, 1, 2); err != nil {
+		return nil, err
+	}
+
+	root := "$"
+	var path interface{}
+	if len(args) == 2 && args[1] != nil {
+		path = args[1]
+		root = fmt.Sprintf("%v", path)
+	}
+
+	walker := &jsonWalker{rows: result.NewQueryResult(), index: result.NewColumnIndex(jsonEachColumns...)}
+	value, ok, err := jsonAtPath(args[0], path)
+	if err != nil || !ok {
+		return walker.rows, err
+	}
+
+	if recursive {
+		walker.walk(nil, value, root, root, nil)
+		return walker.rows, nil
+	}
+
+	switch container := value.(type) {
+	case []interface{}:
+		for i, elem := range container {
+			walker.add(int64(i), elem, fmt.Sprintf("%s[%d]", root, i), root, nil)
+		}
+	case jsonObject:
+		for _, member := range container {
+			walker.add(member.Key, member.Value, root+"."+jsonPathKey(member.Key), root, nil)
+		}
+	default:
+		walker.add(nil, value, root, root, nil)
+	}
+	return walker.rows, nil
+}
+
+// walk emits a row for value and, recursively, for all of its children
+func (w *jsonWalker) walk(key interface{}, value interface{}, fullkey, path string, parent interface{}) {
+	id := w.add(key, value, fullkey, path, parent)
+
+	switch container := value.(type) {
+	case []interface{}:
+		for i, elem := range container {
+			w.walk(int64(i), elem, fmt.Sprintf("%s[%d]", fullkey, i), fullkey, id)
+		}
+	case jsonObject:
+		for _, member := range container {
+			w.walk(member.Key, member.Value, fullkey+"."+jsonPathKey(member.Key), fullkey, id)
+		}
+	}
+}
+
+// add appends one row with the SQLite json_each column set and returns its id
+func (w *jsonWalker) add(key interface{}, value interface{}, fullkey, path string, parent interface{}) int64 {
+	id := w.nextID
+	w.nextID++
+
+	var atom interface{}
+	switch value.(type) {
+	case []interface{}, jsonObject:
+	default:
+		atom = jsonToSQL(value)
+	}
+
+	row := w.index.NewRow()
+	row.Add("key", key)
+	row.Add("value", jsonToSQL(value))
+	row.Add("type", jsonTypeName(value))
+	row.Add("atom", atom)
+	row.Add("id", id)
+	row.Add("parent", parent)
+	row.Add("fullkey", fullkey)
+	row.Add("path", path)
+	w.rows.AppendResult(row)
+	return id
+}
+
+// jsonPathKey quotes an object key for use in a path when it is not a plain label
+func jsonPathKey(key string) string {
+	for _, c := range key {
+		if !(c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9') {
+			return strconv.Quote(key)
+		}
+	}
+	if key == "" {
+		return `""`
+	}
+	return key
+}
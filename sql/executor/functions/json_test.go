@@ -0,0 +1,113 @@
+package functions
+
+import (
+	"testing"
+)
+
+func TestJSONExtract(t *testing.T) {
+	doc := `{"name":"Alpha","permissions":["storage","webRequest"],"background":{"persistent":false},"a.b":1.5}`
+	tests := []struct {
+		path string
+		want interface{}
+	}{
+		{"$.name", "Alpha"},
+		{"$.permissions[1]", "webRequest"},
+		{"$.permissions[#-1]", "webRequest"},
+		{"$.permissions", `["storage","webRequest"]`},
+		{"$.background.persistent", int64(0)},
+		{`$."a.b"`, 1.5},
+		{"$.missing", nil},
+	}
+
+	for _, test := range tests {
+		got, err := jsonExtract([]interface{}{doc, test.path})
+		if err != nil {
+			t.Errorf("json_extract(%s) returned error: %v", test.path, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("json_extract(%s) = %#v, want %#v", test.path, got, test.want)
+		}
+	}
+
+	got, err := jsonExtract([]interface{}{doc, "$.name", "$.missing"})
+	if err != nil || got != `["Alpha",null]` {
+		t.Errorf("json_extract with two paths = %v, %v", got, err)
+	}
+
+	if _, err := jsonExtract([]interface{}{"{not json", "$"}); err == nil {
+		t.Error("json_extract on malformed JSON should fail")
+	}
+}
+
+func TestJSONTypeAndValid(t *testing.T) {
+	types := map[string]string{
+		`null`: "null", `true`: "true", `1`: "integer", `1.5`: "real",
+		`"x"`: "text", `[1]`: "array", `{"a":1}`: "object",
+	}
+	for doc, want := range types {
+		got, err := jsonType([]interface{}{doc})
+		if err != nil || got != want {
+			t.Errorf("json_type(%s) = %v, %v; want %s", doc, got, err, want)
+		}
+	}
+
+	for doc, want := range map[string]int64{`{"a":[1,2]}`: 1, `{"a":`: 0, `[1] [2]`: 0} {
+		got, _ := jsonValid([]interface{}{doc})
+		if got != want {
+			t.Errorf("json_valid(%s) = %v, want %d", doc, got, want)
+		}
+	}
+
+	length, err := jsonArrayLength([]interface{}{`{"a":[1,2,3]}`, "$.a"})
+	if err != nil || length != int64(3) {
+		t.Errorf("json_array_length = %v, %v; want 3", length, err)
+	}
+}
+
+func TestJSONObjectKeepsOrder(t *testing.T) {
+	got, err := jsonObjectFunc([]interface{}{"z", int32(1), "a", "<b>", "m", nil})
+	if err != nil {
+		t.Fatalf("json_object returned error: %v", err)
+	}
+	if want := `{"z":1,"a":"<b>","m":null}`; got != want {
+		t.Errorf("json_object = %s, want %s", got, want)
+	}
+
+	if _, err := jsonObjectFunc([]interface{}{"a"}); err == nil {
+		t.Error("json_object with an odd number of arguments should fail")
+	}
+}
+
+func TestJSONEachAndTree(t *testing.T) {
+	rows, err := jsonWalk("json_each", []interface{}{`{"b":1,"a":[true,null]}`}, false)
+	if err != nil {
+		t.Fatalf("json_each returned error: %v", err)
+	}
+	if rows.Size() != 2 {
+		t.Fatalf("json_each returned %d rows, want 2", rows.Size())
+	}
+	first, second := (*rows)[0], (*rows)[1]
+	if first.Get("key") != "b" || first.Get("value") != int64(1) || first.Get("type") != "integer" || first.Get("fullkey") != "$.b" {
+		t.Errorf("unexpected first json_each row: %v", first)
+	}
+	if second.Get("value") != "[true,null]" || second.Get("atom") != nil || second.Get("path") != "$" {
+		t.Errorf("unexpected second json_each row: %v", second)
+	}
+
+	rows, err = jsonWalk("json_tree", []interface{}{`{"a":[true,null]}`, "$.a"}, true)
+	if err != nil {
+		t.Fatalf("json_tree returned error: %v", err)
+	}
+	if rows.Size() != 3 {
+		t.Fatalf("json_tree returned %d rows, want 3", rows.Size())
+	}
+	if leaf := (*rows)[2]; leaf.Get("fullkey") != "$.a[1]" || leaf.Get("parent") != (*rows)[0].Get("id") || leaf.Get("type") != "null" {
+		t.Errorf("unexpected json_tree leaf row: %v", leaf)
+	}
+
+	rows, err = jsonWalk("json_each", []interface{}{nil}, false)
+	if err != nil || rows.Size() != 0 {
+		t.Errorf("json_each(NULL) = %v, %v; want no rows", rows, err)
+	}
+}
@@ -0,0 +1,184 @@
+package functions
+
+import (
+	"math/big"
+	"net/netip"
+	"strings"
+)
+
+func init() {
+	RegisterScalar("ip_in_cidr", ipInCIDR)
+	RegisterScalar("cidr_contains", cidrContains)
+	RegisterScalar("is_private_ip", isPrivateIP)
+	RegisterScalar("is_loopback", isLoopback)
+	RegisterScalar("ip_version", ipVersion)
+	RegisterScalar("ip_to_int", ipToInt)
+	RegisterScalar("ip_normalize", ipNormalize)
+}
+
+// parseIPAddress parses an address as it appears in the network tables.
+// Brackets, IPv6 zone IDs (fe80::1%eth0) and the IPv4-mapped form
+// (::ffff:10.0.0.1) are normalized away so that comparisons against IPv4
+// networks behave as expected.
+func parseIPAddress(arg interface{}) (netip.Addr, bool) {
+	text, ok := argText(arg)
+	if !ok {
+		return netip.Addr{}, false
+	}
+	text = strings.TrimSpace(text)
+	text = strings.TrimSuffix(strings.TrimPrefix(text, "["), "]")
+
+	addr, err := netip.ParseAddr(text)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return addr.WithZone("").Unmap(), true
+}
+
+// parseIPPrefix parses a CIDR such as 10.0.0.0/8. A bare address is treated
+// as a single-host network.
+func parseIPPrefix(arg interface{}) (netip.Prefix, bool) {
+	text, ok := argText(arg)
+	if !ok {
+		return netip.Prefix{}, false
+	}
+	text = strings.TrimSpace(text)
+
+	if !strings.Contains(text, "/") {
+		addr, ok := parseIPAddress(text)
+		if !ok {
+			return netip.Prefix{}, false
+		}
+		return netip.PrefixFrom(addr, addr.BitLen()), true
+	}
+
+	prefix, err := netip.ParsePrefix(text)
+	if err != nil {
+		return netip.Prefix{}, false
+	}
+	addr := prefix.Addr()
+	bits := prefix.Bits()
+	if addr.Is4In6() && bits >= 96 {
+		addr, bits = addr.Unmap(), bits-96
+	}
+	return netip.PrefixFrom(addr, bits).Masked(), true
+}
+
+// boolResult converts a Go boolean into a SQL integer
+func boolResult(b bool) interface{} {
+	if b {
+		return int64(1)
+	}
+	return int64(0)
+}
+
+// ipInCIDR implements ip_in_cidr(address, cidr)
+func ipInCIDR(args []interface{}) (interface{}, error) {
+	if err := checkArgCount("ip_in_cidr", args, 2, 2); err != nil {
+		return nil, err
+	}
+
+	addr, ok := parseIPAddress(args[0])
+	if !ok {
+		return nil, nil
+	}
+	prefix, ok := parseIPPrefix(args[1])
+	if !ok {
+		return nil, nil
+	}
+	return boolResult(prefix.Contains(addr)), nil
+}
+
+// cidrContains implements cidr_contains(outer, inner), where inner may be a
+// network or a single address
+func cidrContains(args []interface{}) (interface{}, error) {
+	if err := checkArgCount("cidr_contains", args, 2, 2); err != nil {
+		return nil, err
+	}
+
+	outer, ok := parseIPPrefix(args[0])
+	if !ok {
+		return nil, nil
+	}
+	inner, ok := parseIPPrefix(args[1])
+	if !ok {
+		return nil, nil
+	}
+	return boolResult(outer.Bits() <= inner.Bits() && outer.Contains(inner.Addr())), nil
+}
+
+// isPrivateIP implements is_private_ip(address): RFC 1918 IPv4 networks and
+// RFC 4193 unique local IPv6 addresses
+func isPrivateIP(args []interface{}) (interface{}, error) {
+	if err := checkArgCount("is_private_ip", args, 1, 1); err != nil {
+		return nil, err
+	}
+
+	addr, ok := parseIPAddress(args[0])
+	if !ok {
+		return nil, nil
+	}
+	return boolResult(addr.IsPrivate()), nil
+}
+
+// isLoopback implements is_loopback(address)
+func isLoopback(args []interface{}) (interface{}, error) {
+	if err := checkArgCount("is_loopback", args, 1, 1); err != nil {
+		return nil, err
+	}
+
+	addr, ok := parseIPAddress(args[0])
+	if !ok {
+		return nil, nil
+	}
+	return boolResult(addr.IsLoopback()), nil
+}
+
+// ipVersion implements ip_version(address), returning 4, 6 or NULL
+func ipVersion(args []interface{}) (interface{}, error) {
+	if err := checkArgCount("ip_version", args, 1, 1); err != nil {
+		return nil, err
+	}
+
+	addr, ok := parseIPAddress(args[0])
+	if !ok {
+		return nil, nil
+	}
+	if addr.Is4() {
+		return int64(4), nil
+	}
+	return int64(6), nil
+}
+
+// ipToInt implements ip_to_int(address). IPv4 addresses become integers;
+// IPv6 addresses do not fit in 64 bits and are returned as decimal text.
+func ipToInt(args []interface{}) (interface{}, error) {
+	if err := checkArgCount("ip_to_int", args, 1, 1); err != nil {
+		return nil, err
+	}
+
+	addr, ok := parseIPAddress(args[0])
+	if !ok {
+		return nil, nil
+	}
+	if addr.Is4() {
+		b := addr.As4()
+		return int64(b[0])<<24 | int64(b[1])<<16 | int64(b[2])<<8 | int64(b[3]), nil
+	}
+	b := addr.As16()
+	return new(big.Int).SetBytes(b[:]).String(), nil
+}
+
+// ipNormalize implements ip_normalize(address), returning the canonical text
+// form used by the network tables
+func ipNormalize(args []interface{}) (interface{}, error) {
+	if err := checkArgCount("ip_normalize", args, 1, 1); err != nil {
+		return nil, err
+	}
+
+	addr, ok := parseIPAddress(args[0])
+	if !ok {
+		return nil, nil
+	}
+	return addr.String(), nil
+}
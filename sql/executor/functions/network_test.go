@@ -0,0 +1,47 @@
+package functions
+
+import (
+	"testing"
+)
+
+func TestNetworkFunctions(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   ScalarFunction
+		args []interface{}
+		want interface{}
+	}{
+		{"ip_in_cidr", ipInCIDR, []interface{}{"10.1.2.3", "10.0.0.0/8"}, int64(1)},
+		{"ip_in_cidr outside", ipInCIDR, []interface{}{"11.1.2.3", "10.0.0.0/8"}, int64(0)},
+		{"ip_in_cidr mapped", ipInCIDR, []interface{}{"::ffff:192.168.1.20", "192.168.0.0/16"}, int64(1)},
+		{"ip_in_cidr zone", ipInCIDR, []interface{}{"fe80::1%eth0", "fe80::/10"}, int64(1)},
+		{"ip_in_cidr unmasked", ipInCIDR, []interface{}{"172.16.5.4", "172.16.5.1/24"}, int64(1)},
+		{"ip_in_cidr invalid", ipInCIDR, []interface{}{"*", "10.0.0.0/8"}, nil},
+		{"cidr_contains", cidrContains, []interface{}{"10.0.0.0/8", "10.20.0.0/16"}, int64(1)},
+		{"cidr_contains wider", cidrContains, []interface{}{"10.20.0.0/16", "10.0.0.0/8"}, int64(0)},
+		{"cidr_contains address", cidrContains, []interface{}{"2001:db8::/32", "2001:db8::1"}, int64(1)},
+		{"is_private_ip", isPrivateIP, []interface{}{"192.168.0.1"}, int64(1)},
+		{"is_private_ip public", isPrivateIP, []interface{}{"8.8.8.8"}, int64(0)},
+		{"is_private_ip ula", isPrivateIP, []interface{}{"[fd00::1]"}, int64(1)},
+		{"is_loopback", isLoopback, []interface{}{"::1"}, int64(1)},
+		{"is_loopback mapped", isLoopback, []interface{}{"::ffff:127.0.0.1"}, int64(1)},
+		{"ip_version 4", ipVersion, []interface{}{"1.2.3.4"}, int64(4)},
+		{"ip_version 6", ipVersion, []interface{}{"2001:db8::1"}, int64(6)},
+		{"ip_version null", ipVersion, []interface{}{nil}, nil},
+		{"ip_to_int", ipToInt, []interface{}{"10.0.0.1"}, int64(167772161)},
+		{"ip_to_int v6", ipToInt, []interface{}{"::2"}, "2"},
+		{"ip_normalize", ipNormalize, []interface{}{"2001:0DB8:0000::0001%3"}, "2001:db8::1"},
+		{"ip_normalize mapped", ipNormalize, []interface{}{"::ffff:10.0.0.1"}, "10.0.0.1"},
+	}
+
+	for _, test := range tests {
+		got, err := test.fn(test.args)
+		if err != nil {
+			t.Errorf("%s returned error: %v", test.name, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("%s = %#v, want %#v", test.name, got, test.want)
+		}
+	}
+}
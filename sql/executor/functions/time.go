@@ -0,0 +1,367 @@
+package functions
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	// Embed the timezone database so convert_tz works on hosts without one, such as Windows
+	_ "time/tzdata"
+
+	"github.com/scrymastic/goosquery/sql/executor/operations"
+)
+
+const (
+	// filetimeTicksPerSecond is the number of 100-nanosecond FILETIME intervals per second
+	filetimeTicksPerSecond = 10000000
+	// filetimeUnixEpochSeconds is the number of seconds between 1601-01-01 and 1970-01-01
+	filetimeUnixEpochSeconds = 11644473600
+	// julianDayUnixEpoch is the Julian day number of 1970-01-01 00:00:00 UTC
+	julianDayUnixEpoch = 2440587.5
+)
+
+func init() {
+	RegisterScalar("datetime", func(args []interface{}) (interface{}, error) {
+		return formatTimeFunc("datetime", args, "2006-01-02 15:04:05")
+	})
+	RegisterScalar("date", func(args []interface{}) (interface{}, error) {
+		return formatTimeFunc("date", args, "2006-01-02")
+	})
+	RegisterScalar("time", func(args []interface{}) (interface{}, error) {
+		return formatTimeFunc("time", args, "15:04:05")
+	})
+	RegisterScalar("strftime", strftimeFunc)
+	RegisterScalar("convert_tz", convertTZ)
+	RegisterScalar("filetime_to_unix", filetimeToUnix)
+	RegisterScalar("unix_to_filetime", unixToFiletime)
+}
+
+// timeLayouts are the time string formats accepted by the date and time functions
+var timeLayouts = []string{
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04",
+	"2006-01-02T15:04",
+	"2006-01-02",
+	"15:04:05.999999999",
+	"15:04",
+}
+
+// timezoneSuffix matches a trailing Z or [+-]HH:MM on a time string
+var timezoneSuffix = regexp.MustCompile(`(Z|[+-]\d\d:\d\d)$`)
+
+// relativeModifier matches modifiers such as '+7 days' or '-1.5 hours'
+var relativeModifier = regexp.MustCompile(`^([+-]?\d+(?:\.\d+)?)\s+(second|minute|hour|day|month|year)s?$`)
+
+// parseTimeValue converts a time value and its modifiers into a wall-clock time.
+// Results are kept in the UTC location; the 'localtime' and 'utc' modifiers
+// shift the wall clock the same way SQLite does. ok is false for NULL or
+// unparseable input, which the callers return as NULL.
+func parseTimeValue(value interface{}, modifiers []interface{}) (t time.Time, ok bool) {
+	if value == nil {
+		return time.Time{}, false
+	}
+
+	// Numeric values are Julian day numbers unless 'unixepoch' follows
+	unixEpoch := len(modifiers) > 0 && strings.EqualFold(fmt.Sprintf("%v", modifiers[0]), "unixepoch")
+	if number, isNumber := operations.ToFloat64(value); isNumber {
+		if unixEpoch {
+			modifiers = modifiers[1:]
+			if seconds, exact := argInt64(value); exact && float64(seconds) == number {
+				t = time.Unix(seconds, 0).UTC()
+			} else {
+				t = time.Unix(0, int64(number*float64(time.Second))).UTC()
+			}
+		} else {
+			t = time.Unix(0, int64((number-julianDayUnixEpoch)*86400*float64(time.Second))).UTC()
+		}
+	} else {
+		text, _ := argText(value)
+		parsed, parsedOK := parseTimeString(text)
+		if !parsedOK {
+			return time.Time{}, false
+		}
+		t = parsed
+	}
+
+	for _, modifier := range modifiers {
+		text, isText := argText(modifier)
+		if !isText {
+			return time.Time{}, false
+		}
+		t, ok = applyTimeModifier(t, strings.ToLower(strings.TrimSpace(text)))
+		if !ok {
+			return time.Time{}, false
+		}
+	}
+	return t, true
+}
+
+// parseTimeString parses 'now' or one of the supported time string formats
+func parseTimeString(text string) (time.Time, bool) {
+	text = strings.TrimSpace(text)
+	if strings.EqualFold(text, "now") {
+		return time.Now().UTC(), true
+	}
+
+	// A timezone suffix converts the value to UTC
+	offset := 0
+	if match := timezoneSuffix.FindString(text); match != "" && len(text) > len(match) {
+		text = strings.TrimSpace(strings.TrimSuffix(text, match))
+		if match != "Z" {
+			hours, _ := strconv.Atoi(match[1:3])
+			minutes, _ := strconv.Atoi(match[4:6])
+			offset = hours*3600 + minutes*60
+			if match[0] == '-' {
+				offset = -offset
+			}
+		}
+	}
+
+	for _, layout := range timeLayouts {
+		if t, err := time.ParseInLocation(layout, text, time.UTC); err == nil {
+			if t.Year() == 0 {
+				// Time-only values are on 2000-01-01, as in SQLite
+				t = t.AddDate(2000, 0, 0)
+			}
+			return t.Add(-time.Duration(offset) * time.Second), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// applyTimeModifier applies a single SQLite date and time modifier
+func applyTimeModifier(t time.Time, modifier string) (time.Time, bool) {
+	switch modifier {
+	case "localtime":
+		local := t.In(time.Local)
+		return time.Date(local.Year(), local.Month(), local.Day(), local.Hour(), local.Minute(), local.Second(), local.Nanosecond(), time.UTC), true
+	case "utc":
+		local := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.Local)
+		return local.UTC(), true
+	case "start of day":
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC), true
+	case "start of month":
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC), true
+	case "start of year":
+		return time.Date(t.Year(), 1, 1, 0, 0, 0, 0, time.UTC), true
+	}
+
+	if strings.HasPrefix(modifier, "weekday ") {
+		weekday, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(modifier, "weekday ")))
+		if err != nil || weekday < 0 || weekday > 6 {
+			return t, false
+		}
+		return t.AddDate(0, 0, (weekday-int(t.Weekday())+7)%7), true
+	}
+
+	match := relativeModifier.FindStringSubmatch(modifier)
+	if match == nil {
+		return t, false
+	}
+	amount, err := strconv.ParseFloat(match[1], 64)
+	if err != nil {
+		return t, false
+	}
+
+	switch match[2] {
+	case "second":
+		return t.Add(time.Duration(amount * float64(time.Second))), true
+	case "minute":
+		return t.Add(time.Duration(amount * float64(time.Minute))), true
+	case "hour":
+		return t.Add(time.Duration(amount * float64(time.Hour))), true
+	case "day":
+		return t.Add(time.Duration(amount * 24 * float64(time.Hour))), true
+	case "month":
+		return t.AddDate(0, int(amount), 0), true
+	case "year":
+		return t.AddDate(int(amount), 0, 0), true
+	}
+	return t, false
+}
+
+// formatTimeFunc implements datetime(), date() and time()
+func formatTimeFunc(name string, args []interface{}, layout string) (interface{}, error) {
+	value := interface{}("now")
+	if len(args) > 0 {
+		value = args[0]
+		args = args[1:]
+	}
+
+	t, ok := parseTimeValue(value, args)
+	if !ok {
+		return nil, nil
+	}
+	return t.Format(layout), nil
+}
+
+// strftimeFunc implements strftime(format, time-value, modifier, ...)
+func strftimeFunc(args []interface{}) (interface{}, error) {
+	if err := checkArgCount("strftime", args, 1, -1); err != nil {
+		return nil, err
+	}
+
+	format, ok := argText(args[0])
+	if !ok {
+		return nil, nil
+	}
+	value := interface{}("now")
+	var modifiers []interface{}
+	if len(args) > 1 {
+		value = args[1]
+		modifiers = args[2:]
+	}
+
+	t, ok := parseTimeValue(value, modifiers)
+	if !ok {
+		return nil, nil
+	}
+	return strftime(format, t), nil
+}
+
+// strftime formats a time with SQLite strftime() substitutions
+func strftime(format string, t time.Time) string {
+	var sb strings.Builder
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' || i+1 >= len(format) {
+			sb.WriteByte(format[i])
+			continue
+		}
+		i++
+		switch format[i] {
+		case 'd':
+			fmt.Fprintf(&sb, "%02d", t.Day())
+		case 'e':
+			fmt.Fprintf(&sb, "%2d", t.Day())
+		case 'f':
+			fmt.Fprintf(&sb, "%06.3f", float64(t.Second())+float64(t.Nanosecond())/1e9)
+		case 'F':
+			sb.WriteString(t.Format("2006-01-02"))
+		case 'H':
+			fmt.Fprintf(&sb, "%02d", t.Hour())
+		case 'I':
+			fmt.Fprintf(&sb, "%02d", (t.Hour()+11)%12+1)
+		case 'j':
+			fmt.Fprintf(&sb, "%03d", t.YearDay())
+		case 'J':
+			sb.WriteString(strconv.FormatFloat(float64(t.UnixNano())/float64(time.Second)/86400+julianDayUnixEpoch, 'f', -1, 64))
+		case 'k':
+			fmt.Fprintf(&sb, "%2d", t.Hour())
+		case 'l':
+			fmt.Fprintf(&sb, "%2d", (t.Hour()+11)%12+1)
+		case 'm':
+			fmt.Fprintf(&sb, "%02d", int(t.Month()))
+		case 'M':
+			fmt.Fprintf(&sb, "%02d", t.Minute())
+		case 'p':
+			sb.WriteString(t.Format("PM"))
+		case 'P':
+			sb.WriteString(strings.ToLower(t.Format("PM")))
+		case 'R':
+			sb.WriteString(t.Format("15:04"))
+		case 's':
+			fmt.Fprintf(&sb, "%d", t.Unix())
+		case 'S':
+			fmt.Fprintf(&sb, "%02d", t.Second())
+		case 'T':
+			sb.WriteString(t.Format("15:04:05"))
+		case 'u':
+			fmt.Fprintf(&sb, "%d", (int(t.Weekday())+6)%7+1)
+		case 'w':
+			fmt.Fprintf(&sb, "%d", int(t.Weekday()))
+		case 'W':
+			fmt.Fprintf(&sb, "%02d", (t.YearDay()+6-(int(t.Weekday())+6)%7)/7)
+		case 'Y':
+			fmt.Fprintf(&sb, "%04d", t.Year())
+		case '%':
+			sb.WriteByte('%')
+		default:
+			sb.WriteByte('%')
+			sb.WriteByte(format[i])
+		}
+	}
+	return sb.String()
+}
+
+// loadTimezone resolves 'UTC', 'SYSTEM', 'localtime', an offset such as
+// '+05:30' or an IANA name such as 'Europe/Berlin'
+func loadTimezone(name string) (*time.Location, error) {
+	switch strings.ToLower(name) {
+	case "utc", "z":
+		return time.UTC, nil
+	case "system", "local", "localtime":
+		return time.Local, nil
+	}
+
+	if timezoneSuffix.MatchString(name) && len(name) == 6 {
+		hours, _ := strconv.Atoi(name[1:3])
+		minutes, _ := strconv.Atoi(name[4:6])
+		offset := hours*3600 + minutes*60
+		if name[0] == '-' {
+			offset = -offset
+		}
+		return time.FixedZone(name, offset), nil
+	}
+	return time.LoadLocation(name)
+}
+
+// convertTZ implements convert_tz(datetime, from_tz, to_tz), which reinterprets
+// a wall-clock time from one timezone in another
+func convertTZ(args []interface{}) (interface{}, error) {
+	if err := checkArgCount("convert_tz", args, 3, 3); err != nil {
+		return nil, err
+	}
+
+	t, ok := parseTimeValue(args[0], nil)
+	fromName, fromOK := argText(args[1])
+	toName, toOK := argText(args[2])
+	if !ok || !fromOK || !toOK {
+		return nil, nil
+	}
+
+	from, err := loadTimezone(fromName)
+	if err != nil {
+		return nil, fmt.Errorf("convert_tz(): unknown timezone %q", fromName)
+	}
+	to, err := loadTimezone(toName)
+	if err != nil {
+		return nil, fmt.Errorf("convert_tz(): unknown timezone %q", toName)
+	}
+
+	wallClock := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), from)
+	return wallClock.In(to).Format("2006-01-02 15:04:05"), nil
+}
+
+// filetimeToUnix implements filetime_to_unix(filetime), converting 100-nanosecond
+// intervals since 1601-01-01 (FILETIME, time.win_timestamp) to unix seconds
+func filetimeToUnix(args []interface{}) (interface{}, error) {
+	if err := checkArgCount("filetime_to_unix", args, 1, 1); err != nil {
+		return nil, err
+	}
+
+	filetime, ok := argInt64(args[0])
+	if !ok {
+		return nil, nil
+	}
+	return filetime/filetimeTicksPerSecond - filetimeUnixEpochSeconds, nil
+}
+
+// unixToFiletime implements unix_to_filetime(seconds)
+func unixToFiletime(args []interface{}) (interface{}, error) {
+	if err := checkArgCount("unix_to_filetime", args, 1, 1); err != nil {
+		return nil, err
+	}
+
+	seconds, ok := argInt64(args[0])
+	if !ok {
+		return nil, nil
+	}
+	if seconds > math.MaxInt64/filetimeTicksPerSecond-filetimeUnixEpochSeconds {
+		return nil, fmt.Errorf("unix_to_filetime(): value out of range")
+	}
+	return (seconds + filetimeUnixEpochSeconds) * filetimeTicksPerSecond, nil
+}
@@ -0,0 +1,74 @@
+package functions
+
+import (
+	"testing"
+)
+
+func TestDateTimeFunctions(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   ScalarFunction
+		args []interface{}
+		want interface{}
+	}{
+		{"datetime unixepoch", scalarFunctions["datetime"], []interface{}{int64(1700000000), "unixepoch"}, "2023-11-14 22:13:20"},
+		{"datetime string", scalarFunctions["datetime"], []interface{}{"2024-02-28T23:30:00Z", "+1 hour"}, "2024-02-29 00:30:00"},
+		{"datetime offset", scalarFunctions["datetime"], []interface{}{"2024-01-01 10:00:00+02:00"}, "2024-01-01 08:00:00"},
+		{"datetime julian", scalarFunctions["datetime"], []interface{}{2460000.5}, "2023-02-25 00:00:00"},
+		{"date start of month", scalarFunctions["date"], []interface{}{"2024-03-15 12:00:00", "start of month", "-1 day"}, "2024-02-29"},
+		{"time", scalarFunctions["time"], []interface{}{"2024-03-15 12:34:56"}, "12:34:56"},
+		{"invalid", scalarFunctions["datetime"], []interface{}{"yesterday"}, nil},
+		{"null", scalarFunctions["datetime"], []interface{}{nil}, nil},
+		{"strftime", scalarFunctions["strftime"], []interface{}{"%Y-%m-%dT%H:%M:%S %j %w %s", "2024-03-15 12:34:56"}, "2024-03-15T12:34:56 075 5 1710506096"},
+		{"convert_tz", scalarFunctions["convert_tz"], []interface{}{"2024-07-01 12:00:00", "UTC", "Europe/Berlin"}, "2024-07-01 14:00:00"},
+		{"convert_tz offset", scalarFunctions["convert_tz"], []interface{}{"2024-07-01 12:00:00", "+05:30", "UTC"}, "2024-07-01 06:30:00"},
+		{"filetime_to_unix", scalarFunctions["filetime_to_unix"], []interface{}{int64(133444737200000000)}, int64(1700000120)},
+		{"unix_to_filetime", scalarFunctions["unix_to_filetime"], []interface{}{int32(1700000120)}, int64(133444737200000000)},
+	}
+
+	for _, test := range tests {
+		got, err := test.fn(test.args)
+		if err != nil {
+			t.Errorf("%s returned error: %v", test.name, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("%s = %#v, want %#v", test.name, got, test.want)
+		}
+	}
+
+	if _, err := convertTZ([]interface{}{"2024-07-01 12:00:00", "UTC", "Nowhere/City"}); err == nil {
+		t.Error("convert_tz with an unknown timezone should fail")
+	}
+}
+
+func TestEncodingFunctions(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   ScalarFunction
+		arg  interface{}
+		want interface{}
+	}{
+		{"hex", hexFunc, "goose", "676F6F7365"},
+		{"unhex", unhexFunc, "676f6f7365", "goose"},
+		{"unhex invalid", unhexFunc, "zz", nil},
+		{"to_base64", toBase64, "goose", "Z29vc2U="},
+		{"from_base64", fromBase64, "Z29vc2U=", "goose"},
+		{"from_base64 unpadded", fromBase64, "Z29vc2U", "goose"},
+		{"from_base64 invalid", fromBase64, "!!", nil},
+		{"utf16le_decode", utf16leDecode, "C\x00:\x00\\\x00\x00\x00junk", `C:\`},
+		{"rot13", rot13Func, "HRZR_EHACNGU:P:\\Jvaqbjf", "UEME_RUNPATH:C:\\Windows"},
+		{"null", rot13Func, nil, nil},
+	}
+
+	for _, test := range tests {
+		got, err := test.fn([]interface{}{test.arg})
+		if err != nil {
+			t.Errorf("%s returned error: %v", test.name, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("%s = %#v, want %#v", test.name, got, test.want)
+		}
+	}
+}
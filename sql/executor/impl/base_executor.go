@@ -2,7 +2,6 @@ package impl
 
 import (
 	"github.com/blastrain/vitess-sqlparser/sqlparser"
-	"github.com/scrymastic/goosquery/sql/executor/operations"
 	"github.com/scrymastic/goosquery/sql/result"
 	"github.com/scrymastic/goosquery/sql/sqlctx"
 )
@@ -10,92 +9,17 @@ import (
 // BaseExecutor provides common functionality for all executors
 type BaseExecutor struct{}
 
-// MatchesWhereClause checks if a row matches the WHERE clause
+// MatchesWhereClause checks if a row matches the WHERE clause.
+// Executors filtering many rows compile the clause once with CompileWhereClause.
 func (e *BaseExecutor) MatchesWhereClause(row result.Result, expr sqlparser.Expr) bool {
-	switch expr := expr.(type) {
-	case *sqlparser.ComparisonExpr:
-		return e.EvaluateComparison(row, expr)
-	case *sqlparser.AndExpr:
-		return e.MatchesWhereClause(row, expr.Left) && e.MatchesWhereClause(row, expr.Right)
-	case *sqlparser.OrExpr:
-		return e.MatchesWhereClause(row, expr.Left) || e.MatchesWhereClause(row, expr.Right)
-	case *sqlparser.NotExpr:
-		return !e.MatchesWhereClause(row, expr.Expr)
-	case *sqlparser.ParenExpr:
-		return e.MatchesWhereClause(row, expr.Expr)
-	}
-	return false
+	predicate, err := e.CompileWhereClause(expr)
+	return err == nil && predicate(row)
 }
 
 // EvaluateComparison evaluates a comparison expression
 func (e *BaseExecutor) EvaluateComparison(row result.Result, expr *sqlparser.ComparisonExpr) bool {
-	// Get left operand
-	var leftValue interface{}
-	if colName, ok := expr.Left.(*sqlparser.ColName); ok {
-		columnName := colName.Name.String()
-		if val, exists := row[columnName]; exists {
-			leftValue = val
-		} else {
-			return false // Column doesn't exist in this row
-		}
-	} else {
-		// Try to extract a literal value
-		leftValue = operations.ExtractLiteralValue(expr.Left)
-	}
-
-	// Get right operand
-	var rightValue interface{}
-	if colName, ok := expr.Right.(*sqlparser.ColName); ok {
-		columnName := colName.Name.String()
-		if val, exists := row[columnName]; exists {
-			rightValue = val
-		} else {
-			return false // Column doesn't exist in this row
-		}
-	} else {
-		// Try to extract a literal value
-		rightValue = operations.ExtractLiteralValue(expr.Right)
-	}
-
-	// Handle NULL values
-	if leftValue == nil || rightValue == nil {
-		// NULL comparison semantics
-		if expr.Operator == "is" {
-			return rightValue == nil
-		}
-		if expr.Operator == "is not" {
-			return rightValue != nil
-		}
-		return false // Any comparison with NULL yields false
-	}
-
-	// Perform the comparison
-	switch expr.Operator {
-	case "=":
-		return operations.Compare(leftValue, rightValue) == 0
-	case "!=", "<>":
-		return operations.Compare(leftValue, rightValue) != 0
-	case "<":
-		return operations.Compare(leftValue, rightValue) < 0
-	case "<=":
-		return operations.Compare(leftValue, rightValue) <= 0
-	case ">":
-		return operations.Compare(leftValue, rightValue) > 0
-	case ">=":
-		return operations.Compare(leftValue, rightValue) >= 0
-	case "like":
-		return operations.MatchesLike(leftValue, rightValue)
-	case "not like":
-		return !operations.MatchesLike(leftValue, rightValue)
-	case "in":
-		// TODO: Implement IN comparison
-		return false
-	case "not in":
-		// TODO: Implement NOT IN comparison
-		return false
-	}
-
-	return false
+	predicate, err := e.compileComparison(expr)
+	return err == nil && predicate(row)
 }
 
 // GetAllRequiredColumns returns all columns required for the query
@@ -155,6 +79,10 @@ func (e *BaseExecutor) GetSelectedColumns(selectExprs sqlparser.SelectExprs) []s
 			case *sqlparser.FuncExpr:
 				// Add columns used in function arguments
 				columns = append(columns, e.GetAggregationColumns(selectExprs)...)
+				columns = append(columns, e.GetWhereColumns(exprType)...)
+			default:
+				// Add columns used in casts, arithmetic and other expressions
+				columns = append(columns, e.GetWhereColumns(exprType)...)
 			}
 		case *sqlparser.StarExpr:
 			// SELECT * means all columns
@@ -179,6 +107,9 @@ func (e *BaseExecutor) GetWhereColumns(whereExpr sqlparser.Expr) []string {
 // extractWhereColumns recursively extracts column names from an expression
 func (e *BaseExecutor) extractWhereColumns(expr sqlparser.Expr, columnsMap map[string]bool) {
 	switch expr := expr.(type) {
+	case *sqlparser.ColName:
+		columnsMap[expr.Name.String()] = true
+
 	case *sqlparser.ComparisonExpr:
 		// Handle left side
 		if colName, ok := expr.Left.(*sqlparser.ColName); ok {
@@ -214,6 +145,16 @@ func (e *BaseExecutor) extractWhereColumns(expr sqlparser.Expr, columnsMap map[s
 				e.extractWhereColumns(aliasedExpr.Expr, columnsMap)
 			}
 		}
+
+	default:
+		// Collect every column referenced by other expressions such as
+		// IS NULL tests, BETWEEN, IN lists, casts and arithmetic
+		_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+			if colName, ok := node.(*sqlparser.ColName); ok {
+				columnsMap[colName.Name.String()] = true
+			}
+			return true, nil
+		}, expr)
 	}
 }
 
@@ -247,22 +188,42 @@ func (e *BaseExecutor) GetAggregationColumns(selectExprs sqlparser.SelectExprs)
 	return columns
 }
 
-// GetConstants extracts constants from WHERE expressions and adds them to the context
+// GetConstants extracts constants from WHERE expressions and adds them to the context.
+// Only equality, LIKE and IN comparisons are kept, as generators use the
+// constants to look values up, e.g. the pids of the processes table.
 func (e *BaseExecutor) GetConstants(expr sqlparser.Expr, ctx *sqlctx.Context) {
 	switch expr := expr.(type) {
 	case *sqlparser.ComparisonExpr:
-		if colName, ok := expr.Left.(*sqlparser.ColName); ok {
-			if sqlVal, ok := expr.Right.(*sqlparser.SQLVal); ok {
-				strVal := string(sqlVal.Val)
-				// Store column name and value in context (without operator)
-				ctx.AddConstant(colName.Name.String(), strVal)
+		switch expr.Operator {
+		case sqlparser.EqualStr, sqlparser.LikeStr:
+			if colName, ok := expr.Left.(*sqlparser.ColName); ok {
+				if sqlVal, ok := expr.Right.(*sqlparser.SQLVal); ok {
+					// Store column name and value in context (without operator)
+					ctx.AddConstant(colName.Name.String(), string(sqlVal.Val))
+				}
+			} else if colName, ok := expr.Right.(*sqlparser.ColName); ok {
+				if sqlVal, ok := expr.Left.(*sqlparser.SQLVal); ok {
+					ctx.AddConstant(colName.Name.String(), string(sqlVal.Val))
+				}
+			}
+		case sqlparser.InStr:
+			colName, ok := expr.Left.(*sqlparser.ColName)
+			tuple, isTuple := expr.Right.(sqlparser.ValTuple)
+			if !ok || !isTuple {
+				return
+			}
+			// The list is only pushed down whole, as a generator given part
+			// of it would miss the rows matching the other values
+			values := make([]string, 0, len(tuple))
+			for _, value := range tuple {
+				sqlVal, ok := value.(*sqlparser.SQLVal)
+				if !ok {
+					return
+				}
+				values = append(values, string(sqlVal.Val))
 			}
-		} else if colName, ok := expr.Right.(*sqlparser.ColName); ok {
-			if sqlVal, ok := expr.Left.(*sqlparser.SQLVal); ok {
-				strVal := string(sqlVal.Val)
-				// Store column name and value in context (without operator)
-				// Note: original code was inverting operator but AddConstant doesn't take an operator
-				ctx.AddConstant(colName.Name.String(), strVal)
+			for _, value := range values {
+				ctx.AddConstant(colName.Name.String(), value)
 			}
 		}
 	case *sqlparser.AndExpr:
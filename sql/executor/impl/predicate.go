@@ -0,0 +1,338 @@
+package impl
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/blastrain/vitess-sqlparser/sqlparser"
+	"github.com/scrymastic/goosquery/sql/executor/functions"
+	"github.com/scrymastic/goosquery/sql/executor/operations"
+	"github.com/scrymastic/goosquery/sql/result"
+)
+
+// Predicate reports whether a row satisfies a compiled WHERE condition
+type Predicate func(row result.Result) bool
+
+// operand yields the value of one side of a comparison. ok is false when the
+// row cannot satisfy the comparison at all, e.g. the column is missing.
+type operand func(row result.Result) (value interface{}, ok bool)
+
+func init() {
+	functions.RegisterConditionCompiler(func(expr sqlparser.Expr) (func(row result.Result) bool, error) {
+		var e BaseExecutor
+		return e.CompileWhereClause(expr)
+	})
+}
+
+// CompileWhereClause compiles a WHERE condition once per query into a closure,
+// so that rows are filtered without walking the syntax tree again. Literals
+// are converted and prepared for comparison, and constant LIKE and REGEXP
+// patterns are compiled, ahead of time.
+func (e *BaseExecutor) CompileWhereClause(expr sqlparser.Expr) (Predicate, error) {
+	switch expr := expr.(type) {
+	case *sqlparser.ComparisonExpr:
+		return e.compileComparison(expr)
+	case *sqlparser.AndExpr:
+		left, right, err := e.compilePair(expr.Left, expr.Right)
+		if err != nil {
+			return nil, err
+		}
+		return func(row result.Result) bool { return left(row) && right(row) }, nil
+	case *sqlparser.OrExpr:
+		left, right, err := e.compilePair(expr.Left, expr.Right)
+		if err != nil {
+			return nil, err
+		}
+		return func(row result.Result) bool { return left(row) || right(row) }, nil
+	case *sqlparser.NotExpr:
+		inner, err := e.CompileWhereClause(expr.Expr)
+		if err != nil {
+			return nil, err
+		}
+		return func(row result.Result) bool { return !inner(row) }, nil
+	case *sqlparser.ParenExpr:
+		return e.CompileWhereClause(expr.Expr)
+	case *sqlparser.IsExpr:
+		return compileIs(expr)
+	case *sqlparser.RangeCond:
+		return compileRange(expr)
+	}
+
+	// Function calls, arithmetic and plain columns are true if non-zero
+	compiled, err := functions.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	return func(row result.Result) bool {
+		value, err := compiled.Eval(row)
+		return err == nil && functions.IsTruthy(value)
+	}, nil
+}
+
+// compilePair compiles the two operands of AND and OR
+func (e *BaseExecutor) compilePair(left, right sqlparser.Expr) (Predicate, Predicate, error) {
+	leftPredicate, err := e.CompileWhereClause(left)
+	if err != nil {
+		return nil, nil, err
+	}
+	rightPredicate, err := e.CompileWhereClause(right)
+	if err != nil {
+		return nil, nil, err
+	}
+	return leftPredicate, rightPredicate, nil
+}
+
+// compileComparison compiles a comparison, IN list, LIKE or REGEXP
+func (e *BaseExecutor) compileComparison(expr *sqlparser.ComparisonExpr) (Predicate, error) {
+	left, _, err := compileOperand(expr.Left)
+	if err != nil {
+		return nil, err
+	}
+
+	// IN and NOT IN compare against a list of values
+	if tuple, ok := expr.Right.(sqlparser.ValTuple); ok {
+		return compileIn(expr.Operator, left, tuple)
+	}
+
+	right, rightConstant, err := compileOperand(expr.Right)
+	if err != nil {
+		return nil, err
+	}
+
+	switch expr.Operator {
+	case "like", "not like", "regexp", "not regexp":
+		return compileMatch(expr.Operator, left, right, rightConstant)
+	}
+
+	test, ok := comparisonTests[expr.Operator]
+	if !ok {
+		return nil, fmt.Errorf("unsupported operator: %s", expr.Operator)
+	}
+
+	// Compare against a literal prepared once
+	if rightConstant != nil {
+		prepared := operations.Prepare(rightConstant.Value)
+		return func(row result.Result) bool {
+			leftValue, ok := left(row)
+			// Any comparison with NULL yields false
+			if !ok || leftValue == nil || prepared.Value == nil {
+				return false
+			}
+			return test(prepared.CompareTo(leftValue))
+		}, nil
+	}
+
+	return func(row result.Result) bool {
+		leftValue, ok := left(row)
+		if !ok || leftValue == nil {
+			return false
+		}
+		rightValue, ok := right(row)
+		if !ok || rightValue == nil {
+			return false
+		}
+		return test(operations.Compare(leftValue, rightValue))
+	}, nil
+}
+
+// comparisonTests map comparison operators to a test of the result of Compare
+var comparisonTests = map[string]func(cmp int) bool{
+	"=":  func(cmp int) bool { return cmp == 0 },
+	"!=": func(cmp int) bool { return cmp != 0 },
+	"<>": func(cmp int) bool { return cmp != 0 },
+	"<":  func(cmp int) bool { return cmp < 0 },
+	"<=": func(cmp int) bool { return cmp <= 0 },
+	">":  func(cmp int) bool { return cmp > 0 },
+	">=": func(cmp int) bool { return cmp >= 0 },
+}
+
+// compileOperand compiles one side of a comparison. A column missing from the
+// row makes the comparison false, as does an evaluation error. For constant
+// operands the compiled expression is returned as well.
+func compileOperand(expr sqlparser.Expr) (operand, *functions.CompiledExpr, error) {
+	if colName, ok := expr.(*sqlparser.ColName); ok {
+		column := colName.Name.String()
+		return func(row result.Result) (interface{}, bool) {
+			value, exists := row.Lookup(column)
+			return value, exists
+		}, nil, nil
+	}
+
+	compiled, err := functions.Compile(expr)
+	if err != nil {
+		return nil, nil, err
+	}
+	if compiled.Constant {
+		value := compiled.Value
+		return func(result.Result) (interface{}, bool) { return value, true }, compiled, nil
+	}
+	return func(row result.Result) (interface{}, bool) {
+		value, err := compiled.Eval(row)
+		return value, err == nil
+	}, nil, nil
+}
+
+// compileMatch compiles LIKE and REGEXP, translating a constant pattern once
+func compileMatch(operator string, left, right operand, rightConstant *functions.CompiledExpr) (Predicate, error) {
+	negate := operator == "not like" || operator == "not regexp"
+	isLike := operator == "like" || operator == "not like"
+
+	match := func(value, pattern interface{}) bool {
+		if isLike {
+			return operations.MatchesLike(value, pattern)
+		}
+		return operations.MatchesRegexp(value, pattern)
+	}
+	if rightConstant != nil && rightConstant.Value != nil {
+		var re *regexp.Regexp
+		pattern := operations.FormatValue(rightConstant.Value)
+		if isLike {
+			re = operations.LikeRegexp(pattern)
+		} else {
+			// An invalid expression matches nothing
+			re, _ = regexp.Compile(pattern)
+		}
+		match = func(value, _ interface{}) bool {
+			return re != nil && re.MatchString(operations.FormatValue(value))
+		}
+	}
+
+	return func(row result.Result) bool {
+		value, ok := left(row)
+		if !ok || value == nil {
+			return false
+		}
+		pattern, ok := right(row)
+		if !ok || pattern == nil {
+			return false
+		}
+		return match(value, pattern) != negate
+	}, nil
+}
+
+// compileIn compiles IN and NOT IN. As in SQL, a NULL value or a non-matching
+// list containing NULL is never true.
+func compileIn(operator string, left operand, tuple sqlparser.ValTuple) (Predicate, error) {
+	if operator != "in" && operator != "not in" {
+		return nil, fmt.Errorf("unsupported operator: %s", operator)
+	}
+
+	// Each item is a constant prepared once or an expression of the row
+	type inItem struct {
+		prepared *operations.PreparedValue
+		eval     functions.Evaluator
+	}
+	items := make([]inItem, 0, len(tuple))
+	for _, item := range tuple {
+		compiled, err := functions.Compile(item)
+		if err != nil {
+			return nil, err
+		}
+		if compiled.Constant {
+			prepared := operations.Prepare(compiled.Value)
+			items = append(items, inItem{prepared: &prepared})
+		} else {
+			items = append(items, inItem{eval: compiled.Eval})
+		}
+	}
+
+	return func(row result.Result) bool {
+		value, ok := left(row)
+		if !ok || value == nil {
+			return false
+		}
+
+		found, sawNull := false, false
+		for _, item := range items {
+			prepared := item.prepared
+			if prepared == nil {
+				itemValue, err := item.eval(row)
+				if err != nil {
+					return false
+				}
+				p := operations.Prepare(itemValue)
+				prepared = &p
+			}
+
+			if prepared.Value == nil {
+				sawNull = true
+				continue
+			}
+			if prepared.CompareTo(value) == 0 {
+				found = true
+				break
+			}
+		}
+
+		if operator == "in" {
+			return found
+		}
+		return !found && !sawNull
+	}, nil
+}
+
+// compileIs compiles an IS [NOT] NULL, TRUE or FALSE test
+func compileIs(expr *sqlparser.IsExpr) (Predicate, error) {
+	compiled, err := functions.Compile(expr.Expr)
+	if err != nil {
+		return nil, err
+	}
+
+	var test func(value interface{}) bool
+	switch expr.Operator {
+	case sqlparser.IsNullStr:
+		test = func(value interface{}) bool { return value == nil }
+	case sqlparser.IsNotNullStr:
+		test = func(value interface{}) bool { return value != nil }
+	case sqlparser.IsTrueStr:
+		test = functions.IsTruthy
+	case sqlparser.IsNotTrueStr:
+		test = func(value interface{}) bool { return !functions.IsTruthy(value) }
+	case sqlparser.IsFalseStr:
+		test = func(value interface{}) bool { return value != nil && !functions.IsTruthy(value) }
+	case sqlparser.IsNotFalseStr:
+		test = func(value interface{}) bool { return value == nil || functions.IsTruthy(value) }
+	default:
+		return nil, fmt.Errorf("unsupported operator: %s", expr.Operator)
+	}
+
+	return func(row result.Result) bool {
+		value, err := compiled.Eval(row)
+		return err == nil && test(value)
+	}, nil
+}
+
+// compileRange compiles a [NOT] BETWEEN condition
+func compileRange(expr *sqlparser.RangeCond) (Predicate, error) {
+	left, err := functions.Compile(expr.Left)
+	if err != nil {
+		return nil, err
+	}
+	from, err := functions.Compile(expr.From)
+	if err != nil {
+		return nil, err
+	}
+	to, err := functions.Compile(expr.To)
+	if err != nil {
+		return nil, err
+	}
+	negate := expr.Operator == sqlparser.NotBetweenStr
+
+	return func(row result.Result) bool {
+		value, err := left.Eval(row)
+		if err != nil || value == nil {
+			return false
+		}
+		fromValue, err := from.Eval(row)
+		if err != nil || fromValue == nil {
+			return false
+		}
+		toValue, err := to.Eval(row)
+		if err != nil || toValue == nil {
+			return false
+		}
+
+		between := operations.Compare(value, fromValue) >= 0 && operations.Compare(value, toValue) <= 0
+		return between != negate
+	}, nil
+}
@@ -0,0 +1,97 @@
+package impl
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/blastrain/vitess-sqlparser/sqlparser"
+	"github.com/scrymastic/goosquery/sql/parser"
+	"github.com/scrymastic/goosquery/sql/result"
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+)
+
+const benchmarkWhere = `SELECT pid, path, size FROM test WHERE path LIKE 'C:\Windows\System32\%' AND size > 5000 AND pid IN (1, 2, 3, 4, 5, 250) ORDER BY size DESC`
+
+// genMemoryMap returns many rows shaped like process_memory_map, with numbers
+// stored as text as most table generators do
+func genMemoryMap(ctx *sqlctx.Context) (*result.Results, error) {
+	results := result.NewQueryResult()
+	for i := 0; i < 10000; i++ {
+		results.AppendResult(result.FromMap(map[string]interface{}{
+			"pid":         int64(i % 300),
+			"start":       fmt.Sprintf("0x%08x", i*4096),
+			"path":        fmt.Sprintf(`C:\Windows\System32\lib%d.dll`, i%500),
+			"size":        fmt.Sprintf("%d", (i*7919)%100000),
+			"permissions": "r-x",
+		}))
+	}
+	return results, nil
+}
+
+func TestCompileWhereClauseErrors(t *testing.T) {
+	e := &BaseExecutor{}
+	for _, query := range []string{
+		"SELECT * FROM test WHERE no_such_function(pid) = 1",
+		"SELECT * FROM test WHERE pid <=> 1",
+	} {
+		parsed, err := parser.Parse(query)
+		if err != nil {
+			t.Fatalf("Failed to parse %q: %v", query, err)
+		}
+		where := parsed.Statement.(*sqlparser.Select).Where.Expr
+		if _, err := e.CompileWhereClause(where); err == nil {
+			t.Errorf("CompileWhereClause(%q) should fail", query)
+		}
+	}
+}
+
+func benchmarkWhereExpr(b *testing.B) (sqlparser.Expr, *result.Results) {
+	parsed, err := parser.Parse(benchmarkWhere)
+	if err != nil {
+		b.Fatal(err)
+	}
+	rows, _ := genMemoryMap(nil)
+	return parsed.Statement.(*sqlparser.Select).Where.Expr, rows
+}
+
+// BenchmarkMatchesWhereClause evaluates the condition from its syntax tree for every row
+func BenchmarkMatchesWhereClause(b *testing.B) {
+	where, rows := benchmarkWhereExpr(b)
+	e := &BaseExecutor{}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, row := range *rows {
+			e.MatchesWhereClause(row, where)
+		}
+	}
+}
+
+// BenchmarkCompiledWhereClause compiles the condition once and runs the closure for every row
+func BenchmarkCompiledWhereClause(b *testing.B) {
+	where, rows := benchmarkWhereExpr(b)
+	e := &BaseExecutor{}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		predicate, err := e.CompileWhereClause(where)
+		if err != nil {
+			b.Fatal(err)
+		}
+		for _, row := range *rows {
+			predicate(row)
+		}
+	}
+}
+
+func BenchmarkExecute(b *testing.B) {
+	parsed, err := parser.Parse(benchmarkWhere)
+	if err != nil {
+		b.Fatal(err)
+	}
+	executor := &TableExecutor{TableName: "test", Generator: genMemoryMap}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := executor.Execute(parsed); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
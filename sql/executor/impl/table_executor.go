@@ -2,11 +2,17 @@ package impl
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/blastrain/vitess-sqlparser/sqlparser"
 	"github.com/scrymastic/goosquery/sql/executor/aggregation"
+	"github.com/scrymastic/goosquery/sql/executor/functions"
 	"github.com/scrymastic/goosquery/sql/executor/postops"
 	"github.com/scrymastic/goosquery/sql/executor/projection"
+	"github.com/scrymastic/goosquery/sql/parser"
 	"github.com/scrymastic/goosquery/sql/result"
 	"github.com/scrymastic/goosquery/sql/sqlctx"
 )
@@ -19,22 +25,105 @@ type TableExecutor struct {
 	TableName string
 	Generator DataGenerator
 	BaseExecutor
+
+	// Stats of the last query executed
+	Stats Stats
 }
 
-// Execute executes a query against the table using the provided data function
-func (e *TableExecutor) Execute(stmt *sqlparser.Select) (*result.Results, error) {
-	// Check if the query uses aggregation functions
-	hasAggregations := aggregation.HasAggregations(stmt.SelectExprs)
+// Stats reports where the time of a query was spent
+type Stats struct {
+	GeneratorTime  time.Duration // collecting the rows of the table
+	ProcessingTime time.Duration // filtering, aggregating, sorting and projecting
+	GeneratedRows  int
+}
 
-	// Get aggregation information if needed
-	var aggs []aggregation.AggregationInfo
-	if hasAggregations {
-		aggs = aggregation.ExtractAggregations(stmt.SelectExprs)
+// Plan describes how a query is executed, without running it
+type Plan struct {
+	Table          string
+	Columns        []string            // columns requested from the generator, * for all
+	Constants      map[string][]string // WHERE constants passed to the generator
+	TableFunctions []string
+	Filter         string
+	Aggregations   []string
+	GroupBy        string
+	OrderBy        string
+	Limit          string
+}
+
+// String renders the plan with one step per line, in execution order
+func (p *Plan) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "SCAN %s\n", p.Table)
+	fmt.Fprintf(&sb, "  columns: %s\n", strings.Join(p.Columns, ", "))
+	if len(p.Constants) > 0 {
+		names := make([]string, 0, len(p.Constants))
+		for name := range p.Constants {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for i, name := range names {
+			values := p.Constants[name]
+			if len(values) == 1 {
+				names[i] = fmt.Sprintf("%s = %q", name, values[0])
+				continue
+			}
+			quoted := make([]string, len(values))
+			for j, value := range values {
+				quoted[j] = strconv.Quote(value)
+			}
+			names[i] = fmt.Sprintf("%s IN (%s)", name, strings.Join(quoted, ", "))
+		}
+		fmt.Fprintf(&sb, "  constraints: %s\n", strings.Join(names, ", "))
+	}
+	for _, name := range p.TableFunctions {
+		fmt.Fprintf(&sb, "JOIN %s\n", name)
+	}
+	if p.Filter != "" {
+		fmt.Fprintf(&sb, "FILTER %s\n", p.Filter)
+	}
+	if len(p.Aggregations) > 0 {
+		fmt.Fprintf(&sb, "AGGREGATE %s\n", strings.Join(p.Aggregations, ", "))
+	}
+	if p.GroupBy != "" {
+		fmt.Fprintf(&sb, "GROUP BY %s\n", p.GroupBy)
+	}
+	if p.OrderBy != "" {
+		fmt.Fprintf(&sb, "SORT BY %s\n", p.OrderBy)
+	}
+	if p.Limit != "" {
+		fmt.Fprintf(&sb, "LIMIT %s\n", p.Limit)
+	}
+	return sb.String()
+}
+
+// GenDual generates the single empty row of the implicit "dual" table,
+// which is queried when a SELECT has no FROM clause
+func GenDual(ctx *sqlctx.Context) (*result.Results, error) {
+	results := result.NewQueryResult()
+	results.AppendResult(result.Result{})
+	return results, nil
+}
+
+// prepare checks the statement of a query and creates the context passed to
+// the generator, with the columns to fetch and the WHERE constants
+func (e *TableExecutor) prepare(query *parser.ParsedQuery) (*sqlparser.Select, *sqlctx.Context, error) {
+	stmt, ok := query.Statement.(*sqlparser.Select)
+	if !ok {
+		return nil, nil, fmt.Errorf("only SELECT statements are supported")
 	}
 
 	// Get all required columns for this query - these are the columns we need to fetch
 	requiredColumns := e.GetAllRequiredColumns(stmt)
 
+	// Add columns passed to table-valued functions
+	if len(requiredColumns) != 1 || requiredColumns[0] != "*" {
+		for _, tableFunction := range query.TableFunctions {
+			for _, arg := range tableFunction.Args {
+				requiredColumns = append(requiredColumns, e.GetWhereColumns(arg)...)
+			}
+		}
+	}
+
 	// Create context for query execution
 	ctx := sqlctx.NewContext()
 
@@ -45,24 +134,103 @@ func (e *TableExecutor) Execute(stmt *sqlparser.Select) (*result.Results, error)
 
 	// Set the columns in the context to ensure all required data is fetched
 	ctx.SetColumns(requiredColumns)
+	return stmt, ctx, nil
+}
+
+// Plan returns the steps executing a query would take
+func (e *TableExecutor) Plan(query *parser.ParsedQuery) (*Plan, error) {
+	stmt, ctx, err := e.prepare(query)
+	if err != nil {
+		return nil, err
+	}
+
+	columns := append([]string(nil), ctx.Columns...)
+	sort.Strings(columns)
+	plan := &Plan{
+		Table:     e.TableName,
+		Columns:   columns,
+		Constants: ctx.Constants,
+	}
+	for _, tableFunction := range query.TableFunctions {
+		plan.TableFunctions = append(plan.TableFunctions, tableFunction.Name)
+	}
+	if stmt.Where != nil {
+		plan.Filter = sqlparser.String(stmt.Where.Expr)
+	}
+	if aggregation.HasAggregations(stmt.SelectExprs) {
+		for _, agg := range aggregation.ExtractAggregations(stmt.SelectExprs) {
+			plan.Aggregations = append(plan.Aggregations, agg.Alias)
+		}
+	}
+	if len(stmt.GroupBy) > 0 {
+		plan.GroupBy = strings.TrimPrefix(sqlparser.String(stmt.GroupBy), " group by ")
+	}
+	if len(stmt.OrderBy) > 0 {
+		plan.OrderBy = strings.TrimPrefix(sqlparser.String(stmt.OrderBy), " order by ")
+	}
+	if stmt.Limit != nil {
+		plan.Limit = strings.TrimPrefix(sqlparser.String(stmt.Limit), " limit ")
+	}
+	return plan, nil
+}
+
+// Execute executes a query against the table using the provided data function
+func (e *TableExecutor) Execute(query *parser.ParsedQuery) (*result.Results, error) {
+	stmt, ctx, err := e.prepare(query)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check if the query uses aggregation functions
+	hasAggregations := aggregation.HasAggregations(stmt.SelectExprs)
+
+	// Get aggregation information if needed
+	var aggs []aggregation.AggregationInfo
+	if hasAggregations {
+		aggs = aggregation.ExtractAggregations(stmt.SelectExprs)
+	}
 
 	// Fetch data with all necessary columns
+	started := time.Now()
 	data, err := e.Generator(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get %s data: %w", e.TableName, err)
 	}
+	e.Stats = Stats{GeneratorTime: time.Since(started), GeneratedRows: data.Size()}
+	started = time.Now()
+	defer func() { e.Stats.ProcessingTime = time.Since(started) }()
+
+	// Join rows with table-valued functions from the FROM clause
+	for _, tableFunction := range query.TableFunctions {
+		data, err = functions.ApplyTableFunction(data, tableFunction.Name, tableFunction.Args)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	// Create result
 	res := result.NewQueryResult()
 
-	// Apply WHERE clause if present
+	// Apply WHERE clause if present, compiled once for all rows
+	matches := func(result.Result) bool { return true }
+	if stmt.Where != nil {
+		matches, err = e.CompileWhereClause(stmt.Where.Expr)
+		if err != nil {
+			return nil, err
+		}
+	}
 	for _, itemMap := range *data {
-		if stmt.Where == nil || e.MatchesWhereClause(itemMap, stmt.Where.Expr) {
+		if matches(itemMap) {
 			// Add all columns at this stage - we'll project down later
 			res.AppendResult(itemMap)
 		}
 	}
 
+	// Compute scalar functions and other expressions in the SELECT list
+	if err := projection.EvaluateExpressions(res, stmt); err != nil {
+		return nil, err
+	}
+
 	// Apply aggregations if needed
 	if hasAggregations {
 		res, err = aggregation.ApplyAggregations(res, aggs, stmt.GroupBy)
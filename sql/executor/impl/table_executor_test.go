@@ -0,0 +1,307 @@
+package impl
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/scrymastic/goosquery/sql/parser"
+	"github.com/scrymastic/goosquery/sql/result"
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+)
+
+// genExtensions returns a fixed set of rows shaped like chrome_extensions
+func genExtensions(ctx *sqlctx.Context) (*result.Results, error) {
+	results := result.NewQueryResult()
+	results.AppendResult(result.FromMap(map[string]interface{}{
+		"identifier":       "aaa",
+		"key":              "ext-key",
+		"permissions_json": `["storage","webRequest"]`,
+		"manifest_json":    `{"name":"Alpha","version":"1.0","manifest_version":3}`,
+	}))
+	results.AppendResult(result.FromMap(map[string]interface{}{
+		"identifier":       "bbb",
+		"key":              "ext-key",
+		"permissions_json": `["tabs"]`,
+		"manifest_json":    `{"name":"Beta","version":"2.1","manifest_version":2}`,
+	}))
+	return results, nil
+}
+
+func executeQuery(t *testing.T, generator DataGenerator, query string) *result.Results {
+	t.Helper()
+	parsedQuery, err := parser.Parse(query)
+	if err != nil {
+		t.Fatalf("Failed to parse %q: %v", query, err)
+	}
+	executor := &TableExecutor{TableName: "test", Generator: generator}
+	results, err := executor.Execute(parsedQuery)
+	if err != nil {
+		t.Fatalf("Failed to execute %q: %v", query, err)
+	}
+	return results
+}
+
+func TestJSONEachJoin(t *testing.T) {
+	results := executeQuery(t, genExtensions,
+		"SELECT identifier FROM test, json_each(permissions_json) WHERE value = 'webRequest'")
+	if results.Size() != 1 || (*results)[0].Get("identifier") != "aaa" {
+		t.Fatalf("Expected only extension aaa, got %v", *results)
+	}
+
+	// Table columns take precedence over json_each columns of the same name
+	results = executeQuery(t, genExtensions, "SELECT `key`, value FROM test, json_each(permissions_json) AS p")
+	if results.Size() != 3 {
+		t.Fatalf("Expected 3 joined rows, got %d", results.Size())
+	}
+	for _, row := range *results {
+		if row.Get("key") != "ext-key" {
+			t.Errorf("Expected table column key, got %v", row.Get("key"))
+		}
+	}
+}
+
+func TestScalarJSONFunctions(t *testing.T) {
+	results := executeQuery(t, genExtensions,
+		"SELECT identifier, json_extract(manifest_json, '$.name') AS name FROM test WHERE json_extract(manifest_json, '$.manifest_version') = 3")
+	if results.Size() != 1 || (*results)[0].Get("name") != "Alpha" {
+		t.Fatalf("Expected Alpha, got %v", *results)
+	}
+
+	results = executeQuery(t, genExtensions, "SELECT json_array_length(permissions_json) FROM test ORDER BY identifier")
+	if got := (*results)[0].Get("json_array_length(permissions_json)"); got != int64(2) {
+		t.Errorf("Expected array length 2, got %v", got)
+	}
+
+	results = executeQuery(t, genExtensions, "SELECT json_group_array(identifier) AS ids FROM test")
+	if got := (*results)[0].Get("ids"); got != `["aaa","bbb"]` {
+		t.Errorf("Expected JSON array of identifiers, got %v", got)
+	}
+}
+
+func TestDualTable(t *testing.T) {
+	results := executeQuery(t, GenDual, `SELECT json_object('a', 1, 'b', 'x') AS obj`)
+	if got := (*results)[0].Get("obj"); got != `{"a":1,"b":"x"}` {
+		t.Errorf("Expected JSON object, got %v", got)
+	}
+
+	results = executeQuery(t, GenDual, `SELECT fullkey, type FROM json_tree('{"a":[1,2]}')`)
+	if results.Size() != 4 {
+		t.Fatalf("Expected 4 json_tree rows, got %d: %v", results.Size(), *results)
+	}
+}
+
+// genProcesses returns a fixed set of rows shaped like processes
+func genProcesses(ctx *sqlctx.Context) (*result.Results, error) {
+	results := result.NewQueryResult()
+	results.AppendResult(result.FromMap(map[string]interface{}{"pid": int64(4), "name": "System", "path": nil, "size": "1024"}))
+	results.AppendResult(result.FromMap(map[string]interface{}{"pid": int64(812), "name": "cmd.exe", "path": `C:\Windows\System32\cmd.exe`, "size": "2048 bytes"}))
+	results.AppendResult(result.FromMap(map[string]interface{}{"pid": int64(1200), "name": "evil.exe", "path": `C:\Users\bob\AppData\evil.exe`, "size": "4096"}))
+	return results, nil
+}
+
+func TestSQLiteDialect(t *testing.T) {
+	tests := []struct {
+		query string
+		pids  []int64
+	}{
+		{`SELECT pid FROM test WHERE path LIKE 'C:\Windows\%'`, []int64{812}},
+		{`SELECT pid FROM test WHERE "name" = "cmd.exe"`, []int64{812}},
+		{`SELECT pid FROM test WHERE name GLOB '*.exe' AND name NOT GLOB 'CMD*'`, []int64{812, 1200}},
+		{`SELECT pid FROM test WHERE name GLOB 'CMD*'`, nil},
+		{`SELECT pid FROM test WHERE pid IN (4, 1200)`, []int64{4, 1200}},
+		{`SELECT pid FROM test WHERE pid NOT IN (4, 1200)`, []int64{812}},
+		{`SELECT pid FROM test WHERE path IS NULL`, []int64{4}},
+		{`SELECT pid FROM test WHERE path IS NOT NULL AND pid BETWEEN 800 AND 900`, []int64{812}},
+		{`SELECT pid FROM test WHERE pid == 4`, []int64{4}},
+		{`SELECT pid FROM test WHERE pid % 2 = 0 AND pid * 2 > 2000`, []int64{1200}},
+		{`SELECT pid FROM test ORDER BY pid LIMIT 1, 1`, []int64{812}},
+	}
+
+	for _, test := range tests {
+		results := executeQuery(t, genProcesses, test.query)
+		var pids []int64
+		for _, row := range *results {
+			pids = append(pids, row.Get("pid").(int64))
+		}
+		if len(pids) != len(test.pids) {
+			t.Errorf("%s: got pids %v, want %v", test.query, pids, test.pids)
+			continue
+		}
+		for i := range pids {
+			if pids[i] != test.pids[i] {
+				t.Errorf("%s: got pids %v, want %v", test.query, pids, test.pids)
+				break
+			}
+		}
+	}
+}
+
+func TestSQLiteExpressions(t *testing.T) {
+	results := executeQuery(t, genProcesses,
+		`SELECT name || ' (' || pid || ')' AS label, CAST(size AS INTEGER) AS bytes, ifnull(path, '-') AS path FROM test WHERE pid = 812`)
+	if results.Size() != 1 {
+		t.Fatalf("Expected 1 row, got %v", *results)
+	}
+	row := (*results)[0]
+	if row.Get("label") != "cmd.exe (812)" {
+		t.Errorf("Expected concatenated label, got %v", row.Get("label"))
+	}
+	if row.Get("bytes") != int64(2048) {
+		t.Errorf("Expected CAST to use the numeric prefix, got %v", row.Get("bytes"))
+	}
+	if row.Get("path") != `C:\Windows\System32\cmd.exe` {
+		t.Errorf("Expected path, got %v", row.Get("path"))
+	}
+
+	results = executeQuery(t, genProcesses, `SELECT pid / 4 AS quarter, upper(name) FROM test WHERE pid = 4`)
+	row = (*results)[0]
+	if row.Get("quarter") != int64(1) || row.Get("upper(name)") != "SYSTEM" {
+		t.Errorf("Unexpected computed columns: %v", row)
+	}
+}
+
+func TestCaseExpression(t *testing.T) {
+	results := executeQuery(t, genProcesses,
+		`SELECT pid, CASE WHEN pid < 1000 THEN 'system' ELSE 'user' END AS kind, CASE name WHEN 'cmd.exe' THEN 1 END AS shell FROM test`)
+	expected := []struct {
+		kind  string
+		shell interface{}
+	}{{"system", nil}, {"system", 1}, {"user", nil}}
+	if results.Size() != len(expected) {
+		t.Fatalf("Expected %d rows, got %v", len(expected), *results)
+	}
+	for i, row := range *results {
+		if row.Get("kind") != expected[i].kind || row.Get("shell") != expected[i].shell {
+			t.Errorf("Unexpected CASE values for pid %v: %v", row.Get("pid"), row.ToMap())
+		}
+	}
+
+	// CASE can be filtered on like any other expression
+	results = executeQuery(t, genProcesses, `SELECT pid FROM test WHERE CASE WHEN path IS NULL THEN 1 ELSE 0 END`)
+	if results.Size() != 1 || (*results)[0].Get("pid") != int64(4) {
+		t.Errorf("Expected only pid 4, got %v", *results)
+	}
+}
+
+func TestAggregationRows(t *testing.T) {
+	results := executeQuery(t, genMemoryMap, "SELECT permissions, count(*) AS n, max(pid) FROM test GROUP BY permissions")
+	if results.Size() != 1 {
+		t.Fatalf("Expected a single group, got %v", *results)
+	}
+	row := (*results)[0]
+	if row.Get("permissions") != "r-x" || row.Get("n") != 10000 || row.Get("MAX(pid)") != int64(299) {
+		t.Errorf("Unexpected aggregated row: %v", row.ToMap())
+	}
+
+	results = executeQuery(t, genProcesses, "SELECT count(*), count(DISTINCT size) FROM test WHERE pid > 100")
+	row = (*results)[0]
+	if row.Get("COUNT(*)") != 2 || row.Get("COUNT(size)") != 2 {
+		t.Errorf("Unexpected counts: %v", row.ToMap())
+	}
+}
+
+func TestProjectionColumnOrder(t *testing.T) {
+	results := executeQuery(t, genProcesses, "SELECT path, upper(name) AS upper_name, pid FROM test")
+	if got := fmt.Sprint(results.GetColumns()); got != "[path upper_name pid]" {
+		t.Errorf("GetColumns() = %s, want the SELECT order", got)
+	}
+}
+
+func TestPlan(t *testing.T) {
+	query := "SELECT identifier, COUNT(*) FROM test WHERE key = 'ext-key' AND identifier LIKE 'a%' GROUP BY identifier ORDER BY identifier DESC LIMIT 5"
+	parsedQuery, err := parser.Parse(query)
+	if err != nil {
+		t.Fatalf("Failed to parse %q: %v", query, err)
+	}
+	executor := &TableExecutor{TableName: "test", Generator: genExtensions}
+	plan, err := executor.Plan(parsedQuery)
+	if err != nil {
+		t.Fatalf("Failed to plan %q: %v", query, err)
+	}
+
+	expected := "SCAN test\n" +
+		"  columns: identifier, key\n" +
+		"  constraints: identifier = \"a%\", key = \"ext-key\"\n" +
+		"FILTER `key` = 'ext-key' and identifier like 'a%'\n" +
+		"AGGREGATE COUNT(*)\n" +
+		"GROUP BY identifier\n" +
+		"SORT BY identifier desc\n" +
+		"LIMIT 5\n"
+	if plan.String() != expected {
+		t.Errorf("Expected plan:\n%s\ngot:\n%s", expected, plan.String())
+	}
+}
+
+func TestConstants(t *testing.T) {
+	tests := []struct {
+		query     string
+		constants []string
+		rows      int
+	}{
+		// Equality and LIKE, with the column on either side
+		{"SELECT * FROM test WHERE key = 'ext-key'", []string{"ext-key"}, 2},
+		{"SELECT * FROM test WHERE 'ext-key' = key", []string{"ext-key"}, 2},
+		{"SELECT * FROM test WHERE key LIKE 'ext-%'", []string{"ext-%"}, 2},
+		{"SELECT * FROM test WHERE key = ''", []string{""}, 0},
+
+		// IN lists, whose duplicates are dropped, are only pushed down whole
+		{"SELECT * FROM test WHERE key IN ('a', 'b')", []string{"a", "b"}, 0},
+		{"SELECT * FROM test WHERE key IN ('ext-key', 'a', 'ext-key')", []string{"ext-key", "a"}, 2},
+		{"SELECT * FROM test WHERE key IN ('a', identifier)", nil, 0},
+		{"SELECT * FROM test WHERE key NOT IN ('a', 'b')", nil, 2},
+
+		// Other comparisons leave the generator unconstrained
+		{"SELECT * FROM test WHERE key != 'a'", nil, 2},
+		{"SELECT * FROM test WHERE key <> 'a'", nil, 2},
+		{"SELECT * FROM test WHERE key > 'a'", nil, 2},
+		{"SELECT * FROM test WHERE key >= 'ext-key'", nil, 2},
+		{"SELECT * FROM test WHERE key < 'z'", nil, 2},
+		{"SELECT * FROM test WHERE key NOT LIKE 'a%'", nil, 2},
+		{"SELECT * FROM test WHERE NOT key = 'a'", nil, 2},
+
+		// Either side of an OR may match, so neither is pushed down
+		{"SELECT * FROM test WHERE key = 'a' OR identifier = 'aaa'", nil, 1},
+		{"SELECT * FROM test WHERE key = 'a' OR key = 'ext-key'", nil, 2},
+		{"SELECT * FROM test WHERE identifier = 'aaa' AND (key = 'a' OR key = 'b')", nil, 0},
+
+		// Both sides of an AND constrain the rows
+		{"SELECT * FROM test WHERE (key = 'ext-key') AND identifier = 'bbb'", []string{"ext-key"}, 1},
+		{"SELECT * FROM test WHERE key = 'ext-key' AND key IN ('a', 'ext-key')", []string{"ext-key", "a"}, 2},
+	}
+	for _, test := range tests {
+		var constants []string
+		constrained := false
+		results := executeQuery(t, func(ctx *sqlctx.Context) (*result.Results, error) {
+			constants = ctx.GetConstants("key")
+			constrained = ctx.HasConstant("key")
+			return genExtensions(ctx)
+		}, test.query)
+		if fmt.Sprintf("%q", constants) != fmt.Sprintf("%q", test.constants) {
+			t.Errorf("%s: expected constants %q, got %q", test.query, test.constants, constants)
+		}
+		// An empty string constant is a constraint, unlike no constant at all
+		if constrained != (test.constants != nil) {
+			t.Errorf("%s: expected HasConstant %v", test.query, test.constants != nil)
+		}
+		if results.Size() != test.rows {
+			t.Errorf("%s: expected %d rows, got %d", test.query, test.rows, results.Size())
+		}
+	}
+}
+
+func TestStats(t *testing.T) {
+	parsedQuery, err := parser.Parse("SELECT identifier FROM test WHERE identifier = 'bbb'")
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	executor := &TableExecutor{TableName: "test", Generator: genExtensions}
+	if _, err := executor.Execute(parsedQuery); err != nil {
+		t.Fatalf("Failed to execute: %v", err)
+	}
+	if executor.Stats.GeneratedRows != 2 {
+		t.Errorf("Expected 2 generated rows, got %d", executor.Stats.GeneratedRows)
+	}
+	if executor.Stats.GeneratorTime <= 0 || executor.Stats.ProcessingTime <= 0 {
+		t.Errorf("Expected positive durations, got %+v", executor.Stats)
+	}
+}
@@ -0,0 +1,62 @@
+//go:build linux
+
+package execintf
+
+import (
+	"fmt"
+
+	"github.com/scrymastic/goosquery/sql/executor/impl"
+	"github.com/scrymastic/goosquery/tables/system"
+)
+
+// getPlatformExecutor returns the executor of a table only provided on Linux
+func getPlatformExecutor(tableName string) (Executor, error) {
+	return getExecutorSystemLinux(tableName)
+}
+
+func getExecutorSystemLinux(tableName string) (Executor, error) {
+	switch tableName {
+	case "apk_packages":
+		return &impl.TableExecutor{
+			TableName: "apk_packages",
+			Generator: system.GenApkPackages,
+		}, nil
+	case "block_devices":
+		return &impl.TableExecutor{
+			TableName: "block_devices",
+			Generator: system.GenBlockDevices,
+		}, nil
+	case "crontab":
+		return &impl.TableExecutor{
+			TableName: "crontab",
+			Generator: system.GenCrontab,
+		}, nil
+	case "deb_package_files":
+		return &impl.TableExecutor{
+			TableName: "deb_package_files",
+			Generator: system.GenDebPackageFiles,
+		}, nil
+	case "deb_packages":
+		return &impl.TableExecutor{
+			TableName: "deb_packages",
+			Generator: system.GenDebPackages,
+		}, nil
+	case "kernel_modules":
+		return &impl.TableExecutor{
+			TableName: "kernel_modules",
+			Generator: system.GenKernelModules,
+		}, nil
+	case "mounts":
+		return &impl.TableExecutor{
+			TableName: "mounts",
+			Generator: system.GenMounts,
+		}, nil
+	case "systemd_units":
+		return &impl.TableExecutor{
+			TableName: "systemd_units",
+			Generator: system.GenSystemdUnits,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported table: %s", tableName)
+	}
+}
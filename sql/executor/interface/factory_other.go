@@ -0,0 +1,10 @@
+//go:build !linux && !windows
+
+package execintf
+
+import "fmt"
+
+// getPlatformExecutor fails, as only the portable tables are provided here
+func getPlatformExecutor(tableName string) (Executor, error) {
+	return nil, fmt.Errorf("unsupported table: %s", tableName)
+}
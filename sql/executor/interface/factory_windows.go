@@ -0,0 +1,311 @@
+//go:build windows
+
+package execintf
+
+import (
+	"fmt"
+
+	"github.com/scrymastic/goosquery/sql/executor/impl"
+	"github.com/scrymastic/goosquery/tables/networking"
+	"github.com/scrymastic/goosquery/tables/system"
+	"github.com/scrymastic/goosquery/tables/utility"
+)
+
+// getPlatformExecutor returns the executor of a table only provided on Windows
+func getPlatformExecutor(tableName string) (Executor, error) {
+	executor, err := getExecutorNetworkingWindows(tableName)
+	if err == nil {
+		return executor, nil
+	}
+	executor, err = getExecutorSystemWindows(tableName)
+	if err == nil {
+		return executor, nil
+	}
+	return getExecutorUtilityWindows(tableName)
+}
+
+func getExecutorNetworkingWindows(tableName string) (Executor, error) {
+	switch tableName {
+	case "connectivity":
+		return &impl.TableExecutor{
+			TableName: "connectivity",
+			Generator: networking.GenConnectivity,
+		}, nil
+	case "windows_firewall_rules":
+		return &impl.TableExecutor{
+			TableName: "windows_firewall_rules",
+			Generator: networking.GenWindowsFirewallRules,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported table: %s", tableName)
+	}
+}
+
+func getExecutorSystemWindows(tableName string) (Executor, error) {
+	switch tableName {
+	case "appcompat_shims":
+		return &impl.TableExecutor{
+			TableName: "appcompat_shims",
+			Generator: system.GenAppCompatShims,
+		}, nil
+	case "authenticode":
+		return &impl.TableExecutor{
+			TableName: "authenticode",
+			Generator: system.GenAuthenticode,
+		}, nil
+	case "autoexec":
+		return &impl.TableExecutor{
+			TableName: "autoexec",
+			Generator: system.GenAutoexec,
+		}, nil
+	case "background_activities_moderator":
+		return &impl.TableExecutor{
+			TableName: "background_activities_moderator",
+			Generator: system.GenBackgroundActivitiesModerator,
+		}, nil
+	case "bitlocker_info":
+		return &impl.TableExecutor{
+			TableName: "bitlocker_info",
+			Generator: system.GenBitlockerInfo,
+		}, nil
+	case "certificates":
+		return &impl.TableExecutor{
+			TableName: "certificates",
+			Generator: system.GenCertificates,
+		}, nil
+	case "chassis_info":
+		return &impl.TableExecutor{
+			TableName: "chassis_info",
+			Generator: system.GenChassisInfo,
+		}, nil
+	case "chocolatey_packages":
+		return &impl.TableExecutor{
+			TableName: "chocolatey_packages",
+			Generator: system.GenChocolateyPackages,
+		}, nil
+	case "cpuid":
+		return &impl.TableExecutor{
+			TableName: "cpuid",
+			Generator: system.GenCpuId,
+		}, nil
+	case "default_environment":
+		return &impl.TableExecutor{
+			TableName: "default_environment",
+			Generator: system.GenDefaultEnvironments,
+		}, nil
+	case "deviceguard_status":
+		return &impl.TableExecutor{
+			TableName: "deviceguard_status",
+			Generator: system.GenDeviceGuardStatus,
+		}, nil
+	case "disk_info":
+		return &impl.TableExecutor{
+			TableName: "disk_info",
+			Generator: system.GenDiskInfo,
+		}, nil
+	case "dns_cache":
+		return &impl.TableExecutor{
+			TableName: "dns_cache",
+			Generator: system.GenDnsCache,
+		}, nil
+	case "drivers":
+		return &impl.TableExecutor{
+			TableName: "drivers",
+			Generator: system.GenDrivers,
+		}, nil
+	case "ie_extensions":
+		return &impl.TableExecutor{
+			TableName: "ie_extensions",
+			Generator: system.GenIeExtensions,
+		}, nil
+	case "kva_speculative_info":
+		return &impl.TableExecutor{
+			TableName: "kva_speculative_info",
+			Generator: system.GenKvaSpeculativeInfo,
+		}, nil
+	case "logical_drives":
+		return &impl.TableExecutor{
+			TableName: "logical_drives",
+			Generator: system.GenLogicalDrives,
+		}, nil
+	case "logon_sessions":
+		return &impl.TableExecutor{
+			TableName: "logon_sessions",
+			Generator: system.GenLogonSessions,
+		}, nil
+	case "memory_devices":
+		return &impl.TableExecutor{
+			TableName: "memory_devices",
+			Generator: system.GenMemoryDevices,
+		}, nil
+	case "ntdomains":
+		return &impl.TableExecutor{
+			TableName: "ntdomains",
+			Generator: system.GenNTDomains,
+		}, nil
+	case "ntfs_acl_permissions":
+		return &impl.TableExecutor{
+			TableName: "ntfs_acl_permissions",
+			Generator: system.GenNtfsAclPermissions,
+		}, nil
+	case "patches":
+		return &impl.TableExecutor{
+			TableName: "patches",
+			Generator: system.GenPatches,
+		}, nil
+	case "pipes":
+		return &impl.TableExecutor{
+			TableName: "pipes",
+			Generator: system.GenPipes,
+		}, nil
+	case "prefetch":
+		return &impl.TableExecutor{
+			TableName: "prefetch",
+			Generator: system.GenPrefetch,
+		}, nil
+	case "process_memory_map":
+		return &impl.TableExecutor{
+			TableName: "process_memory_map",
+			Generator: system.GenProcessMemoryMap,
+		}, nil
+	case "programs":
+		return &impl.TableExecutor{
+			TableName: "programs",
+			Generator: system.GenPrograms,
+		}, nil
+	case "registry":
+		return &impl.TableExecutor{
+			TableName: "registry",
+			Generator: system.GenRegistry,
+		}, nil
+	case "scheduled_tasks":
+		return &impl.TableExecutor{
+			TableName: "scheduled_tasks",
+			Generator: system.GenScheduledTasks,
+		}, nil
+	case "security_profile_info":
+		return &impl.TableExecutor{
+			TableName: "security_profile_info",
+			Generator: system.GenSecurityProfileInfo,
+		}, nil
+	case "services":
+		return &impl.TableExecutor{
+			TableName: "services",
+			Generator: system.GenServices,
+		}, nil
+	case "shared_resources":
+		return &impl.TableExecutor{
+			TableName: "shared_resources",
+			Generator: system.GenSharedResources,
+		}, nil
+	case "shellbags":
+		return &impl.TableExecutor{
+			TableName: "shellbags",
+			Generator: system.GenShellbags,
+		}, nil
+	case "shimcache":
+		return &impl.TableExecutor{
+			TableName: "shimcache",
+			Generator: system.GenShimcache,
+		}, nil
+	case "tpm_info":
+		return &impl.TableExecutor{
+			TableName: "tpm_info",
+			Generator: system.GenTpmInfo,
+		}, nil
+	case "user_ssh_keys":
+		return &impl.TableExecutor{
+			TableName: "user_ssh_keys",
+			Generator: system.GenUserSshKeys,
+		}, nil
+	case "userassist":
+		return &impl.TableExecutor{
+			TableName: "userassist",
+			Generator: system.GenUserAssist,
+		}, nil
+	case "video_info":
+		return &impl.TableExecutor{
+			TableName: "video_info",
+			Generator: system.GenVideoInfo,
+		}, nil
+	case "winbaseobj":
+		return &impl.TableExecutor{
+			TableName: "winbaseobj",
+			Generator: system.GenWinbaseObj,
+		}, nil
+	case "windows_crashes":
+		return &impl.TableExecutor{
+			TableName: "windows_crashes",
+			Generator: system.GenWindowsCrashes,
+		}, nil
+	case "windows_eventlog":
+		return &impl.TableExecutor{
+			TableName: "windows_eventlog",
+			Generator: system.GenWindowsEventLog,
+		}, nil
+	case "windows_optional_features":
+		return &impl.TableExecutor{
+			TableName: "windows_optional_features",
+			Generator: system.GenWindowsOptionalFeatures,
+		}, nil
+	case "windows_search":
+		return &impl.TableExecutor{
+			TableName: "windows_search",
+			Generator: system.GenWindowsSearch,
+		}, nil
+	case "windows_security_center":
+		return &impl.TableExecutor{
+			TableName: "windows_security_center",
+			Generator: system.GenWindowsSecurityCenter,
+		}, nil
+	case "windows_security_products":
+		return &impl.TableExecutor{
+			TableName: "windows_security_products",
+			Generator: system.GenWindowsSecurityProducts,
+		}, nil
+	case "windows_update_history":
+		return &impl.TableExecutor{
+			TableName: "windows_update_history",
+			Generator: system.GenWindowsUpdateHistory,
+		}, nil
+	case "wmi_bios_info":
+		return &impl.TableExecutor{
+			TableName: "wmi_bios_info",
+			Generator: system.GenWmiBiosInfo,
+		}, nil
+	case "wmi_cli_event_consumers":
+		return &impl.TableExecutor{
+			TableName: "wmi_cli_event_consumers",
+			Generator: system.GenWmiCliEventConsumers,
+		}, nil
+	case "wmi_event_filters":
+		return &impl.TableExecutor{
+			TableName: "wmi_event_filters",
+			Generator: system.GenWmiEventFilters,
+		}, nil
+	case "wmi_filter_consumer_binding":
+		return &impl.TableExecutor{
+			TableName: "wmi_filter_consumer_binding",
+			Generator: system.GenWmiFilterConsumerBinding,
+		}, nil
+	case "wmi_script_event_consumers":
+		return &impl.TableExecutor{
+			TableName: "wmi_script_event_consumers",
+			Generator: system.GenWmiScriptEventConsumers,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported table: %s", tableName)
+	}
+}
+
+func getExecutorUtilityWindows(tableName string) (Executor, error) {
+	switch tableName {
+	case "file":
+		return &impl.TableExecutor{
+			TableName: "file",
+			Generator: utility.GenFile,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported table: %s", tableName)
+	}
+}
@@ -0,0 +1,57 @@
+package execintf
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+
+	"github.com/scrymastic/goosquery/sql/result"
+	"github.com/scrymastic/goosquery/tables/applications"
+	"github.com/scrymastic/goosquery/tables/networking"
+	"github.com/scrymastic/goosquery/tables/system"
+	"github.com/scrymastic/goosquery/tables/utility"
+)
+
+// AllTables returns the tables of every platform, sorted by name
+func AllTables() []result.Table {
+	var tables []result.Table
+	tables = append(tables, applications.Tables...)
+	tables = append(tables, networking.Tables...)
+	tables = append(tables, system.Tables...)
+	tables = append(tables, utility.Tables...)
+
+	sort.Slice(tables, func(i, j int) bool {
+		return tables[i].Name < tables[j].Name
+	})
+	return tables
+}
+
+// GetTables returns the tables supported by GetExecutor on this platform,
+// sorted by name
+func GetTables() []result.Table {
+	var tables []result.Table
+	for _, table := range AllTables() {
+		if table.AvailableOn(runtime.GOOS) {
+			tables = append(tables, table)
+		}
+	}
+	return tables
+}
+
+// GetSchema returns the schema of a supported table
+func GetSchema(tableName string) (result.Schema, error) {
+	if table, ok := findTable(GetTables(), tableName); ok {
+		return table.Schema, nil
+	}
+	return nil, fmt.Errorf("unsupported table: %s", tableName)
+}
+
+// findTable looks a table up by name
+func findTable(tables []result.Table, tableName string) (result.Table, bool) {
+	for _, table := range tables {
+		if table.Name == tableName {
+			return table, true
+		}
+	}
+	return result.Table{}, false
+}
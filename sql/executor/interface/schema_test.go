@@ -0,0 +1,43 @@
+package execintf
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/scrymastic/goosquery/sql/result"
+)
+
+func TestGetTables(t *testing.T) {
+	all := AllTables()
+	tables := GetTables()
+	if len(tables) == 0 || len(tables) > len(all) {
+		t.Fatalf("Expected between 1 and %d tables, got %d", len(all), len(tables))
+	}
+	for _, table := range tables {
+		if !table.AvailableOn(runtime.GOOS) {
+			t.Errorf("Table %s is listed but not provided on %s", table.Name, runtime.GOOS)
+		}
+		// Every listed table must have an executor on this platform
+		if _, err := GetExecutor(table.Name); err != nil {
+			t.Errorf("No executor for table %s: %v", table.Name, err)
+		}
+	}
+
+	for _, name := range []string{"time", "hash", "etc_hosts"} {
+		if _, err := GetSchema(name); err != nil {
+			t.Errorf("Expected the portable table %s to be listed: %v", name, err)
+		}
+	}
+}
+
+func TestTablesHaveUniqueNames(t *testing.T) {
+	for name, tables := range map[string][]result.Table{"AllTables": AllTables(), "GetTables": GetTables()} {
+		seen := make(map[string]bool)
+		for _, table := range tables {
+			if seen[table.Name] {
+				t.Errorf("Table %s is listed twice by %s", table.Name, name)
+			}
+			seen[table.Name] = true
+		}
+	}
+}
@@ -40,8 +40,8 @@ func Compare(a, b interface{}) int {
 	}
 
 	// Convert both to string and compare lexically
-	aStr := fmt.Sprintf("%v", a)
-	bStr := fmt.Sprintf("%v", b)
+	aStr := FormatValue(a)
+	bStr := FormatValue(b)
 
 	// Additional attempt for numeric string comparison
 	// This helps with sorting columns that store numbers as strings
@@ -60,13 +60,7 @@ func Compare(a, b interface{}) int {
 	}
 
 	// Fallback to lexical comparison
-	if aStr < bStr {
-		return -1
-	}
-	if aStr > bStr {
-		return 1
-	}
-	return 0
+	return strings.Compare(aStr, bStr)
 }
 
 // ToFloat64 converts a value to float64 if possible
@@ -106,43 +100,77 @@ func ToFloat64(v interface{}) (float64, bool) {
 	return 0, false
 }
 
-// SortResults sorts the results based on ORDER BY clause
+// SortResults sorts the results based on ORDER BY clause. The sort keys of
+// every row are extracted and prepared once instead of at each comparison.
 func SortResults(results *result.Results, orderBy sqlparser.OrderBy) error {
 	if len(orderBy) == 0 || len(*results) == 0 {
 		return nil
 	}
 
-	// Create a copy of the results for sorting
-	sortedResults := append(result.Results{}, *results...)
+	// Resolve the sort columns and directions once
+	var columns []string
+	var ascending []bool
+	for _, order := range orderBy {
+		colName, ok := order.Expr.(*sqlparser.ColName)
+		if !ok {
+			// Unsupported expression type
+			continue
+		}
+		columns = append(columns, colName.Name.String())
+		// In vitess-sqlparser, order.Direction is a string representation
+		direction := string(order.Direction)
+		ascending = append(ascending, direction == "" || strings.ToLower(direction) == "asc")
+	}
+	if len(columns) == 0 {
+		return nil
+	}
+
+	type sortRow struct {
+		row  result.Result
+		keys []PreparedValue
+	}
+	rows := make([]sortRow, len(*results))
+	for i, row := range *results {
+		keys := make([]PreparedValue, len(columns))
+		for j, column := range columns {
+			keys[j] = Prepare(row.Get(column))
+		}
+		rows[i] = sortRow{row: row, keys: keys}
+	}
 
 	// Sort the results
-	sort.SliceStable(sortedResults, func(i, j int) bool {
-		return CompareRows(sortedResults[i], sortedResults[j], orderBy)
+	sort.SliceStable(rows, func(i, j int) bool {
+		for k := range columns {
+			cmp := ComparePrepared(&rows[i].keys[k], &rows[j].keys[k])
+			if cmp != 0 {
+				// Return result based on sort direction
+				return (ascending[k] && cmp < 0) || (!ascending[k] && cmp > 0)
+			}
+			// If equal, continue to next ORDER BY expression
+		}
+		return false
 	})
 
-	// Update the original results
+	// Create a sorted copy of the results and update the original results
+	sortedResults := make(result.Results, len(rows))
+	for i, row := range rows {
+		sortedResults[i] = row.row
+	}
 	*results = sortedResults
 	return nil
 }
 
 // CompareRows compares two rows based on ORDER BY expressions
-func CompareRows(a, b map[string]interface{}, orderBy sqlparser.OrderBy) bool {
+func CompareRows(a, b result.Result, orderBy sqlparser.OrderBy) bool {
 	for _, order := range orderBy {
 		var aVal, bVal interface{}
-		var exists bool
 
 		// Extract column values
 		switch expr := order.Expr.(type) {
 		case *sqlparser.ColName:
 			colName := expr.Name.String()
-			aVal, exists = a[colName]
-			if !exists {
-				aVal = nil
-			}
-			bVal, exists = b[colName]
-			if !exists {
-				bVal = nil
-			}
+			aVal = a.Get(colName)
+			bVal = b.Get(colName)
 		default:
 			// Unsupported expression type
 			continue
@@ -240,19 +268,13 @@ func ExtractLiteralValue(expr sqlparser.Expr) interface{} {
 
 // MatchesLike checks if a value matches a LIKE pattern
 func MatchesLike(a, b interface{}) bool {
-	// Convert both to strings
-	aStr := fmt.Sprintf("%v", a)
-	bStr := fmt.Sprintf("%v", b)
-
-	// Escape special regex characters in the pattern
-	pattern := strings.Replace(strings.Replace(bStr, "%", ".*", -1), "_", ".", -1)
-	pattern = "^" + pattern + "$" // Ensure full string match
-
-	// Only use case-insensitive matching if the compiler supports it
-	reStr := "(?i)" + pattern
+	return LikeRegexp(FormatValue(b)).MatchString(FormatValue(a))
+}
 
-	// Check if the string matches the pattern
-	matched, err := regexp.MatchString(reStr, aStr)
+// MatchesRegexp checks if a value matches a regular expression, as used by
+// REGEXP and by GLOB patterns translated to regular expressions
+func MatchesRegexp(a, b interface{}) bool {
+	matched, err := regexp.MatchString(FormatValue(b), FormatValue(a))
 	if err != nil {
 		return false
 	}
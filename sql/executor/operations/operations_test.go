@@ -0,0 +1,99 @@
+package operations
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/blastrain/vitess-sqlparser/sqlparser"
+	"github.com/scrymastic/goosquery/sql/result"
+)
+
+var compareValues = []interface{}{
+	nil, int64(0), int64(-3), int64(42), 42, uint64(7), 2.5, float32(1.5), "42", "042", "2.5", "abc", "ABC", "", " 7", true, []byte("x"),
+}
+
+func TestPreparedValueMatchesCompare(t *testing.T) {
+	for _, a := range compareValues {
+		for _, b := range compareValues {
+			prepared := Prepare(b)
+			if got, want := prepared.CompareTo(a), Compare(a, b); got != want {
+				t.Errorf("Prepare(%#v).CompareTo(%#v) = %d, want %d", b, a, got, want)
+			}
+			preparedA := Prepare(a)
+			if got, want := ComparePrepared(&preparedA, &prepared), Compare(a, b); got != want {
+				t.Errorf("ComparePrepared(%#v, %#v) = %d, want %d", a, b, got, want)
+			}
+		}
+	}
+}
+
+func TestMatchesLike(t *testing.T) {
+	tests := []struct {
+		value, pattern string
+		want           bool
+	}{
+		{`C:\Windows\System32\cmd.exe`, `C:\Windows\%`, true},
+		{`C:\Windows\System32\cmd.exe`, `c:\windows\%.EXE`, true},
+		{`cmdxexe`, `cmd.exe`, false},
+		{`a+b`, `a+_`, true},
+	}
+	for _, test := range tests {
+		if got := MatchesLike(test.value, test.pattern); got != test.want {
+			t.Errorf("%q LIKE %q = %v, want %v", test.value, test.pattern, got, test.want)
+		}
+	}
+}
+
+// benchmarkRows returns rows shaped like process_memory_map, with numbers
+// stored as text as most table generators do
+func benchmarkRows(n int) result.Results {
+	rows := make(result.Results, n)
+	for i := range rows {
+		rows[i] = result.FromMap(map[string]interface{}{
+			"pid":   int64(i % 300),
+			"start": fmt.Sprintf("0x%08x", i*4096),
+			"path":  fmt.Sprintf(`C:\Windows\System32\lib%d.dll`, i%500),
+			"size":  fmt.Sprintf("%d", (i*7919)%100000),
+		})
+	}
+	return rows
+}
+
+func BenchmarkCompare(b *testing.B) {
+	rows := benchmarkRows(1000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, row := range rows {
+			Compare(row.Get("path"), `C:\Windows\System32\lib7.dll`)
+			Compare(row.Get("size"), 5000)
+		}
+	}
+}
+
+func BenchmarkPreparedCompare(b *testing.B) {
+	rows := benchmarkRows(1000)
+	path := Prepare(`C:\Windows\System32\lib7.dll`)
+	size := Prepare(5000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, row := range rows {
+			path.CompareTo(row.Get("path"))
+			size.CompareTo(row.Get("size"))
+		}
+	}
+}
+
+func BenchmarkSortResults(b *testing.B) {
+	rows := benchmarkRows(10000)
+	orderBy := sqlparser.OrderBy{
+		&sqlparser.Order{Expr: &sqlparser.ColName{Name: sqlparser.NewColIdent("size")}, Direction: sqlparser.DescScr},
+		&sqlparser.Order{Expr: &sqlparser.ColName{Name: sqlparser.NewColIdent("path")}, Direction: sqlparser.AscScr},
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		results := append(result.Results{}, rows...)
+		if err := SortResults(&results, orderBy); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
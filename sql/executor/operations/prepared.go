@@ -0,0 +1,139 @@
+package operations
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// PreparedValue holds a value together with its numeric and text forms, so
+// that it can be compared against many other values without converting it
+// again each time. Literals of a query and ORDER BY keys are prepared once.
+type PreparedValue struct {
+	Value interface{}
+
+	null     bool
+	num      float64
+	isNum    bool
+	str      string
+	strNum   float64
+	isStrNum bool
+}
+
+// Prepare converts a value into a PreparedValue
+func Prepare(value interface{}) PreparedValue {
+	p := PreparedValue{Value: value, null: value == nil}
+	if p.null {
+		return p
+	}
+	p.num, p.isNum = ToFloat64(value)
+	p.str = FormatValue(value)
+	if p.isNum {
+		p.strNum, p.isStrNum = p.num, true
+	} else {
+		p.strNum, p.isStrNum = ToFloat64(p.str)
+	}
+	return p
+}
+
+// CompareTo compares a value against the prepared value and returns -1, 0,
+// or 1 exactly like Compare(a, p.Value). A text value is only parsed as a
+// number when the prepared value is numeric, as otherwise the outcome does
+// not depend on it.
+func (p *PreparedValue) CompareTo(a interface{}) int {
+	if a == nil {
+		if p.null {
+			return 0
+		}
+		return -1
+	}
+	if p.null {
+		return 1
+	}
+
+	if p.isNum || p.isStrNum {
+		if aNum, ok := ToFloat64(a); ok {
+			if p.isNum {
+				return compareFloats(aNum, p.num)
+			}
+			// Compare falls back to the text forms, which are both numeric here
+			if aStrNum, ok := ToFloat64(FormatValue(a)); ok {
+				return compareFloats(aStrNum, p.strNum)
+			}
+		} else if aStrNum, ok := ToFloat64(FormatValue(a)); ok {
+			return compareFloats(aStrNum, p.strNum)
+		}
+	}
+	return strings.Compare(FormatValue(a), p.str)
+}
+
+// ComparePrepared compares two prepared values like Compare(a.Value, b.Value)
+func ComparePrepared(a, b *PreparedValue) int {
+	switch {
+	case a.null && b.null:
+		return 0
+	case a.null:
+		return -1
+	case b.null:
+		return 1
+	case a.isNum && b.isNum:
+		return compareFloats(a.num, b.num)
+	case a.isStrNum && b.isStrNum:
+		return compareFloats(a.strNum, b.strNum)
+	}
+	return strings.Compare(a.str, b.str)
+}
+
+// FormatValue returns the text form of a value, as fmt.Sprintf("%v") does,
+// without going through fmt for the common types
+func FormatValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case int:
+		return strconv.Itoa(v)
+	case int32:
+		return strconv.FormatInt(int64(v), 10)
+	case uint64:
+		return strconv.FormatUint(v, 10)
+	case uint32:
+		return strconv.FormatUint(uint64(v), 10)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// LikeRegexp compiles a LIKE pattern into a case-insensitive regular
+// expression, so that a constant pattern is only translated once per query
+func LikeRegexp(pattern string) *regexp.Regexp {
+	// Escape special regex characters in the pattern, so that literal
+	// dots and backslashes in paths such as 'C:\Windows\%.exe' match as is
+	var sb strings.Builder
+	sb.WriteString("(?i)^")
+	for _, c := range pattern {
+		switch c {
+		case '%':
+			sb.WriteString("(?s:.*)")
+		case '_':
+			sb.WriteString("(?s:.)")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	sb.WriteString("$") // Ensure full string match
+	return regexp.MustCompile(sb.String())
+}
+
+func compareFloats(a, b float64) int {
+	if a < b {
+		return -1
+	}
+	if a > b {
+		return 1
+	}
+	return 0
+}
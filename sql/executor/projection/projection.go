@@ -2,14 +2,77 @@ package projection
 
 import (
 	"fmt"
-	"strings"
 
 	"github.com/blastrain/vitess-sqlparser/sqlparser"
+	"github.com/scrymastic/goosquery/sql/executor/aggregation"
+	"github.com/scrymastic/goosquery/sql/executor/functions"
 	"github.com/scrymastic/goosquery/sql/result"
 )
 
+// EvaluateExpressions computes the scalar function calls, casts, arithmetic
+// and literals of the SELECT list for every row and stores each value under
+// its output column name, so that GROUP BY, ORDER BY and the final projection
+// can refer to it
+func EvaluateExpressions(results *result.Results, stmt *sqlparser.Select) error {
+	for _, expr := range stmt.SelectExprs {
+		aliasedExpr, ok := expr.(*sqlparser.AliasedExpr)
+		if !ok || !isComputedExpression(aliasedExpr.Expr) {
+			continue
+		}
+		if funcExpr, ok := aliasedExpr.Expr.(*sqlparser.FuncExpr); ok && !functions.IsScalar(funcExpr.Name.String()) {
+			return fmt.Errorf("unsupported function: %s", funcExpr.Name.String())
+		}
+
+		compiled, err := functions.Compile(aliasedExpr.Expr)
+		if err != nil {
+			return err
+		}
+
+		column := computedColumnName(aliasedExpr)
+		for i := range *results {
+			value, err := compiled.Eval((*results)[i])
+			if err != nil {
+				return err
+			}
+			(*results)[i].Add(column, value)
+		}
+	}
+	return nil
+}
+
+// computedColumnName returns the output column name of a computed expression:
+// its alias if present, otherwise the expression text
+func computedColumnName(expr *sqlparser.AliasedExpr) string {
+	if !expr.As.IsEmpty() {
+		return expr.As.String()
+	}
+	return sqlparser.String(expr.Expr)
+}
+
+// isComputedExpression checks if a SELECT expression is computed per row,
+// i.e. it is neither a plain column reference nor an aggregation
+func isComputedExpression(expr sqlparser.Expr) bool {
+	switch expr := expr.(type) {
+	case *sqlparser.ColName:
+		return false
+	case *sqlparser.FuncExpr:
+		return !aggregation.IsAggregateFunction(expr.Name.String())
+	}
+	return true
+}
+
+// projectedColumn is a column of the final result: the value stored under
+// source, or under fallback when absent, is returned under name
+type projectedColumn struct {
+	source   string
+	fallback string
+	name     string
+	star     bool
+}
+
 // ProjectFinalResults applies final projection to the result to ensure only the requested columns are returned
-// It handles column selection, aliases from SELECT, and aggregation function results
+// It handles column selection, aliases from SELECT, and aggregation function results.
+// Columns are returned in the order of the SELECT list.
 func ProjectFinalResults(results *result.Results, stmt *sqlparser.Select) *result.Results {
 	// If there are no results, return empty result
 	if len(*results) == 0 {
@@ -21,121 +84,87 @@ func ProjectFinalResults(results *result.Results, stmt *sqlparser.Select) *resul
 		return results
 	}
 
-	// Create a new result with only the requested columns, including aliases
-	projectedResult := result.NewQueryResult()
-
-	// Build a map of original column names to their aliases
-	aliasMap := buildAliasMap(stmt.SelectExprs)
-
 	// Get the list of columns to include in the final result
-	finalColumns := getFinalProjectionColumns(stmt.SelectExprs)
+	columns := getProjectedColumns(stmt.SelectExprs)
+
+	// Create a new result with only the requested columns, including aliases,
+	// sharing one column index in SELECT order
+	projectedResult := make(result.Results, 0, len(*results))
+	index := result.NewColumnIndex()
 
 	// Apply projection for each row
 	for _, row := range *results {
-		projectedRow := make(map[string]interface{})
-
-		// Handle columns with aliases
-		for originalCol, alias := range aliasMap {
-			if value, exists := row[originalCol]; exists {
-				projectedRow[alias] = value
-			}
-		}
-
-		// Handle direct columns (no aliases)
-		for _, col := range finalColumns {
-			if _, isAliased := aliasMap[col]; isAliased {
-				// Already handled above
+		projectedRow := index.NewRow()
+
+		for _, col := range columns {
+			if col.star {
+				row.Range(func(name string, value interface{}) bool {
+					projectedRow.Add(name, value)
+					return true
+				})
 				continue
 			}
 
-			// Not an alias, use the column directly
-			if value, exists := row[col]; exists {
-				projectedRow[col] = value
+			if value, exists := row.Lookup(col.source); exists {
+				projectedRow.Add(col.name, value)
+			} else if value, exists := row.Lookup(col.fallback); exists {
+				// Aggregations and computed values are stored under their alias directly
+				projectedRow.Add(col.name, value)
 			}
 		}
 
-		projectedResult.AppendResult(projectedRow)
+		projectedResult = append(projectedResult, projectedRow)
 	}
 
-	return projectedResult
+	return &projectedResult
 }
 
-// buildAliasMap creates a map of original column names to their aliases
-func buildAliasMap(selectExprs sqlparser.SelectExprs) map[string]string {
-	aliasMap := make(map[string]string)
-
-	for _, expr := range selectExprs {
-		aliasedExpr, ok := expr.(*sqlparser.AliasedExpr)
-		if !ok || aliasedExpr.As.IsEmpty() {
-			continue
-		}
-
-		alias := aliasedExpr.As.String()
-
-		// Handle direct column reference with alias
-		if colName, ok := aliasedExpr.Expr.(*sqlparser.ColName); ok {
-			aliasMap[colName.Name.String()] = alias
-			continue
-		}
-
-		// Handle aggregation function with alias
-		if funcExpr, ok := aliasedExpr.Expr.(*sqlparser.FuncExpr); ok {
-			funcName := strings.ToUpper(funcExpr.Name.String())
-
-			// For other aggregation functions with a single column argument
-			if len(funcExpr.Exprs) > 0 {
-				if argAliasedExpr, ok := funcExpr.Exprs[0].(*sqlparser.AliasedExpr); ok {
-					if colName, ok := argAliasedExpr.Expr.(*sqlparser.ColName); ok {
-						// Create aggregate function name as key
-						originalCol := fmt.Sprintf("%s(%s)", funcName, colName.Name.String())
-						aliasMap[originalCol] = alias
-					}
-				}
-			}
-		}
-	}
-
-	return aliasMap
-}
-
-// getFinalProjectionColumns gets the list of columns to include in the final result
-func getFinalProjectionColumns(selectExprs sqlparser.SelectExprs) []string {
-	var columns []string
+// getProjectedColumns gets the list of columns to include in the final result
+func getProjectedColumns(selectExprs sqlparser.SelectExprs) []projectedColumn {
+	var columns []projectedColumn
 
 	for _, expr := range selectExprs {
 		switch expr := expr.(type) {
 		case *sqlparser.AliasedExpr:
-			// For direct column reference
-			if colName, ok := expr.Expr.(*sqlparser.ColName); ok {
-				// If there's no alias, use the column name
-				if expr.As.IsEmpty() {
-					columns = append(columns, colName.Name.String())
+			switch {
+			case isComputedExpression(expr.Expr):
+				// Computed values are stored under their output column name
+				name := computedColumnName(expr)
+				columns = append(columns, projectedColumn{source: name, fallback: name, name: name})
+
+			case isColumn(expr.Expr):
+				// For direct column reference, renamed by its alias if any
+				source := expr.Expr.(*sqlparser.ColName).Name.String()
+				name := source
+				if !expr.As.IsEmpty() {
+					name = expr.As.String()
 				}
-				// If there is an alias, it will be handled by the alias map
-			} else if funcExpr, ok := expr.Expr.(*sqlparser.FuncExpr); ok {
-				// For aggregation function
-				funcName := strings.ToUpper(funcExpr.Name.String())
-
-				// For other aggregation functions
-				if len(funcExpr.Exprs) > 0 && expr.As.IsEmpty() {
-					if argAliasedExpr, ok := funcExpr.Exprs[0].(*sqlparser.AliasedExpr); ok {
-						if colName, ok := argAliasedExpr.Expr.(*sqlparser.ColName); ok {
-							// If no alias, use function name as column name
-							originalCol := fmt.Sprintf("%s(%s)", funcName, colName.Name.String())
-							columns = append(columns, originalCol)
-						}
-					}
+				columns = append(columns, projectedColumn{source: source, fallback: name, name: name})
+
+			default:
+				// For aggregation function, stored under its alias or e.g. COUNT(pid)
+				aggs := aggregation.ExtractAggregations(sqlparser.SelectExprs{expr})
+				if len(aggs) == 0 {
+					continue
 				}
+				name := aggs[0].Alias
+				columns = append(columns, projectedColumn{source: name, fallback: name, name: name})
 			}
 		case *sqlparser.StarExpr:
-			// For SELECT *, return empty to signal all columns
-			return []string{}
+			// For SELECT *, include all columns of the row
+			columns = append(columns, projectedColumn{star: true})
 		}
 	}
 
 	return columns
 }
 
+// isColumn checks if an expression is a plain column reference
+func isColumn(expr sqlparser.Expr) bool {
+	_, ok := expr.(*sqlparser.ColName)
+	return ok
+}
+
 // isSelectStar checks if the query is a SELECT * query
 func isSelectStar(selectExprs sqlparser.SelectExprs) bool {
 	for _, expr := range selectExprs {
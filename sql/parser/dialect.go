@@ -0,0 +1,384 @@
+package parser
+
+import (
+	"strings"
+)
+
+// reservedColumnNames are words reserved by the MySQL grammar of the parser
+// that SQLite accepts as bare identifiers and osquery schemas use as column
+// names, e.g. the key column of json_each or the interval of a schedule
+var reservedColumnNames = map[string]bool{
+	"key": true, "end": true, "interval": true, "index": true, "default": true,
+	"unique": true, "tables": true, "separator": true, "boolean": true, "div": true,
+	"mod": true, "rlike": true, "lock": true, "force": true, "ignore": true, "use": true,
+	"show": true, "describe": true, "rename": true, "analyze": true, "explain": true,
+	"character": true, "database": true,
+}
+
+// sqliteCastTypes maps SQLite CAST type names to the parser's convert types
+var sqliteCastTypes = map[string]string{
+	"integer": "signed", "int": "signed", "bigint": "signed", "smallint": "signed", "tinyint": "signed",
+	"text": "char", "varchar": "char", "clob": "char",
+	"real": "decimal", "float": "decimal", "double": "decimal", "numeric": "decimal",
+	"blob": "binary",
+}
+
+// identifierFollowers are tokens after which a double-quoted word is a column
+// name rather than a string literal, as in "name" = 'cmd.exe'
+var identifierFollowers = map[string]bool{
+	"=": true, "==": true, "!=": true, "<>": true, "<": true, ">": true, "<=": true, ">=": true,
+	"like": true, "glob": true, "regexp": true, "in": true, "is": true, "not": true,
+	"between": true, "as": true, ".": true, "asc": true, "desc": true,
+}
+
+// identifierPredecessors are tokens after which a double-quoted word is a name
+var identifierPredecessors = map[string]bool{
+	"select": true, "distinct": true, "from": true, "join": true, "as": true, "by": true, ".": true,
+}
+
+// RewriteSQLiteDialect rewrites the SQLite constructs used by osquery queries
+// and packs into the MySQL dialect understood by the parser:
+//
+//   - string literals keep backslashes literally, as in 'C:\Windows\%'
+//   - "double-quoted" words are column names where a name is expected
+//     (select list, left of a comparison, ORDER BY, ...) and strings elsewhere
+//   - reserved MySQL words such as key and interval are usable as column names
+//   - a || b concatenates instead of meaning OR
+//   - a GLOB 'pattern' matches case-sensitive shell wildcards
+//   - CAST(x AS INTEGER), TEXT and REAL map to the parser's convert types
+//   - == is equality
+//   - JOIN ... USING (column) becomes an equivalent ON condition
+//
+// LIMIT offset, count and IFNULL have the same meaning in both dialects and
+// need no rewriting. Queries without any of these constructs are returned
+// unchanged.
+func RewriteSQLiteDialect(query string) string {
+	tokens := scanSQLiteTokens(query)
+	if len(tokens) == 0 {
+		return query
+	}
+
+	changed := false
+	rewritten := make([]token, len(tokens))
+	copy(rewritten, tokens)
+
+	caseDepth := 0
+	var parenOwners []string
+	for i, tok := range tokens {
+		next := ""
+		if i+1 < len(tokens) {
+			next = strings.ToLower(tokens[i+1].text)
+		}
+
+		switch tok.kind {
+		case tokenString:
+			if strings.Contains(tok.text, `\`) {
+				rewritten[i].text = strings.ReplaceAll(tok.text, `\`, `\\`)
+				changed = true
+			}
+
+		case tokenQuotedIdent:
+			if !strings.HasPrefix(tok.text, `"`) {
+				continue
+			}
+			value := unquote(tok.text)
+			if isIdentifierPosition(tokens, i, parenOwners) {
+				rewritten[i].text = "`" + strings.ReplaceAll(value, "`", "``") + "`"
+			} else {
+				rewritten[i].kind = tokenString
+				rewritten[i].text = quoteString(value)
+			}
+			changed = true
+
+		case tokenIdent:
+			word := strings.ToLower(tok.text)
+			switch {
+			case word == "case":
+				caseDepth++
+			case word == "end" && caseDepth > 0:
+				caseDepth--
+			case reservedColumnNames[word] && next != "(":
+				rewritten[i].text = "`" + tok.text + "`"
+				changed = true
+			case word == "as" && len(parenOwners) > 0 && parenOwners[len(parenOwners)-1] == "cast" && i+1 < len(tokens):
+				if castType, ok := sqliteCastTypes[next]; ok {
+					rewritten[i+1].text = castType
+					changed = true
+				}
+			}
+
+		case tokenPunct:
+			switch tok.text {
+			case "(":
+				owner := ""
+				if i > 0 && tokens[i-1].kind == tokenIdent {
+					owner = strings.ToLower(tokens[i-1].text)
+				}
+				parenOwners = append(parenOwners, owner)
+			case ")":
+				if len(parenOwners) > 0 {
+					parenOwners = parenOwners[:len(parenOwners)-1]
+				}
+			case "==":
+				rewritten[i].text = "="
+				changed = true
+			}
+		}
+	}
+
+	if concatenated, ok := rewriteConcatenation(rewritten); ok {
+		rewritten = concatenated
+		changed = true
+	}
+	if globbed, ok := rewriteGlob(rewritten); ok {
+		rewritten = globbed
+		changed = true
+	}
+	if joined, ok := rewriteJoinUsing(rewritten); ok {
+		rewritten = joined
+		changed = true
+	}
+
+	if !changed {
+		return query
+	}
+	return renderTokens(rewritten)
+}
+
+// isIdentifierPosition decides whether the double-quoted token at i names a
+// column. SQLite resolves this at run time by looking for a matching column;
+// here it is decided from the surrounding syntax instead.
+func isIdentifierPosition(tokens []token, i int, parenOwners []string) bool {
+	if i+1 < len(tokens) && identifierFollowers[strings.ToLower(tokens[i+1].text)] {
+		return true
+	}
+	if i == 0 {
+		return false
+	}
+
+	prev := strings.ToLower(tokens[i-1].text)
+	if identifierPredecessors[prev] {
+		return true
+	}
+	// Items of the select, GROUP BY and ORDER BY lists
+	return prev == "," && len(parenOwners) == 0
+}
+
+// rewriteConcatenation turns chains of a || b || c into concat(a, b, c)
+func rewriteConcatenation(tokens []token) ([]token, bool) {
+	changed := false
+	for i := 0; i < len(tokens); i++ {
+		if !tokens[i].is("||") || i == 0 {
+			continue
+		}
+
+		start := operandStart(tokens, i-1)
+		operands := [][]token{tokens[start:i]}
+		end := i
+		for end < len(tokens) && tokens[end].is("||") && end+1 < len(tokens) {
+			operandEnd := operandEnd(tokens, end+1)
+			operands = append(operands, tokens[end+1:operandEnd+1])
+			end = operandEnd + 1
+		}
+
+		replacement := []token{synthetic(tokenIdent, "concat"), synthetic(tokenPunct, "(")}
+		for j, operand := range operands {
+			if j > 0 {
+				replacement = append(replacement, synthetic(tokenPunct, ","))
+			}
+			replacement = append(replacement, operand...)
+		}
+		replacement = append(replacement, synthetic(tokenPunct, ")"))
+
+		tokens = splice(tokens, start, end, replacement)
+		i = start + len(replacement) - 1
+		changed = true
+	}
+	return tokens, changed
+}
+
+// rewriteGlob turns a [NOT] GLOB pattern into a [NOT] REGEXP glob_to_regexp(pattern)
+func rewriteGlob(tokens []token) ([]token, bool) {
+	changed := false
+	for i := 0; i < len(tokens); i++ {
+		if !tokens[i].is("glob") || i+1 >= len(tokens) {
+			continue
+		}
+
+		end := operandEnd(tokens, i+1)
+		replacement := []token{
+			synthetic(tokenIdent, "regexp"),
+			synthetic(tokenIdent, "glob_to_regexp"),
+			synthetic(tokenPunct, "("),
+		}
+		replacement = append(replacement, tokens[i+1:end+1]...)
+		replacement = append(replacement, synthetic(tokenPunct, ")"))
+
+		tokens = splice(tokens, i, end+1, replacement)
+		i += len(replacement) - 1
+		changed = true
+	}
+	return tokens, changed
+}
+
+// joinKeywords are the words that may appear between two joined tables
+var joinKeywords = map[string]bool{
+	"join": true, "left": true, "right": true, "inner": true, "outer": true, "cross": true, "natural": true,
+}
+
+// rewriteJoinUsing turns A JOIN B USING (c1, c2) into A JOIN B ON A.c1 = B.c1
+// AND A.c2 = B.c2, naming each table by its alias when it has one
+func rewriteJoinUsing(tokens []token) ([]token, bool) {
+	changed := false
+	for i := 0; i < len(tokens); i++ {
+		if !tokens[i].is("using") || i+1 >= len(tokens) || !tokens[i+1].is("(") {
+			continue
+		}
+		closing := matchingParen(tokens, i+1)
+		if closing < 0 {
+			continue
+		}
+
+		// The right table is the name or alias just before USING, the left
+		// one is the name or alias just before the JOIN keywords
+		right := i - 1
+		join := right
+		for join >= 0 && !tokens[join].is("join") {
+			join--
+		}
+		left := join
+		for left >= 0 && (tokens[left].kind == tokenPunct || joinKeywords[strings.ToLower(tokens[left].text)]) {
+			left--
+		}
+		if right < 0 || left < 0 {
+			continue
+		}
+
+		var replacement []token
+		replacement = append(replacement, synthetic(tokenIdent, "on"))
+		for j := i + 2; j < closing; j++ {
+			if tokens[j].is(",") {
+				continue
+			}
+			if len(replacement) > 1 {
+				replacement = append(replacement, synthetic(tokenIdent, "and"))
+			}
+			replacement = append(replacement,
+				tokens[left], synthetic(tokenPunct, "."), tokens[j],
+				synthetic(tokenPunct, "="),
+				tokens[right], synthetic(tokenPunct, "."), tokens[j],
+			)
+		}
+
+		tokens = splice(tokens, i, closing+1, replacement)
+		i += len(replacement) - 1
+		changed = true
+	}
+	return tokens, changed
+}
+
+// operandStart returns the index of the first token of the operand ending at end
+func operandStart(tokens []token, end int) int {
+	start := end
+	if tokens[end].is(")") {
+		depth := 0
+		for start = end; start >= 0; start-- {
+			if tokens[start].is(")") {
+				depth++
+			} else if tokens[start].is("(") {
+				depth--
+				if depth == 0 {
+					break
+				}
+			}
+		}
+		if start <= 0 {
+			return 0
+		}
+		// Include the name of a function call
+		if tokens[start-1].kind == tokenIdent {
+			start--
+		}
+		return start
+	}
+
+	// Include the qualifier of table.column
+	for start >= 2 && tokens[start-1].is(".") {
+		start -= 2
+	}
+	return start
+}
+
+// operandEnd returns the index of the last token of the operand starting at start
+func operandEnd(tokens []token, start int) int {
+	if (tokens[start].is("-") || tokens[start].is("+")) && start+1 < len(tokens) {
+		return operandEnd(tokens, start+1)
+	}
+	if tokens[start].is("(") {
+		if closing := matchingParen(tokens, start); closing > 0 {
+			return closing
+		}
+		return len(tokens) - 1
+	}
+
+	end := start
+	if tokens[start].kind == tokenIdent && start+1 < len(tokens) && tokens[start+1].is("(") {
+		if closing := matchingParen(tokens, start+1); closing > 0 {
+			return closing
+		}
+		return len(tokens) - 1
+	}
+	for end+2 < len(tokens) && tokens[end+1].is(".") {
+		end += 2
+	}
+	return end
+}
+
+// splice replaces tokens[start:end] with replacement
+func splice(tokens []token, start, end int, replacement []token) []token {
+	spliced := make([]token, 0, len(tokens)-(end-start)+len(replacement))
+	spliced = append(spliced, tokens[:start]...)
+	spliced = append(spliced, replacement...)
+	return append(spliced, tokens[end:]...)
+}
+
+// synthetic creates a token that does not come from the original query
+func synthetic(kind tokenKind, text string) token {
+	return token{kind: kind, text: text, start: -1, end: -1}
+}
+
+// renderTokens joins tokens back into query text
+func renderTokens(tokens []token) string {
+	var sb strings.Builder
+	for i, tok := range tokens {
+		if i > 0 {
+			prev := tokens[i-1]
+			glued := prev.is("(") || prev.is(".") || tok.is(")") || tok.is(",") || tok.is(".") || tok.is(";") ||
+				tok.is("(") && prev.kind == tokenIdent
+			if !glued {
+				sb.WriteByte(' ')
+			}
+		}
+		sb.WriteString(tok.text)
+	}
+	return sb.String()
+}
+
+// unquote removes the quotes around a quoted token, undoubling embedded quotes
+func unquote(text string) string {
+	if len(text) < 2 {
+		return ""
+	}
+	quote := text[:1]
+	inner := text[1 : len(text)-1]
+	if !strings.HasSuffix(text, quote) {
+		inner = text[1:]
+	}
+	return strings.ReplaceAll(inner, quote+quote, quote)
+}
+
+// quoteString renders a value as a string literal in the parser's dialect
+func quoteString(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
@@ -0,0 +1,103 @@
+package parser
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/blastrain/vitess-sqlparser/sqlparser"
+)
+
+func TestRewriteSQLiteDialect(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{
+			query: `SELECT name FROM processes WHERE path LIKE 'C:\Windows\%'`,
+			want:  `select name from processes where path like 'C:\\Windows\\%'`,
+		},
+		{
+			query: `SELECT "name", pid FROM processes WHERE "name" = "cmd.exe"`,
+			want:  "select name, pid from processes where name = 'cmd.exe'",
+		},
+		{
+			query: "SELECT key, value FROM registry WHERE key LIKE 'HKEY_USERS%'",
+			want:  "select `key`, value from registry where `key` like 'HKEY_USERS%'",
+		},
+		{
+			query: "SELECT name || ' (' || pid || ')' AS label FROM processes",
+			want:  "select concat(name, ' (', pid, ')') as label from processes",
+		},
+		{
+			query: "SELECT name FROM file WHERE filename GLOB '*.exe'",
+			want:  "select name from file where filename regexp glob_to_regexp('*.exe')",
+		},
+		{
+			query: "SELECT CAST(size AS INTEGER) AS size FROM file WHERE pid == 4",
+			want:  "select convert(size, signed) as size from file where pid = 4",
+		},
+		{
+			query: "SELECT p.name, l.port FROM listening_ports AS l JOIN processes p USING (pid)",
+			want:  "select p.name, l.port from listening_ports as l join processes as p on l.pid = p.pid",
+		},
+		{
+			query: "SELECT CASE WHEN uid = 0 THEN 'root' ELSE 'user' END AS kind FROM users",
+			want:  "select case when uid = 0 then 'root' else 'user' end as kind from users",
+		},
+	}
+
+	for _, test := range tests {
+		stmt, err := sqlparser.Parse(RewriteSQLiteDialect(test.query))
+		if err != nil {
+			t.Errorf("RewriteSQLiteDialect(%q) is not parseable: %v", test.query, err)
+			continue
+		}
+		if got := sqlparser.String(stmt); got != test.want {
+			t.Errorf("RewriteSQLiteDialect(%q)\n got: %s\nwant: %s", test.query, got, test.want)
+		}
+	}
+}
+
+func TestRewriteSQLiteDialectKeepsMySQLQueries(t *testing.T) {
+	query := "SELECT name, count(pid) AS n FROM processes WHERE name IN ('a', 'b') GROUP BY name LIMIT 5, 10"
+	if got := RewriteSQLiteDialect(query); got != query {
+		t.Errorf("RewriteSQLiteDialect(%q) = %q, want it unchanged", query, got)
+	}
+}
+
+// TestOsqueryPackCorpus parses queries taken from the osquery packs shipped
+// with osquery (incident-response, windows-attacks, ...), written in SQLite.
+// The engine tests also execute them against stubs of their tables.
+func TestOsqueryPackCorpus(t *testing.T) {
+	data, err := os.ReadFile("testdata/osquery_packs.sql")
+	if err != nil {
+		t.Fatalf("Failed to read corpus: %v", err)
+	}
+
+	count := 0
+	for _, query := range strings.Split(string(data), ";\n") {
+		query = strings.TrimSpace(stripComments(query))
+		if query == "" {
+			continue
+		}
+		count++
+		if _, err := Parse(query); err != nil {
+			t.Errorf("Parse(%q) returned error: %v", query, err)
+		}
+	}
+	if count == 0 {
+		t.Fatal("Corpus contains no queries")
+	}
+}
+
+// stripComments removes -- line comments from a corpus entry
+func stripComments(query string) string {
+	var lines []string
+	for _, line := range strings.Split(query, "\n") {
+		if !strings.HasPrefix(strings.TrimSpace(line), "--") {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
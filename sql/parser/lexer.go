@@ -0,0 +1,154 @@
+package parser
+
+import "strings"
+
+// tokenKind classifies the tokens produced by scanTokens
+type tokenKind int
+
+const (
+	// tokenIdent is a bare identifier or keyword
+	tokenIdent tokenKind = iota
+	// tokenString is a single-quoted string literal
+	tokenString
+	// tokenQuotedIdent is a double-quoted or backtick-quoted identifier
+	tokenQuotedIdent
+	// tokenNumber is a numeric literal
+	tokenNumber
+	// tokenPunct is an operator or punctuation character
+	tokenPunct
+)
+
+// token is a lexical token with its byte offsets in the original query
+type token struct {
+	kind  tokenKind
+	text  string
+	start int
+	end   int
+}
+
+// is reports whether the token is the given keyword or punctuation, ignoring case
+func (t token) is(text string) bool {
+	return (t.kind == tokenIdent || t.kind == tokenPunct) && strings.EqualFold(t.text, text)
+}
+
+// multiCharPunct lists the operators that are scanned as a single token
+var multiCharPunct = []string{"||", "<=", ">=", "<>", "!=", "==", "->"}
+
+// scanTokens splits a query in the parser's MySQL dialect into tokens,
+// skipping whitespace and comments. It is deliberately lenient: it only needs
+// to be precise enough to find clause boundaries and rewrite constructs.
+func scanTokens(query string) []token {
+	return scan(query, true)
+}
+
+// scanSQLiteTokens is like scanTokens, but follows SQLite quoting rules,
+// where a backslash inside a string literal is an ordinary character
+func scanSQLiteTokens(query string) []token {
+	return scan(query, false)
+}
+
+func scan(query string, backslashEscapes bool) []token {
+	var tokens []token
+	i := 0
+	for i < len(query) {
+		c := query[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '-' && strings.HasPrefix(query[i:], "--"):
+			end := strings.IndexByte(query[i:], '\n')
+			if end < 0 {
+				i = len(query)
+			} else {
+				i += end + 1
+			}
+		case c == '/' && strings.HasPrefix(query[i:], "/*"):
+			end := strings.Index(query[i+2:], "*/")
+			if end < 0 {
+				i = len(query)
+			} else {
+				i += end + 4
+			}
+		case c == '\'' || c == '"' || c == '`':
+			end := scanQuoted(query, i, backslashEscapes)
+			kind := tokenString
+			if c != '\'' {
+				kind = tokenQuotedIdent
+			}
+			tokens = append(tokens, token{kind: kind, text: query[i:end], start: i, end: end})
+			i = end
+		case isIdentStart(c):
+			start := i
+			for i < len(query) && isIdentChar(query[i]) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: query[start:i], start: start, end: i})
+		case c >= '0' && c <= '9' || c == '.' && i+1 < len(query) && query[i+1] >= '0' && query[i+1] <= '9':
+			start := i
+			for i < len(query) && (isIdentChar(query[i]) || query[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: query[start:i], start: start, end: i})
+		default:
+			width := 1
+			for _, op := range multiCharPunct {
+				if strings.HasPrefix(query[i:], op) {
+					width = len(op)
+					break
+				}
+			}
+			tokens = append(tokens, token{kind: tokenPunct, text: query[i : i+width], start: i, end: i + width})
+			i += width
+		}
+	}
+	return tokens
+}
+
+// scanQuoted returns the offset just past the quoted section starting at start.
+// A doubled quote character inside the section is treated as an escaped quote.
+func scanQuoted(query string, start int, backslashEscapes bool) int {
+	quote := query[start]
+	i := start + 1
+	for i < len(query) {
+		switch query[i] {
+		case '\\':
+			if backslashEscapes && quote == '\'' {
+				i += 2
+				continue
+			}
+		case quote:
+			if i+1 < len(query) && query[i+1] == quote {
+				i += 2
+				continue
+			}
+			return i + 1
+		}
+		i++
+	}
+	return len(query)
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
+
+func isIdentChar(c byte) bool {
+	return isIdentStart(c) || c >= '0' && c <= '9' || c == '$'
+}
+
+// matchingParen returns the index of the token closing the parenthesis opened
+// at tokens[open], or -1 if it is unbalanced
+func matchingParen(tokens []token, open int) int {
+	depth := 0
+	for i := open; i < len(tokens); i++ {
+		if tokens[i].is("(") {
+			depth++
+		} else if tokens[i].is(")") {
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
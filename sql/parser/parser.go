@@ -10,18 +10,28 @@ import (
 type ParsedQuery struct {
 	Statement sqlparser.Statement
 	Original  string
+
+	// Table-valued functions joined against the main table, in FROM order
+	TableFunctions []TableFunction
 }
 
-// Parse parses a SQL query string into a structured form
+// Parse parses a SQL query string into a structured form.
+// Queries may be written in the SQLite dialect used by osquery.
 func Parse(query string) (*ParsedQuery, error) {
-	stmt, err := sqlparser.Parse(query)
+	rewritten, tableFunctions, err := extractTableFunctions(RewriteSQLiteDialect(query))
+	if err != nil {
+		return nil, fmt.Errorf("SQL parse error: %w", err)
+	}
+
+	stmt, err := sqlparser.Parse(rewritten)
 	if err != nil {
 		return nil, fmt.Errorf("SQL parse error: %w", err)
 	}
 
 	return &ParsedQuery{
-		Statement: stmt,
-		Original:  query,
+		Statement:      stmt,
+		Original:       query,
+		TableFunctions: tableFunctions,
 	}, nil
 }
 
@@ -36,6 +46,10 @@ func GetTableName(stmt sqlparser.Statement) (string, error) {
 		return "", fmt.Errorf("no FROM clause found")
 	}
 
+	if _, ok := selectStmt.From[0].(*sqlparser.JoinTableExpr); ok {
+		return "", fmt.Errorf("joins between tables are not supported")
+	}
+
 	fromExpr, ok := selectStmt.From[0].(*sqlparser.AliasedTableExpr)
 	if !ok {
 		return "", fmt.Errorf("unsupported FROM expression")
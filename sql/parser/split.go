@@ -0,0 +1,29 @@
+package parser
+
+// SplitStatements splits SQL text into the statements terminated by
+// semicolons, ignoring semicolons in string literals, quoted identifiers and
+// comments. Statements are returned without their semicolon and comments
+// around them, and empty statements are skipped. The text following the last
+// semicolon, which may be an incomplete statement, is returned as rest.
+func SplitStatements(sql string) (statements []string, rest string) {
+	start, first, last := 0, -1, 0
+	for _, tok := range scanSQLiteTokens(sql) {
+		if !tok.is(";") {
+			if first < 0 {
+				first = tok.start
+			}
+			last = tok.end
+			continue
+		}
+		if first >= 0 {
+			statements = append(statements, sql[first:last])
+		}
+		start, first = tok.end, -1
+	}
+	return statements, sql[start:]
+}
+
+// IsBlank reports whether SQL text holds nothing but whitespace and comments
+func IsBlank(sql string) bool {
+	return len(scanSQLiteTokens(sql)) == 0
+}
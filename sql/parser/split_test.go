@@ -0,0 +1,74 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitStatements(t *testing.T) {
+	testCases := []struct {
+		name       string
+		sql        string
+		statements []string
+		rest       string
+	}{
+		{
+			name:       "single statement",
+			sql:        "SELECT * FROM processes;",
+			statements: []string{"SELECT * FROM processes"},
+		},
+		{
+			name:       "several statements",
+			sql:        "SELECT 1;\nSELECT 2 ; SELECT 3;",
+			statements: []string{"SELECT 1", "SELECT 2", "SELECT 3"},
+		},
+		{
+			name:       "semicolons in quotes",
+			sql:        `SELECT 'a;b', "c;d", ` + "`e;f`" + ` FROM t;`,
+			statements: []string{`SELECT 'a;b', "c;d", ` + "`e;f`" + ` FROM t`},
+		},
+		{
+			name:       "semicolons in comments",
+			sql:        "-- first; query\nSELECT 1 /* ; */ FROM t; -- done;\n",
+			statements: []string{"SELECT 1 /* ; */ FROM t"},
+			rest:       " -- done;\n",
+		},
+		{
+			name:       "empty statements",
+			sql:        ";; SELECT 1;;",
+			statements: []string{"SELECT 1"},
+		},
+		{
+			name:       "incomplete statement",
+			sql:        "SELECT 1; SELECT name\nFROM processes WHERE name = 'a;",
+			statements: []string{"SELECT 1"},
+			rest:       " SELECT name\nFROM processes WHERE name = 'a;",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			statements, rest := SplitStatements(tc.sql)
+			if !reflect.DeepEqual(statements, tc.statements) {
+				t.Errorf("Expected statements %q, got %q", tc.statements, statements)
+			}
+			if rest != tc.rest {
+				t.Errorf("Expected rest %q, got %q", tc.rest, rest)
+			}
+		})
+	}
+}
+
+func TestIsBlank(t *testing.T) {
+	for sql, expected := range map[string]bool{
+		"":                       true,
+		" \n\t":                  true,
+		"-- comment\n/* more */": true,
+		"SELECT 1":               false,
+		"/* x */ 1":              false,
+	} {
+		if got := IsBlank(sql); got != expected {
+			t.Errorf("IsBlank(%q) = %v, want %v", sql, got, expected)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/blastrain/vitess-sqlparser/sqlparser"
+)
+
+// TableFunction represents a table-valued function call in the FROM clause,
+// such as json_each(permissions_json). The MySQL grammar used by the parser
+// has no notion of these, so they are lifted out of the query text before
+// parsing and joined against the rows of the main table at execution time.
+type TableFunction struct {
+	Name  string
+	Args  []sqlparser.Expr
+	Alias string
+}
+
+// fromClauseTerminators are the keywords that end a FROM clause
+var fromClauseTerminators = map[string]bool{
+	"where": true, "group": true, "order": true, "limit": true, "having": true, "union": true,
+}
+
+// aliasStopWords are keywords that may follow a FROM item and are never an alias
+var aliasStopWords = map[string]bool{
+	"where": true, "group": true, "order": true, "limit": true, "having": true, "union": true,
+	"join": true, "cross": true, "inner": true, "left": true, "right": true, "on": true, "using": true,
+}
+
+// textEdit replaces query[start:end] with text
+type textEdit struct {
+	start int
+	end   int
+	text  string
+}
+
+// extractTableFunctions removes table-valued function calls from the FROM
+// clause of a query and returns the rewritten query with the calls found.
+// A function that is the only FROM item is replaced by the "dual" table.
+func extractTableFunctions(query string) (string, []TableFunction, error) {
+	tokens := scanTokens(query)
+
+	from := -1
+	depth := 0
+	for i, tok := range tokens {
+		if tok.is("(") {
+			depth++
+		} else if tok.is(")") {
+			depth--
+		} else if depth == 0 && tok.is("from") {
+			from = i
+			break
+		}
+	}
+	if from < 0 {
+		return query, nil, nil
+	}
+
+	var functions []TableFunction
+	var edits []textEdit
+
+	for i := from + 1; i < len(tokens); i++ {
+		tok := tokens[i]
+		if tok.is(";") || tok.kind == tokenIdent && fromClauseTerminators[strings.ToLower(tok.text)] {
+			break
+		}
+
+		// Subqueries and ON conditions cannot contain FROM items of this query
+		if tok.is("(") {
+			if closing := matchingParen(tokens, i); closing > 0 {
+				i = closing
+			}
+			continue
+		}
+
+		// A FROM item starts right after FROM, a comma or JOIN
+		prev := tokens[i-1]
+		if i != from+1 && !prev.is(",") && !prev.is("join") {
+			continue
+		}
+		if tok.kind != tokenIdent || i+1 >= len(tokens) || !tokens[i+1].is("(") {
+			continue
+		}
+
+		closing := matchingParen(tokens, i+1)
+		if closing < 0 {
+			return "", nil, fmt.Errorf("unbalanced parentheses in call to %s", tok.text)
+		}
+
+		fn := TableFunction{Name: strings.ToLower(tok.text)}
+		args, err := parseExprList(query[tokens[i+1].end:tokens[closing].start])
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid arguments to %s: %w", tok.text, err)
+		}
+		fn.Args = args
+
+		last := closing
+		if last+1 < len(tokens) && tokens[last+1].is("as") && last+2 < len(tokens) {
+			fn.Alias = strings.Trim(tokens[last+2].text, "\"`")
+			last += 2
+		} else if last+1 < len(tokens) && (tokens[last+1].kind == tokenIdent || tokens[last+1].kind == tokenQuotedIdent) &&
+			!aliasStopWords[strings.ToLower(tokens[last+1].text)] {
+			fn.Alias = strings.Trim(tokens[last+1].text, "\"`")
+			last++
+		}
+		functions = append(functions, fn)
+
+		switch {
+		case prev.is(","):
+			edits = append(edits, textEdit{start: prev.start, end: tokens[last].end})
+		case prev.is("join"):
+			// Remove from the end of the preceding FROM item, including JOIN modifiers
+			first := i - 1
+			if tokens[first-1].is("cross") || tokens[first-1].is("inner") {
+				first--
+			}
+			edits = append(edits, textEdit{start: tokens[first-1].end, end: tokens[last].end})
+		default:
+			// First FROM item: drop a trailing comma so the next item takes its place
+			if last+1 < len(tokens) && tokens[last+1].is(",") {
+				edits = append(edits, textEdit{start: tok.start, end: tokens[last+1].end})
+			} else {
+				edits = append(edits, textEdit{start: tok.start, end: tokens[last].end, text: "dual"})
+			}
+		}
+		i = last
+	}
+
+	if len(functions) == 0 {
+		return query, nil, nil
+	}
+	return applyEdits(query, edits), functions, nil
+}
+
+// parseExprList parses a comma-separated list of expressions
+func parseExprList(text string) ([]sqlparser.Expr, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, fmt.Errorf("missing arguments")
+	}
+
+	stmt, err := sqlparser.Parse("select " + text)
+	if err != nil {
+		return nil, err
+	}
+	selectStmt, ok := stmt.(*sqlparser.Select)
+	if !ok {
+		return nil, fmt.Errorf("unexpected statement")
+	}
+
+	exprs := make([]sqlparser.Expr, 0, len(selectStmt.SelectExprs))
+	for _, selectExpr := range selectStmt.SelectExprs {
+		aliasedExpr, ok := selectExpr.(*sqlparser.AliasedExpr)
+		if !ok {
+			return nil, fmt.Errorf("unsupported argument: %s", sqlparser.String(selectExpr))
+		}
+		exprs = append(exprs, aliasedExpr.Expr)
+	}
+	return exprs, nil
+}
+
+// applyEdits applies non-overlapping edits, given in ascending order, to a query
+func applyEdits(query string, edits []textEdit) string {
+	var sb strings.Builder
+	pos := 0
+	for _, edit := range edits {
+		sb.WriteString(query[pos:edit.start])
+		sb.WriteString(edit.text)
+		pos = edit.end
+	}
+	sb.WriteString(query[pos:])
+	return sb.String()
+}
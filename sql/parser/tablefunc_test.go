@@ -0,0 +1,73 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/blastrain/vitess-sqlparser/sqlparser"
+)
+
+func TestExtractTableFunctions(t *testing.T) {
+	tests := []struct {
+		query     string
+		rewritten string
+		name      string
+		alias     string
+	}{
+		{
+			query:     "SELECT identifier FROM chrome_extensions, json_each(permissions_json) WHERE value = 'webRequest'",
+			rewritten: "SELECT identifier FROM chrome_extensions WHERE value = 'webRequest'",
+			name:      "json_each",
+		},
+		{
+			query:     "SELECT * FROM chrome_extensions CROSS JOIN json_tree(manifest_json, '$.permissions') AS p ORDER BY p.id",
+			rewritten: "SELECT * FROM chrome_extensions ORDER BY p.id",
+			name:      "json_tree",
+			alias:     "p",
+		},
+		{
+			query:     "SELECT value FROM JSON_EACH('[1, 2, 3]') j;",
+			rewritten: "SELECT value FROM dual;",
+			name:      "json_each",
+			alias:     "j",
+		},
+	}
+
+	for _, test := range tests {
+		rewritten, functions, err := extractTableFunctions(test.query)
+		if err != nil {
+			t.Errorf("extractTableFunctions(%q) returned error: %v", test.query, err)
+			continue
+		}
+		if rewritten != test.rewritten {
+			t.Errorf("rewritten query = %q, want %q", rewritten, test.rewritten)
+		}
+		if len(functions) != 1 || functions[0].Name != test.name || functions[0].Alias != test.alias {
+			t.Errorf("table functions = %+v, want %s AS %q", functions, test.name, test.alias)
+		}
+	}
+}
+
+func TestParseKeepsPlainQueries(t *testing.T) {
+	query := "SELECT name, count(pid) FROM processes WHERE name IN ('a', 'b') GROUP BY name"
+	parsed, err := Parse(query)
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if len(parsed.TableFunctions) != 0 {
+		t.Errorf("unexpected table functions: %+v", parsed.TableFunctions)
+	}
+
+	parsed, err = Parse("SELECT value FROM processes, json_each(json_object('pid', pid)) WHERE value > 0")
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if len(parsed.TableFunctions) != 1 {
+		t.Fatalf("expected one table function, got %+v", parsed.TableFunctions)
+	}
+	if _, ok := parsed.TableFunctions[0].Args[0].(*sqlparser.FuncExpr); !ok {
+		t.Errorf("expected a function call argument, got %T", parsed.TableFunctions[0].Args[0])
+	}
+	if table, _ := GetTableName(parsed.Statement); table != "processes" {
+		t.Errorf("table name = %q, want processes", table)
+	}
+}
@@ -0,0 +1,45 @@
+-- Queries from the osquery packs (incident-response, windows-attacks,
+-- windows-hardening, it-compliance, ossec-rootkit, unwanted-chrome-extensions)
+-- and common fleet queries, one per entry, separated by ";" at end of line.
+
+SELECT * FROM processes WHERE on_disk = 0;
+SELECT name, path, pid FROM processes WHERE path LIKE 'C:\Users\%\AppData\Local\Temp\%';
+SELECT * FROM services WHERE start_type = 'AUTO_START' AND path NOT LIKE 'C:\Windows\system32\svchost.exe%';
+SELECT name, data FROM registry WHERE key = 'HKEY_LOCAL_MACHINE\SOFTWARE\Microsoft\Windows\CurrentVersion\Run';
+SELECT * FROM registry WHERE key LIKE 'HKEY_USERS\%\Software\Microsoft\Windows\CurrentVersion\Run%';
+SELECT path, name FROM registry WHERE path LIKE 'HKEY_LOCAL_MACHINE\SOFTWARE\Microsoft\Windows NT\CurrentVersion\Image File Execution Options\%\Debugger';
+SELECT * FROM scheduled_tasks WHERE enabled = 1 AND action NOT LIKE '%\Microsoft\%';
+SELECT "name", "path", "cmdline" FROM processes WHERE "name" = "powershell.exe";
+SELECT name, pid, parent FROM processes WHERE lower(name) IN ('cmd.exe', 'powershell.exe', 'wscript.exe', 'cscript.exe', 'mshta.exe');
+SELECT p.pid, p.name, p.cmdline FROM processes p WHERE p.cmdline LIKE '%-enc%' OR p.cmdline LIKE '%-EncodedCommand%';
+SELECT name || ' (' || pid || ')' AS label FROM processes;
+SELECT * FROM file WHERE path LIKE 'C:\Windows\Temp\%%' AND filename GLOB '*.exe';
+SELECT path, CAST(size AS INTEGER) AS size FROM file WHERE directory = 'C:\Windows\Tasks';
+SELECT key, name, data, mtime FROM registry WHERE key = 'HKEY_LOCAL_MACHINE\SYSTEM\CurrentControlSet\Control\Lsa' AND name = 'RunAsPPL';
+SELECT * FROM users WHERE uid == 500;
+SELECT username, uid, CASE WHEN uid < 1000 THEN 'system' ELSE 'user' END AS kind FROM users;
+SELECT COUNT(*) AS count FROM processes;
+SELECT name, COUNT(*) AS instances FROM processes GROUP BY name ORDER BY instances DESC LIMIT 10;
+SELECT * FROM processes LIMIT 10, 20;
+SELECT * FROM chrome_extensions WHERE identifier IN ('gighmmpiobklfepjocnamgkkbiglidom', 'cjpalhdlnbpafiamejdnhcphjbkeiagm');
+SELECT name, identifier, version FROM chrome_extensions WHERE permissions LIKE '%<all_urls>%' OR permissions LIKE '%webRequest%';
+SELECT identifier, value FROM chrome_extensions, json_each(permissions_json) WHERE value = 'nativeMessaging';
+SELECT datetime(start_time, 'unixepoch') AS started, name FROM processes ORDER BY start_time DESC;
+SELECT * FROM logged_in_users WHERE type = 'user' AND host != '';
+SELECT ifnull(cmdline, '') AS cmdline, name FROM processes WHERE parent = 4;
+SELECT * FROM interface_addresses WHERE address NOT LIKE '127.%' AND address NOT LIKE 'fe80%';
+SELECT * FROM process_open_sockets WHERE remote_port IN (4444, 1337, 31337) AND remote_address != '127.0.0.1';
+SELECT pid, name, path FROM processes WHERE path IS NULL OR path = '';
+SELECT * FROM users WHERE shell NOT IN ('/usr/bin/false', '/sbin/nologin', '/bin/false');
+SELECT * FROM startup_items WHERE path LIKE '%.vbs' OR path LIKE '%.js' OR path LIKE '%.hta';
+SELECT * FROM processes WHERE pid BETWEEN 100 AND 1000;
+SELECT name, path FROM drivers WHERE signed = 0 AND path NOT LIKE 'C:\Windows\System32\drivers\%';
+SELECT * FROM programs WHERE name LIKE '%TeamViewer%' OR name LIKE '%AnyDesk%';
+SELECT DISTINCT(name) FROM processes;
+SELECT substr(path, 1, 3) AS drive, count(*) AS n FROM processes GROUP BY drive;
+SELECT * FROM patches ORDER BY installed_on DESC;
+SELECT `key`, identifier FROM chrome_extensions WHERE `key` != '';
+SELECT datetime('now') AS now, strftime('%s', 'now') AS epoch;
+SELECT username, directory FROM users WHERE directory GLOB '/home/*' AND username NOT GLOB '_*';
+SELECT datetime('now') IS NOT NULL AS has_time;
+SELECT pid, path IS NULL AS no_path, pid BETWEEN 1 AND 100 AS early, parent = 1 AS orphan FROM processes;
@@ -1,6 +1,11 @@
 package result
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+
 	"github.com/scrymastic/goosquery/sql/sqlctx"
 )
 
@@ -8,54 +13,283 @@ type Column struct {
 	Name        string
 	Type        string
 	Description string
+	Options     ColumnOptions
 }
 
 type Schema []Column
 
-type Result map[string]interface{}
+// Result is a single row. Its values are stored in a slice whose positions
+// are given by a ColumnIndex shared with the other rows of the same table,
+// instead of a map per row. A Result is a handle: copies of it refer to the
+// same row, as copies of a map would.
+type Result struct {
+	row *row
+}
+
+type row struct {
+	index  *ColumnIndex
+	values []interface{}
+}
+
+// absent marks a position of the index for which the row has no column
+type absent struct{}
+
+// ColumnIndex maps column names to positions in the values of rows. It only
+// ever grows, so rows created before a column was added simply lack it.
+// Adding columns is not safe for concurrent use, looking them up is.
+type ColumnIndex struct {
+	names     []string
+	positions map[string]int
+
+	// pruned are the columns of the schema that the query does not use,
+	// which the rows of the index have no position for
+	pruned map[string]bool
+}
+
+// NewColumnIndex creates an index with the given columns in order
+func NewColumnIndex(names ...string) *ColumnIndex {
+	index := &ColumnIndex{positions: make(map[string]int, len(names))}
+	for _, name := range names {
+		index.position(name)
+	}
+	return index
+}
+
+// position returns the position of a column, adding it if needed
+func (idx *ColumnIndex) position(name string) int {
+	if pos, ok := idx.positions[name]; ok {
+		return pos
+	}
+	idx.names = append(idx.names, name)
+	idx.positions[name] = len(idx.names) - 1
+	return len(idx.names) - 1
+}
+
+// Names returns the columns of the index in order
+func (idx *ColumnIndex) Names() []string {
+	return idx.names
+}
+
+// NewRow creates an empty row sharing this index
+func (idx *ColumnIndex) NewRow() Result {
+	return Result{row: &row{index: idx}}
+}
 
 func (r *Result) Add(key string, value interface{}) {
-	(*r)[key] = value
+	if r.row == nil {
+		r.row = &row{index: NewColumnIndex()}
+	}
+	pos := r.row.index.position(key)
+	for len(r.row.values) <= pos {
+		r.row.values = append(r.row.values, absent{})
+	}
+	r.row.values[pos] = value
+}
+
+func (r Result) Get(key string) interface{} {
+	value, _ := r.Lookup(key)
+	return value
+}
+
+// Lookup returns the value of a column and whether the row has the column
+func (r Result) Lookup(key string) (interface{}, bool) {
+	if r.row == nil {
+		return nil, false
+	}
+	pos, ok := r.row.index.positions[key]
+	if !ok || pos >= len(r.row.values) {
+		return nil, false
+	}
+	value := r.row.values[pos]
+	if _, missing := value.(absent); missing {
+		return nil, false
+	}
+	return value, true
+}
+
+// Set changes the value of a column of the row. Generators set every column
+// of their schema, so the columns pruned because the query does not use them
+// are ignored. Columns that are neither in the index nor pruned must be added
+// with Add: setting them is a programming error, such as a column missing
+// from the schema, and panics.
+func (r Result) Set(key string, value interface{}) {
+	if r.row != nil {
+		if pos, ok := r.row.index.positions[key]; ok {
+			for len(r.row.values) <= pos {
+				r.row.values = append(r.row.values, absent{})
+			}
+			r.row.values[pos] = value
+			return
+		}
+		if r.row.index.pruned[key] {
+			return
+		}
+	}
+	panic(fmt.Sprintf("result: Set of the unknown column %q, use Add to add columns", key))
 }
 
-func (r *Result) Get(key string) interface{} {
-	return (*r)[key]
+// Delete removes a column from the row
+func (r Result) Delete(key string) {
+	if _, ok := r.Lookup(key); ok {
+		r.row.values[r.row.index.positions[key]] = absent{}
+	}
+}
+
+func (r Result) Size() int {
+	size := 0
+	r.Range(func(string, interface{}) bool {
+		size++
+		return true
+	})
+	return size
+}
+
+// Columns returns the names of the columns of the row in order
+func (r Result) Columns() []string {
+	var columns []string
+	r.Range(func(key string, _ interface{}) bool {
+		columns = append(columns, key)
+		return true
+	})
+	return columns
 }
 
-func (r *Result) Set(key string, value interface{}) {
-	// Only set the value if the key already exists
-	if _, ok := (*r)[key]; ok {
-		(*r)[key] = value
+// Range calls fn for every column of the row in order, until fn returns false
+func (r Result) Range(fn func(key string, value interface{}) bool) {
+	if r.row == nil {
+		return
+	}
+	for pos, value := range r.row.values {
+		if _, missing := value.(absent); missing {
+			continue
+		}
+		if !fn(r.row.index.names[pos], value) {
+			return
+		}
 	}
 }
 
-func (r *Result) Size() int {
-	return len(*r)
+// Copy returns an independent copy of the row sharing the same index
+func (r Result) Copy() Result {
+	if r.row == nil {
+		return Result{}
+	}
+	values := make([]interface{}, len(r.row.values))
+	copy(values, r.row.values)
+	return Result{row: &row{index: r.row.index, values: values}}
+}
+
+// ToMap returns the columns of the row as a map
+func (r Result) ToMap() map[string]interface{} {
+	m := make(map[string]interface{})
+	r.Range(func(key string, value interface{}) bool {
+		m[key] = value
+		return true
+	})
+	return m
+}
+
+// FromMap creates a row from a map, with the columns in name order
+func FromMap(m map[string]interface{}) Result {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	res := NewColumnIndex(keys...).NewRow()
+	res.row.values = make([]interface{}, len(keys))
+	for i, key := range keys {
+		res.row.values[i] = m[key]
+	}
+	return res
+}
+
+// MarshalJSON encodes the row as a JSON object with the columns in order
+func (r Result) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	first := true
+	var err error
+	r.Range(func(key string, value interface{}) bool {
+		if !first {
+			buf.WriteByte(',')
+		}
+		first = false
+
+		var encoded []byte
+		if encoded, err = json.Marshal(key); err != nil {
+			return false
+		}
+		buf.Write(encoded)
+		buf.WriteByte(':')
+		if encoded, err = json.Marshal(value); err != nil {
+			return false
+		}
+		buf.Write(encoded)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// layoutKey identifies the row layout of a schema in a query context
+type layoutKey struct {
+	schema *Column
+	size   int
+}
+
+// layout is the shared index and default values of the rows of a schema
+type layout struct {
+	index    *ColumnIndex
+	defaults []interface{}
 }
 
 func NewResult(ctx *sqlctx.Context, schema Schema) *Result {
-	result := Result{}
-
-	for _, col := range schema {
-		if ctx.IsColumnUsed(col.Name) {
-			switch col.Type {
-			case "TEXT":
-				result.Add(col.Name, "")
-			case "INTEGER":
-				result.Add(col.Name, int32(-1))
-			case "BIGINT":
-				result.Add(col.Name, int64(-1))
-			case "FLOAT":
-				result.Add(col.Name, float64(-1))
-			default:
-				result.Add(col.Name, nil)
+	if len(schema) == 0 {
+		return NewEmptyResult()
+	}
+
+	// The columns used by the query are resolved once per schema and query
+	cached := ctx.Cached(layoutKey{schema: &schema[0], size: len(schema)}, func() interface{} {
+		l := &layout{index: NewColumnIndex()}
+		for _, col := range schema {
+			if ctx.IsColumnUsed(col.Name) {
+				l.index.position(col.Name)
+				l.defaults = append(l.defaults, defaultValue(col.Type))
+			} else {
+				if l.index.pruned == nil {
+					l.index.pruned = make(map[string]bool)
+				}
+				l.index.pruned[col.Name] = true
 			}
 		}
-	}
+		return l
+	}).(*layout)
+
+	values := make([]interface{}, len(cached.defaults))
+	copy(values, cached.defaults)
+	return &Result{row: &row{index: cached.index, values: values}}
+}
 
-	return &result
+// defaultValue returns the value of a column that a generator has not set
+func defaultValue(columnType string) interface{} {
+	switch columnType {
+	case "TEXT":
+		return ""
+	case "INTEGER":
+		return int32(-1)
+	case "BIGINT":
+		return int64(-1)
+	case "FLOAT":
+		return float64(-1)
+	}
+	return nil
 }
 
 func NewEmptyResult() *Result {
-	return &Result{}
+	return &Result{row: &row{index: NewColumnIndex()}}
 }
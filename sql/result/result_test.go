@@ -0,0 +1,185 @@
+package result
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+)
+
+var testSchema = Schema{
+	{Name: "pid", Type: "BIGINT"},
+	{Name: "name", Type: "TEXT"},
+	{Name: "path", Type: "TEXT"},
+	{Name: "threads", Type: "INTEGER"},
+}
+
+func TestNewResultSharesColumnIndex(t *testing.T) {
+	ctx := sqlctx.NewContext()
+	ctx.SetColumns([]string{"pid", "name"})
+
+	first := NewResult(ctx, testSchema)
+	second := NewResult(ctx, testSchema)
+	if first.row.index != second.row.index {
+		t.Fatal("Rows of the same schema and query should share a column index")
+	}
+
+	// Set only changes columns requested by the query
+	first.Set("pid", int64(4))
+	first.Set("path", `C:\Windows`)
+	if got := first.Get("pid"); got != int64(4) {
+		t.Errorf("Get(pid) = %v, want 4", got)
+	}
+	if _, ok := first.Lookup("path"); ok {
+		t.Error("Set should not add columns that were not requested")
+	}
+	if got := second.Get("pid"); got != int64(-1) {
+		t.Errorf("Other rows should keep the default value, got %v", got)
+	}
+	if got := second.Get("name"); got != "" {
+		t.Errorf("Get(name) = %q, want the TEXT default", got)
+	}
+
+	// Add extends the shared index without affecting other rows
+	first.Add("label", "System")
+	if _, ok := second.Lookup("label"); ok {
+		t.Error("Adding a column to one row should not add it to others")
+	}
+	if got := first.Columns(); fmt.Sprint(got) != "[pid name label]" {
+		t.Errorf("Columns() = %v, want schema order followed by added columns", got)
+	}
+
+	// Changing the requested columns gives a new layout
+	ctx.SetColumns([]string{"*"})
+	if all := NewResult(ctx, testSchema); all.Size() != len(testSchema) {
+		t.Errorf("SELECT * row has %d columns, want %d", all.Size(), len(testSchema))
+	}
+}
+
+func TestSetUnknownColumn(t *testing.T) {
+	ctx := sqlctx.NewContext()
+	ctx.SetColumns([]string{"pid"})
+	row := NewResult(ctx, testSchema)
+
+	// Columns of the schema that the query does not use are ignored
+	row.Set("threads", int32(8))
+
+	// Other columns are a bug of the caller, and panic
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected Set of a column missing from the schema to panic")
+		}
+	}()
+	row.Set("thread_count", int32(8))
+}
+
+func TestSetAbsentColumn(t *testing.T) {
+	// A column of the shared index that the row lacks is set like any other
+	first := NewColumnIndex("pid").NewRow()
+	second := NewColumnIndex().NewRow()
+	first.Add("pid", int64(4))
+	first.Add("name", "System")
+	second.row.index = first.row.index
+	second.Set("name", "cmd.exe")
+	if second.Get("name") != "cmd.exe" {
+		t.Errorf("Expected name to be set, got %v", second.ToMap())
+	}
+}
+
+func TestResultsCloneAndColumns(t *testing.T) {
+	results := NewQueryResult()
+	results.AppendResult(FromMap(map[string]interface{}{"b": 1, "a": 2}))
+	other := NewColumnIndex("c", "a").NewRow()
+	other.Add("c", 3)
+	results.AppendResult(other)
+
+	if got := results.GetColumns(); fmt.Sprint(got) != "[a b c]" {
+		t.Errorf("GetColumns() = %v, want [a b c]", got)
+	}
+
+	clone := results.Clone()
+	(*clone)[0].Set("a", 20)
+	if got := (*results)[0].Get("a"); got != 2 {
+		t.Errorf("Modifying a clone changed the original: %v", got)
+	}
+
+	results.SetValue(1, "d", 4)
+	if value, ok := results.GetValue(1, "d"); !ok || value != 4 {
+		t.Errorf("GetValue(1, d) = %v, %v", value, ok)
+	}
+}
+
+func TestResultMarshalJSON(t *testing.T) {
+	row := NewColumnIndex("pid", "name", "path").NewRow()
+	row.Add("pid", int64(4))
+	row.Add("path", nil)
+	row.Add("name", "System")
+
+	data, err := json.Marshal(Results{row})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `[{"pid":4,"name":"System","path":null}]`; string(data) != want {
+		t.Errorf("json.Marshal = %s, want %s", data, want)
+	}
+}
+
+// benchmarkSchema is a wide schema like those of file and process_memory_map
+var benchmarkSchema = func() Schema {
+	schema := make(Schema, 24)
+	for i := range schema {
+		schema[i] = Column{Name: fmt.Sprintf("column_%d", i), Type: "TEXT"}
+	}
+	return schema
+}()
+
+func BenchmarkMapRows(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		rows := make([]map[string]interface{}, 0, 10000)
+		for j := 0; j < 10000; j++ {
+			row := make(map[string]interface{})
+			for _, col := range benchmarkSchema {
+				row[col.Name] = ""
+			}
+			row["column_3"] = "value"
+			rows = append(rows, row)
+		}
+	}
+}
+
+func BenchmarkSliceRows(b *testing.B) {
+	ctx := sqlctx.NewContext()
+	ctx.SetColumns([]string{"*"})
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		rows := make(Results, 0, 10000)
+		for j := 0; j < 10000; j++ {
+			row := NewResult(ctx, benchmarkSchema)
+			row.Set("column_3", "value")
+			rows = append(rows, *row)
+		}
+	}
+}
+
+func TestOnPlatform(t *testing.T) {
+	tables := OnPlatform([]Table{{Name: "time"}, {Name: "hash"}}, "windows", "linux")
+	for _, table := range tables {
+		if !table.AvailableOn("windows") || !table.AvailableOn("linux") || table.AvailableOn("darwin") {
+			t.Errorf("Unexpected platforms of %s: %v", table.Name, table.Platforms)
+		}
+	}
+}
+
+func TestStatusOn(t *testing.T) {
+	table := Table{Name: "startup_items", Platforms: []string{"windows", "linux"}, NotImplementedOn: []string{"windows"}}
+	if table.StatusOn("windows") != NotImplemented || table.StatusOn("linux") != Implemented {
+		t.Errorf("Expected startup_items to be implemented on linux only")
+	}
+
+	table = Table{Name: "prefetch", Platforms: []string{"windows"}, Status: NotImplemented}
+	if table.StatusOn("windows") != NotImplemented {
+		t.Errorf("Expected prefetch not to be implemented")
+	}
+}
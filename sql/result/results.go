@@ -1,7 +1,7 @@
 package result
 
 // Results represents the result of a SQL query
-// It's implemented as a slice of results (maps) for flexibility
+// It's implemented as a slice of rows that usually share one column index
 type Results []Result
 
 // NewQueryResult creates a new empty query result
@@ -20,26 +20,22 @@ func (r *Results) AppendResults(results Results) {
 }
 
 // GetColumns returns all column names in the result
-// This inspects all results and combines their keys to get all possible columns
+// This inspects all results and combines their columns in order of first appearance
 func (r *Results) GetColumns() []string {
 	if len(*r) == 0 {
 		return []string{}
 	}
 
-	// Use a map to eliminate duplicates
-	columnMap := make(map[string]bool)
-
-	// Collect all column names from all results
+	columns := []string{}
+	seen := make(map[string]bool)
 	for _, result := range *r {
-		for column := range result {
-			columnMap[column] = true
-		}
-	}
-
-	// Convert map keys to slice
-	columns := make([]string, 0, len(columnMap))
-	for column := range columnMap {
-		columns = append(columns, column)
+		result.Range(func(column string, _ interface{}) bool {
+			if !seen[column] {
+				seen[column] = true
+				columns = append(columns, column)
+			}
+			return true
+		})
 	}
 
 	return columns
@@ -61,17 +57,13 @@ func (r *Results) Clone() *Results {
 		return NewQueryResult()
 	}
 
-	clone := NewQueryResult()
+	clone := make(Results, 0, len(*r))
 	for _, result := range *r {
-		// Create a new map for each result
-		resultCopy := make(map[string]interface{})
-		for k, v := range result {
-			resultCopy[k] = v
-		}
-		clone.AppendResult(resultCopy)
+		// Copy the values of each result, sharing the column index
+		clone = append(clone, result.Copy())
 	}
 
-	return clone
+	return &clone
 }
 
 // GetValue retrieves a value from a specific result and column
@@ -81,9 +73,7 @@ func (r *Results) GetValue(rowIndex int, columnName string) (interface{}, bool)
 		return nil, false
 	}
 
-	result := (*r)[rowIndex]
-	value, exists := result[columnName]
-	return value, exists
+	return (*r)[rowIndex].Lookup(columnName)
 }
 
 // SetValue sets a value in a specific result and column
@@ -93,15 +83,14 @@ func (r *Results) SetValue(rowIndex int, columnName string, value interface{}) b
 		return false
 	}
 
-	result := (*r)[rowIndex]
-	result[columnName] = value
+	(*r)[rowIndex].Add(columnName, value)
 	return true
 }
 
 // GetRow returns a specific result by index
 func (r *Results) GetRow(rowIndex int) Result {
 	if rowIndex < 0 || rowIndex >= r.Size() {
-		return nil
+		return Result{}
 	}
 
 	return (*r)[rowIndex]
@@ -112,11 +101,7 @@ func (r *Results) GetColumnValues(columnName string) []interface{} {
 	values := make([]interface{}, 0, r.Size())
 
 	for _, result := range *r {
-		if value, exists := result[columnName]; exists {
-			values = append(values, value)
-		} else {
-			values = append(values, nil)
-		}
+		values = append(values, result.Get(columnName))
 	}
 
 	return values
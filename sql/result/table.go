@@ -0,0 +1,81 @@
+package result
+
+// Table describes a table that can be queried
+type Table struct {
+	Name        string
+	Description string
+	Schema      Schema
+	Platforms   []string // operating systems providing the table, as in GOOS
+	Status      TableStatus
+
+	// NotImplementedOn lists the platforms whose generator only returns an
+	// error, for tables implemented on the other platforms
+	NotImplementedOn []string
+}
+
+// TableStatus tells whether the generator of a table returns rows
+type TableStatus int
+
+const (
+	Implemented    TableStatus = iota
+	NotImplemented             // the generator only returns an error
+)
+
+func (s TableStatus) String() string {
+	if s == NotImplemented {
+		return "not implemented"
+	}
+	return "implemented"
+}
+
+// StatusOn returns the status of the table on a platform
+func (t Table) StatusOn(platform string) TableStatus {
+	for _, p := range t.NotImplementedOn {
+		if p == platform {
+			return NotImplemented
+		}
+	}
+	return t.Status
+}
+
+// MarshalText encodes the status as its name
+func (s TableStatus) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// ColumnOptions tell how the constraints of a query on a column are used,
+// with the meaning of the column options of osquery table specs
+type ColumnOptions uint8
+
+const (
+	// Required columns must be constrained by the WHERE clause, such as the
+	// path of the file table, as the generator has nothing to list otherwise
+	Required ColumnOptions = 1 << iota
+)
+
+// Names returns the names of the options as in osquery table specs
+func (o ColumnOptions) Names() []string {
+	var names []string
+	if o&Required != 0 {
+		names = append(names, "required")
+	}
+	return names
+}
+
+// OnPlatform records that the tables are provided on the platforms
+func OnPlatform(tables []Table, platforms ...string) []Table {
+	for i := range tables {
+		tables[i].Platforms = append(tables[i].Platforms, platforms...)
+	}
+	return tables
+}
+
+// AvailableOn reports whether the table is provided on a platform
+func (t Table) AvailableOn(platform string) bool {
+	for _, p := range t.Platforms {
+		if p == platform {
+			return true
+		}
+	}
+	return false
+}
@@ -1,24 +1,31 @@
 package sqlctx
 
-import "slices"
+import (
+	"slices"
+	"sync"
+)
 
 // Context provides information about the SQL query execution context
 // It stores metadata, constants, and other information relevant to query execution
 type Context struct {
-	// Constants extracted from the WHERE clause (e.g., id = 1, name = 'foo')
-	Constants map[string]string
+	// Constants extracted from the WHERE clause (e.g., id = 1, name IN ('a', 'b'))
+	Constants map[string][]string
 
 	// Columns requested in the query
 	Columns []string
 
 	// // Additional query metadata
 	// Metadata map[string]interface{}
+
+	// Values derived from the requested columns, see Cached
+	cacheMu sync.Mutex
+	cache   map[interface{}]interface{}
 }
 
 // NewContext creates a new query execution context
 func NewContext() *Context {
 	return &Context{
-		Constants: make(map[string]string),
+		Constants: make(map[string][]string),
 		Columns:   []string{},
 		// Metadata:  make(map[string]interface{}),
 	}
@@ -28,9 +35,11 @@ func NewContext() *Context {
 // For example, in WHERE id = 1, this would add "id" -> "1"
 func (c *Context) AddConstant(name string, value string) {
 	if c.Constants == nil {
-		c.Constants = make(map[string]string)
+		c.Constants = make(map[string][]string)
+	}
+	if !slices.Contains(c.Constants[name], value) {
+		c.Constants[name] = append(c.Constants[name], value)
 	}
-	c.Constants[name] = value
 }
 
 // HasConstant checks if a constant exists in the context
@@ -61,11 +70,37 @@ func (c *Context) AddColumn(column string) {
 		c.Columns = []string{}
 	}
 	c.Columns = append(c.Columns, column)
+	c.resetCache()
 }
 
 // SetColumns sets the list of requested columns
 func (c *Context) SetColumns(columns []string) {
 	c.Columns = columns
+	c.resetCache()
+}
+
+// Cached returns the value stored under key, computing it with build the
+// first time. It lets other packages derive values from the requested
+// columns once per query, such as the column layout of result rows.
+// Changing the requested columns clears the cache.
+func (c *Context) Cached(key interface{}, build func() interface{}) interface{} {
+	c.cacheMu.Lock()
+	defer c.cacheMu.Unlock()
+	if value, ok := c.cache[key]; ok {
+		return value
+	}
+	if c.cache == nil {
+		c.cache = make(map[interface{}]interface{})
+	}
+	value := build()
+	c.cache[key] = value
+	return value
+}
+
+func (c *Context) resetCache() {
+	c.cacheMu.Lock()
+	c.cache = nil
+	c.cacheMu.Unlock()
 }
 
 // IsColumnUsed checks if a column is used in the query.
@@ -90,13 +125,13 @@ func (c *Context) IsAnyOfColumnsUsed(columns []string) bool {
 	return false
 }
 
-// GetConstants returns all values for a specific key.
-// For example, if multiple URLs are specified in WHERE clauses
-// like "url='https://example1.com' OR url='https://example2.com'",
+// GetConstants returns all values for a specific key, or nil when the key
+// is not constrained. For example, if multiple URLs are specified in WHERE
+// clauses like "url IN ('https://example1.com', 'https://example2.com')",
 // GetConstants("url") would return ["https://example1.com", "https://example2.com"]
 func (c *Context) GetConstants(key string) []string {
 	if c.Constants == nil {
 		return nil
 	}
-	return []string{c.Constants[key]}
+	return c.Constants[key]
 }
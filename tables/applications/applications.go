@@ -0,0 +1,28 @@
+package applications
+
+import (
+	"slices"
+
+	"github.com/scrymastic/goosquery/sql/result"
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+	"github.com/scrymastic/goosquery/tables/applications/chrome_extensions"
+	"github.com/scrymastic/goosquery/tables/applications/firefox_addons"
+)
+
+// Tables lists the application tables with their schemas and the platforms providing them
+var Tables = slices.Concat(
+	result.OnPlatform([]result.Table{
+		{Name: chrome_extensions.TableName, Description: chrome_extensions.Description, Schema: chrome_extensions.Schema},
+		{Name: firefox_addons.TableName, Description: firefox_addons.Description, Schema: firefox_addons.Schema},
+	}, "windows", "linux", "darwin"),
+)
+
+// GenChromeExtensions generates the extensions of the Chromium-based browser profiles of every user
+func GenChromeExtensions(ctx *sqlctx.Context) (*result.Results, error) {
+	return chrome_extensions.GenChromeExtensions(ctx)
+}
+
+// GenFirefoxAddons generates the addons of the Firefox profiles of every user
+func GenFirefoxAddons(ctx *sqlctx.Context) (*result.Results, error) {
+	return firefox_addons.GenFirefoxAddons(ctx)
+}
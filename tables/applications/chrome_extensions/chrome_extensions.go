@@ -4,9 +4,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
+	"sort"
 
+	"github.com/scrymastic/goosquery/sql/result"
 	"github.com/scrymastic/goosquery/sql/sqlctx"
-	"github.com/scrymastic/goosquery/tables/system/users"
+	"github.com/scrymastic/goosquery/tables/internal/homes"
 )
 
 type ChromeExtension struct {
@@ -74,6 +77,49 @@ var WindowsChromePathSuffixMap = map[int32]string{
 	EdgeBeta:           "AppData\\Local\\Microsoft\\Edge Beta\\User Data",
 	Opera:              "AppData\\Roaming\\Opera Software\\Opera Stable",
 	Vivaldi:            "AppData\\Local\\Vivaldi\\User Data",
+	Arc:                "AppData\\Local\\Packages\\TheBrowserCompany.Arc_ttt1ap7aakyb4\\LocalCache\\Local\\Arc\\User Data",
+}
+
+var LinuxChromePathSuffixMap = map[int32]string{
+	GoogleChrome:       ".config/google-chrome",
+	GoogleChromeBeta:   ".config/google-chrome-beta",
+	GoogleChromeDev:    ".config/google-chrome-unstable",
+	GoogleChromeCanary: ".config/google-chrome-canary",
+	Brave:              ".config/BraveSoftware/Brave-Browser",
+	Chromium:           ".config/chromium",
+	Yandex:             ".config/yandex-browser",
+	Edge:               ".config/microsoft-edge",
+	EdgeBeta:           ".config/microsoft-edge-beta",
+	Opera:              ".config/opera",
+	Vivaldi:            ".config/vivaldi",
+	Arc:                ".config/Arc/User Data",
+}
+
+var MacChromePathSuffixMap = map[int32]string{
+	GoogleChrome:       "Library/Application Support/Google/Chrome",
+	GoogleChromeBeta:   "Library/Application Support/Google/Chrome Beta",
+	GoogleChromeDev:    "Library/Application Support/Google/Chrome Dev",
+	GoogleChromeCanary: "Library/Application Support/Google/Chrome Canary",
+	Brave:              "Library/Application Support/BraveSoftware/Brave-Browser",
+	Chromium:           "Library/Application Support/Chromium",
+	Yandex:             "Library/Application Support/Yandex/YandexBrowser",
+	Edge:               "Library/Application Support/Microsoft Edge",
+	EdgeBeta:           "Library/Application Support/Microsoft Edge Beta",
+	Opera:              "Library/Application Support/com.operasoftware.Opera",
+	Vivaldi:            "Library/Application Support/Vivaldi",
+	Arc:                "Library/Application Support/Arc/User Data",
+}
+
+// chromePathSuffixMap returns the user data directories of the browsers on
+// a platform, relative to the home directories
+func chromePathSuffixMap(goos string) map[int32]string {
+	switch goos {
+	case "windows":
+		return WindowsChromePathSuffixMap
+	case "darwin":
+		return MacChromePathSuffixMap
+	}
+	return LinuxChromePathSuffixMap
 }
 
 type UserInformation struct {
@@ -81,6 +127,26 @@ type UserInformation struct {
 	Path string `json:"path"`
 }
 
+// getUserInformationList returns the users with a home directory, root
+// included
+func getUserInformationList() ([]UserInformation, error) {
+	userInfoList := []UserInformation{}
+
+	userHomes, err := homes.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the home directories: %w", err)
+	}
+
+	for _, home := range userHomes {
+		userInfoList = append(userInfoList, UserInformation{
+			Uid:  home.UID,
+			Path: home.Directory,
+		})
+	}
+
+	return userInfoList, nil
+}
+
 type ChromeProfilePath struct {
 	Type  int32
 	Value string
@@ -120,39 +186,16 @@ type ChromeProfileSnapshot struct {
 	UnreferencedExtensions map[string]Extension
 }
 
-func getUserInformationList() ([]UserInformation, error) {
-	userInfoList := []UserInformation{}
-
-	ctx := sqlctx.NewContext()
-	users, err := users.GenUsers(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate users: %w", err)
-	}
-
-	for _, user := range *users {
-		if user.Get("uid").(int64) == 0 || user.Get("directory").(string) == "" {
-			continue
-		}
-
-		userInfoList = append(userInfoList, UserInformation{
-			Uid:  user.Get("uid").(int64),
-			Path: user.Get("directory").(string),
-		})
-	}
-
-	return userInfoList, nil
-}
-
 func isValidChromeProfile(path string) bool {
 	// Valid chrome profile contains 'Preferences' and 'Secure Preferences'
 	preferencesPath := filepath.Join(path, kPreferencesFile)
 	securePreferencesPath := filepath.Join(path, kSecurePreferencesFile)
 
 	// Check if both files exist
-	if _, err := os.Stat(preferencesPath); os.IsNotExist(err) {
+	if _, err := os.Stat(hostPath(preferencesPath)); os.IsNotExist(err) {
 		return false
 	}
-	if _, err := os.Stat(securePreferencesPath); os.IsNotExist(err) {
+	if _, err := os.Stat(hostPath(securePreferencesPath)); os.IsNotExist(err) {
 		return false
 	}
 
@@ -168,15 +211,25 @@ func getChromeProfilePathList() ([]ChromeProfilePath, error) {
 	chromeProfilePathList := []ChromeProfilePath{}
 
 	for _, userInfo := range userInfoList {
-		for browserType, pathSuffix := range WindowsChromePathSuffixMap {
+		for browserType, pathSuffix := range chromePathSuffixMap(runtime.GOOS) {
 			// Check if the user data path exists
 			userDataPath := filepath.Join(userInfo.Path, pathSuffix)
-			if _, err := os.Stat(userDataPath); os.IsNotExist(err) {
+			if _, err := os.Stat(hostPath(userDataPath)); os.IsNotExist(err) {
+				continue
+			}
+
+			// Opera keeps its only profile in the user data path itself
+			if isValidChromeProfile(userDataPath) {
+				chromeProfilePathList = append(chromeProfilePathList, ChromeProfilePath{
+					Type:  browserType,
+					Value: userDataPath,
+					Uid:   userInfo.Uid,
+				})
 				continue
 			}
 
 			// List all subdirectories in the user data path and check if they are valid chrome profiles
-			subdirectories, err := os.ReadDir(userDataPath)
+			subdirectories, err := os.ReadDir(hostPath(userDataPath))
 			if err != nil {
 				continue
 			}
@@ -201,6 +254,11 @@ func getChromeProfilePathList() ([]ChromeProfilePath, error) {
 		}
 	}
 
+	// Map iteration order is random, keep the profiles in a stable order
+	sort.Slice(chromeProfilePathList, func(i, j int) bool {
+		return chromeProfilePathList[i].Value < chromeProfilePathList[j].Value
+	})
+
 	return chromeProfilePathList, nil
 }
 
@@ -212,14 +270,14 @@ func captureProfileSnapshotSettingsFromPath(chromeProfileSnapshot *ChromeProfile
 	preferencesPath := filepath.Join(chromeProfilePath.Value, kPreferencesFile)
 	securePreferencesPath := filepath.Join(chromeProfilePath.Value, kSecurePreferencesFile)
 
-	preferences, err := os.ReadFile(preferencesPath)
+	preferences, err := os.ReadFile(hostPath(preferencesPath))
 	if err != nil {
 		return fmt.Errorf("failed to read preferences: %w", err)
 	}
 
 	chromeProfileSnapshot.Preferences = string(preferences)
 
-	securePreferences, err := os.ReadFile(securePreferencesPath)
+	securePreferences, err := os.ReadFile(hostPath(securePreferencesPath))
 	if err != nil {
 		return fmt.Errorf("failed to read secure preferences: %w", err)
 	}
@@ -229,21 +287,64 @@ func captureProfileSnapshotSettingsFromPath(chromeProfileSnapshot *ChromeProfile
 	return nil
 }
 
+// readExtension reads the manifest of the extension folder
+func readExtension(path string) (Extension, error) {
+	manifest, err := os.ReadFile(hostPath(filepath.Join(path, kExtensionManifestFile)))
+	if err != nil {
+		return Extension{}, err
+	}
+	return Extension{Path: path, Manifest: string(manifest)}, nil
+}
+
 func captureProfileSnapshotExtensionsFromPath(chromeProfileSnapshot *ChromeProfileSnapshot, chromeProfilePath ChromeProfilePath) error {
+	chromeProfileSnapshot.ReferencedExtensions = map[string]Extension{}
+	chromeProfileSnapshot.UnreferencedExtensions = map[string]Extension{}
+
+	// The extensions referenced by the preferences, wherever they are installed
+	referencedPaths := map[string]bool{}
+	for identifier, settings := range readExtensionSettings(chromeProfileSnapshot) {
+		path := settings.extensionPath(chromeProfilePath.Value)
+		if path == "" {
+			continue
+		}
+		extension, err := readExtension(path)
+		if err != nil {
+			continue
+		}
+		chromeProfileSnapshot.ReferencedExtensions[identifier] = extension
+		referencedPaths[path] = true
+	}
+
 	extensionsFolderPath := filepath.Join(chromeProfilePath.Value, kExtensionsFolderName)
-	if _, err := os.Stat(extensionsFolderPath); os.IsNotExist(err) {
+	if _, err := os.Stat(hostPath(extensionsFolderPath)); os.IsNotExist(err) {
 		return nil
 	}
 
-	extensions, err := os.ReadDir(extensionsFolderPath)
+	extensions, err := os.ReadDir(hostPath(extensionsFolderPath))
 	if err != nil {
 		return fmt.Errorf("failed to read extensions: %w", err)
 	}
 
+	// The other extensions of the Extensions folder, one folder per version
 	for _, extension := range extensions {
 		if !extension.IsDir() {
 			continue
 		}
+		versions, err := os.ReadDir(hostPath(filepath.Join(extensionsFolderPath, extension.Name())))
+		if err != nil {
+			continue
+		}
+		for _, version := range versions {
+			path := filepath.Join(extensionsFolderPath, extension.Name(), version.Name())
+			if !version.IsDir() || referencedPaths[path] {
+				continue
+			}
+			unreferenced, err := readExtension(path)
+			if err != nil {
+				continue
+			}
+			chromeProfileSnapshot.UnreferencedExtensions[path] = unreferenced
+		}
 	}
 
 	return nil
@@ -264,8 +365,28 @@ func getChromeProfileSnapshotList() ([]ChromeProfileSnapshot, error) {
 			return nil, fmt.Errorf("failed to capture profile snapshot settings from path: %w", err)
 		}
 
+		err = captureProfileSnapshotExtensionsFromPath(&chromeProfileSnapshot, chromeProfilePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to capture profile snapshot extensions from path: %w", err)
+		}
+
 		chromeProfileSnapshotList = append(chromeProfileSnapshotList, chromeProfileSnapshot)
 	}
 
 	return chromeProfileSnapshotList, nil
 }
+
+func GenChromeExtensions(ctx *sqlctx.Context) (*result.Results, error) {
+	results := result.NewQueryResult()
+
+	chromeProfileSnapshotList, err := getChromeProfileSnapshotList()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range chromeProfileSnapshotList {
+		results.AppendResults(*genProfileExtensions(ctx, &chromeProfileSnapshotList[i]))
+	}
+
+	return results, nil
+}
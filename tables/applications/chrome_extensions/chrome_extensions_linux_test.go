@@ -0,0 +1,84 @@
+//go:build linux
+
+package chrome_extensions
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+	"github.com/scrymastic/goosquery/tables/internal/hostfstest"
+)
+
+func TestGenChromeExtensionsFromRoot(t *testing.T) {
+	root := t.TempDir()
+	chrome := "home/alice/.config/google-chrome/Default/"
+	opera := "home/alice/.config/opera/"
+	files := map[string]string{
+		"etc/passwd": "root:x:0:0:root:/root:/bin/bash\n" +
+			"alice:x:1000:1000::/home/alice:/bin/sh\n",
+		chrome + "Preferences": `{"profile":{"name":"Person 1"},"extensions":{"settings":{
+			"aeabkkaehgepiemfhhhpigjkgbjdmcpj":{"path":"aeabkkaehgepiemfhhhpigjkgbjdmcpj/1.0_0","from_webstore":true,"install_time":"13300000000000000"}}}}`,
+		chrome + "Secure Preferences": `{"extensions":{"settings":{
+			"aeabkkaehgepiemfhhhpigjkgbjdmcpj":{"path":"aeabkkaehgepiemfhhhpigjkgbjdmcpj/1.0_0","from_webstore":true,"install_time":"13300000000000000","disable_reasons":1}}}}`,
+		chrome + "Extensions/aeabkkaehgepiemfhhhpigjkgbjdmcpj/1.0_0/manifest.json": "\xef\xbb\xbf" + `{"name":"__MSG_appName__","version":"1.0",
+			"default_locale":"en","key":"cHVibGljIGtleSBvZiB0aGUgZXh0ZW5zaW9u","author":{"email":"dev@example.com"},
+			"permissions":["cookies"],"host_permissions":["<all_urls>"],"background":{"persistent":true}}`,
+		chrome + "Extensions/aeabkkaehgepiemfhhhpigjkgbjdmcpj/1.0_0/_locales/en/messages.json": `{"appname":{"message":"Cookie Helper"}}`,
+		chrome + "Extensions/leftover/0.9_0/manifest.json":                                     `{"name":"Leftover","version":"0.9"}`,
+		// Opera keeps its only profile in the user data path itself
+		opera + "Preferences":        `{"extensions":{"settings":{}}}`,
+		opera + "Secure Preferences": `{}`,
+		// The profiles of root are read as well
+		"root/.config/chromium/Default/Preferences":        `{}`,
+		"root/.config/chromium/Default/Secure Preferences": `{}`,
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	hostfstest.SetRoot(t, root)
+
+	profiles, err := getChromeProfilePathList()
+	if err != nil {
+		t.Fatalf("Error listing the profiles: %v", err)
+	}
+	if len(profiles) != 3 || profiles[1].Type != Opera || profiles[1].Value != "/home/alice/.config/opera" ||
+		profiles[2].Type != Chromium || profiles[2].Uid != 0 || profiles[2].Value != "/root/.config/chromium/Default" {
+		t.Fatalf("Expected the Chrome and Opera profiles of alice and the Chromium profile of root, got %+v", profiles)
+	}
+
+	ctx := sqlctx.NewContext()
+	ctx.SetColumns([]string{"*"})
+	extensions, err := GenChromeExtensions(ctx)
+	if err != nil {
+		t.Fatalf("Error generating extensions: %v", err)
+	}
+
+	// The profiles and extensions are reported with their paths on the host
+	expected := []map[string]interface{}{
+		{"browser_type": "chrome", "uid": int64(1000), "profile": "Person 1", "profile_path": "/home/alice/.config/google-chrome/Default",
+			"name": "Cookie Helper", "identifier": "aeabkkaehgepiemfhhhpigjkgbjdmcpj", "referenced_identifier": "aeabkkaehgepiemfhhhpigjkgbjdmcpj",
+			"version": "1.0", "author": "dev@example.com", "persistent": int32(1), "referenced": int64(1), "state": "0", "from_webstore": "true",
+			"permissions": "cookies, <all_urls>", "permissions_json": `["cookies","<all_urls>"]`, "install_timestamp": int64(1655526400),
+			"path": "/home/alice/.config/google-chrome/Default/Extensions/aeabkkaehgepiemfhhhpigjkgbjdmcpj/1.0_0"},
+		{"browser_type": "chrome", "name": "Leftover", "version": "0.9", "referenced": int64(0), "referenced_identifier": "", "state": "",
+			"path": "/home/alice/.config/google-chrome/Default/Extensions/leftover/0.9_0"},
+	}
+	if extensions.Size() != len(expected) {
+		t.Fatalf("Expected %d extensions, got %v", len(expected), extensions)
+	}
+	for i, columns := range expected {
+		for column, value := range columns {
+			if (*extensions)[i].Get(column) != value {
+				t.Errorf("Expected %s = %v for extension %d, got %v", column, value, i, (*extensions)[i].Get(column))
+			}
+		}
+	}
+}
@@ -0,0 +1,11 @@
+//go:build !windows
+
+package chrome_extensions
+
+import "github.com/scrymastic/goosquery/tables/internal/hostfs"
+
+// hostPath returns the path of a file of the host under hostfs.Root, as
+// the home directories of /etc/passwd are relative to it
+func hostPath(path string) string {
+	return hostfs.Path(path)
+}
@@ -1,49 +1,98 @@
 package chrome_extensions
 
 import (
-	"encoding/json"
+	"os"
+	"path/filepath"
 	"testing"
 )
 
-func TestGetUserInformationList(t *testing.T) {
-	// Test case 1: Basic functionality
-	userInfoList, err := getUserInformationList()
+// writeProfile creates a Chrome profile directory with its preferences
+func writeProfile(t *testing.T, files map[string]string) string {
+	t.Helper()
+	profile := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(profile, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return profile
+}
+
+func TestIsValidChromeProfile(t *testing.T) {
+	profile := writeProfile(t, map[string]string{kPreferencesFile: "{}", kSecurePreferencesFile: "{}"})
+	if !isValidChromeProfile(profile) {
+		t.Errorf("Expected %s to be a valid profile", profile)
+	}
+
+	profile = writeProfile(t, map[string]string{kPreferencesFile: "{}"})
+	if isValidChromeProfile(profile) {
+		t.Errorf("Expected a profile without secure preferences to be invalid")
+	}
+}
+
+func TestCaptureProfileSnapshotSettingsFromPath(t *testing.T) {
+	profile := writeProfile(t, map[string]string{
+		kPreferencesFile:       `{"extensions":{"settings":{}}}`,
+		kSecurePreferencesFile: `{"extensions":{"settings":{"aaa":{}}}}`,
+	})
+
+	snapshot := ChromeProfileSnapshot{}
+	err := captureProfileSnapshotSettingsFromPath(&snapshot, ChromeProfilePath{Type: Brave, Value: profile, Uid: 1001})
 	if err != nil {
-		t.Errorf("getUserInformationList() returned error: %v", err)
+		t.Fatalf("Failed to capture profile settings: %v", err)
+	}
+	if snapshot.Type != Brave || snapshot.Path != profile || snapshot.Uid != 1001 {
+		t.Errorf("Unexpected snapshot identity: %+v", snapshot)
+	}
+	if snapshot.Preferences != `{"extensions":{"settings":{}}}` || snapshot.SecurePreferences != `{"extensions":{"settings":{"aaa":{}}}}` {
+		t.Errorf("Unexpected preferences: %q, %q", snapshot.Preferences, snapshot.SecurePreferences)
 	}
 
-	// We can't predict the exact number of users, but we can check if the list is populated
-	if len(userInfoList) == 0 {
-		t.Error("getUserInformationList() returned empty list")
+	err = captureProfileSnapshotSettingsFromPath(&snapshot, ChromeProfilePath{Value: t.TempDir()})
+	if err == nil {
+		t.Error("Expected an error for a profile without preferences")
 	}
+}
 
-	// Check that each user has valid UID and path
-	for _, user := range userInfoList {
-		if user.Uid == 0 {
-			t.Error("getUserInformationList() returned user with invalid UID")
-		}
-		if user.Path == "" {
-			t.Error("getUserInformationList() returned user with empty path")
+func TestChromePathSuffixMaps(t *testing.T) {
+	for _, goos := range []string{"windows", "linux", "darwin"} {
+		paths := chromePathSuffixMap(goos)
+		for browser := GoogleChrome; browser <= Arc; browser++ {
+			if paths[browser] == "" {
+				t.Errorf("Expected a %s profile path for browser %d", goos, browser)
+			}
 		}
 	}
+	if chromePathSuffixMap("darwin")[GoogleChrome] != "Library/Application Support/Google/Chrome" {
+		t.Errorf("Expected the macOS profile paths on darwin")
+	}
+	if chromePathSuffixMap("freebsd")[GoogleChrome] != ".config/google-chrome" {
+		t.Errorf("Expected the Linux profile paths on the other platforms")
+	}
 }
 
-func TestGetChromeProfilePathList(t *testing.T) {
-	// Test case 1: Basic functionality
-	profilePaths, err := getChromeProfilePathList()
-	if err != nil {
-		t.Errorf("getChromeProfilePathList() returned error: %v", err)
+func TestComputeIdentifier(t *testing.T) {
+	// The identifier is derived from the decoded public key
+	identifier := computeIdentifier("cHVibGljIGtleSBvZiB0aGUgZXh0ZW5zaW9u", "/ignored")
+	if identifier != "aeabkkaehgepiemfhhhpigjkgbjdmcpj" {
+		t.Errorf("Unexpected identifier %s", identifier)
 	}
+}
 
-	// We expect multiple paths (number of users * number of browser types)
-	if len(profilePaths) == 0 {
-		t.Error("getChromeProfilePathList() returned empty list")
+func TestPermissionList(t *testing.T) {
+	permissions, permissionsJson := permissionList(
+		[]byte(`["tabs",{"fileSystem":["write"]}]`),
+		[]byte(`["<all_urls>"]`),
+	)
+	if permissions != `tabs, {"fileSystem":["write"]}, <all_urls>` {
+		t.Errorf("Unexpected permissions %q", permissions)
+	}
+	if permissionsJson != `["tabs",{"fileSystem":["write"]},"<all_urls>"]` {
+		t.Errorf("Unexpected permissions JSON %q", permissionsJson)
 	}
 
-	// Pretty print the profile paths
-	json, err := json.MarshalIndent(profilePaths, "", "  ")
-	if err != nil {
-		t.Errorf("failed to marshal profile paths: %v", err)
+	permissions, permissionsJson = permissionList(nil)
+	if permissions != "" || permissionsJson != "" {
+		t.Errorf("Expected no permissions, got %q and %q", permissions, permissionsJson)
 	}
-	t.Logf("Profile paths: %s", string(json))
 }
@@ -0,0 +1,8 @@
+//go:build windows
+
+package chrome_extensions
+
+// hostPath returns the path of a file of the host
+func hostPath(path string) string {
+	return path
+}
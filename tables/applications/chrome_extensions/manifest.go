@@ -0,0 +1,296 @@
+package chrome_extensions
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/scrymastic/goosquery/sql/result"
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+)
+
+// browserTypeNames maps the browsers to the values of the browser_type column
+var browserTypeNames = map[int32]string{
+	GoogleChrome:       "chrome",
+	GoogleChromeBeta:   "chrome_beta",
+	GoogleChromeDev:    "chrome_dev",
+	GoogleChromeCanary: "chrome_canary",
+	Brave:              "brave",
+	Chromium:           "chromium",
+	Yandex:             "yandex",
+	Opera:              "opera",
+	Edge:               "edge",
+	EdgeBeta:           "edge_beta",
+	Vivaldi:            "vivaldi",
+	Arc:                "arc",
+}
+
+// webkitEpochOffset is the number of seconds between 1601-01-01, the epoch of
+// the install times, and 1970-01-01
+const webkitEpochOffset = 11644473600
+
+// preferences holds the parts of the Preferences and Secure Preferences files
+// describing the profile and its extensions
+type preferences struct {
+	Extensions struct {
+		Settings map[string]extensionSettings `json:"settings"`
+	} `json:"extensions"`
+	Profile struct {
+		Name string `json:"name"`
+	} `json:"profile"`
+}
+
+type extensionSettings struct {
+	Path           string          `json:"path"`
+	State          *int            `json:"state"`
+	DisableReasons json.RawMessage `json:"disable_reasons"`
+	FromWebstore   *bool           `json:"from_webstore"`
+	InstallTime    string          `json:"install_time"`
+}
+
+// extensionPath returns the folder of the extension, the relative paths being
+// under the Extensions folder of the profile
+func (s extensionSettings) extensionPath(profilePath string) string {
+	if s.Path == "" || filepath.IsAbs(s.Path) {
+		return s.Path
+	}
+	return filepath.Join(profilePath, kExtensionsFolderName, s.Path)
+}
+
+// enabled returns "1" if the extension is enabled, from its state in the
+// older versions and from the reasons it is disabled in the newer ones
+func (s extensionSettings) enabled() string {
+	if s.State != nil {
+		return strconv.Itoa(*s.State)
+	}
+	reasons := strings.TrimSpace(string(s.DisableReasons))
+	if reasons == "" || reasons == "0" || reasons == "[]" || reasons == "null" {
+		return "1"
+	}
+	return "0"
+}
+
+// readPreferences merges the Preferences and the Secure Preferences of the
+// profile, which keep the settings of the extensions in either of them
+func readPreferences(chromeProfileSnapshot *ChromeProfileSnapshot) preferences {
+	var prefs preferences
+	json.Unmarshal([]byte(chromeProfileSnapshot.Preferences), &prefs)
+	json.Unmarshal([]byte(chromeProfileSnapshot.SecurePreferences), &prefs)
+	return prefs
+}
+
+// readExtensionSettings returns the settings of the extensions by identifier
+func readExtensionSettings(chromeProfileSnapshot *ChromeProfileSnapshot) map[string]extensionSettings {
+	return readPreferences(chromeProfileSnapshot).Extensions.Settings
+}
+
+// manifest holds the fields of manifest.json reported by the table
+type manifest struct {
+	Name                string          `json:"name"`
+	Version             string          `json:"version"`
+	Description         string          `json:"description"`
+	DefaultLocale       string          `json:"default_locale"`
+	UpdateURL           string          `json:"update_url"`
+	Author              json.RawMessage `json:"author"`
+	Key                 string          `json:"key"`
+	Permissions         json.RawMessage `json:"permissions"`
+	HostPermissions     json.RawMessage `json:"host_permissions"`
+	OptionalPermissions json.RawMessage `json:"optional_permissions"`
+	Background          struct {
+		Persistent *bool `json:"persistent"`
+	} `json:"background"`
+}
+
+// parseManifest decodes a manifest, which may start with a byte order mark
+func parseManifest(content string) (manifest, error) {
+	var m manifest
+	err := json.Unmarshal(bytes.TrimPrefix([]byte(content), []byte("\xef\xbb\xbf")), &m)
+	return m, err
+}
+
+// author returns the author, a string or an object with an email
+func (m manifest) author() string {
+	var name string
+	if json.Unmarshal(m.Author, &name) == nil {
+		return name
+	}
+	var author struct {
+		Email string `json:"email"`
+	}
+	if json.Unmarshal(m.Author, &author) == nil {
+		return author.Email
+	}
+	return ""
+}
+
+// permissionList returns the permissions of lists such as "permissions" and
+// "host_permissions", as JSON and as a comma separated list where the
+// permissions with parameters are written as JSON
+func permissionList(lists ...json.RawMessage) (string, string) {
+	permissions := []interface{}{}
+	for _, list := range lists {
+		var entries []interface{}
+		if json.Unmarshal(list, &entries) == nil {
+			permissions = append(permissions, entries...)
+		}
+	}
+	if len(permissions) == 0 {
+		return "", ""
+	}
+	names := make([]string, 0, len(permissions))
+	for _, permission := range permissions {
+		if name, ok := permission.(string); ok {
+			names = append(names, name)
+			continue
+		}
+		names = append(names, marshalJSON(permission))
+	}
+	return strings.Join(names, ", "), marshalJSON(permissions)
+}
+
+// marshalJSON encodes a value as JSON, without escaping hosts such as <all_urls>
+func marshalJSON(value interface{}) string {
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.Encode(value)
+	return strings.TrimSuffix(buffer.String(), "\n")
+}
+
+// localize resolves a __MSG_name__ string from the messages of the locale
+func localize(extensionPath string, locale string, value string) string {
+	if !strings.HasPrefix(value, kLocalizedMessagePrefix) || !strings.HasSuffix(value, "__") || locale == "" {
+		return value
+	}
+	key := strings.TrimSuffix(strings.TrimPrefix(value, kLocalizedMessagePrefix), "__")
+
+	content, err := os.ReadFile(hostPath(filepath.Join(extensionPath, "_locales", locale, "messages.json")))
+	if err != nil {
+		return value
+	}
+	var messages map[string]struct {
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(bytes.TrimPrefix(content, []byte("\xef\xbb\xbf")), &messages) != nil {
+		return value
+	}
+	// The message names are case insensitive
+	for name, message := range messages {
+		if strings.EqualFold(name, key) {
+			return message.Message
+		}
+	}
+	return value
+}
+
+// computeIdentifier returns the identifier Chrome derives from the public key
+// of an extension, or from its path for the unpacked ones: the first 128 bits
+// of the SHA-256 hash, with the hexadecimal digits written from 'a' to 'p'
+func computeIdentifier(key string, path string) string {
+	data := []byte(path)
+	if decoded, err := base64.StdEncoding.DecodeString(key); key != "" && err == nil {
+		data = decoded
+	}
+	hash := sha256.Sum256(data)
+	identifier := []byte(hex.EncodeToString(hash[:16]))
+	for i, digit := range identifier {
+		value, _ := strconv.ParseUint(string(digit), 16, 8)
+		identifier[i] = byte('a' + value)
+	}
+	return string(identifier)
+}
+
+// genExtension generates the row of an extension, the settings being nil for
+// the extensions which are not referenced by the preferences
+func genExtension(ctx *sqlctx.Context, chromeProfileSnapshot *ChromeProfileSnapshot, profile string, referencedIdentifier string, settings *extensionSettings, extension Extension) *result.Result {
+	row := result.NewResult(ctx, Schema)
+	row.Set("browser_type", browserTypeNames[chromeProfileSnapshot.Type])
+	row.Set("uid", chromeProfileSnapshot.Uid)
+	row.Set("profile", profile)
+	row.Set("profile_path", chromeProfileSnapshot.Path)
+	row.Set("path", extension.Path)
+	row.Set("manifest_json", extension.Manifest)
+	hash := sha256.Sum256([]byte(extension.Manifest))
+	row.Set("manifest_hash", hex.EncodeToString(hash[:]))
+
+	if settings != nil {
+		row.Set("referenced", int64(1))
+		row.Set("referenced_identifier", referencedIdentifier)
+		row.Set("state", settings.enabled())
+		if settings.FromWebstore != nil {
+			row.Set("from_webstore", strconv.FormatBool(*settings.FromWebstore))
+		}
+		row.Set("install_time", settings.InstallTime)
+		if installTime, err := strconv.ParseInt(settings.InstallTime, 10, 64); err == nil {
+			row.Set("install_timestamp", installTime/1000000-webkitEpochOffset)
+		}
+	} else {
+		row.Set("referenced", int64(0))
+	}
+
+	m, err := parseManifest(extension.Manifest)
+	if err != nil {
+		return row
+	}
+	row.Set("name", localize(extension.Path, m.DefaultLocale, m.Name))
+	row.Set("description", localize(extension.Path, m.DefaultLocale, m.Description))
+	row.Set("version", m.Version)
+	row.Set("default_locale", m.DefaultLocale)
+	row.Set("update_url", m.UpdateURL)
+	row.Set("author", m.author())
+	row.Set("key", m.Key)
+	if m.Key == "" && referencedIdentifier != "" {
+		row.Set("identifier", referencedIdentifier)
+	} else {
+		row.Set("identifier", computeIdentifier(m.Key, extension.Path))
+	}
+	persistent := int32(0)
+	if m.Background.Persistent != nil && *m.Background.Persistent {
+		persistent = 1
+	}
+	row.Set("persistent", persistent)
+	permissions, permissionsJson := permissionList(m.Permissions, m.HostPermissions)
+	row.Set("permissions", permissions)
+	row.Set("permissions_json", permissionsJson)
+	optionalPermissions, optionalPermissionsJson := permissionList(m.OptionalPermissions)
+	row.Set("optional_permissions", optionalPermissions)
+	row.Set("optional_permissions_json", optionalPermissionsJson)
+	return row
+}
+
+// genProfileExtensions generates the extensions referenced by the preferences
+// of the profile, followed by the other extensions of its Extensions folder
+func genProfileExtensions(ctx *sqlctx.Context, chromeProfileSnapshot *ChromeProfileSnapshot) *result.Results {
+	extensions := result.NewQueryResult()
+	prefs := readPreferences(chromeProfileSnapshot)
+
+	identifiers := make([]string, 0, len(chromeProfileSnapshot.ReferencedExtensions))
+	for identifier := range chromeProfileSnapshot.ReferencedExtensions {
+		identifiers = append(identifiers, identifier)
+	}
+	sort.Strings(identifiers)
+	for _, identifier := range identifiers {
+		settings := prefs.Extensions.Settings[identifier]
+		extension := chromeProfileSnapshot.ReferencedExtensions[identifier]
+		extensions.AppendResult(*genExtension(ctx, chromeProfileSnapshot, prefs.Profile.Name, identifier, &settings, extension))
+	}
+
+	paths := make([]string, 0, len(chromeProfileSnapshot.UnreferencedExtensions))
+	for path := range chromeProfileSnapshot.UnreferencedExtensions {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		extension := chromeProfileSnapshot.UnreferencedExtensions[path]
+		extensions.AppendResult(*genExtension(ctx, chromeProfileSnapshot, prefs.Profile.Name, "", nil, extension))
+	}
+
+	return extensions
+}
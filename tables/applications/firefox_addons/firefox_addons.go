@@ -0,0 +1,235 @@
+package firefox_addons
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/scrymastic/goosquery/sql/result"
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+	"github.com/scrymastic/goosquery/tables/internal/homes"
+)
+
+// extensionsFile lists the addons installed in a profile, with their state
+type extensionsFile struct {
+	Addons []extension `json:"addons"`
+}
+
+type extension struct {
+	ID                     string `json:"id"`
+	Version                string `json:"version"`
+	Type                   string `json:"type"`
+	Location               string `json:"location"`
+	Path                   string `json:"path"`
+	SourceURI              string `json:"sourceURI"`
+	Active                 bool   `json:"active"`
+	Visible                bool   `json:"visible"`
+	UserDisabled           bool   `json:"userDisabled"`
+	AppDisabled            bool   `json:"appDisabled"`
+	SoftDisabled           bool   `json:"softDisabled"`
+	ApplyBackgroundUpdates int    `json:"applyBackgroundUpdates"`
+	SignedState            *int   `json:"signedState"`
+	DefaultLocale          struct {
+		Name        string          `json:"name"`
+		Description string          `json:"description"`
+		Creator     json.RawMessage `json:"creator"`
+	} `json:"defaultLocale"`
+	UserPermissions *struct {
+		Permissions []string `json:"permissions"`
+		Origins     []string `json:"origins"`
+	} `json:"userPermissions"`
+}
+
+// addonsFile caches the metadata of the addons from addons.mozilla.org
+type addonsFile struct {
+	Addons []addon `json:"addons"`
+}
+
+type addon struct {
+	ID          string          `json:"id"`
+	Type        string          `json:"type"`
+	Name        string          `json:"name"`
+	Version     string          `json:"version"`
+	Description string          `json:"description"`
+	Creator     json.RawMessage `json:"creator"`
+	SourceURI   string          `json:"sourceURI"`
+}
+
+// signedStates maps the signedState of extensions.json to its name
+var signedStates = map[int]string{
+	-2: "broken",
+	-1: "unknown",
+	0:  "missing",
+	1:  "preliminary",
+	2:  "signed",
+	3:  "system",
+	4:  "privileged",
+}
+
+// creatorName returns the creator, which is a string in extensions.json and
+// an object with a name in addons.json
+func creatorName(raw json.RawMessage) string {
+	var name string
+	if json.Unmarshal(raw, &name) == nil {
+		return name
+	}
+	var creator struct {
+		Name string `json:"name"`
+	}
+	if json.Unmarshal(raw, &creator) == nil {
+		return creator.Name
+	}
+	return ""
+}
+
+// boolToInt32 returns 1 for true and 0 for false
+func boolToInt32(b bool) int32 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// readJSON decodes a JSON file of a profile, a missing file being empty
+func readJSON(path string, v interface{}) error {
+	data, err := os.ReadFile(hostPath(path))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// genProfileAddons lists the addons of extensions.json, completed by the
+// metadata of addons.json, followed by the addons only known to addons.json
+func genProfileAddons(ctx *sqlctx.Context, uid int64, profilePath string) (*result.Results, error) {
+	addons := result.NewQueryResult()
+
+	var extensions extensionsFile
+	if err := readJSON(filepath.Join(profilePath, "extensions.json"), &extensions); err != nil {
+		return addons, err
+	}
+	var metadata addonsFile
+	if err := readJSON(filepath.Join(profilePath, "addons.json"), &metadata); err != nil {
+		return addons, err
+	}
+	metadataByID := make(map[string]addon, len(metadata.Addons))
+	for _, a := range metadata.Addons {
+		metadataByID[a.ID] = a
+	}
+
+	for _, e := range extensions.Addons {
+		a := metadataByID[e.ID]
+		delete(metadataByID, e.ID)
+
+		row := result.NewResult(ctx, Schema)
+		row.Set("uid", uid)
+		row.Set("identifier", e.ID)
+		row.Set("name", firstNonEmpty(e.DefaultLocale.Name, a.Name))
+		row.Set("creator", firstNonEmpty(creatorName(e.DefaultLocale.Creator), creatorName(a.Creator)))
+		row.Set("type", e.Type)
+		row.Set("version", e.Version)
+		row.Set("description", firstNonEmpty(e.DefaultLocale.Description, a.Description))
+		row.Set("source_url", firstNonEmpty(e.SourceURI, a.SourceURI))
+		row.Set("visible", boolToInt32(e.Visible))
+		row.Set("active", boolToInt32(e.Active))
+		row.Set("disabled", boolToInt32(e.UserDisabled || e.AppDisabled || e.SoftDisabled))
+		row.Set("autoupdate", boolToInt32(e.ApplyBackgroundUpdates != 0))
+		if e.SignedState != nil {
+			row.Set("signed_state", signedStates[*e.SignedState])
+		}
+		if e.UserPermissions != nil {
+			permissions := append(append([]string{}, e.UserPermissions.Permissions...), e.UserPermissions.Origins...)
+			row.Set("permissions", strings.Join(permissions, ", "))
+		}
+		row.Set("location", e.Location)
+		row.Set("path", e.Path)
+		row.Set("profile_path", profilePath)
+		addons.AppendResult(*row)
+	}
+
+	// The state of the addons missing from extensions.json is unknown
+	for _, a := range metadata.Addons {
+		if _, ok := metadataByID[a.ID]; !ok {
+			continue
+		}
+		row := result.NewResult(ctx, Schema)
+		row.Set("uid", uid)
+		row.Set("identifier", a.ID)
+		row.Set("name", a.Name)
+		row.Set("creator", creatorName(a.Creator))
+		row.Set("type", a.Type)
+		row.Set("version", a.Version)
+		row.Set("description", a.Description)
+		row.Set("source_url", a.SourceURI)
+		row.Set("profile_path", profilePath)
+		addons.AppendResult(*row)
+	}
+
+	return addons, nil
+}
+
+// firstNonEmpty returns the first of the strings which is not empty
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// getProfilePaths returns the profiles under the home directory, being the
+// directories holding an extensions.json or an addons.json
+func getProfilePaths(directory string) []string {
+	var profiles []string
+	for _, profileDir := range profileDirs() {
+		entries, err := os.ReadDir(hostPath(filepath.Join(directory, profileDir)))
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				continue
+			}
+			profile := filepath.Join(directory, profileDir, entry.Name())
+			for _, name := range []string{"extensions.json", "addons.json"} {
+				if _, err := os.Stat(hostPath(filepath.Join(profile, name))); err == nil {
+					profiles = append(profiles, profile)
+					break
+				}
+			}
+		}
+	}
+	return profiles
+}
+
+func GenFirefoxAddons(ctx *sqlctx.Context) (*result.Results, error) {
+	results := result.NewQueryResult()
+
+	userHomes, err := homes.List()
+	if err != nil {
+		return nil, err
+	}
+
+	// Several accounts may share a home directory
+	seen := make(map[string]bool)
+	for _, home := range userHomes {
+		if seen[home.Directory] {
+			continue
+		}
+		seen[home.Directory] = true
+		for _, profile := range getProfilePaths(home.Directory) {
+			addons, err := genProfileAddons(ctx, home.UID, profile)
+			if err == nil {
+				results.AppendResults(*addons)
+			}
+			// We don't return on error for individual profiles, just continue
+		}
+	}
+
+	return results, nil
+}
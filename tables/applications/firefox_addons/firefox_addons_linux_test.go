@@ -0,0 +1,56 @@
+//go:build linux
+
+package firefox_addons
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+	"github.com/scrymastic/goosquery/tables/internal/hostfstest"
+)
+
+func TestGenFirefoxAddonsFromRoot(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"etc/passwd": "root:x:0:0:root:/root:/bin/bash\n" +
+			"alice:x:1000:1000::/home/alice:/bin/sh\n",
+		"home/alice/.mozilla/firefox/abcd.default-release/extensions.json":         `{"addons":[{"id":"a@example.com","version":"1.0","active":true}]}`,
+		"home/alice/snap/firefox/common/.mozilla/firefox/efgh.default/addons.json": `{"addons":[{"id":"b@example.com","name":"B"}]}`,
+		"home/alice/.mozilla/firefox/Crash Reports/events/.keep":                   "",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	hostfstest.SetRoot(t, root)
+
+	ctx := sqlctx.NewContext()
+	ctx.SetColumns([]string{"uid", "identifier", "profile_path"})
+	addons, err := GenFirefoxAddons(ctx)
+	if err != nil {
+		t.Fatalf("Error generating addons: %v", err)
+	}
+
+	// The profiles are reported with their paths on the host
+	expected := []map[string]interface{}{
+		{"uid": int64(1000), "identifier": "a@example.com", "profile_path": "/home/alice/.mozilla/firefox/abcd.default-release"},
+		{"uid": int64(1000), "identifier": "b@example.com", "profile_path": "/home/alice/snap/firefox/common/.mozilla/firefox/efgh.default"},
+	}
+	if addons.Size() != len(expected) {
+		t.Fatalf("Expected %d addons, got %v", len(expected), addons)
+	}
+	for i, columns := range expected {
+		for column, value := range columns {
+			if (*addons)[i].Get(column) != value {
+				t.Errorf("Expected %s = %v for addon %d, got %v", column, value, i, (*addons)[i].Get(column))
+			}
+		}
+	}
+}
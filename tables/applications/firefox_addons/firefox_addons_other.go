@@ -0,0 +1,35 @@
+//go:build !windows
+
+package firefox_addons
+
+import (
+	"runtime"
+
+	"github.com/scrymastic/goosquery/tables/internal/hostfs"
+)
+
+// linuxProfileDirs are the directories of the Firefox profiles, relative to
+// the home directories, for the packages, the snap and the flatpak
+var linuxProfileDirs = []string{
+	".mozilla/firefox",
+	"snap/firefox/common/.mozilla/firefox",
+	".var/app/org.mozilla.firefox/.mozilla/firefox",
+}
+
+// macProfileDirs are the directories of the Firefox profiles on macOS
+var macProfileDirs = []string{"Library/Application Support/Firefox/Profiles"}
+
+// profileDirs returns the directories of the Firefox profiles, relative to
+// the home directories
+func profileDirs() []string {
+	if runtime.GOOS == "darwin" {
+		return macProfileDirs
+	}
+	return linuxProfileDirs
+}
+
+// hostPath returns the path of a file of the host under hostfs.Root, as
+// the home directories of /etc/passwd are relative to it
+func hostPath(path string) string {
+	return hostfs.Path(path)
+}
@@ -0,0 +1,63 @@
+package firefox_addons
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+)
+
+func TestGenProfileAddons(t *testing.T) {
+	profile := t.TempDir()
+	files := map[string]string{
+		"extensions.json": `{"schemaVersion":36,"addons":[
+			{"id":"uBlock0@raymondhill.net","version":"1.52.2","type":"extension","location":"app-profile",
+			 "path":"/home/alice/.mozilla/firefox/abcd.default/extensions/uBlock0@raymondhill.net.xpi",
+			 "sourceURI":"https://addons.mozilla.org/firefox/downloads/file/1/ublock_origin-1.52.2.xpi",
+			 "active":true,"visible":true,"userDisabled":false,"appDisabled":false,"applyBackgroundUpdates":1,
+			 "signedState":2,"defaultLocale":{"name":"uBlock Origin","description":"Finally, an efficient blocker.","creator":"Raymond Hill"},
+			 "userPermissions":{"permissions":["storage","tabs"],"origins":["<all_urls>"]}},
+			{"id":"side-loaded@example.com","version":"0.1","type":"extension","location":"app-system-share",
+			 "active":false,"visible":true,"userDisabled":true,"applyBackgroundUpdates":0,"signedState":0,
+			 "defaultLocale":{"name":"","creator":null}}]}`,
+		"addons.json": `{"schema":6,"addons":[
+			{"id":"side-loaded@example.com","name":"Side Loaded","creator":{"name":"Example","url":"https://example.com"}},
+			{"id":"stale@example.com","type":"extension","name":"Stale","version":"2.0"}]}`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(profile, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx := sqlctx.NewContext()
+	ctx.SetColumns([]string{"*"})
+	addons, err := genProfileAddons(ctx, 1000, profile)
+	if err != nil {
+		t.Fatalf("Error generating addons: %v", err)
+	}
+
+	// The metadata of addons.json completes extensions.json, and the addons
+	// only known to addons.json have no state
+	expected := []map[string]interface{}{
+		{"uid": int64(1000), "identifier": "uBlock0@raymondhill.net", "name": "uBlock Origin", "creator": "Raymond Hill",
+			"version": "1.52.2", "type": "extension", "active": int32(1), "visible": int32(1), "disabled": int32(0),
+			"autoupdate": int32(1), "signed_state": "signed", "permissions": "storage, tabs, <all_urls>",
+			"source_url": "https://addons.mozilla.org/firefox/downloads/file/1/ublock_origin-1.52.2.xpi",
+			"location":   "app-profile", "profile_path": profile},
+		{"identifier": "side-loaded@example.com", "name": "Side Loaded", "creator": "Example", "active": int32(0),
+			"disabled": int32(1), "autoupdate": int32(0), "signed_state": "missing", "permissions": "", "location": "app-system-share"},
+		{"identifier": "stale@example.com", "name": "Stale", "version": "2.0", "active": int32(-1), "signed_state": ""},
+	}
+	if addons.Size() != len(expected) {
+		t.Fatalf("Expected %d addons, got %v", len(expected), addons)
+	}
+	for i, columns := range expected {
+		for column, value := range columns {
+			if (*addons)[i].Get(column) != value {
+				t.Errorf("Expected %s = %v for addon %d, got %v", column, value, i, (*addons)[i].Get(column))
+			}
+		}
+	}
+}
@@ -0,0 +1,14 @@
+//go:build windows
+
+package firefox_addons
+
+// profileDirs returns the directories of the Firefox profiles, relative to
+// the home directories
+func profileDirs() []string {
+	return []string{`AppData\Roaming\Mozilla\Firefox\Profiles`}
+}
+
+// hostPath returns the path of a file of the host
+func hostPath(path string) string {
+	return path
+}
@@ -0,0 +1,27 @@
+package firefox_addons
+
+import (
+	"github.com/scrymastic/goosquery/sql/result"
+)
+
+var TableName = "firefox_addons"
+var Description = "Firefox browser extensions, webapps, and addons."
+var Schema = result.Schema{
+	result.Column{Name: "uid", Type: "BIGINT", Description: "The local user that owns the addon"},
+	result.Column{Name: "name", Type: "TEXT", Description: "Addon display name"},
+	result.Column{Name: "identifier", Type: "TEXT", Description: "Addon identifier"},
+	result.Column{Name: "creator", Type: "TEXT", Description: "Addon-supported creator string"},
+	result.Column{Name: "type", Type: "TEXT", Description: "Extension, addon, webapp"},
+	result.Column{Name: "version", Type: "TEXT", Description: "Addon-supplied version string"},
+	result.Column{Name: "description", Type: "TEXT", Description: "Addon-supplied description string"},
+	result.Column{Name: "source_url", Type: "TEXT", Description: "URL that installed the addon"},
+	result.Column{Name: "visible", Type: "INTEGER", Description: "1 If the addon is shown in browser else 0"},
+	result.Column{Name: "active", Type: "INTEGER", Description: "1 If the addon is active else 0"},
+	result.Column{Name: "disabled", Type: "INTEGER", Description: "1 If the addon is application-disabled else 0"},
+	result.Column{Name: "autoupdate", Type: "INTEGER", Description: "1 If the addon applies background updates else 0"},
+	result.Column{Name: "signed_state", Type: "TEXT", Description: "Signature of the addon: signed, system, privileged, preliminary, missing, broken or unknown"},
+	result.Column{Name: "permissions", Type: "TEXT", Description: "The permissions and host origins granted to the addon, separated by commas"},
+	result.Column{Name: "location", Type: "TEXT", Description: "Global, profile location"},
+	result.Column{Name: "path", Type: "TEXT", Description: "Path to plugin bundle"},
+	result.Column{Name: "profile_path", Type: "TEXT", Description: "The Firefox profile holding the addon"},
+}
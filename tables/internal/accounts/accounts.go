@@ -0,0 +1,170 @@
+// Package accounts reads the local accounts of a Linux host from
+// /etc/passwd, /etc/shadow and /etc/group under hostfs.Root.
+package accounts
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/scrymastic/goosquery/tables/internal/hostfs"
+)
+
+// User is an entry of /etc/passwd
+type User struct {
+	Name      string
+	UID       uint32
+	GID       uint32
+	Gecos     string
+	Directory string
+	Shell     string
+}
+
+// Shadow is an entry of /etc/shadow. The dates are in days since Epoch,
+// and -1 when the field is empty.
+type Shadow struct {
+	Name       string
+	Password   string
+	LastChange int64
+	Expire     int64
+}
+
+// Group is an entry of /etc/group
+type Group struct {
+	Name    string
+	GID     uint32
+	Members []string
+}
+
+// readEntries returns the colon separated fields of the lines of a file,
+// skipping comments and the lines with less than count fields
+func readEntries(path string, count int) ([][]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries [][]string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if fields := strings.Split(line, ":"); len(fields) >= count {
+			entries = append(entries, fields)
+		}
+	}
+	return entries, scanner.Err()
+}
+
+// parseID parses a uid or a gid
+func parseID(value string) (uint32, bool) {
+	id, err := strconv.ParseUint(value, 10, 32)
+	return uint32(id), err == nil
+}
+
+// parseDays parses a date of /etc/shadow
+func parseDays(value string) int64 {
+	days, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return -1
+	}
+	return days
+}
+
+// ReadUsers returns the users of /etc/passwd. Entries with malformed ids,
+// such as the NIS "+" entries, are skipped.
+func ReadUsers() ([]User, error) {
+	return ReadUsersUnder(hostfs.Root)
+}
+
+// ReadUsersUnder returns the users of the /etc/passwd of another root, such
+// as the directory where an offline image is mounted
+func ReadUsersUnder(root string) ([]User, error) {
+	entries, err := readEntries(hostfs.Under(root, "etc", "passwd"), 7)
+	if err != nil {
+		return nil, err
+	}
+	users := make([]User, 0, len(entries))
+	for _, fields := range entries {
+		uid, ok := parseID(fields[2])
+		if !ok {
+			continue
+		}
+		gid, ok := parseID(fields[3])
+		if !ok {
+			continue
+		}
+		users = append(users, User{
+			Name:      fields[0],
+			UID:       uid,
+			GID:       gid,
+			Gecos:     fields[4],
+			Directory: fields[5],
+			Shell:     fields[6],
+		})
+	}
+	return users, nil
+}
+
+// ReadShadow returns the entries of /etc/shadow by user name. The file is
+// only readable by root, so callers treat errors as missing fields.
+func ReadShadow() (map[string]Shadow, error) {
+	entries, err := readEntries(hostfs.Path("etc", "shadow"), 8)
+	if err != nil {
+		return nil, err
+	}
+	shadows := make(map[string]Shadow, len(entries))
+	for _, fields := range entries {
+		shadows[fields[0]] = Shadow{
+			Name:       fields[0],
+			Password:   fields[1],
+			LastChange: parseDays(fields[2]),
+			Expire:     parseDays(fields[7]),
+		}
+	}
+	return shadows, nil
+}
+
+// ReadGroups returns the groups of /etc/group
+func ReadGroups() ([]Group, error) {
+	entries, err := readEntries(hostfs.Path("etc", "group"), 4)
+	if err != nil {
+		return nil, err
+	}
+	groups := make([]Group, 0, len(entries))
+	for _, fields := range entries {
+		gid, ok := parseID(fields[2])
+		if !ok {
+			continue
+		}
+		group := Group{Name: fields[0], GID: gid}
+		for _, member := range strings.Split(fields[3], ",") {
+			if member = strings.TrimSpace(member); member != "" {
+				group.Members = append(group.Members, member)
+			}
+		}
+		groups = append(groups, group)
+	}
+	return groups, nil
+}
+
+// PasswordStatus describes the password field of /etc/shadow: "locked" for
+// the passwords disabled with a leading "!" or "*", "empty" for accounts
+// without password, "not_set" for accounts that never had one, and
+// "active" otherwise
+func (s Shadow) PasswordStatus() string {
+	switch {
+	case s.Password == "":
+		return "empty"
+	case s.Password == "!" || s.Password == "!!" || s.Password == "*":
+		return "not_set"
+	case strings.HasPrefix(s.Password, "!") || strings.HasPrefix(s.Password, "*"):
+		return "locked"
+	default:
+		return "active"
+	}
+}
@@ -0,0 +1,86 @@
+package accounts
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/scrymastic/goosquery/tables/internal/hostfstest"
+)
+
+func TestReadAccounts(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"etc/passwd": "root:x:0:0:root:/root:/bin/bash\n" +
+			"# comment\n" +
+			"+::::::\n" +
+			"alice:x:1000:1000:Alice,,,:/home/alice:/bin/zsh\n",
+		"etc/shadow": "root:*:19000:0:99999:7:::\n" +
+			"alice:$6$salt$hash:19500:0:99999:7::20000:\n",
+		"etc/group": "root:x:0:\n" +
+			"sudo:x:27:alice, bob\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	hostfstest.SetRoot(t, root)
+
+	users, err := ReadUsers()
+	if err != nil {
+		t.Fatalf("Failed to read users: %v", err)
+	}
+	expectedUsers := []User{
+		{Name: "root", UID: 0, GID: 0, Gecos: "root", Directory: "/root", Shell: "/bin/bash"},
+		{Name: "alice", UID: 1000, GID: 1000, Gecos: "Alice,,,", Directory: "/home/alice", Shell: "/bin/zsh"},
+	}
+	if !reflect.DeepEqual(users, expectedUsers) {
+		t.Errorf("Expected users %v, got %v", expectedUsers, users)
+	}
+
+	shadows, err := ReadShadow()
+	if err != nil {
+		t.Fatalf("Failed to read shadow: %v", err)
+	}
+	if root := shadows["root"]; root.PasswordStatus() != "not_set" || root.LastChange != 19000 || root.Expire != -1 {
+		t.Errorf("Unexpected shadow entry for root: %+v", root)
+	}
+	if alice := shadows["alice"]; alice.PasswordStatus() != "active" || alice.Expire != 20000 {
+		t.Errorf("Unexpected shadow entry for alice: %+v", alice)
+	}
+
+	groups, err := ReadGroups()
+	if err != nil {
+		t.Fatalf("Failed to read groups: %v", err)
+	}
+	expectedGroups := []Group{
+		{Name: "root", GID: 0},
+		{Name: "sudo", GID: 27, Members: []string{"alice", "bob"}},
+	}
+	if !reflect.DeepEqual(groups, expectedGroups) {
+		t.Errorf("Expected groups %v, got %v", expectedGroups, groups)
+	}
+}
+
+func TestPasswordStatus(t *testing.T) {
+	tests := map[string]string{
+		"":            "empty",
+		"!":           "not_set",
+		"*":           "not_set",
+		"!$6$s$h":     "locked",
+		"$y$j9T$s$h":  "active",
+		"!!":          "not_set",
+		"*LOCK*$6$sh": "locked",
+	}
+	for password, expected := range tests {
+		if status := (Shadow{Password: password}).PasswordStatus(); status != expected {
+			t.Errorf("Expected %s for %q, got %s", expected, password, status)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+//go:build linux
+
+package cpuinfo
+
+import (
+	"strings"
+	"syscall"
+)
+
+// Arch returns the machine hardware name of uname, named as on Windows:
+// x86, x86_64, ARM or ARM64
+func Arch() string {
+	var uname syscall.Utsname
+	if err := syscall.Uname(&uname); err != nil {
+		return ""
+	}
+	var machine strings.Builder
+	for _, c := range uname.Machine {
+		if c == 0 {
+			break
+		}
+		machine.WriteByte(byte(c))
+	}
+
+	switch name := machine.String(); {
+	case name == "aarch64" || name == "arm64":
+		return "ARM64"
+	case strings.HasPrefix(name, "arm"):
+		return "ARM"
+	case name == "i386" || name == "i486" || name == "i586" || name == "i686":
+		return "x86"
+	default:
+		return name
+	}
+}
@@ -0,0 +1,82 @@
+// Package cpuinfo reads the processors of a Linux host from /proc/cpuinfo,
+// for the tables describing the hardware.
+package cpuinfo
+
+import (
+	"bufio"
+	"os"
+	"strings"
+
+	"github.com/scrymastic/goosquery/tables/internal/hostfs"
+)
+
+// Processor holds the fields of a logical processor of /proc/cpuinfo, such
+// as "model name" or "physical id". The fields depend on the architecture.
+type Processor map[string]string
+
+// Read returns the logical processors of /proc/cpuinfo
+func Read() ([]Processor, error) {
+	file, err := os.Open(hostfs.Proc("cpuinfo"))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var processors []Processor
+	current := Processor{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		key, value, found := strings.Cut(scanner.Text(), ":")
+		if !found {
+			// Processors are separated by blank lines
+			if len(current) > 0 {
+				processors = append(processors, current)
+				current = Processor{}
+			}
+			continue
+		}
+		key = strings.TrimSpace(key)
+		// Some architectures end with fields of the whole system
+		if key == "processor" && current["processor"] != "" {
+			processors = append(processors, current)
+			current = Processor{}
+		}
+		current[key] = strings.TrimSpace(value)
+	}
+	if len(current) > 0 {
+		processors = append(processors, current)
+	}
+	return processors, scanner.Err()
+}
+
+// Packages groups the logical processors by physical package, in the order
+// of their first processor. Architectures without "physical id", such as
+// most ARM hosts, have a single package.
+func Packages(processors []Processor) [][]Processor {
+	var packages [][]Processor
+	indexes := make(map[string]int)
+	for _, processor := range processors {
+		if _, ok := processor["processor"]; !ok {
+			continue
+		}
+		id := processor["physical id"]
+		index, ok := indexes[id]
+		if !ok {
+			index = len(packages)
+			indexes[id] = index
+			packages = append(packages, nil)
+		}
+		packages[index] = append(packages[index], processor)
+	}
+	return packages
+}
+
+// Cores returns the number of physical cores of a package, from the
+// distinct "core id" of its processors
+func Cores(processors []Processor) int {
+	cores := make(map[string]bool)
+	for _, processor := range processors {
+		cores[processor["core id"]] = true
+	}
+	return len(cores)
+}
@@ -0,0 +1,60 @@
+package cpuinfo
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/scrymastic/goosquery/tables/internal/hostfstest"
+)
+
+func TestReadPackages(t *testing.T) {
+	root := t.TempDir()
+	var cpuinfo string
+	// Two sockets of two cores with hyper-threading
+	for i, ids := range [][2]string{{"0", "0"}, {"0", "1"}, {"1", "0"}, {"1", "1"}, {"0", "0"}, {"0", "1"}, {"1", "0"}, {"1", "1"}} {
+		cpuinfo += "processor\t: " + string(rune('0'+i)) + "\n" +
+			"model name\t: Test CPU\n" +
+			"physical id\t: " + ids[0] + "\n" +
+			"core id\t\t: " + ids[1] + "\n\n"
+	}
+	if err := os.WriteFile(filepath.Join(root, "cpuinfo"), []byte(cpuinfo), 0644); err != nil {
+		t.Fatal(err)
+	}
+	hostfstest.SetProcRoot(t, root)
+
+	processors, err := Read()
+	if err != nil {
+		t.Fatalf("Failed to read cpuinfo: %v", err)
+	}
+	if len(processors) != 8 || processors[7]["processor"] != "7" || processors[0]["model name"] != "Test CPU" {
+		t.Fatalf("Unexpected processors: %v", processors)
+	}
+
+	packages := Packages(processors)
+	if len(packages) != 2 || len(packages[0]) != 4 || packages[1][0]["processor"] != "2" {
+		t.Fatalf("Unexpected packages: %v", packages)
+	}
+	if cores := Cores(packages[0]); cores != 2 {
+		t.Errorf("Expected 2 cores, got %d", cores)
+	}
+}
+
+func TestReadWithoutBlankLines(t *testing.T) {
+	// ARM hosts list the processors, then the fields of the system
+	root := t.TempDir()
+	cpuinfo := "processor\t: 0\nBogoMIPS\t: 48.00\nprocessor\t: 1\nBogoMIPS\t: 48.00\n\nHardware\t: BCM2835\n"
+	if err := os.WriteFile(filepath.Join(root, "cpuinfo"), []byte(cpuinfo), 0644); err != nil {
+		t.Fatal(err)
+	}
+	hostfstest.SetProcRoot(t, root)
+
+	processors, err := Read()
+	if err != nil {
+		t.Fatalf("Failed to read cpuinfo: %v", err)
+	}
+	packages := Packages(processors)
+	if len(packages) != 1 || len(packages[0]) != 2 {
+		t.Errorf("Expected a package of 2 processors, got %v", packages)
+	}
+}
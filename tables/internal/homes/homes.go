@@ -0,0 +1,9 @@
+// Package homes lists the home directories of the users, where tables such
+// as chrome_extensions and firefox_addons look for browser profiles.
+package homes
+
+// Home is the home directory of a user
+type Home struct {
+	UID       int64
+	Directory string
+}
@@ -0,0 +1,18 @@
+//go:build darwin
+
+package homes
+
+// rootHome is the home directory of root on macOS
+const rootHome = "/var/root"
+
+// List returns the home directories of root and of the folders of /Users.
+// The users table has no macOS backend, the accounts being kept by Directory
+// Services rather than in /etc/passwd, so the homes are found on disk.
+func List() ([]Home, error) {
+	homes := []Home{{UID: 0, Directory: rootHome}}
+	users, err := scanUsersDir("/Users")
+	if err != nil {
+		return nil, err
+	}
+	return append(homes, users...), nil
+}
@@ -0,0 +1,28 @@
+//go:build !darwin
+
+package homes
+
+import (
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+	"github.com/scrymastic/goosquery/tables/system/users"
+)
+
+// List returns the home directories of the users table
+func List() ([]Home, error) {
+	ctx := sqlctx.NewContext()
+	ctx.SetColumns([]string{"uid", "directory"})
+	rows, err := users.GenUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var homes []Home
+	for _, row := range *rows {
+		uid, _ := row.Get("uid").(int64)
+		directory, _ := row.Get("directory").(string)
+		if directory != "" {
+			homes = append(homes, Home{UID: uid, Directory: directory})
+		}
+	}
+	return homes, nil
+}
@@ -0,0 +1,40 @@
+//go:build !windows
+
+package homes
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+
+	"github.com/scrymastic/goosquery/tables/internal/hostfs"
+)
+
+// scanUsersDir returns the home directories under a directory such as
+// /Users, owned by their users. Shared and the hidden folders are not homes.
+func scanUsersDir(dir string) ([]Home, error) {
+	entries, err := os.ReadDir(hostfs.Path(dir))
+	if err != nil {
+		return nil, err
+	}
+
+	var homes []Home
+	for _, entry := range entries {
+		if !entry.IsDir() || entry.Name() == "Shared" || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		stat, ok := info.Sys().(*syscall.Stat_t)
+		if !ok {
+			continue
+		}
+		homes = append(homes, Home{UID: int64(stat.Uid), Directory: filepath.Join(dir, entry.Name())})
+	}
+	sort.Slice(homes, func(i, j int) bool { return homes[i].Directory < homes[j].Directory })
+	return homes, nil
+}
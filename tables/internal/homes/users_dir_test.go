@@ -0,0 +1,35 @@
+//go:build !windows
+
+package homes
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/scrymastic/goosquery/tables/internal/hostfstest"
+)
+
+func TestScanUsersDir(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"Users/alice/Library", "Users/bob", "Users/Shared", "Users/.localized.d"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(root, "Users", ".localized"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	hostfstest.SetRoot(t, root)
+
+	homes, err := scanUsersDir("/Users")
+	if err != nil {
+		t.Fatalf("Failed to scan the homes: %v", err)
+	}
+	if len(homes) != 2 || homes[0].Directory != "/Users/alice" || homes[1].Directory != "/Users/bob" {
+		t.Fatalf("Expected the homes of alice and bob, got %+v", homes)
+	}
+	if homes[0].UID != int64(os.Getuid()) {
+		t.Errorf("Expected the uid of the owner %d, got %d", os.Getuid(), homes[0].UID)
+	}
+}
@@ -0,0 +1,60 @@
+// Package hostfs locates the files read by the Linux tables. The locations
+// can be moved, to read the host from a container where its /proc and /sys are
+// mounted elsewhere, to read the files of an offline image, or to run the
+// tables against fixture trees in tests.
+package hostfs
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// Root is the directory holding the files of the host, such as /etc/passwd,
+// / unless GOOSQUERY_ROOT is set
+var Root = fromEnv("GOOSQUERY_ROOT", "/")
+
+// ProcRoot is the directory of the proc filesystem, /proc unless
+// GOOSQUERY_PROC_ROOT is set
+var ProcRoot = fromEnv("GOOSQUERY_PROC_ROOT", "/proc")
+
+// SysRoot is the directory of the sys filesystem, /sys unless
+// GOOSQUERY_SYS_ROOT is set
+var SysRoot = fromEnv("GOOSQUERY_SYS_ROOT", "/sys")
+
+// fromEnv returns the value of an environment variable, or a default value
+func fromEnv(name string, defaultValue string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// Path returns the path of a file of the host, such as Path("/etc/passwd")
+func Path(elem ...string) string {
+	return Under(Root, elem...)
+}
+
+// Under returns the path of a file under another root than Root, such as the
+// directory where an offline image is mounted
+func Under(root string, elem ...string) string {
+	return filepath.Join(append([]string{root}, elem...)...)
+}
+
+// Proc returns the path of a file under the proc filesystem
+func Proc(elem ...string) string {
+	return filepath.Join(append([]string{ProcRoot}, elem...)...)
+}
+
+// Sys returns the path of a file under the sys filesystem
+func Sys(elem ...string) string {
+	return filepath.Join(append([]string{SysRoot}, elem...)...)
+}
+
+// Roots returns the roots constrained by a query, such as the directories
+// where offline images are mounted, or Root when there is none
+func Roots(constraints []string) []string {
+	if len(constraints) == 0 {
+		return []string{Root}
+	}
+	return constraints
+}
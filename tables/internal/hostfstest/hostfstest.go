@@ -0,0 +1,35 @@
+// Package hostfstest moves the locations of hostfs to fixture trees for the
+// duration of a test.
+package hostfstest
+
+import (
+	"testing"
+
+	"github.com/scrymastic/goosquery/tables/internal/hostfs"
+)
+
+// SetRoot reads the files of the host, such as /etc/passwd, from dir until
+// the end of the test
+func SetRoot(t testing.TB, dir string) {
+	t.Helper()
+	set(t, &hostfs.Root, dir)
+}
+
+// SetProcRoot reads the proc filesystem from dir until the end of the test
+func SetProcRoot(t testing.TB, dir string) {
+	t.Helper()
+	set(t, &hostfs.ProcRoot, dir)
+}
+
+// SetSysRoot reads the sys filesystem from dir until the end of the test
+func SetSysRoot(t testing.TB, dir string) {
+	t.Helper()
+	set(t, &hostfs.SysRoot, dir)
+}
+
+// set changes a location, restored when the test and its subtests finish
+func set(t testing.TB, location *string, dir string) {
+	previous := *location
+	*location = dir
+	t.Cleanup(func() { *location = previous })
+}
@@ -0,0 +1,22 @@
+package hostfstest
+
+import (
+	"testing"
+
+	"github.com/scrymastic/goosquery/tables/internal/hostfs"
+)
+
+func TestSetRestores(t *testing.T) {
+	root, proc, sys := hostfs.Root, hostfs.ProcRoot, hostfs.SysRoot
+	t.Run("fixture", func(t *testing.T) {
+		SetRoot(t, "/fixture")
+		SetProcRoot(t, "/fixture/proc")
+		SetSysRoot(t, "/fixture/sys")
+		if hostfs.Path("etc") != "/fixture/etc" || hostfs.Proc("1") != "/fixture/proc/1" || hostfs.Sys("block") != "/fixture/sys/block" {
+			t.Errorf("Expected the fixture locations, got %s, %s and %s", hostfs.Root, hostfs.ProcRoot, hostfs.SysRoot)
+		}
+	})
+	if hostfs.Root != root || hostfs.ProcRoot != proc || hostfs.SysRoot != sys {
+		t.Errorf("Expected the locations to be restored, got %s, %s and %s", hostfs.Root, hostfs.ProcRoot, hostfs.SysRoot)
+	}
+}
@@ -0,0 +1,63 @@
+//go:build linux
+
+package arp_cache
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/scrymastic/goosquery/sql/result"
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+	"github.com/scrymastic/goosquery/tables/internal/hostfs"
+)
+
+// Flags of the entries of /proc/net/arp
+const (
+	atfComplete  = 0x02
+	atfPermanent = 0x04
+)
+
+// GenARPCache retrieves the current ARP cache entries from /proc/net/arp,
+// whose lines are "IP address, HW type, Flags, HW address, Mask, Device"
+func GenARPCache(ctx *sqlctx.Context) (*result.Results, error) {
+	file, err := os.Open(hostfs.Proc("net", "arp"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ARP cache: %w", err)
+	}
+	defer file.Close()
+
+	entries := result.NewQueryResult()
+	scanner := bufio.NewScanner(file)
+	scanner.Scan()
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 6 {
+			continue
+		}
+		flags, err := strconv.ParseUint(fields[2], 0, 32)
+		if err != nil {
+			continue
+		}
+		// Incomplete entries are the neighbors that did not answer yet
+		if flags&(atfComplete|atfPermanent) == 0 || fields[3] == "00:00:00:00:00:00" {
+			continue
+		}
+
+		entry := result.NewResult(ctx, Schema)
+
+		entry.Set("address", fields[0])
+		entry.Set("mac", fields[3])
+		entry.Set("interface", fields[5])
+		entry.Set("permanent", map[bool]string{true: "1", false: "0"}[flags&atfPermanent != 0])
+
+		entries.AppendResult(*entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read ARP cache: %w", err)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,50 @@
+//go:build linux
+
+package arp_cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+	"github.com/scrymastic/goosquery/tables/internal/hostfstest"
+)
+
+func TestGenARPCacheFromProc(t *testing.T) {
+	root := t.TempDir()
+	arp := "IP address       HW type     Flags       HW address            Mask     Device\n" +
+		"192.168.1.1      0x1         0x2         52:54:00:12:34:56     *        eth0\n" +
+		"192.168.1.7      0x1         0x0         00:00:00:00:00:00     *        eth0\n" +
+		"192.168.1.9      0x1         0x6         52:54:00:ab:cd:ef     *        eth1\n"
+	if err := os.MkdirAll(filepath.Join(root, "net"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "net", "arp"), []byte(arp), 0644); err != nil {
+		t.Fatal(err)
+	}
+	hostfstest.SetProcRoot(t, root)
+
+	ctx := sqlctx.NewContext()
+	ctx.SetColumns([]string{"*"})
+	entries, err := GenARPCache(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get ARP entries: %v", err)
+	}
+	// The incomplete entry is skipped
+	if entries.Size() != 2 {
+		t.Fatalf("Expected 2 entries, got %d", entries.Size())
+	}
+
+	expected := []map[string]string{
+		{"address": "192.168.1.1", "mac": "52:54:00:12:34:56", "interface": "eth0", "permanent": "0"},
+		{"address": "192.168.1.9", "mac": "52:54:00:ab:cd:ef", "interface": "eth1", "permanent": "1"},
+	}
+	for i, columns := range expected {
+		for column, value := range columns {
+			if (*entries)[i].Get(column) != value {
+				t.Errorf("Expected %s = %q for entry %d, got %v", column, value, i, (*entries)[i].Get(column))
+			}
+		}
+	}
+}
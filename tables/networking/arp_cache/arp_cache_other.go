@@ -0,0 +1,15 @@
+//go:build !linux && !windows
+
+package arp_cache
+
+import (
+	"fmt"
+
+	"github.com/scrymastic/goosquery/sql/result"
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+)
+
+// GenARPCache fails, as the ARP cache is only read on Linux and Windows
+func GenARPCache(ctx *sqlctx.Context) (*result.Results, error) {
+	return nil, fmt.Errorf("the ARP cache is not supported on this platform")
+}
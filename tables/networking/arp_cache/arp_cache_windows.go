@@ -1,3 +1,5 @@
+//go:build windows
+
 package arp_cache
 
 import (
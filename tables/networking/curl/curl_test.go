@@ -3,14 +3,20 @@ package curl
 import (
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/scrymastic/goosquery/sql/sqlctx"
 )
 
 func TestGenCurl(t *testing.T) {
-	// Test with a known working URL
-	url := "https://httpbin.org/get"
+	// Test with a local server, so that no network access is needed
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "ok")
+	}))
+	defer server.Close()
+	url := server.URL
 	userAgent := ""
 
 	// Create context with URL
@@ -49,3 +55,31 @@ func TestGenCurl_InvalidURL(t *testing.T) {
 		t.Error("Expected error for invalid URL, got nil")
 	}
 }
+
+func TestGenCurlUserAgent(t *testing.T) {
+	// The server answers with the user agent it received
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, r.UserAgent())
+	}))
+	defer server.Close()
+
+	for _, userAgent := range []string{"test-agent", ""} {
+		ctx := sqlctx.NewContext()
+		ctx.SetColumns([]string{"user_agent", "result"})
+		ctx.AddConstant("url", server.URL)
+		expected := defaultUserAgent
+		if userAgent != "" {
+			ctx.AddConstant("user_agent", userAgent)
+			expected = userAgent
+		}
+
+		results, err := GenCurl(ctx)
+		if err != nil {
+			t.Fatalf("Failed to make request: %v", err)
+		}
+		row := results.GetRow(0)
+		if row.Get("user_agent") != expected || row.Get("result") != expected {
+			t.Errorf("Expected the user agent %q to be sent and reported, got %v", expected, row.ToMap())
+		}
+	}
+}
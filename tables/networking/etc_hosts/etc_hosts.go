@@ -4,22 +4,12 @@ import (
 	"bufio"
 	"fmt"
 	"os"
-	"path/filepath"
 	"strings"
 
 	"github.com/scrymastic/goosquery/sql/result"
 	"github.com/scrymastic/goosquery/sql/sqlctx"
-	"golang.org/x/sys/windows"
 )
 
-func getSystemRoot() string {
-	systemRoot, err := windows.GetWindowsDirectory()
-	if err != nil {
-		return `C:\Windows`
-	}
-	return systemRoot
-}
-
 func parseHostsFile(path string, ctx *sqlctx.Context) (*result.Results, error) {
 	file, err := os.Open(path)
 	if err != nil {
@@ -76,12 +66,7 @@ func parseHostsFile(path string, ctx *sqlctx.Context) (*result.Results, error) {
 // GenEtcHosts retrieves the contents of the hosts file from the system.
 // It returns a slice of map[string]interface{} and an error if the operation fails.
 func GenEtcHosts(ctx *sqlctx.Context) (*result.Results, error) {
-	// Get Windows system root
-	sysRoot := getSystemRoot()
-
-	// Construct paths to hosts files
-	hostsPath := filepath.Join(sysRoot, "System32", "drivers", "etc", "hosts")
-	hostsIcsPath := filepath.Join(sysRoot, "System32", "drivers", "etc", "hosts.ics")
+	hostsPath, optionalPaths := hostsFiles()
 
 	// Read and parse main hosts file
 	entries, err := parseHostsFile(hostsPath, ctx)
@@ -89,10 +74,12 @@ func GenEtcHosts(ctx *sqlctx.Context) (*result.Results, error) {
 		return nil, fmt.Errorf("error reading hosts file: %w", err)
 	}
 
-	// Read and parse ICS hosts file if it exists
-	icsEntries, err := parseHostsFile(hostsIcsPath, ctx)
-	if err == nil {
-		entries.AppendResults(*icsEntries)
+	// Read and parse other hosts files if they exist
+	for _, path := range optionalPaths {
+		optionalEntries, err := parseHostsFile(path, ctx)
+		if err == nil {
+			entries.AppendResults(*optionalEntries)
+		}
 	}
 
 	return entries, nil
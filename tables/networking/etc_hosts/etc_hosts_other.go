@@ -0,0 +1,8 @@
+//go:build !windows
+
+package etc_hosts
+
+// hostsFiles returns the path of the hosts file
+func hostsFiles() (string, []string) {
+	return "/etc/hosts", nil
+}
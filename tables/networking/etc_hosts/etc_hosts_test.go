@@ -9,7 +9,9 @@ import (
 )
 
 func TestGenEtcHosts(t *testing.T) {
-	entries, err := GenEtcHosts(sqlctx.NewContext())
+	ctx := sqlctx.NewContext()
+	ctx.SetColumns([]string{"*"})
+	entries, err := GenEtcHosts(ctx)
 	if err != nil {
 		t.Fatalf("Failed to get hosts entries: %v", err)
 	}
@@ -24,10 +26,10 @@ func TestGenEtcHosts(t *testing.T) {
 
 	// Basic validation of entries
 	for i, entry := range *entries {
-		if address, ok := entry["address"]; !ok || address.(string) == "" {
+		if address, ok := entry.Lookup("address"); !ok || address.(string) == "" {
 			t.Errorf("Entry %d has empty or missing address", i)
 		}
-		if hostnames, ok := entry["hostnames"]; !ok || hostnames.(string) == "" {
+		if hostnames, ok := entry.Lookup("hostnames"); !ok || hostnames.(string) == "" {
 			t.Errorf("Entry %d has empty or missing hostnames", i)
 		}
 	}
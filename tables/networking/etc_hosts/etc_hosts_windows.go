@@ -0,0 +1,24 @@
+//go:build windows
+
+package etc_hosts
+
+import (
+	"path/filepath"
+
+	"golang.org/x/sys/windows"
+)
+
+func getSystemRoot() string {
+	systemRoot, err := windows.GetWindowsDirectory()
+	if err != nil {
+		return `C:\Windows`
+	}
+	return systemRoot
+}
+
+// hostsFiles returns the path of the hosts file, and of the hosts file of
+// Internet Connection Sharing which may not exist
+func hostsFiles() (string, []string) {
+	etcPath := filepath.Join(getSystemRoot(), "System32", "drivers", "etc")
+	return filepath.Join(etcPath, "hosts"), []string{filepath.Join(etcPath, "hosts.ics")}
+}
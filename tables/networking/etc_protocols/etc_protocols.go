@@ -4,22 +4,12 @@ import (
 	"bufio"
 	"fmt"
 	"os"
-	"path/filepath"
 	"strings"
 
 	"github.com/scrymastic/goosquery/sql/result"
 	"github.com/scrymastic/goosquery/sql/sqlctx"
-	"golang.org/x/sys/windows"
 )
 
-func getSystemRoot() string {
-	systemRoot, err := windows.GetWindowsDirectory()
-	if err != nil {
-		return `C:\Windows`
-	}
-	return systemRoot
-}
-
 func parseProtocolsFile(path string, ctx *sqlctx.Context) (*result.Results, error) {
 	file, err := os.Open(path)
 	if err != nil {
@@ -81,8 +71,7 @@ func parseProtocolsFile(path string, ctx *sqlctx.Context) (*result.Results, erro
 // GenEtcProtocols retrieves the contents of the protocols file from the system.
 // It returns a slice of map[string]interface{} and an error if the operation fails.
 func GenEtcProtocols(ctx *sqlctx.Context) (*result.Results, error) {
-	protocolsPath := filepath.Join(getSystemRoot(), "System32", "drivers", "etc", "protocol")
-	protocols, err := parseProtocolsFile(protocolsPath, ctx)
+	protocols, err := parseProtocolsFile(protocolsFile(), ctx)
 	if err != nil {
 		return nil, fmt.Errorf("error parsing protocols file: %w", err)
 	}
@@ -0,0 +1,8 @@
+//go:build !windows
+
+package etc_protocols
+
+// protocolsFile returns the path of the protocols file
+func protocolsFile() string {
+	return "/etc/protocols"
+}
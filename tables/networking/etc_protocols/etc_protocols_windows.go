@@ -0,0 +1,22 @@
+//go:build windows
+
+package etc_protocols
+
+import (
+	"path/filepath"
+
+	"golang.org/x/sys/windows"
+)
+
+func getSystemRoot() string {
+	systemRoot, err := windows.GetWindowsDirectory()
+	if err != nil {
+		return `C:\Windows`
+	}
+	return systemRoot
+}
+
+// protocolsFile returns the path of the protocols file
+func protocolsFile() string {
+	return filepath.Join(getSystemRoot(), "System32", "drivers", "etc", "protocol")
+}
@@ -4,21 +4,12 @@ import (
 	"bufio"
 	"fmt"
 	"os"
-	"path/filepath"
 	"strings"
 
 	"github.com/scrymastic/goosquery/sql/result"
 	"github.com/scrymastic/goosquery/sql/sqlctx"
 )
 
-func getSystemRoot() string {
-	systemRoot := os.Getenv("SystemRoot")
-	if systemRoot == "" {
-		systemRoot = `C:\Windows`
-	}
-	return systemRoot
-}
-
 func parseServiceEntry(line string, ctx *sqlctx.Context) (*result.Result, bool) {
 	// Skip empty lines and comments
 	if len(line) == 0 || strings.HasPrefix(line, "#") {
@@ -92,14 +83,8 @@ func parseServicesFile(path string, ctx *sqlctx.Context) (*result.Results, error
 // GenEtcServices retrieves the contents of the services file from the system.
 // It returns a slice of map[string]interface{} and an error if the operation fails.
 func GenEtcServices(ctx *sqlctx.Context) (*result.Results, error) {
-	// Get Windows system root
-	sysRoot := getSystemRoot()
-
-	// Construct path to services file
-	servicesPath := filepath.Join(sysRoot, "System32", "drivers", "etc", "services")
-
 	// Read and parse services
-	services, err := parseServicesFile(servicesPath, ctx)
+	services, err := parseServicesFile(servicesFile(), ctx)
 	if err != nil {
 		return nil, fmt.Errorf("error reading services file: %w", err)
 	}
@@ -0,0 +1,8 @@
+//go:build !windows
+
+package etc_services
+
+// servicesFile returns the path of the services file
+func servicesFile() string {
+	return "/etc/services"
+}
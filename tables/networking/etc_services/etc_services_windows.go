@@ -0,0 +1,21 @@
+//go:build windows
+
+package etc_services
+
+import (
+	"os"
+	"path/filepath"
+)
+
+func getSystemRoot() string {
+	systemRoot := os.Getenv("SystemRoot")
+	if systemRoot == "" {
+		systemRoot = `C:\Windows`
+	}
+	return systemRoot
+}
+
+// servicesFile returns the path of the services file
+func servicesFile() string {
+	return filepath.Join(getSystemRoot(), "System32", "drivers", "etc", "services")
+}
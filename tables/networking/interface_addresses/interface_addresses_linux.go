@@ -0,0 +1,59 @@
+//go:build linux
+
+package interface_addresses
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/scrymastic/goosquery/sql/result"
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+)
+
+// processInterfaceAddress returns the row of an address of an interface
+func processInterfaceAddress(iface net.Interface, ipNet *net.IPNet, ctx *sqlctx.Context) *result.Result {
+	address := result.NewResult(ctx, Schema)
+	ones, _ := ipNet.Mask.Size()
+	isIPv6 := ipNet.IP.To4() == nil
+
+	address.Set("interface", iface.Name)
+	address.Set("friendly_name", iface.Name)
+	address.Set("address", ipNet.IP.String())
+	address.Set("mask", ipNetMaskToString(uint8(ones), isIPv6))
+	address.Set("broadcast", calculateBroadcast(ipNet.IP, uint8(ones)))
+	address.Set("point_to_point", "false")
+	if iface.Flags&net.FlagPointToPoint != 0 {
+		address.Set("point_to_point", "true")
+	}
+	// The origin of an address is only known to the tools that configured it
+	address.Set("type", "unknown")
+	if iface.Flags&net.FlagLoopback != 0 {
+		address.Set("type", "other")
+	}
+
+	return address
+}
+
+// GenInterfaceAddresses returns a list of all interface addresses on the system
+func GenInterfaceAddresses(ctx *sqlctx.Context) (*result.Results, error) {
+	interfaces, err := net.Interfaces()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list interfaces: %v", err)
+	}
+
+	results := result.NewQueryResult()
+	for _, iface := range interfaces {
+		addrs, err := iface.Addrs()
+		if err != nil {
+			// The interface may be removed while it is listed
+			continue
+		}
+		for _, addr := range addrs {
+			if ipNet, ok := addr.(*net.IPNet); ok {
+				results.AppendResult(*processInterfaceAddress(iface, ipNet, ctx))
+			}
+		}
+	}
+
+	return results, nil
+}
@@ -0,0 +1,56 @@
+//go:build linux
+
+package interface_addresses
+
+import (
+	"net"
+	"testing"
+
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+)
+
+func TestProcessInterfaceAddress(t *testing.T) {
+	ctx := sqlctx.NewContext()
+	ctx.SetColumns([]string{"*"})
+
+	iface := net.Interface{Index: 2, Name: "eth0", Flags: net.FlagUp | net.FlagBroadcast}
+	_, ipNet, _ := net.ParseCIDR("192.168.1.0/24")
+	ipNet.IP = net.IPv4(192, 168, 1, 20)
+	address := processInterfaceAddress(iface, ipNet, ctx)
+
+	expected := map[string]string{
+		"interface":      "eth0",
+		"friendly_name":  "eth0",
+		"address":        "192.168.1.20",
+		"mask":           "255.255.255.0",
+		"broadcast":      "192.168.1.255",
+		"point_to_point": "false",
+		"type":           "unknown",
+	}
+	for column, value := range expected {
+		if address.Get(column) != value {
+			t.Errorf("Expected %s = %q, got %v", column, value, address.Get(column))
+		}
+	}
+
+	iface = net.Interface{Index: 1, Name: "lo", Flags: net.FlagUp | net.FlagLoopback}
+	address = processInterfaceAddress(iface, &net.IPNet{IP: net.IPv6loopback, Mask: net.CIDRMask(128, 128)}, ctx)
+	if address.Get("mask") != "ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff" || address.Get("broadcast") != "" || address.Get("type") != "other" {
+		t.Errorf("Unexpected loopback address: %v", address)
+	}
+}
+
+func TestGenInterfaceAddressesLive(t *testing.T) {
+	ctx := sqlctx.NewContext()
+	ctx.SetColumns([]string{"interface", "address"})
+
+	addresses, err := GenInterfaceAddresses(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get interface addresses: %v", err)
+	}
+	for _, address := range *addresses {
+		if net.ParseIP(address.Get("address").(string)) == nil {
+			t.Errorf("Expected an IP address, got %v", address.Get("address"))
+		}
+	}
+}
@@ -0,0 +1,15 @@
+//go:build !linux && !windows
+
+package interface_addresses
+
+import (
+	"fmt"
+
+	"github.com/scrymastic/goosquery/sql/result"
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+)
+
+// GenInterfaceAddresses fails, as addresses are only listed on Linux and Windows
+func GenInterfaceAddresses(ctx *sqlctx.Context) (*result.Results, error) {
+	return nil, fmt.Errorf("interface addresses are not supported on this platform")
+}
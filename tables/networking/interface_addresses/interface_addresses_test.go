@@ -1,29 +1,44 @@
 package interface_addresses
 
 import (
-	"encoding/json"
-	"fmt"
+	"net"
 	"testing"
-
-	"github.com/scrymastic/goosquery/sql/sqlctx"
 )
 
-func TestGenInterfaceAddresses(t *testing.T) {
-	addresses, err := GenInterfaceAddresses(sqlctx.NewContext())
-	if err != nil {
-		t.Fatalf("Failed to get interface addresses: %v", err)
+func TestIPNetMaskToString(t *testing.T) {
+	tests := []struct {
+		prefixLength uint8
+		isIPv6       bool
+		expected     string
+	}{
+		{24, false, "255.255.255.0"},
+		{32, false, "255.255.255.255"},
+		{0, false, "0.0.0.0"},
+		{64, true, "ffff:ffff:ffff:ffff::"},
+		{128, true, "ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff"},
 	}
-
-	// Verify we got at least one interface
-	if addresses.Size() == 0 {
-		t.Error("Expected at least one interface address, got none")
+	for _, test := range tests {
+		if mask := ipNetMaskToString(test.prefixLength, test.isIPv6); mask != test.expected {
+			t.Errorf("Expected mask %s for /%d, got %s", test.expected, test.prefixLength, mask)
+		}
 	}
+}
 
-	// Print results as JSON for inspection
-	jsonData, err := json.MarshalIndent(addresses, "", "  ")
-	if err != nil {
-		t.Fatalf("Failed to marshal interface addresses to JSON: %v", err)
+func TestCalculateBroadcast(t *testing.T) {
+	tests := []struct {
+		ip           net.IP
+		prefixLength uint8
+		expected     string
+	}{
+		{net.IPv4(192, 168, 1, 20).To4(), 24, "192.168.1.255"},
+		// The 16 bytes form of an IPv4 address gives the same broadcast
+		{net.IPv4(192, 168, 1, 20), 24, "192.168.1.255"},
+		{net.IPv4(10, 1, 2, 3), 8, "10.255.255.255"},
+		{net.ParseIP("fe80::1"), 64, ""},
+	}
+	for _, test := range tests {
+		if broadcast := calculateBroadcast(test.ip, test.prefixLength); broadcast != test.expected {
+			t.Errorf("Expected broadcast %q for %s/%d, got %q", test.expected, test.ip, test.prefixLength, broadcast)
+		}
 	}
-	fmt.Printf("Interface Addresses Results:\n%s\n", string(jsonData))
-	fmt.Printf("Total interfaces: %d\n", addresses.Size())
 }
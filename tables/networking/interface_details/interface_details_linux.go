@@ -0,0 +1,125 @@
+//go:build linux
+
+package interface_details
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/scrymastic/goosquery/sql/result"
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+	"github.com/scrymastic/goosquery/tables/internal/hostfs"
+)
+
+// iffUp is the netdevice flag of the interfaces that are up
+const iffUp = 0x1
+
+// interfaceStats maps the columns to the counters of
+// /sys/class/net/<interface>/statistics
+var interfaceStats = map[string]string{
+	"ipackets":   "rx_packets",
+	"opackets":   "tx_packets",
+	"ibytes":     "rx_bytes",
+	"obytes":     "tx_bytes",
+	"ierrors":    "rx_errors",
+	"oerrors":    "tx_errors",
+	"idrops":     "rx_dropped",
+	"odrops":     "tx_dropped",
+	"collisions": "collisions",
+}
+
+// readSysValue returns the trimmed content of an attribute of an interface
+func readSysValue(name string, elem ...string) (string, bool) {
+	data, err := os.ReadFile(hostfs.Sys(append([]string{"class", "net", name}, elem...)...))
+	if err != nil {
+		return "", false
+	}
+	return strings.TrimSpace(string(data)), true
+}
+
+// readSysInt returns a numeric attribute of an interface, such as the mtu or
+// the hexadecimal flags
+func readSysInt(name string, elem ...string) (int64, bool) {
+	value, ok := readSysValue(name, elem...)
+	if !ok {
+		return 0, false
+	}
+	// Reading the speed of an interface without link fails or gives -1
+	number, err := strconv.ParseInt(value, 0, 64)
+	return number, err == nil
+}
+
+// getInterfaceDetails fills the columns of an interface from the attributes
+// of /sys/class/net/<interface>. Missing attributes leave their columns to
+// the default values.
+func getInterfaceDetails(ifDetail *result.Result, ctx *sqlctx.Context, name string) {
+	ifDetail.Set("interface", name)
+	ifDetail.Set("friendly_name", name)
+	ifDetail.Set("last_change", int64(-1))
+	// Linux has no metric for interfaces, only for routes
+	ifDetail.Set("metric", int32(0))
+
+	if mac, ok := readSysValue(name, "address"); ok {
+		ifDetail.Set("mac", mac)
+	}
+	if description, ok := readSysValue(name, "ifalias"); ok {
+		ifDetail.Set("description", description)
+	}
+	if ifType, ok := readSysInt(name, "type"); ok {
+		ifDetail.Set("type", int32(ifType))
+	}
+	if mtu, ok := readSysInt(name, "mtu"); ok {
+		ifDetail.Set("mtu", int32(mtu))
+	}
+	if flags, ok := readSysInt(name, "flags"); ok {
+		ifDetail.Set("flags", int32(flags))
+		ifDetail.Set("enabled", boolToInt32(flags&iffUp != 0))
+	}
+
+	for column, counter := range interfaceStats {
+		if !ctx.IsColumnUsed(column) {
+			continue
+		}
+		if value, ok := readSysInt(name, "statistics", counter); ok {
+			ifDetail.Set(column, value)
+		}
+	}
+
+	if ctx.IsAnyOfColumnsUsed([]string{"physical_adapter", "service"}) {
+		// Virtual interfaces, such as bridges and tunnels, have no device
+		_, err := os.Stat(hostfs.Sys("class", "net", name, "device"))
+		ifDetail.Set("physical_adapter", boolToInt32(err == nil))
+		if driver, err := os.Readlink(hostfs.Sys("class", "net", name, "device", "driver")); err == nil {
+			ifDetail.Set("service", filepath.Base(driver))
+		}
+	}
+
+	if ctx.IsColumnUsed("speed") {
+		// The speed is in megabits per second
+		if speed, ok := readSysInt(name, "speed"); ok && speed > 0 {
+			ifDetail.Set("speed", int32(min(speed*1000000, math.MaxInt32)))
+		}
+	}
+}
+
+// GenInterfaceDetails returns the details and statistics of the interfaces
+// of /sys/class/net
+func GenInterfaceDetails(ctx *sqlctx.Context) (*result.Results, error) {
+	entries, err := os.ReadDir(hostfs.Sys("class", "net"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list interfaces: %v", err)
+	}
+
+	interfaces := result.NewQueryResult()
+	for _, entry := range entries {
+		ifDetail := result.NewResult(ctx, Schema)
+		getInterfaceDetails(ifDetail, ctx, entry.Name())
+		interfaces.AppendResult(*ifDetail)
+	}
+
+	return interfaces, nil
+}
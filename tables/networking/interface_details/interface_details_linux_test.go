@@ -0,0 +1,135 @@
+//go:build linux
+
+package interface_details
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+	"github.com/scrymastic/goosquery/tables/internal/hostfstest"
+)
+
+// writeSysFixture creates a sys filesystem with a physical interface and
+// the loopback interface, and makes the table read it
+func writeSysFixture(t *testing.T) {
+	t.Helper()
+	root := t.TempDir()
+	files := map[string]string{
+		"devices/pci0000:00/0000:00:03.0/net/eth0/address":               "52:54:00:12:34:56\n",
+		"devices/pci0000:00/0000:00:03.0/net/eth0/type":                  "1\n",
+		"devices/pci0000:00/0000:00:03.0/net/eth0/mtu":                   "1500\n",
+		"devices/pci0000:00/0000:00:03.0/net/eth0/flags":                 "0x1003\n",
+		"devices/pci0000:00/0000:00:03.0/net/eth0/speed":                 "1000\n",
+		"devices/pci0000:00/0000:00:03.0/net/eth0/ifalias":               "uplink\n",
+		"devices/pci0000:00/0000:00:03.0/net/eth0/statistics/rx_packets": "120\n",
+		"devices/pci0000:00/0000:00:03.0/net/eth0/statistics/tx_packets": "80\n",
+		"devices/pci0000:00/0000:00:03.0/net/eth0/statistics/rx_bytes":   "64000\n",
+		"devices/pci0000:00/0000:00:03.0/net/eth0/statistics/tx_bytes":   "32000\n",
+		"devices/pci0000:00/0000:00:03.0/net/eth0/statistics/rx_errors":  "1\n",
+		"devices/pci0000:00/0000:00:03.0/net/eth0/statistics/tx_errors":  "2\n",
+		"devices/pci0000:00/0000:00:03.0/net/eth0/statistics/rx_dropped": "3\n",
+		"devices/pci0000:00/0000:00:03.0/net/eth0/statistics/tx_dropped": "4\n",
+		"devices/pci0000:00/0000:00:03.0/net/eth0/statistics/collisions": "5\n",
+		"devices/virtual/net/lo/address":                                 "00:00:00:00:00:00\n",
+		"devices/virtual/net/lo/type":                                    "772\n",
+		"devices/virtual/net/lo/mtu":                                     "65536\n",
+		"devices/virtual/net/lo/flags":                                   "0x8\n",
+		"devices/virtual/net/lo/statistics/rx_packets":                   "7\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Join(root, "class/net"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	links := map[string]string{
+		"class/net/eth0": "../../devices/pci0000:00/0000:00:03.0/net/eth0",
+		"class/net/lo":   "../../devices/virtual/net/lo",
+		"devices/pci0000:00/0000:00:03.0/net/eth0/device": "../../../0000:00:03.0",
+		"devices/pci0000:00/0000:00:03.0/driver":          "../../../bus/pci/drivers/virtio_net",
+	}
+	for name, target := range links {
+		if err := os.Symlink(target, filepath.Join(root, name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	hostfstest.SetSysRoot(t, root)
+}
+
+func TestGenInterfaceDetailsFromSys(t *testing.T) {
+	writeSysFixture(t)
+	ctx := sqlctx.NewContext()
+	ctx.SetColumns([]string{"*"})
+
+	interfaces, err := GenInterfaceDetails(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get interface details: %v", err)
+	}
+	if interfaces.Size() != 2 {
+		t.Fatalf("Expected 2 interfaces, got %d", interfaces.Size())
+	}
+
+	expected := map[string]interface{}{
+		"interface":        "eth0",
+		"mac":              "52:54:00:12:34:56",
+		"description":      "uplink",
+		"type":             int32(1),
+		"mtu":              int32(1500),
+		"flags":            int32(0x1003),
+		"enabled":          int32(1),
+		"ipackets":         int64(120),
+		"opackets":         int64(80),
+		"ibytes":           int64(64000),
+		"obytes":           int64(32000),
+		"ierrors":          int64(1),
+		"oerrors":          int64(2),
+		"idrops":           int64(3),
+		"odrops":           int64(4),
+		"collisions":       int64(5),
+		"physical_adapter": int32(1),
+		"service":          "virtio_net",
+		"speed":            int32(1000000000),
+	}
+	eth0 := (*interfaces)[0]
+	for column, value := range expected {
+		if eth0.Get(column) != value {
+			t.Errorf("Expected %s = %v for eth0, got %v", column, value, eth0.Get(column))
+		}
+	}
+
+	expected = map[string]interface{}{
+		"interface":        "lo",
+		"type":             int32(772),
+		"enabled":          int32(0),
+		"ipackets":         int64(7),
+		"physical_adapter": int32(0),
+		// Missing counters keep their default value
+		"opackets": int64(-1),
+		"speed":    int32(-1),
+	}
+	lo := (*interfaces)[1]
+	for column, value := range expected {
+		if lo.Get(column) != value {
+			t.Errorf("Expected %s = %v for lo, got %v", column, value, lo.Get(column))
+		}
+	}
+}
+
+func TestGenInterfaceDetailsLive(t *testing.T) {
+	ctx := sqlctx.NewContext()
+	ctx.SetColumns([]string{"interface", "mtu"})
+
+	if _, err := GenInterfaceDetails(ctx); err != nil {
+		t.Fatalf("Failed to get interface details: %v", err)
+	}
+}
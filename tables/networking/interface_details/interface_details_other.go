@@ -0,0 +1,15 @@
+//go:build !linux && !windows
+
+package interface_details
+
+import (
+	"fmt"
+
+	"github.com/scrymastic/goosquery/sql/result"
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+)
+
+// GenInterfaceDetails fails, as interfaces are only listed on Linux and Windows
+func GenInterfaceDetails(ctx *sqlctx.Context) (*result.Results, error) {
+	return nil, fmt.Errorf("interface details are not supported on this platform")
+}
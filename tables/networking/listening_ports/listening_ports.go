@@ -11,31 +11,12 @@ import (
 
 // GenListeningPorts retrieves information about listening ports from all process sockets
 func GenListeningPorts(ctx *sqlctx.Context) (*result.Results, error) {
-	// Remap columns
+	// The columns of the sockets are needed to find the listening ones, and
+	// the pids constrained by the query still limit the sockets listed
 	context := sqlctx.NewContext()
-	if ctx.IsColumnUsed("pid") {
-		context.AddConstant("pid", "pid")
-	}
-	if ctx.IsColumnUsed("port") {
-		context.AddConstant("port", "local_port")
-	}
-	if ctx.IsColumnUsed("protocol") {
-		context.AddConstant("protocol", "proto")
-	}
-	if ctx.IsColumnUsed("family") {
-		context.AddConstant("family", "family")
-	}
-	if ctx.IsColumnUsed("address") {
-		context.AddConstant("address", "local_address")
-	}
-	if ctx.IsColumnUsed("fd") {
-		context.AddConstant("fd", "fd")
-	}
-	if ctx.IsColumnUsed("socket") {
-		context.AddConstant("socket", "socket")
-	}
-	if ctx.IsColumnUsed("path") {
-		context.AddConstant("path", "path")
+	context.SetColumns([]string{"*"})
+	for _, pid := range ctx.GetConstants("pid") {
+		context.AddConstant("pid", pid)
 	}
 
 	// Get all open sockets
@@ -48,15 +29,17 @@ func GenListeningPorts(ctx *sqlctx.Context) (*result.Results, error) {
 
 	for i := 0; i < sockets.Size(); i++ {
 		socket := sockets.GetRow(i)
+		family, _ := socket.Get("family").(int32)
+
 		// Skip anonymous unix domain sockets
-		if family, ok := socket.Get("family").(int32); ok && family == syscall.AF_UNIX {
+		if family == syscall.AF_UNIX {
 			if path, ok := socket.Get("path").(string); ok && path == "" {
 				continue
 			}
 		}
 
 		// For IPv4/IPv6 sockets, only include those with remote_port = 0 (listening)
-		if family, ok := socket.Get("family").(int32); ok && (family == syscall.AF_INET || family == syscall.AF_INET6) {
+		if family == syscall.AF_INET || family == syscall.AF_INET6 {
 			if remotePort, ok := socket.Get("remote_port").(int32); ok && remotePort != 0 {
 				continue
 			}
@@ -64,44 +47,19 @@ func GenListeningPorts(ctx *sqlctx.Context) (*result.Results, error) {
 
 		// Initialize port map with default values for all requested columns
 		port := result.NewResult(ctx, Schema)
-
-		// Copy values from socket map if they exist
-		if pid, ok := socket.Get("pid").(int32); ok {
-			port.Set("pid", pid)
-		}
-
-		if proto, ok := socket.Get("proto").(int32); ok {
-			port.Set("protocol", proto)
-		}
-
-		if family, ok := socket.Get("family").(int32); ok {
-			port.Set("family", family)
-		}
-
-		if fd, ok := socket.Get("fd").(int32); ok {
-			port.Set("fd", fd)
-		}
-
-		if sock, ok := socket.Get("socket").(int32); ok {
-			port.Set("socket", sock)
-		}
-
-		if path, ok := socket.Get("path").(string); ok {
-			port.Set("path", path)
-		}
+		port.Set("pid", socket.Get("pid"))
+		port.Set("protocol", socket.Get("protocol"))
+		port.Set("family", family)
+		port.Set("fd", socket.Get("fd"))
+		port.Set("socket", socket.Get("socket"))
+		port.Set("path", socket.Get("path"))
 
 		// Handle different socket families
-		if family, ok := socket.Get("family").(int32); ok {
-			if family == syscall.AF_UNIX {
-				port.Set("port", int32(0))
-			} else {
-				if addr, ok := socket.Get("local_address").(string); ok {
-					port.Set("address", addr)
-				}
-				if lport, ok := socket.Get("local_port").(int32); ok {
-					port.Set("port", lport)
-				}
-			}
+		if family == syscall.AF_UNIX {
+			port.Set("port", int32(0))
+		} else {
+			port.Set("address", socket.Get("local_address"))
+			port.Set("port", socket.Get("local_port"))
 		}
 
 		results.AppendResult(*port)
@@ -0,0 +1,66 @@
+//go:build linux
+
+package listening_ports
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+	"github.com/scrymastic/goosquery/tables/internal/hostfstest"
+)
+
+func TestGenListeningPortsFromProc(t *testing.T) {
+	root := t.TempDir()
+	header := "  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode\n"
+	files := map[string]string{
+		"net/tcp": header +
+			"   0: 00000000:0016 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 1001 1 0000000000000000 100 0 0 10 0\n" +
+			"   1: 0100007F:0016 0100007F:C350 01 00000000:00000000 00:00000000 00000000     0        0 1002 1 0000000000000000 20 4 30 10 -1\n",
+		"net/udp6": header + "    0: 00000000000000000000000000000000:0035 00000000000000000000000000000000:0000 07 00000000:00000000 00:00000000 00000000   101        0 2001 2 0000000000000000 0\n",
+		"net/unix": "Num       RefCount Protocol Flags    Type St Inode Path\n" +
+			"0000000000000000: 00000002 00000000 00010000 0001 01 1003 /run/app.sock\n" +
+			"0000000000000000: 00000003 00000000 00000000 0001 03 1004\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.MkdirAll(filepath.Join(root, "100", "fd"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("socket:[1001]", filepath.Join(root, "100", "fd", "3")); err != nil {
+		t.Fatal(err)
+	}
+	hostfstest.SetProcRoot(t, root)
+
+	ctx := sqlctx.NewContext()
+	ctx.SetColumns([]string{"*"})
+	ports, err := GenListeningPorts(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get listening ports: %v", err)
+	}
+
+	// The established connection and the anonymous unix socket are left out
+	expected := []map[string]interface{}{
+		{"pid": int32(100), "port": int32(22), "protocol": int32(6), "family": int32(2), "address": "0.0.0.0", "fd": int64(3), "socket": int64(1001)},
+		{"pid": int32(-1), "port": int32(53), "protocol": int32(17), "family": int32(10), "address": "::", "socket": int64(2001)},
+		{"pid": int32(-1), "port": int32(0), "family": int32(1), "path": "/run/app.sock", "socket": int64(1003)},
+	}
+	if ports.Size() != len(expected) {
+		t.Fatalf("Expected %d listening ports, got %v", len(expected), ports)
+	}
+	for i, columns := range expected {
+		for column, value := range columns {
+			if got := (*ports)[i].Get(column); got != value {
+				t.Errorf("Expected %s = %v for port %d, got %v", column, value, i, got)
+			}
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package networking
 
 import (
+	"slices"
+
 	"github.com/scrymastic/goosquery/sql/result"
 	"github.com/scrymastic/goosquery/sql/sqlctx"
 	"github.com/scrymastic/goosquery/tables/networking/arp_cache"
@@ -18,16 +20,34 @@ import (
 	"github.com/scrymastic/goosquery/tables/networking/windows_firewall_rules"
 )
 
+// Tables lists the networking tables with their schemas and the platforms providing them
+var Tables = slices.Concat(
+	result.OnPlatform([]result.Table{
+		{Name: curl.TableName, Description: curl.Description, Schema: curl.Schema},
+		{Name: curl_certificate.TableName, Description: curl_certificate.Description, Schema: curl_certificate.Schema, Status: result.NotImplemented},
+		{Name: etc_hosts.TableName, Description: etc_hosts.Description, Schema: etc_hosts.Schema},
+		{Name: etc_protocols.TableName, Description: etc_protocols.Description, Schema: etc_protocols.Schema},
+		{Name: etc_services.TableName, Description: etc_services.Description, Schema: etc_services.Schema},
+	}, "windows", "linux", "darwin"),
+	result.OnPlatform([]result.Table{
+		{Name: arp_cache.TableName, Description: arp_cache.Description, Schema: arp_cache.Schema},
+		{Name: interface_addresses.TableName, Description: interface_addresses.Description, Schema: interface_addresses.Schema},
+		{Name: interface_details.TableName, Description: interface_details.Description, Schema: interface_details.Schema},
+		{Name: listening_ports.TableName, Description: listening_ports.Description, Schema: listening_ports.Schema},
+		{Name: process_open_sockets.TableName, Description: process_open_sockets.Description, Schema: process_open_sockets.Schema},
+		{Name: routes.TableName, Description: routes.Description, Schema: routes.Schema},
+	}, "windows", "linux"),
+	result.OnPlatform([]result.Table{
+		{Name: connectivity.TableName, Description: connectivity.Description, Schema: connectivity.Schema},
+		{Name: windows_firewall_rules.TableName, Description: windows_firewall_rules.Description, Schema: windows_firewall_rules.Schema},
+	}, "windows"),
+)
+
 // GenARPCache generates ARP cache entries
 func GenARPCache(ctx *sqlctx.Context) (*result.Results, error) {
 	return arp_cache.GenARPCache(ctx)
 }
 
-// GenConnectivity generates connectivity information
-func GenConnectivity(ctx *sqlctx.Context) (*result.Results, error) {
-	return connectivity.GenConnectivity(ctx)
-}
-
 // GenCurl generates results from a curl request
 func GenCurl(ctx *sqlctx.Context) (*result.Results, error) {
 	return curl.GenCurl(ctx)
@@ -77,8 +97,3 @@ func GenProcessOpenSockets(ctx *sqlctx.Context) (*result.Results, error) {
 func GenRoutes(ctx *sqlctx.Context) (*result.Results, error) {
 	return routes.GenRoutes(ctx)
 }
-
-// GenWindowsFirewallRules generates Windows firewall rules
-func GenWindowsFirewallRules(ctx *sqlctx.Context) (*result.Results, error) {
-	return windows_firewall_rules.GenWindowsFirewallRules(ctx)
-}
@@ -0,0 +1,20 @@
+//go:build windows
+
+package networking
+
+import (
+	"github.com/scrymastic/goosquery/sql/result"
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+	"github.com/scrymastic/goosquery/tables/networking/connectivity"
+	"github.com/scrymastic/goosquery/tables/networking/windows_firewall_rules"
+)
+
+// GenConnectivity generates connectivity information
+func GenConnectivity(ctx *sqlctx.Context) (*result.Results, error) {
+	return connectivity.GenConnectivity(ctx)
+}
+
+// GenWindowsFirewallRules generates Windows firewall rules
+func GenWindowsFirewallRules(ctx *sqlctx.Context) (*result.Results, error) {
+	return windows_firewall_rules.GenWindowsFirewallRules(ctx)
+}
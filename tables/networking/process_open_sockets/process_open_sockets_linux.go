@@ -0,0 +1,327 @@
+//go:build linux
+
+package process_open_sockets
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/scrymastic/goosquery/sql/result"
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+	"github.com/scrymastic/goosquery/tables/internal/hostfs"
+)
+
+// linuxTCPStates maps the states of /proc/net/tcp to the names used on
+// Windows, so that queries on the state work on both platforms
+var linuxTCPStates = map[uint64]string{
+	0x01: "ESTABLISHED",
+	0x02: "SYN_SENT",
+	0x03: "SYN_RCVD",
+	0x04: "FIN_WAIT1",
+	0x05: "FIN_WAIT2",
+	0x06: "TIME_WAIT",
+	0x07: "CLOSED",
+	0x08: "CLOSE_WAIT",
+	0x09: "LAST_ACK",
+	0x0A: "LISTEN",
+	0x0B: "CLOSING",
+	0x0C: "SYN_RCVD",
+}
+
+// socketFile describes a socket table of /proc/<pid>/net
+type socketFile struct {
+	name     string
+	family   int32
+	protocol int32
+}
+
+var socketFiles = []socketFile{
+	{"tcp", syscall.AF_INET, syscall.IPPROTO_TCP},
+	{"tcp6", syscall.AF_INET6, syscall.IPPROTO_TCP},
+	{"udp", syscall.AF_INET, syscall.IPPROTO_UDP},
+	{"udp6", syscall.AF_INET6, syscall.IPPROTO_UDP},
+	{"raw", syscall.AF_INET, syscall.IPPROTO_RAW},
+	{"raw6", syscall.AF_INET6, syscall.IPPROTO_RAW},
+}
+
+// socketOwner is a file descriptor of a process referring to a socket
+type socketOwner struct {
+	pid int32
+	fd  int64
+}
+
+// parseHexAddress parses an address of /proc/net/tcp such as 0100007F:0277.
+// The address is printed as 32 bit words in host byte order.
+func parseHexAddress(value string, family int32) (string, int32, error) {
+	addrHex, portHex, found := strings.Cut(value, ":")
+	if !found {
+		return "", 0, fmt.Errorf("malformed address: %s", value)
+	}
+	port, err := strconv.ParseUint(portHex, 16, 16)
+	if err != nil {
+		return "", 0, fmt.Errorf("malformed port: %s", value)
+	}
+	words, err := hex.DecodeString(addrHex)
+	if err != nil || (len(words) != 4 && len(words) != 16) {
+		return "", 0, fmt.Errorf("malformed address: %s", value)
+	}
+
+	var addr [16]byte
+	for i := 0; i < len(words); i += 4 {
+		binary.NativeEndian.PutUint32(addr[i:], binary.BigEndian.Uint32(words[i:]))
+	}
+	if family == syscall.AF_INET {
+		return formatIPv4Address(binary.LittleEndian.Uint32(addr[:4])), int32(port), nil
+	}
+	return formatIPv6Address(addr), int32(port), nil
+}
+
+// listSocketOwners maps socket inodes to the processes using them, by
+// reading the links of /proc/<pid>/fd. It also returns a pid for each
+// network namespace, to read the sockets of every namespace.
+func listSocketOwners(pids []string) (map[uint64][]socketOwner, map[string]string) {
+	owners := make(map[uint64][]socketOwner)
+	namespaces := make(map[string]string)
+	for _, pid := range pids {
+		pidNumber, err := strconv.ParseInt(pid, 10, 32)
+		if err != nil {
+			continue
+		}
+		if link, err := os.Readlink(hostfs.Proc(pid, "ns", "net")); err == nil {
+			namespace := strings.TrimSuffix(strings.TrimPrefix(link, "net:["), "]")
+			if _, ok := namespaces[namespace]; !ok {
+				namespaces[namespace] = pid
+			}
+		}
+
+		// The descriptors of the processes of other users need privileges
+		fds, err := os.ReadDir(hostfs.Proc(pid, "fd"))
+		if err != nil {
+			continue
+		}
+		for _, fd := range fds {
+			link, err := os.Readlink(hostfs.Proc(pid, "fd", fd.Name()))
+			if err != nil || !strings.HasPrefix(link, "socket:[") {
+				continue
+			}
+			inode, err := strconv.ParseUint(strings.TrimSuffix(strings.TrimPrefix(link, "socket:["), "]"), 10, 64)
+			if err != nil {
+				continue
+			}
+			fdNumber, _ := strconv.ParseInt(fd.Name(), 10, 64)
+			owners[inode] = append(owners[inode], socketOwner{pid: int32(pidNumber), fd: fdNumber})
+		}
+	}
+	return owners, namespaces
+}
+
+// listProcessIDs returns the pids constrained by the query, or the pids of
+// all the processes
+func listProcessIDs(ctx *sqlctx.Context) ([]string, bool, error) {
+	var pids []string
+	for _, pid := range ctx.GetConstants("pid") {
+		if _, err := strconv.ParseInt(pid, 10, 32); err != nil {
+			pids = nil
+			break
+		}
+		pids = append(pids, pid)
+	}
+	if len(pids) > 0 {
+		return pids, true, nil
+	}
+
+	entries, err := os.ReadDir(hostfs.ProcRoot)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read %s: %v", hostfs.ProcRoot, err)
+	}
+	for _, entry := range entries {
+		if _, err := strconv.ParseInt(entry.Name(), 10, 32); err == nil && entry.IsDir() {
+			pids = append(pids, entry.Name())
+		}
+	}
+	return pids, false, nil
+}
+
+// readSocketLines returns the lines of a socket table without its header
+func readSocketLines(path string) ([][]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var lines [][]string
+	scanner := bufio.NewScanner(file)
+	scanner.Scan()
+	for scanner.Scan() {
+		if fields := strings.Fields(scanner.Text()); len(fields) > 0 {
+			lines = append(lines, fields)
+		}
+	}
+	return lines, scanner.Err()
+}
+
+// socketGenerator builds the rows of the sockets of a network namespace
+type socketGenerator struct {
+	ctx       *sqlctx.Context
+	owners    map[uint64][]socketOwner
+	ownedOnly bool // whether sockets without a known process are skipped
+	sockets   *result.Results
+}
+
+// addSocket appends a row for each process using the socket, or a row
+// without pid when the process is unknown
+func (g *socketGenerator) addSocket(inode uint64, namespace string, fill func(socket *result.Result)) {
+	owners := g.owners[inode]
+	if len(owners) == 0 {
+		if g.ownedOnly {
+			return
+		}
+		owners = []socketOwner{{pid: -1, fd: -1}}
+	}
+	for _, owner := range owners {
+		socket := result.NewResult(g.ctx, Schema)
+		socket.Set("pid", owner.pid)
+		socket.Set("fd", owner.fd)
+		socket.Set("socket", int64(inode))
+		socket.Set("net_namespace", namespace)
+		fill(socket)
+		g.sockets.AppendResult(*socket)
+	}
+}
+
+// parseInetSockets parses a tcp, udp or raw table of /proc/<pid>/net, whose
+// lines are "sl local_address rem_address st ... uid timeout inode"
+func (g *socketGenerator) parseInetSockets(path string, file socketFile, namespace string) error {
+	lines, err := readSocketLines(path)
+	if err != nil {
+		return err
+	}
+	for _, fields := range lines {
+		if len(fields) < 10 {
+			continue
+		}
+		inode, err := strconv.ParseUint(fields[9], 10, 64)
+		if err != nil {
+			continue
+		}
+		localAddress, localPort, err := parseHexAddress(fields[1], file.family)
+		if err != nil {
+			continue
+		}
+		remoteAddress, remotePort, err := parseHexAddress(fields[2], file.family)
+		if err != nil {
+			continue
+		}
+		protocol := file.protocol
+		if protocol == syscall.IPPROTO_RAW {
+			// The port of raw sockets is the IP protocol they receive
+			protocol, localPort, remotePort = localPort, 0, 0
+		}
+		state := ""
+		if file.protocol == syscall.IPPROTO_TCP {
+			code, _ := strconv.ParseUint(fields[3], 16, 8)
+			if state = linuxTCPStates[code]; state == "" {
+				state = fmt.Sprintf("UNKNOWN (%d)", code)
+			}
+		}
+
+		g.addSocket(inode, namespace, func(socket *result.Result) {
+			socket.Set("family", file.family)
+			socket.Set("protocol", protocol)
+			socket.Set("local_address", localAddress)
+			socket.Set("remote_address", remoteAddress)
+			socket.Set("path", "")
+			socket.Set("state", state)
+			socket.Set("local_port", localPort)
+			socket.Set("remote_port", remotePort)
+		})
+	}
+	return nil
+}
+
+// parseUnixSockets parses /proc/<pid>/net/unix, whose lines are
+// "Num RefCount Protocol Flags Type St Inode Path"
+func (g *socketGenerator) parseUnixSockets(path string, namespace string) error {
+	lines, err := readSocketLines(path)
+	if err != nil {
+		return err
+	}
+	for _, fields := range lines {
+		if len(fields) < 7 {
+			continue
+		}
+		inode, err := strconv.ParseUint(fields[6], 10, 64)
+		if err != nil {
+			continue
+		}
+		socketPath := ""
+		if len(fields) > 7 {
+			socketPath = fields[7]
+		}
+
+		g.addSocket(inode, namespace, func(socket *result.Result) {
+			socket.Set("family", int32(syscall.AF_UNIX))
+			socket.Set("protocol", int32(0))
+			socket.Set("local_address", "")
+			socket.Set("remote_address", "")
+			socket.Set("local_port", int32(0))
+			socket.Set("remote_port", int32(0))
+			socket.Set("path", socketPath)
+			socket.Set("state", "")
+		})
+	}
+	return nil
+}
+
+// GenProcessOpenSockets returns a list of open sockets for each process
+func GenProcessOpenSockets(ctx *sqlctx.Context) (*result.Results, error) {
+	pids, constrained, err := listProcessIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	owners, namespaces := listSocketOwners(pids)
+
+	// The tables of /proc/net are those of the namespace of goosquery, so
+	// the tables of the other namespaces are read through their processes
+	netDirs := make(map[string]string)
+	for namespace, pid := range namespaces {
+		netDirs[namespace] = hostfs.Proc(pid, "net")
+	}
+	if len(netDirs) == 0 {
+		netDirs[""] = hostfs.Proc("net")
+	}
+
+	generator := &socketGenerator{
+		ctx:       ctx,
+		owners:    owners,
+		ownedOnly: constrained,
+		sockets:   result.NewQueryResult(),
+	}
+	names := make([]string, 0, len(netDirs))
+	for namespace := range netDirs {
+		names = append(names, namespace)
+	}
+	sort.Strings(names)
+	for _, namespace := range names {
+		dir := netDirs[namespace]
+		for _, file := range socketFiles {
+			// Tables are missing when a protocol is disabled, such as IPv6
+			if err := generator.parseInetSockets(dir+"/"+file.name, file, namespace); err != nil && !os.IsNotExist(err) {
+				return nil, fmt.Errorf("failed to read %s sockets: %v", file.name, err)
+			}
+		}
+		if err := generator.parseUnixSockets(dir+"/unix", namespace); err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to read unix sockets: %v", err)
+		}
+	}
+
+	return generator.sockets, nil
+}
@@ -0,0 +1,142 @@
+//go:build linux
+
+package process_open_sockets
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+	"github.com/scrymastic/goosquery/tables/internal/hostfstest"
+)
+
+// writeProcFixture creates a proc filesystem with a process listening on
+// TCP port 22, connected over IPv6 and bound to a unix socket, along with
+// sockets of unknown processes
+func writeProcFixture(t *testing.T) {
+	t.Helper()
+	root := t.TempDir()
+	header := "  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode\n"
+	files := map[string]string{
+		"100/net/tcp":  header + "   0: 00000000:0016 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 1001 1 0000000000000000 100 0 0 10 0\n",
+		"100/net/tcp6": header + "   0: 00000000000000000000000001000000:1F90 00000000000000000000000001000000:C350 01 00000000:00000000 00:00000000 00000000  1000        0 1002 1 0000000000000000 20 4 30 10 -1\n",
+		"100/net/udp":  header + "  123: 0100007F:0035 00000000:0000 07 00000000:00000000 00:00000000 00000000   101        0 2001 2 0000000000000000 0\n",
+		"100/net/unix": "Num       RefCount Protocol Flags    Type St Inode Path\n" +
+			"0000000000000000: 00000002 00000000 00010000 0001 01 1003 /run/app.sock\n" +
+			"0000000000000000: 00000003 00000000 00000000 0001 03 1004\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	links := map[string]string{
+		"100/ns/net": "net:[4026531840]",
+		"100/fd/0":   "/dev/null",
+		"100/fd/3":   "socket:[1001]",
+		"100/fd/4":   "socket:[1002]",
+		"100/fd/5":   "socket:[1003]",
+	}
+	for name, target := range links {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Symlink(target, path); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	hostfstest.SetProcRoot(t, root)
+}
+
+func TestParseHexAddress(t *testing.T) {
+	tests := []struct {
+		value   string
+		family  int32
+		address string
+		port    int32
+	}{
+		{"0100007F:0277", 2, "127.0.0.1", 631},
+		{"00000000:0016", 2, "0.0.0.0", 22},
+		{"00000000000000000000000001000000:1F90", 10, "::1", 8080},
+		{"0000000000000000FFFF00000100007F:0035", 10, "127.0.0.1", 53},
+		{"B80D0120000000000000000001000000:01BB", 10, "2001:db8::1", 443},
+	}
+	for _, test := range tests {
+		address, port, err := parseHexAddress(test.value, test.family)
+		if err != nil {
+			t.Errorf("Failed to parse %s: %v", test.value, err)
+			continue
+		}
+		if address != test.address || port != test.port {
+			t.Errorf("Expected %s:%d for %s, got %s:%d", test.address, test.port, test.value, address, port)
+		}
+	}
+
+	if _, _, err := parseHexAddress("0100007F", 2); err == nil {
+		t.Error("Expected an error for an address without port")
+	}
+}
+
+func TestGenProcessOpenSocketsFromProc(t *testing.T) {
+	writeProcFixture(t)
+	ctx := sqlctx.NewContext()
+	ctx.SetColumns([]string{"*"})
+
+	sockets, err := GenProcessOpenSockets(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get process open sockets: %v", err)
+	}
+
+	expected := map[int64]map[string]interface{}{
+		1001: {"pid": int32(100), "fd": int64(3), "family": int32(2), "protocol": int32(6), "local_address": "0.0.0.0", "local_port": int32(22), "remote_port": int32(0), "state": "LISTEN", "net_namespace": "4026531840"},
+		1002: {"pid": int32(100), "fd": int64(4), "family": int32(10), "local_address": "::1", "local_port": int32(8080), "remote_address": "::1", "remote_port": int32(50000), "state": "ESTABLISHED"},
+		2001: {"pid": int32(-1), "fd": int64(-1), "protocol": int32(17), "local_address": "127.0.0.1", "local_port": int32(53), "state": ""},
+		1003: {"pid": int32(100), "fd": int64(5), "family": int32(1), "path": "/run/app.sock"},
+		1004: {"pid": int32(-1), "family": int32(1), "path": ""},
+	}
+	if sockets.Size() != len(expected) {
+		t.Fatalf("Expected %d sockets, got %d: %v", len(expected), sockets.Size(), sockets)
+	}
+	for _, socket := range *sockets {
+		inode, _ := socket.Get("socket").(int64)
+		columns, ok := expected[inode]
+		if !ok {
+			t.Errorf("Unexpected socket %d", inode)
+			continue
+		}
+		for column, value := range columns {
+			if socket.Get(column) != value {
+				t.Errorf("Expected %s = %v for socket %d, got %v", column, value, inode, socket.Get(column))
+			}
+		}
+	}
+}
+
+func TestGenProcessOpenSocketsPidConstraint(t *testing.T) {
+	writeProcFixture(t)
+	ctx := sqlctx.NewContext()
+	ctx.SetColumns([]string{"pid", "socket"})
+	ctx.AddConstant("pid", "100")
+
+	sockets, err := GenProcessOpenSockets(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get process open sockets: %v", err)
+	}
+	// The sockets of unknown processes are left out
+	if sockets.Size() != 3 {
+		t.Fatalf("Expected the 3 sockets of pid 100, got %v", sockets)
+	}
+	for _, socket := range *sockets {
+		if socket.Get("pid") != int32(100) {
+			t.Errorf("Unexpected socket of pid %v", socket.Get("pid"))
+		}
+	}
+}
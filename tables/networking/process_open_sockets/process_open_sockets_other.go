@@ -0,0 +1,15 @@
+//go:build !linux && !windows
+
+package process_open_sockets
+
+import (
+	"fmt"
+
+	"github.com/scrymastic/goosquery/sql/result"
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+)
+
+// GenProcessOpenSockets fails, as sockets are only listed on Linux and Windows
+func GenProcessOpenSockets(ctx *sqlctx.Context) (*result.Results, error) {
+	return nil, fmt.Errorf("process open sockets are not supported on this platform")
+}
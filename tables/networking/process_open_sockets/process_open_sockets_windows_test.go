@@ -1,3 +1,5 @@
+//go:build windows
+
 package process_open_sockets
 
 import (
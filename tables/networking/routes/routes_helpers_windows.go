@@ -1,3 +1,5 @@
+//go:build windows
+
 package routes
 
 import "golang.org/x/sys/windows"
@@ -0,0 +1,191 @@
+//go:build linux
+
+package routes
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/bits"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/scrymastic/goosquery/sql/result"
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+	"github.com/scrymastic/goosquery/tables/internal/hostfs"
+)
+
+// rtfLocal is the flag of the routes to the addresses of the host in
+// /proc/net/ipv6_route
+const rtfLocal = 0x80000000
+
+// readRouteLines returns the fields of the lines of a routing table of /proc
+func readRouteLines(path string, skipHeader bool) ([][]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var lines [][]string
+	scanner := bufio.NewScanner(file)
+	if skipHeader {
+		scanner.Scan()
+	}
+	for scanner.Scan() {
+		if fields := strings.Fields(scanner.Text()); len(fields) > 0 {
+			lines = append(lines, fields)
+		}
+	}
+	return lines, scanner.Err()
+}
+
+// parseIPv4Hex parses an address of /proc/net/route, whose bytes in network
+// order are printed as a 32 bit word in host byte order
+func parseIPv4Hex(value string) (net.IP, error) {
+	word, err := strconv.ParseUint(value, 16, 32)
+	if err != nil {
+		return nil, fmt.Errorf("malformed address: %s", value)
+	}
+	ip := make(net.IP, net.IPv4len)
+	binary.NativeEndian.PutUint32(ip, uint32(word))
+	return ip, nil
+}
+
+// parseIPv6Hex parses an address of /proc/net/ipv6_route, printed in network
+// order
+func parseIPv6Hex(value string) (net.IP, error) {
+	ip, err := hex.DecodeString(value)
+	if err != nil || len(ip) != net.IPv6len {
+		return nil, fmt.Errorf("malformed address: %s", value)
+	}
+	return net.IP(ip), nil
+}
+
+// routeGenerator builds the rows of the routes, with the mtu of their
+// interface when the route has none
+type routeGenerator struct {
+	ctx    *sqlctx.Context
+	mtus   map[string]int32
+	routes *result.Results
+}
+
+// interfaceMTU returns the mtu of an interface from /sys/class/net
+func (g *routeGenerator) interfaceMTU(name string) int32 {
+	if mtu, ok := g.mtus[name]; ok {
+		return mtu
+	}
+	mtu := int32(-1)
+	if data, err := os.ReadFile(hostfs.Sys("class", "net", name, "mtu")); err == nil {
+		if value, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 32); err == nil {
+			mtu = int32(value)
+		}
+	}
+	g.mtus[name] = mtu
+	return mtu
+}
+
+// addRoute appends the row of a route
+func (g *routeGenerator) addRoute(destination net.IP, prefixLength int, gateway net.IP, flags int64, iface string, mtu int64, metric int64) {
+	route := result.NewResult(g.ctx, Schema)
+	route.Set("destination", destination.String())
+	route.Set("netmask", int32(prefixLength))
+	route.Set("gateway", gateway.String())
+	route.Set("source", "")
+	// The flags are kept as 32 bits, so the local flag is the sign bit
+	route.Set("flags", int32(uint32(flags)))
+	route.Set("interface", iface)
+	route.Set("metric", int32(metric))
+	if mtu > 0 {
+		route.Set("mtu", int32(mtu))
+	} else if g.ctx.IsColumnUsed("mtu") {
+		route.Set("mtu", g.interfaceMTU(iface))
+	}
+	if iface == "lo" || flags&rtfLocal != 0 {
+		route.Set("type", "local")
+	} else {
+		route.Set("type", "remote")
+	}
+	g.routes.AppendResult(*route)
+}
+
+// parseIPv4Routes parses /proc/net/route, whose lines are
+// "Iface Destination Gateway Flags RefCnt Use Metric Mask MTU Window IRTT"
+func (g *routeGenerator) parseIPv4Routes(path string) error {
+	lines, err := readRouteLines(path, true)
+	if err != nil {
+		return err
+	}
+	for _, fields := range lines {
+		if len(fields) < 9 {
+			continue
+		}
+		destination, err := parseIPv4Hex(fields[1])
+		if err != nil {
+			continue
+		}
+		gateway, err := parseIPv4Hex(fields[2])
+		if err != nil {
+			continue
+		}
+		mask, err := strconv.ParseUint(fields[7], 16, 32)
+		if err != nil {
+			continue
+		}
+		flags, _ := strconv.ParseInt(fields[3], 16, 64)
+		metric, _ := strconv.ParseInt(fields[6], 10, 64)
+		mtu, _ := strconv.ParseInt(fields[8], 10, 64)
+		g.addRoute(destination, bits.OnesCount32(uint32(mask)), gateway, flags, fields[0], mtu, metric)
+	}
+	return nil
+}
+
+// parseIPv6Routes parses /proc/net/ipv6_route, whose lines are
+// "destination prefix source prefix next_hop metric refcnt use flags iface"
+func (g *routeGenerator) parseIPv6Routes(path string) error {
+	lines, err := readRouteLines(path, false)
+	if err != nil {
+		return err
+	}
+	for _, fields := range lines {
+		if len(fields) < 10 {
+			continue
+		}
+		destination, err := parseIPv6Hex(fields[0])
+		if err != nil {
+			continue
+		}
+		gateway, err := parseIPv6Hex(fields[4])
+		if err != nil {
+			continue
+		}
+		prefixLength, err := strconv.ParseInt(fields[1], 16, 32)
+		if err != nil {
+			continue
+		}
+		metric, _ := strconv.ParseInt(fields[5], 16, 64)
+		flags, _ := strconv.ParseInt(fields[8], 16, 64)
+		g.addRoute(destination, int(prefixLength), gateway, flags, fields[9], 0, metric)
+	}
+	return nil
+}
+
+// GenRoutes returns the IPv4 and IPv6 routes of the main routing table
+func GenRoutes(ctx *sqlctx.Context) (*result.Results, error) {
+	generator := &routeGenerator{
+		ctx:    ctx,
+		mtus:   make(map[string]int32),
+		routes: result.NewQueryResult(),
+	}
+	if err := generator.parseIPv4Routes(hostfs.Proc("net", "route")); err != nil {
+		return nil, fmt.Errorf("failed to read IPv4 routes: %v", err)
+	}
+	// The table is missing when IPv6 is disabled
+	if err := generator.parseIPv6Routes(hostfs.Proc("net", "ipv6_route")); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read IPv6 routes: %v", err)
+	}
+	return generator.routes, nil
+}
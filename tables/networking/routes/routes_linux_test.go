@@ -0,0 +1,79 @@
+//go:build linux
+
+package routes
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+	"github.com/scrymastic/goosquery/tables/internal/hostfstest"
+)
+
+// writeRouteFixture creates the routing tables of a host with a default
+// gateway, and the mtu of its interface
+func writeRouteFixture(t *testing.T) {
+	t.Helper()
+	procRoot := t.TempDir()
+	sysRoot := t.TempDir()
+	files := map[string]string{
+		filepath.Join(procRoot, "net/route"): "Iface\tDestination\tGateway \tFlags\tRefCnt\tUse\tMetric\tMask\t\tMTU\tWindow\tIRTT\n" +
+			"eth0\t00000000\t0101A8C0\t0003\t0\t0\t100\t00000000\t0\t0\t0\n" +
+			"eth0\t0001A8C0\t00000000\t0001\t0\t0\t100\t00FFFFFF\t1400\t0\t0\n",
+		filepath.Join(procRoot, "net/ipv6_route"): "" +
+			"fe800000000000000000000000000000 40 00000000000000000000000000000000 00 00000000000000000000000000000000 00000100 00000001 00000000 00000001     eth0\n" +
+			"00000000000000000000000000000001 80 00000000000000000000000000000000 00 00000000000000000000000000000000 00000000 00000002 00000000 80200001       lo\n",
+		filepath.Join(sysRoot, "class/net/eth0/mtu"): "1500\n",
+	}
+	for path, content := range files {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	hostfstest.SetProcRoot(t, procRoot)
+	hostfstest.SetSysRoot(t, sysRoot)
+}
+
+func TestGenRoutesFromProc(t *testing.T) {
+	writeRouteFixture(t)
+	ctx := sqlctx.NewContext()
+	ctx.SetColumns([]string{"*"})
+
+	routes, err := GenRoutes(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get routes: %v", err)
+	}
+	if routes.Size() != 4 {
+		t.Fatalf("Expected 4 routes, got %d", routes.Size())
+	}
+
+	expected := []map[string]interface{}{
+		{"destination": "0.0.0.0", "netmask": int32(0), "gateway": "192.168.1.1", "interface": "eth0", "flags": int32(3), "metric": int32(100), "mtu": int32(1500), "type": "remote"},
+		{"destination": "192.168.1.0", "netmask": int32(24), "gateway": "0.0.0.0", "interface": "eth0", "mtu": int32(1400), "type": "remote"},
+		{"destination": "fe80::", "netmask": int32(64), "gateway": "::", "interface": "eth0", "metric": int32(256), "mtu": int32(1500), "type": "remote"},
+		// The mtu of interfaces without sys attributes keeps its default value
+		{"destination": "::1", "netmask": int32(128), "interface": "lo", "mtu": int32(-1), "type": "local"},
+	}
+	for i, columns := range expected {
+		route := (*routes)[i]
+		for column, value := range columns {
+			if route.Get(column) != value {
+				t.Errorf("Expected %s = %v for route %d, got %v", column, value, i, route.Get(column))
+			}
+		}
+	}
+}
+
+func TestGenRoutesLive(t *testing.T) {
+	ctx := sqlctx.NewContext()
+	ctx.SetColumns([]string{"destination", "netmask", "interface"})
+
+	if _, err := GenRoutes(ctx); err != nil {
+		t.Fatalf("Failed to get routes: %v", err)
+	}
+}
@@ -0,0 +1,15 @@
+//go:build !linux && !windows
+
+package routes
+
+import (
+	"fmt"
+
+	"github.com/scrymastic/goosquery/sql/result"
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+)
+
+// GenRoutes fails, as routes are only listed on Linux and Windows
+func GenRoutes(ctx *sqlctx.Context) (*result.Results, error) {
+	return nil, fmt.Errorf("routes are not supported on this platform")
+}
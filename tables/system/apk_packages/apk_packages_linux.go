@@ -0,0 +1,106 @@
+//go:build linux
+
+package apk_packages
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/scrymastic/goosquery/sql/result"
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+	"github.com/scrymastic/goosquery/tables/internal/hostfs"
+)
+
+// installedPath is the database of apk, under the root of the system
+var installedPath = []string{"lib", "apk", "db", "installed"}
+
+// readRecords reads the records of an apk database, made of lines such as
+// P:musl, separated by blank lines. The file lists of the F: and R: lines are
+// not kept, as only the last value of every field is.
+func readRecords(r io.Reader) ([]map[string]string, error) {
+	var records []map[string]string
+	record := map[string]string{}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			if len(record) > 0 {
+				records = append(records, record)
+				record = map[string]string{}
+			}
+			continue
+		}
+		if len(line) < 2 || line[1] != ':' {
+			continue
+		}
+		record[line[:1]] = line[2:]
+	}
+	if len(record) > 0 {
+		records = append(records, record)
+	}
+	return records, scanner.Err()
+}
+
+// genPackages returns the packages of the apk database of a root
+func genPackages(ctx *sqlctx.Context, root string) ([]result.Result, error) {
+	file, err := os.Open(hostfs.Under(root, installedPath...))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	records, err := readRecords(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var packages []result.Result
+	for _, record := range records {
+		if record["P"] == "" {
+			continue
+		}
+		pkg := result.NewResult(ctx, Schema)
+		pkg.Set("name", record["P"])
+		pkg.Set("version", record["V"])
+		pkg.Set("source", record["o"])
+		if size, err := strconv.ParseInt(record["I"], 10, 64); err == nil {
+			pkg.Set("size", size)
+		}
+		pkg.Set("arch", record["A"])
+		pkg.Set("status", "installed")
+		pkg.Set("maintainer", strings.TrimSpace(record["m"]))
+		pkg.Set("description", record["T"])
+		pkg.Set("url", record["U"])
+		pkg.Set("license", record["L"])
+		pkg.Set("root", root)
+		packages = append(packages, *pkg)
+	}
+	return packages, nil
+}
+
+// GenApkPackages returns the packages of /lib/apk/db/installed, of the system
+// or of the roots constrained by the query
+func GenApkPackages(ctx *sqlctx.Context) (*result.Results, error) {
+	results := result.NewQueryResult()
+	for _, root := range hostfs.Roots(ctx.GetConstants("root")) {
+		packages, err := genPackages(ctx, root)
+		if errors.Is(err, fs.ErrNotExist) {
+			// Not an Alpine based system
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read apk database: %w", err)
+		}
+		for _, pkg := range packages {
+			results.AppendResult(pkg)
+		}
+	}
+	return results, nil
+}
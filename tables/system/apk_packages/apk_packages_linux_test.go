@@ -0,0 +1,84 @@
+//go:build linux
+
+package apk_packages
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+)
+
+const installed = `C:Q1vdcWUiBsBFMEJIBnADpOrTSgYlY=
+P:musl
+V:1.2.4-r2
+A:x86_64
+S:383152
+I:622592
+T:the musl c library (libc) implementation
+U:https://musl.libc.org/
+L:MIT
+o:musl
+m:Timo Teräs <timo.teras@iki.fi>
+t:1698144896
+c:c1f3c8ef1c1b4a6a0a1e7e0e0ab0b1bd7c4b0f3c
+F:lib
+R:ld-musl-x86_64.so.1
+R:libc.musl-x86_64.so.1
+
+C:Q1JqbgI1RtWGgXXsFhHSUmSpyp6ws=
+P:busybox-binsh
+V:1.36.1-r15
+A:x86_64
+I:1
+o:busybox
+F:bin
+R:sh
+`
+
+func TestGenApkPackages(t *testing.T) {
+	root := t.TempDir()
+	db := filepath.Join(root, "lib", "apk", "db")
+	if err := os.MkdirAll(db, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(db, "installed"), []byte(installed), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := sqlctx.NewContext()
+	ctx.SetColumns([]string{"*"})
+	ctx.AddConstant("root", root)
+	packages, err := GenApkPackages(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get apk packages: %v", err)
+	}
+	if packages.Size() != 2 {
+		t.Fatalf("Expected 2 packages, got %d", packages.Size())
+	}
+
+	musl := (*packages)[0]
+	expected := map[string]interface{}{
+		"name":        "musl",
+		"version":     "1.2.4-r2",
+		"source":      "musl",
+		"size":        int64(622592),
+		"arch":        "x86_64",
+		"status":      "installed",
+		"maintainer":  "Timo Teräs <timo.teras@iki.fi>",
+		"description": "the musl c library (libc) implementation",
+		"url":         "https://musl.libc.org/",
+		"license":     "MIT",
+		"root":        root,
+	}
+	for column, value := range expected {
+		if musl.Get(column) != value {
+			t.Errorf("Expected %s to be %v, got %v", column, value, musl.Get(column))
+		}
+	}
+
+	if binsh := (*packages)[1]; binsh.Get("name") != "busybox-binsh" || binsh.Get("source") != "busybox" {
+		t.Errorf("Unexpected package: %v", binsh)
+	}
+}
@@ -0,0 +1,21 @@
+package apk_packages
+
+import (
+	"github.com/scrymastic/goosquery/sql/result"
+)
+
+var TableName = "apk_packages"
+var Description = "The installed Alpine APK package database."
+var Schema = result.Schema{
+	result.Column{Name: "name", Type: "TEXT", Description: "Package name"},
+	result.Column{Name: "version", Type: "TEXT", Description: "Package version"},
+	result.Column{Name: "source", Type: "TEXT", Description: "Origin package the package is built from"},
+	result.Column{Name: "size", Type: "BIGINT", Description: "Installed size in bytes"},
+	result.Column{Name: "arch", Type: "TEXT", Description: "Package architecture"},
+	result.Column{Name: "status", Type: "TEXT", Description: "Package status, installed for the packages of the database"},
+	result.Column{Name: "maintainer", Type: "TEXT", Description: "Package maintainer"},
+	result.Column{Name: "description", Type: "TEXT", Description: "Package description"},
+	result.Column{Name: "url", Type: "TEXT", Description: "Package homepage"},
+	result.Column{Name: "license", Type: "TEXT", Description: "Package license"},
+	result.Column{Name: "root", Type: "TEXT", Description: "Root directory of the system holding the database, such as a mounted image, / by default"},
+}
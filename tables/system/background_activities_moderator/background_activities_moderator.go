@@ -1,3 +1,5 @@
+//go:build windows
+
 package background_activities_moderator
 
 import (
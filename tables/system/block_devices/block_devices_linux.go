@@ -0,0 +1,108 @@
+//go:build linux
+
+package block_devices
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/scrymastic/goosquery/sql/result"
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+	"github.com/scrymastic/goosquery/tables/internal/hostfs"
+)
+
+// sectorSize is the unit of the sizes of sysfs, whatever the block size of
+// the device
+const sectorSize = 512
+
+// readSysValue returns the trimmed content of a file of sysfs
+func readSysValue(elem ...string) string {
+	data, err := os.ReadFile(hostfs.Sys(elem...))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// readUdevProperties returns the properties of a device from the database of
+// udev, /run/udev/data/b<major>:<minor>, where blkid stores the identifiers
+// of the filesystems, such as E:ID_FS_UUID=...
+func readUdevProperties(dev string) map[string]string {
+	properties := map[string]string{}
+	file, err := os.Open(hostfs.Path("run", "udev", "data", "b"+dev))
+	if err != nil {
+		return properties
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line, found := strings.CutPrefix(scanner.Text(), "E:")
+		if !found {
+			continue
+		}
+		if key, value, found := strings.Cut(line, "="); found {
+			properties[key] = value
+		}
+	}
+	return properties
+}
+
+// genBlockDevice returns the row of a disk or of a partition, from its
+// directory under /sys/block
+func genBlockDevice(ctx *sqlctx.Context, dir []string, disk string) *result.Result {
+	name := dir[len(dir)-1]
+	device := result.NewResult(ctx, Schema)
+	device.Set("name", "/dev/"+name)
+	if disk != "" {
+		device.Set("parent", "/dev/"+disk)
+	} else {
+		device.Set("parent", "")
+		disk = name
+	}
+	device.Set("vendor", readSysValue("block", disk, "device", "vendor"))
+	device.Set("model", readSysValue("block", disk, "device", "model"))
+
+	blockSize, err := strconv.ParseInt(readSysValue("block", disk, "queue", "logical_block_size"), 10, 64)
+	if err != nil || blockSize <= 0 {
+		blockSize = sectorSize
+	}
+	device.Set("block_size", int32(blockSize))
+	if sectors, err := strconv.ParseInt(readSysValue(append(dir, "size")...), 10, 64); err == nil {
+		device.Set("size", sectors*sectorSize/blockSize)
+	}
+
+	properties := readUdevProperties(readSysValue(append(dir, "dev")...))
+	device.Set("uuid", properties["ID_FS_UUID"])
+	device.Set("type", properties["ID_FS_TYPE"])
+	device.Set("label", properties["ID_FS_LABEL"])
+	return device
+}
+
+// GenBlockDevices returns the disks of /sys/block and their partitions
+func GenBlockDevices(ctx *sqlctx.Context) (*result.Results, error) {
+	disks, err := os.ReadDir(hostfs.Sys("block"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list block devices: %w", err)
+	}
+
+	results := result.NewQueryResult()
+	for _, disk := range disks {
+		results.AppendResult(*genBlockDevice(ctx, []string{"block", disk.Name()}, ""))
+
+		entries, err := os.ReadDir(hostfs.Sys("block", disk.Name()))
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if _, err := os.Stat(hostfs.Sys("block", disk.Name(), entry.Name(), "partition")); err != nil {
+				continue
+			}
+			results.AppendResult(*genBlockDevice(ctx, []string{"block", disk.Name(), entry.Name()}, disk.Name()))
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,67 @@
+//go:build linux
+
+package block_devices
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+	"github.com/scrymastic/goosquery/tables/internal/hostfstest"
+)
+
+func TestGenBlockDevices(t *testing.T) {
+	sys, root := t.TempDir(), t.TempDir()
+	files := map[string]string{
+		"block/sda/size":                     "1953525168\n",
+		"block/sda/dev":                      "8:0\n",
+		"block/sda/queue/logical_block_size": "4096\n",
+		"block/sda/device/vendor":            "ATA     \n",
+		"block/sda/device/model":             "Samsung SSD 870\n",
+		"block/sda/sda1/partition":           "1\n",
+		"block/sda/sda1/size":                "1048576\n",
+		"block/sda/sda1/dev":                 "8:1\n",
+		"block/sda/holders/.keep":            "",
+	}
+	for name, content := range files {
+		if err := os.MkdirAll(filepath.Join(sys, filepath.Dir(name)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(sys, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	udev := filepath.Join(root, "run", "udev", "data")
+	if err := os.MkdirAll(udev, 0755); err != nil {
+		t.Fatal(err)
+	}
+	properties := "S:disk/by-uuid/4C1A-2B3D\nE:ID_FS_UUID=4C1A-2B3D\nE:ID_FS_TYPE=vfat\nE:ID_FS_LABEL=EFI\n"
+	if err := os.WriteFile(filepath.Join(udev, "b8:1"), []byte(properties), 0644); err != nil {
+		t.Fatal(err)
+	}
+	hostfstest.SetSysRoot(t, sys)
+	hostfstest.SetRoot(t, root)
+
+	ctx := sqlctx.NewContext()
+	ctx.SetColumns([]string{"*"})
+	devices, err := GenBlockDevices(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get block devices: %v", err)
+	}
+	if devices.Size() != 2 {
+		t.Fatalf("Expected a disk and a partition, got %d devices", devices.Size())
+	}
+
+	expected := []map[string]interface{}{
+		{"name": "/dev/sda", "parent": "", "vendor": "ATA", "model": "Samsung SSD 870", "size": int64(244190646), "block_size": int32(4096), "uuid": ""},
+		{"name": "/dev/sda1", "parent": "/dev/sda", "size": int64(131072), "uuid": "4C1A-2B3D", "type": "vfat", "label": "EFI"},
+	}
+	for i, columns := range expected {
+		for column, value := range columns {
+			if got := (*devices)[i].Get(column); got != value {
+				t.Errorf("Expected %s of device %d to be %v, got %v", column, i, value, got)
+			}
+		}
+	}
+}
@@ -0,0 +1,19 @@
+package block_devices
+
+import (
+	"github.com/scrymastic/goosquery/sql/result"
+)
+
+var TableName = "block_devices"
+var Description = "Block (buffered access) device file nodes: disks, ramdisks, and DMG containers."
+var Schema = result.Schema{
+	result.Column{Name: "name", Type: "TEXT", Description: "Block device name"},
+	result.Column{Name: "parent", Type: "TEXT", Description: "Block device parent name, the disk of a partition"},
+	result.Column{Name: "vendor", Type: "TEXT", Description: "Block device vendor string"},
+	result.Column{Name: "model", Type: "TEXT", Description: "Block device model string identifier"},
+	result.Column{Name: "size", Type: "BIGINT", Description: "Block device size in blocks"},
+	result.Column{Name: "block_size", Type: "INTEGER", Description: "Block size in bytes"},
+	result.Column{Name: "uuid", Type: "TEXT", Description: "Block device Universally Unique Identifier"},
+	result.Column{Name: "type", Type: "TEXT", Description: "Block device type string, the filesystem or the content of the device"},
+	result.Column{Name: "label", Type: "TEXT", Description: "Block device label string"},
+}
@@ -12,7 +12,7 @@ var Schema = result.Schema{
 	result.Column{Name: "issuer", Type: "TEXT", Description: "Certificate issuer distinguished name (deprecated, use issuer2)"},
 	result.Column{Name: "ca", Type: "INTEGER", Description: "1 if CA: true (certificate is an authority) else 0"},
 	result.Column{Name: "self_signed", Type: "INTEGER", Description: "1 if self-signed, else 0"},
-	result.Column{Name: "not_valid_before", Type: "certificates", Description: "Lower bound of valid date"},
+	result.Column{Name: "not_valid_before", Type: "DATETIME", Description: "Lower bound of valid date"},
 	result.Column{Name: "not_valid_after", Type: "DATETIME", Description: "Certificate expiration data"},
 	result.Column{Name: "signing_algorithm", Type: "TEXT", Description: "Signing algorithm used"},
 	result.Column{Name: "key_algorithm", Type: "TEXT", Description: "Key algorithm used"},
@@ -0,0 +1,80 @@
+//go:build linux
+
+package cpu_info
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/scrymastic/goosquery/sql/result"
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+	"github.com/scrymastic/goosquery/tables/internal/cpuinfo"
+	"github.com/scrymastic/goosquery/tables/internal/hostfs"
+)
+
+// Values of Win32_Processor used for the processors of Linux, so that
+// queries on these columns work on both platforms
+const (
+	processorTypeCentral = "3"
+	cpuStatusEnabled     = 1
+	availabilityRunning  = "3"
+)
+
+// getMaxClockSpeed returns the maximum frequency of a logical processor in
+// MHz, from cpufreq which is missing in most virtual machines
+func getMaxClockSpeed(processor string) (int32, bool) {
+	data, err := os.ReadFile(hostfs.Sys("devices", "system", "cpu", "cpu"+processor, "cpufreq", "cpuinfo_max_freq"))
+	if err != nil {
+		return 0, false
+	}
+	kilohertz, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return int32(kilohertz / 1000), true
+}
+
+// GenCpuInfo returns a row for each physical processor of /proc/cpuinfo
+func GenCpuInfo(ctx *sqlctx.Context) (*result.Results, error) {
+	processors, err := cpuinfo.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cpuinfo: %w", err)
+	}
+
+	cpuInfo := result.NewQueryResult()
+	for index, logical := range cpuinfo.Packages(processors) {
+		first := logical[0]
+		info := result.NewResult(ctx, Schema)
+
+		info.Set("device_id", fmt.Sprintf("CPU%d", index))
+		info.Set("model", first["model name"])
+		info.Set("manufacturer", first["vendor_id"])
+		info.Set("processor_type", processorTypeCentral)
+		info.Set("cpu_status", int32(cpuStatusEnabled))
+		info.Set("number_of_cores", strconv.Itoa(cpuinfo.Cores(logical)))
+		info.Set("logical_processors", int32(len(logical)))
+		info.Set("availability", availabilityRunning)
+		info.Set("socket_designation", "")
+
+		// The long mode flag of x86 processors, or the 64 bit architectures
+		if slices.Contains(strings.Fields(first["flags"]), "lm") || strings.HasSuffix(cpuinfo.Arch(), "64") {
+			info.Set("address_width", "64")
+		} else {
+			info.Set("address_width", "32")
+		}
+
+		if mhz, err := strconv.ParseFloat(first["cpu MHz"], 64); err == nil {
+			info.Set("current_clock_speed", int32(mhz))
+		}
+		if maxSpeed, ok := getMaxClockSpeed(first["processor"]); ok {
+			info.Set("max_clock_speed", maxSpeed)
+		}
+
+		cpuInfo.AppendResult(*info)
+	}
+
+	return cpuInfo, nil
+}
@@ -0,0 +1,69 @@
+//go:build linux
+
+package cpu_info
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+	"github.com/scrymastic/goosquery/tables/internal/hostfstest"
+)
+
+func TestGenCpuInfoFromProc(t *testing.T) {
+	procRoot := t.TempDir()
+	sysRoot := t.TempDir()
+	cpuinfo := ""
+	// Two sockets with two single threaded cores each
+	for _, ids := range [][3]string{{"0", "0", "0"}, {"1", "0", "1"}, {"2", "1", "0"}, {"3", "1", "1"}} {
+		cpuinfo += "processor\t: " + ids[0] + "\nvendor_id\t: AuthenticAMD\n" +
+			"model name\t: AMD EPYC 7763 64-Core Processor\ncpu MHz\t\t: 2445.406\n" +
+			"physical id\t: " + ids[1] + "\ncore id\t\t: " + ids[2] + "\nflags\t\t: fpu vme lm\n\n"
+	}
+	files := map[string]string{
+		filepath.Join(procRoot, "cpuinfo"):                                         cpuinfo,
+		filepath.Join(sysRoot, "devices/system/cpu/cpu0/cpufreq/cpuinfo_max_freq"): "3529000\n",
+	}
+	for path, content := range files {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	hostfstest.SetProcRoot(t, procRoot)
+	hostfstest.SetSysRoot(t, sysRoot)
+
+	ctx := sqlctx.NewContext()
+	ctx.SetColumns([]string{"*"})
+	cpus, err := GenCpuInfo(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get cpu info: %v", err)
+	}
+	if cpus.Size() != 2 {
+		t.Fatalf("Expected 2 processors, got %d", cpus.Size())
+	}
+
+	expected := map[string]interface{}{
+		"device_id":           "CPU0",
+		"model":               "AMD EPYC 7763 64-Core Processor",
+		"manufacturer":        "AuthenticAMD",
+		"processor_type":      "3",
+		"number_of_cores":     "2",
+		"logical_processors":  int32(2),
+		"address_width":       "64",
+		"current_clock_speed": int32(2445),
+		"max_clock_speed":     int32(3529),
+	}
+	for column, value := range expected {
+		if (*cpus)[0].Get(column) != value {
+			t.Errorf("Expected %s = %v, got %v", column, value, (*cpus)[0].Get(column))
+		}
+	}
+	// The second socket has no cpufreq
+	if second := (*cpus)[1]; second.Get("device_id") != "CPU1" || second.Get("max_clock_speed") != int32(-1) {
+		t.Errorf("Unexpected second processor: %v", second)
+	}
+}
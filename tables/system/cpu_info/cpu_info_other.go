@@ -0,0 +1,15 @@
+//go:build !linux && !windows
+
+package cpu_info
+
+import (
+	"fmt"
+
+	"github.com/scrymastic/goosquery/sql/result"
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+)
+
+// GenCpuInfo fails, as processors are only listed on Linux and Windows
+func GenCpuInfo(ctx *sqlctx.Context) (*result.Results, error) {
+	return nil, fmt.Errorf("cpu info is not supported on this platform")
+}
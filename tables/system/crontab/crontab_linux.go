@@ -0,0 +1,163 @@
+//go:build linux
+
+package crontab
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/scrymastic/goosquery/sql/result"
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+	"github.com/scrymastic/goosquery/tables/internal/hostfs"
+)
+
+// crontab is a crontab file, with the path of the file in the system
+type crontab struct {
+	path string
+	// username is the owner of a user crontab, and empty for system
+	// crontabs, whose lines have a user field
+	username string
+}
+
+// Spools of the user crontabs, named after their users, on Debian, on SUSE
+// and on Red Hat
+var spoolDirs = []string{
+	"/var/spool/cron/crontabs",
+	"/var/spool/cron/tabs",
+	"/var/spool/cron",
+}
+
+// environmentLine matches the variable assignments of crontabs, such as
+// MAILTO=root
+var environmentLine = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*\s*=`)
+
+// listCrontabs returns the crontabs of a root: /etc/crontab, the files of
+// /etc/cron.d and the user crontabs of the spools
+func listCrontabs(root string) []crontab {
+	crontabs := []crontab{{path: "/etc/crontab"}}
+	for _, name := range listFiles(root, "/etc/cron.d") {
+		crontabs = append(crontabs, crontab{path: "/etc/cron.d/" + name})
+	}
+	for _, dir := range spoolDirs {
+		for _, name := range listFiles(root, dir) {
+			crontabs = append(crontabs, crontab{path: dir + "/" + name, username: name})
+		}
+	}
+	return crontabs
+}
+
+// listFiles returns the regular files of a directory of a root, skipping the
+// hidden files and the backups that cron ignores
+func listFiles(root string, dir string) []string {
+	entries, err := os.ReadDir(hostfs.Under(root, dir))
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.Type().IsRegular() || strings.HasPrefix(name, ".") || strings.HasSuffix(name, "~") {
+			continue
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// parseLine splits a crontab line into the columns of a job, or returns
+// false for the blank lines, the comments and the variable assignments
+func parseLine(line string, systemCrontab bool) (map[string]string, bool) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") || environmentLine.MatchString(line) {
+		return nil, false
+	}
+
+	job := map[string]string{}
+	fields := strings.Fields(line)
+	scheduleFields := 5
+	if strings.HasPrefix(fields[0], "@") {
+		job["event"] = fields[0]
+		scheduleFields = 1
+	} else if len(fields) >= 5 {
+		job["minute"] = fields[0]
+		job["hour"] = fields[1]
+		job["day_of_month"] = fields[2]
+		job["month"] = fields[3]
+		job["day_of_week"] = fields[4]
+	}
+	if systemCrontab {
+		scheduleFields++
+	}
+	if len(fields) <= scheduleFields {
+		return nil, false
+	}
+	if systemCrontab {
+		job["username"] = fields[scheduleFields-1]
+	}
+
+	// Keep the spacing of the command, after the schedule and user fields
+	command := line
+	for i := 0; i < scheduleFields; i++ {
+		command = strings.TrimLeft(command, " \t")
+		command = command[len(fields[i]):]
+	}
+	job["command"] = strings.TrimSpace(command)
+	return job, true
+}
+
+// genCrontab returns the jobs of a crontab of a root
+func genCrontab(ctx *sqlctx.Context, root string, tab crontab) ([]result.Result, error) {
+	file, err := os.Open(hostfs.Under(root, tab.path))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var jobs []result.Result
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields, ok := parseLine(scanner.Text(), tab.username == "")
+		if !ok {
+			continue
+		}
+		job := result.NewResult(ctx, Schema)
+		for _, column := range []string{"event", "minute", "hour", "day_of_month", "month", "day_of_week", "username", "command"} {
+			job.Set(column, fields[column])
+		}
+		if tab.username != "" {
+			job.Set("username", tab.username)
+		}
+		job.Set("path", tab.path)
+		job.Set("root", root)
+		jobs = append(jobs, *job)
+	}
+	return jobs, scanner.Err()
+}
+
+// GenCrontab returns the jobs of the system and user crontabs, of the system
+// or of the roots constrained by the query
+func GenCrontab(ctx *sqlctx.Context) (*result.Results, error) {
+	results := result.NewQueryResult()
+	for _, root := range hostfs.Roots(ctx.GetConstants("root")) {
+		for _, tab := range listCrontabs(root) {
+			jobs, err := genCrontab(ctx, root, tab)
+			// User crontabs are only readable by their owners and root
+			if errors.Is(err, fs.ErrNotExist) || errors.Is(err, fs.ErrPermission) {
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to read crontab %s: %w", filepath.Join(root, tab.path), err)
+			}
+			for _, job := range jobs {
+				results.AppendResult(job)
+			}
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,92 @@
+//go:build linux
+
+package crontab
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+)
+
+func TestParseLine(t *testing.T) {
+	tests := []struct {
+		line          string
+		systemCrontab bool
+		expected      map[string]string
+	}{
+		{"# m h dom mon dow user command", true, nil},
+		{"MAILTO=root", true, nil},
+		{"PATH = /usr/bin:/bin", false, nil},
+		{
+			"17 *\t* * *\troot    cd / && run-parts --report /etc/cron.hourly", true,
+			map[string]string{"minute": "17", "hour": "*", "day_of_month": "*", "month": "*", "day_of_week": "*", "username": "root", "command": "cd / && run-parts --report /etc/cron.hourly"},
+		},
+		{
+			"@reboot /home/alice/bin/start  --quiet", false,
+			map[string]string{"event": "@reboot", "command": "/home/alice/bin/start  --quiet"},
+		},
+		{"*/5 * * * * root", true, nil},
+	}
+	for _, test := range tests {
+		job, ok := parseLine(test.line, test.systemCrontab)
+		if test.expected == nil {
+			if ok {
+				t.Errorf("Expected %q to be skipped, got %v", test.line, job)
+			}
+			continue
+		}
+		if !ok {
+			t.Errorf("Expected %q to be parsed", test.line)
+			continue
+		}
+		for column, value := range test.expected {
+			if job[column] != value {
+				t.Errorf("Expected %s of %q to be %q, got %q", column, test.line, value, job[column])
+			}
+		}
+	}
+}
+
+func TestGenCrontab(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"etc/crontab":                   "SHELL=/bin/sh\n25 6 * * * root test -x /usr/sbin/anacron || run-parts /etc/cron.daily\n",
+		"etc/cron.d/backup":             "@daily backup /usr/local/bin/backup\n",
+		"etc/cron.d/.placeholder":       "# ignored\n",
+		"var/spool/cron/crontabs/alice": "0 9 * * 1-5 /home/alice/bin/report\n",
+	}
+	for name, content := range files {
+		if err := os.MkdirAll(filepath.Join(root, filepath.Dir(name)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(root, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx := sqlctx.NewContext()
+	ctx.SetColumns([]string{"*"})
+	ctx.AddConstant("root", root)
+	jobs, err := GenCrontab(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get crontab: %v", err)
+	}
+	if jobs.Size() != 3 {
+		t.Fatalf("Expected 3 jobs, got %d", jobs.Size())
+	}
+
+	expected := []map[string]string{
+		{"username": "root", "minute": "25", "path": "/etc/crontab"},
+		{"username": "backup", "event": "@daily", "path": "/etc/cron.d/backup"},
+		{"username": "alice", "day_of_week": "1-5", "command": "/home/alice/bin/report", "path": "/var/spool/cron/crontabs/alice"},
+	}
+	for i, columns := range expected {
+		for column, value := range columns {
+			if got := (*jobs)[i].Get(column); got != value {
+				t.Errorf("Expected %s of job %d to be %q, got %v", column, i, value, got)
+			}
+		}
+	}
+}
@@ -0,0 +1,20 @@
+package crontab
+
+import (
+	"github.com/scrymastic/goosquery/sql/result"
+)
+
+var TableName = "crontab"
+var Description = "Line parsed values from system and user cron/tab."
+var Schema = result.Schema{
+	result.Column{Name: "event", Type: "TEXT", Description: "The job @event name, such as @reboot"},
+	result.Column{Name: "minute", Type: "TEXT", Description: "The exact minute for the job"},
+	result.Column{Name: "hour", Type: "TEXT", Description: "The hour of the day for the job"},
+	result.Column{Name: "day_of_month", Type: "TEXT", Description: "The day of the month for the job"},
+	result.Column{Name: "month", Type: "TEXT", Description: "The month of the year for the job"},
+	result.Column{Name: "day_of_week", Type: "TEXT", Description: "The day of the week for the job"},
+	result.Column{Name: "username", Type: "TEXT", Description: "User running the job, from the user field of system crontabs or the name of user crontabs"},
+	result.Column{Name: "command", Type: "TEXT", Description: "Raw command string"},
+	result.Column{Name: "path", Type: "TEXT", Description: "File parsed"},
+	result.Column{Name: "root", Type: "TEXT", Description: "Root directory of the system holding the crontabs, such as a mounted image, / by default"},
+}
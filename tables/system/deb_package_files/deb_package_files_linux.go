@@ -0,0 +1,100 @@
+//go:build linux
+
+package deb_package_files
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/scrymastic/goosquery/sql/result"
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+	"github.com/scrymastic/goosquery/tables/internal/hostfs"
+)
+
+// infoPath is the directory of dpkg holding the file lists of the packages,
+// named <package>.list, or <package>:<arch>.list for multi-arch packages
+var infoPath = []string{"var", "lib", "dpkg", "info"}
+
+// listFiles returns the file lists of the packages under a root, only the
+// lists of the given packages when there are some
+func listFiles(root string, packages []string) ([]string, error) {
+	info := hostfs.Under(root, infoPath...)
+	if len(packages) == 0 {
+		return filepath.Glob(filepath.Join(info, "*.list"))
+	}
+
+	var lists []string
+	for _, name := range packages {
+		for _, pattern := range []string{name + ".list", name + ":*.list"} {
+			matches, err := filepath.Glob(filepath.Join(info, pattern))
+			if err != nil {
+				return nil, err
+			}
+			lists = append(lists, matches...)
+		}
+	}
+	return lists, nil
+}
+
+// packageName returns the package of a file list, without its architecture
+func packageName(list string) string {
+	name := strings.TrimSuffix(filepath.Base(list), ".list")
+	name, _, _ = strings.Cut(name, ":")
+	return name
+}
+
+// genListFiles returns the files of a file list
+func genListFiles(ctx *sqlctx.Context, root string, list string) ([]result.Result, error) {
+	file, err := os.Open(list)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	name := packageName(list)
+	var files []result.Result
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		path := scanner.Text()
+		// Every list starts with the root directory
+		if path == "" || path == "/." {
+			continue
+		}
+		entry := result.NewResult(ctx, Schema)
+		entry.Set("package", name)
+		entry.Set("path", path)
+		entry.Set("root", root)
+		files = append(files, *entry)
+	}
+	return files, scanner.Err()
+}
+
+// GenDebPackageFiles returns the files of the packages of /var/lib/dpkg/info,
+// of the system or of the roots constrained by the query
+func GenDebPackageFiles(ctx *sqlctx.Context) (*result.Results, error) {
+	results := result.NewQueryResult()
+	for _, root := range hostfs.Roots(ctx.GetConstants("root")) {
+		lists, err := listFiles(root, ctx.GetConstants("package"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list dpkg file lists: %w", err)
+		}
+		for _, list := range lists {
+			files, err := genListFiles(ctx, root, list)
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s: %w", list, err)
+			}
+			for _, file := range files {
+				results.AppendResult(file)
+			}
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,53 @@
+//go:build linux
+
+package deb_package_files
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+)
+
+func TestGenDebPackageFiles(t *testing.T) {
+	root := t.TempDir()
+	info := filepath.Join(root, "var", "lib", "dpkg", "info")
+	if err := os.MkdirAll(info, 0755); err != nil {
+		t.Fatal(err)
+	}
+	lists := map[string]string{
+		"adduser.list":       "/.\n/usr\n/usr/sbin/adduser\n",
+		"libc6:amd64.list":   "/.\n/lib/x86_64-linux-gnu/libc.so.6\n",
+		"adduser.md5sums":    "0123456789abcdef0123456789abcdef  usr/sbin/adduser\n",
+		"libc6:amd64.shlibs": "libc 6 libc6\n",
+	}
+	for name, content := range lists {
+		if err := os.WriteFile(filepath.Join(info, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx := sqlctx.NewContext()
+	ctx.SetColumns([]string{"*"})
+	ctx.AddConstant("root", root)
+	files, err := GenDebPackageFiles(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get deb package files: %v", err)
+	}
+	if files.Size() != 3 {
+		t.Fatalf("Expected 3 files, got %d", files.Size())
+	}
+
+	ctx.AddConstant("package", "libc6")
+	files, err = GenDebPackageFiles(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get deb package files: %v", err)
+	}
+	if files.Size() != 1 {
+		t.Fatalf("Expected 1 file of libc6, got %d", files.Size())
+	}
+	if libc := (*files)[0]; libc.Get("package") != "libc6" || libc.Get("path") != "/lib/x86_64-linux-gnu/libc.so.6" || libc.Get("root") != root {
+		t.Errorf("Unexpected file: %v", libc)
+	}
+}
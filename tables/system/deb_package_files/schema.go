@@ -0,0 +1,13 @@
+package deb_package_files
+
+import (
+	"github.com/scrymastic/goosquery/sql/result"
+)
+
+var TableName = "deb_package_files"
+var Description = "The files owned by the installed DEB packages."
+var Schema = result.Schema{
+	result.Column{Name: "package", Type: "TEXT", Description: "Name of the package owning the file"},
+	result.Column{Name: "path", Type: "TEXT", Description: "Path of the file or directory, as installed by the package"},
+	result.Column{Name: "root", Type: "TEXT", Description: "Root directory of the system holding the database, such as a mounted image, / by default"},
+}
@@ -0,0 +1,138 @@
+//go:build linux
+
+package deb_packages
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/scrymastic/goosquery/sql/result"
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+	"github.com/scrymastic/goosquery/tables/internal/hostfs"
+)
+
+// statusPath is the database of dpkg, under the root of the system
+var statusPath = []string{"var", "lib", "dpkg", "status"}
+
+// readParagraphs reads the paragraphs of a dpkg status file, with the first
+// line of every field, as the fields read here are never folded
+func readParagraphs(r io.Reader) ([]map[string]string, error) {
+	var paragraphs []map[string]string
+	paragraph := map[string]string{}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			if len(paragraph) > 0 {
+				paragraphs = append(paragraphs, paragraph)
+				paragraph = map[string]string{}
+			}
+			continue
+		}
+		// Continuation lines, such as the long description and the conffiles
+		if line[0] == ' ' || line[0] == '\t' {
+			continue
+		}
+		if key, value, found := strings.Cut(line, ":"); found {
+			paragraph[key] = strings.TrimSpace(value)
+		}
+	}
+	if len(paragraph) > 0 {
+		paragraphs = append(paragraphs, paragraph)
+	}
+	return paragraphs, scanner.Err()
+}
+
+// isInstalled reports whether the files of a package are on the system, from
+// the last word of its status, as removed packages are kept in the database
+func isInstalled(status string) bool {
+	fields := strings.Fields(status)
+	if len(fields) != 3 {
+		return false
+	}
+	return fields[2] != "not-installed" && fields[2] != "config-files"
+}
+
+// sourceName returns the source package of a package, without the version
+// given when it differs from the binary one, or the package itself
+func sourceName(paragraph map[string]string) string {
+	source, _, _ := strings.Cut(paragraph["Source"], " ")
+	if source == "" {
+		return paragraph["Package"]
+	}
+	return source
+}
+
+// revision returns the Debian revision of a version, after its last hyphen
+func revision(version string) string {
+	if i := strings.LastIndex(version, "-"); i >= 0 {
+		return version[i+1:]
+	}
+	return ""
+}
+
+// genPackages returns the installed packages of the dpkg database of a root
+func genPackages(ctx *sqlctx.Context, root string) ([]result.Result, error) {
+	file, err := os.Open(hostfs.Under(root, statusPath...))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	paragraphs, err := readParagraphs(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var packages []result.Result
+	for _, paragraph := range paragraphs {
+		if paragraph["Package"] == "" || !isInstalled(paragraph["Status"]) {
+			continue
+		}
+		pkg := result.NewResult(ctx, Schema)
+		pkg.Set("name", paragraph["Package"])
+		pkg.Set("version", paragraph["Version"])
+		pkg.Set("source", sourceName(paragraph))
+		// Installed-Size is given in KiB
+		if size, err := strconv.ParseInt(paragraph["Installed-Size"], 10, 64); err == nil {
+			pkg.Set("size", size*1024)
+		}
+		pkg.Set("arch", paragraph["Architecture"])
+		pkg.Set("revision", revision(paragraph["Version"]))
+		pkg.Set("status", paragraph["Status"])
+		pkg.Set("maintainer", paragraph["Maintainer"])
+		pkg.Set("section", paragraph["Section"])
+		pkg.Set("priority", paragraph["Priority"])
+		pkg.Set("root", root)
+		packages = append(packages, *pkg)
+	}
+	return packages, nil
+}
+
+// GenDebPackages returns the installed packages of /var/lib/dpkg/status, of
+// the system or of the roots constrained by the query
+func GenDebPackages(ctx *sqlctx.Context) (*result.Results, error) {
+	results := result.NewQueryResult()
+	for _, root := range hostfs.Roots(ctx.GetConstants("root")) {
+		packages, err := genPackages(ctx, root)
+		if errors.Is(err, fs.ErrNotExist) {
+			// Not a Debian based system
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read dpkg database: %w", err)
+		}
+		for _, pkg := range packages {
+			results.AppendResult(pkg)
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,108 @@
+//go:build linux
+
+package deb_packages
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+	"github.com/scrymastic/goosquery/tables/internal/hostfstest"
+)
+
+const status = `Package: libc6
+Status: install ok installed
+Priority: optional
+Section: libs
+Installed-Size: 12986
+Maintainer: GNU Libc Maintainers <debian-glibc@lists.debian.org>
+Architecture: amd64
+Multi-Arch: same
+Source: glibc
+Version: 2.36-9+deb12u4
+Description: GNU C Library: Shared libraries
+ Contains the standard libraries that are used by nearly all programs on
+ the system.
+
+Package: oldtool
+Status: deinstall ok config-files
+Architecture: all
+Version: 1.0
+Conffiles:
+ /etc/oldtool.conf 0123456789abcdef0123456789abcdef
+
+Package: adduser
+Status: install ok installed
+Installed-Size: 686
+Architecture: all
+Version: 3.134
+`
+
+func writeStatus(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	dir := filepath.Join(root, "var", "lib", "dpkg")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "status"), []byte(status), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
+
+func TestGenDebPackagesFromStatus(t *testing.T) {
+	root := writeStatus(t)
+	hostfstest.SetRoot(t, root)
+
+	ctx := sqlctx.NewContext()
+	ctx.SetColumns([]string{"*"})
+	packages, err := GenDebPackages(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get deb packages: %v", err)
+	}
+	if packages.Size() != 2 {
+		t.Fatalf("Expected 2 installed packages, got %d", packages.Size())
+	}
+
+	libc := (*packages)[0]
+	expected := map[string]interface{}{
+		"name":       "libc6",
+		"version":    "2.36-9+deb12u4",
+		"source":     "glibc",
+		"size":       int64(12986 * 1024),
+		"arch":       "amd64",
+		"revision":   "9+deb12u4",
+		"status":     "install ok installed",
+		"maintainer": "GNU Libc Maintainers <debian-glibc@lists.debian.org>",
+		"section":    "libs",
+		"priority":   "optional",
+		"root":       root,
+	}
+	for column, value := range expected {
+		if libc.Get(column) != value {
+			t.Errorf("Expected %s to be %v, got %v", column, value, libc.Get(column))
+		}
+	}
+
+	if adduser := (*packages)[1]; adduser.Get("source") != "adduser" || adduser.Get("revision") != "" {
+		t.Errorf("Unexpected package: %v", adduser)
+	}
+}
+
+func TestGenDebPackagesFromConstrainedRoot(t *testing.T) {
+	root := writeStatus(t)
+
+	ctx := sqlctx.NewContext()
+	ctx.SetColumns([]string{"*"})
+	ctx.AddConstant("root", root)
+	ctx.AddConstant("root", t.TempDir())
+	packages, err := GenDebPackages(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get deb packages: %v", err)
+	}
+	if packages.Size() != 2 {
+		t.Fatalf("Expected 2 installed packages, got %d", packages.Size())
+	}
+}
@@ -0,0 +1,21 @@
+package deb_packages
+
+import (
+	"github.com/scrymastic/goosquery/sql/result"
+)
+
+var TableName = "deb_packages"
+var Description = "The installed DEB package database."
+var Schema = result.Schema{
+	result.Column{Name: "name", Type: "TEXT", Description: "Package name"},
+	result.Column{Name: "version", Type: "TEXT", Description: "Package version"},
+	result.Column{Name: "source", Type: "TEXT", Description: "Source package the package is built from"},
+	result.Column{Name: "size", Type: "BIGINT", Description: "Installed size in bytes"},
+	result.Column{Name: "arch", Type: "TEXT", Description: "Package architecture"},
+	result.Column{Name: "revision", Type: "TEXT", Description: "Package revision, the Debian part of the version"},
+	result.Column{Name: "status", Type: "TEXT", Description: "Package status, such as install ok installed"},
+	result.Column{Name: "maintainer", Type: "TEXT", Description: "Package maintainer"},
+	result.Column{Name: "section", Type: "TEXT", Description: "Package section"},
+	result.Column{Name: "priority", Type: "TEXT", Description: "Package priority"},
+	result.Column{Name: "root", Type: "TEXT", Description: "Root directory of the system holding the database, such as a mounted image, / by default"},
+}
@@ -1,3 +1,5 @@
+//go:build windows
+
 package disk_info
 
 import (
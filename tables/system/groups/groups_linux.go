@@ -0,0 +1,33 @@
+//go:build linux
+
+package groups
+
+import (
+	"fmt"
+
+	"github.com/scrymastic/goosquery/sql/result"
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+	"github.com/scrymastic/goosquery/tables/internal/accounts"
+)
+
+// GenGroups returns the groups of /etc/group
+func GenGroups(ctx *sqlctx.Context) (*result.Results, error) {
+	entries, err := accounts.ReadGroups()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read groups: %w", err)
+	}
+
+	results := result.NewQueryResult()
+	for _, entry := range entries {
+		group := result.NewResult(ctx, Schema)
+		group.Set("gid", int64(entry.GID))
+		group.Set("gid_signed", int64(int32(entry.GID)))
+		group.Set("groupname", entry.Name)
+		group.Set("group_sid", "")
+		group.Set("comment", "")
+
+		results.AppendResult(*group)
+	}
+
+	return results, nil
+}
@@ -0,0 +1,41 @@
+//go:build linux
+
+package groups
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+	"github.com/scrymastic/goosquery/tables/internal/hostfstest"
+)
+
+func TestGenGroupsFromGroupFile(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "etc"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	group := "root:x:0:\nsudo:x:27:alice\nnogroup:x:4294967294:\n"
+	if err := os.WriteFile(filepath.Join(root, "etc", "group"), []byte(group), 0644); err != nil {
+		t.Fatal(err)
+	}
+	hostfstest.SetRoot(t, root)
+
+	ctx := sqlctx.NewContext()
+	ctx.SetColumns([]string{"*"})
+	groups, err := GenGroups(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get groups: %v", err)
+	}
+	if groups.Size() != 3 {
+		t.Fatalf("Expected 3 groups, got %d", groups.Size())
+	}
+
+	if sudo := (*groups)[1]; sudo.Get("groupname") != "sudo" || sudo.Get("gid") != int64(27) {
+		t.Errorf("Unexpected group: %v", sudo)
+	}
+	if nogroup := (*groups)[2]; nogroup.Get("gid") != int64(4294967294) || nogroup.Get("gid_signed") != int64(-2) {
+		t.Errorf("Unexpected group: %v", nogroup)
+	}
+}
@@ -0,0 +1,15 @@
+//go:build !linux && !windows
+
+package groups
+
+import (
+	"fmt"
+
+	"github.com/scrymastic/goosquery/sql/result"
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+)
+
+// GenGroups fails, as groups are only listed on Linux and Windows
+func GenGroups(ctx *sqlctx.Context) (*result.Results, error) {
+	return nil, fmt.Errorf("groups are not supported on this platform")
+}
@@ -56,7 +56,7 @@ func GenFileHash(ctx *sqlctx.Context, path string) (*result.Result, error) {
 }
 
 func GenHash(ctx *sqlctx.Context) (*result.Results, error) {
-	files := ctx.GetConstants("file")
+	files := ctx.GetConstants("path")
 	directories := ctx.GetConstants("directory")
 
 	if len(files) == 0 && len(directories) == 0 {
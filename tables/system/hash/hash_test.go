@@ -3,17 +3,27 @@ package hash
 import (
 	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/scrymastic/goosquery/sql/sqlctx"
 )
 
 func TestGenHash(t *testing.T) {
-	file := "C:\\Windows\\System32\\drivers\\etc\\hosts"
-	hash, err := GenFileHash(sqlctx.NewContext(), file)
+	file := filepath.Join(t.TempDir(), "hosts")
+	if err := os.WriteFile(file, []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	ctx := sqlctx.NewContext()
+	ctx.SetColumns([]string{"*"})
+	hash, err := GenFileHash(ctx, file)
 	if err != nil {
 		t.Fatalf("Failed to generate hash: %v", err)
 	}
+	if md5 := hash.Get("md5"); md5 != "5d41402abc4b2a76b9719d911017c592" {
+		t.Errorf("Unexpected md5: %v", md5)
+	}
 	// Print results as JSON for visibility
 	jsonData, err := json.MarshalIndent(hash, "", "  ")
 	if err != nil {
@@ -0,0 +1,48 @@
+//go:build linux
+
+package kernel_info
+
+import (
+	"os"
+	"strings"
+
+	"github.com/scrymastic/goosquery/sql/result"
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+	"github.com/scrymastic/goosquery/tables/internal/hostfs"
+)
+
+// readProcValue returns the trimmed content of a file of /proc
+func readProcValue(elem ...string) string {
+	data, err := os.ReadFile(hostfs.Proc(elem...))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// GenKernelInfo generates the kernel information from the release of uname
+// and the command line of the kernel
+func GenKernelInfo(ctx *sqlctx.Context) (*result.Results, error) {
+	info := result.NewResult(ctx, Schema)
+
+	// The release printed by uname -r
+	info.Set("version", readProcValue("sys", "kernel", "osrelease"))
+
+	if ctx.IsAnyOfColumnsUsed([]string{"arguments", "path", "device"}) {
+		arguments := readProcValue("cmdline")
+		info.Set("arguments", arguments)
+		// The boot loader passes the image it loaded and the root device
+		for _, argument := range strings.Fields(arguments) {
+			if path, found := strings.CutPrefix(argument, "BOOT_IMAGE="); found {
+				info.Set("path", path)
+			}
+			if device, found := strings.CutPrefix(argument, "root="); found {
+				info.Set("device", device)
+			}
+		}
+	}
+
+	results := result.NewQueryResult()
+	results.AppendResult(*info)
+	return results, nil
+}
@@ -0,0 +1,49 @@
+//go:build linux
+
+package kernel_info
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+	"github.com/scrymastic/goosquery/tables/internal/hostfstest"
+)
+
+func TestGenKernelInfoFromProc(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"sys/kernel/osrelease": "6.1.0-18-amd64\n",
+		"cmdline":              "BOOT_IMAGE=/boot/vmlinuz-6.1.0-18-amd64 root=UUID=0b1c2d3e ro quiet\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	hostfstest.SetProcRoot(t, root)
+
+	ctx := sqlctx.NewContext()
+	ctx.SetColumns([]string{"*"})
+	info, err := GenKernelInfo(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get kernel info: %v", err)
+	}
+
+	expected := map[string]string{
+		"version":   "6.1.0-18-amd64",
+		"arguments": "BOOT_IMAGE=/boot/vmlinuz-6.1.0-18-amd64 root=UUID=0b1c2d3e ro quiet",
+		"path":      "/boot/vmlinuz-6.1.0-18-amd64",
+		"device":    "UUID=0b1c2d3e",
+	}
+	for column, value := range expected {
+		if (*info)[0].Get(column) != value {
+			t.Errorf("Expected %s = %q, got %v", column, value, (*info)[0].Get(column))
+		}
+	}
+}
@@ -0,0 +1,15 @@
+//go:build !linux && !windows
+
+package kernel_info
+
+import (
+	"fmt"
+
+	"github.com/scrymastic/goosquery/sql/result"
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+)
+
+// GenKernelInfo fails, as the kernel is only described on Linux and Windows
+func GenKernelInfo(ctx *sqlctx.Context) (*result.Results, error) {
+	return nil, fmt.Errorf("kernel info is not supported on this platform")
+}
@@ -0,0 +1,58 @@
+//go:build linux
+
+package kernel_modules
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/scrymastic/goosquery/sql/result"
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+	"github.com/scrymastic/goosquery/tables/internal/hostfs"
+)
+
+// GenKernelModules returns the loaded modules of /proc/modules, whose lines
+// are like "ext4 1040384 1 - Live 0xffffffffc0a3b000", with the modules
+// using the module in the fourth field
+func GenKernelModules(ctx *sqlctx.Context) (*result.Results, error) {
+	results := result.NewQueryResult()
+	file, err := os.Open(hostfs.Proc("modules"))
+	if errors.Is(err, fs.ErrNotExist) {
+		// Kernels built without loadable modules
+		return results, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read kernel modules: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 6 {
+			continue
+		}
+		module := result.NewResult(ctx, Schema)
+		module.Set("name", fields[0])
+		if size, err := strconv.ParseInt(fields[1], 10, 64); err == nil {
+			module.Set("size", size)
+		}
+		usedBy := strings.TrimSuffix(fields[3], ",")
+		if usedBy == "-" {
+			usedBy = ""
+		}
+		module.Set("used_by", usedBy)
+		module.Set("status", fields[4])
+		module.Set("address", fields[5])
+		results.AppendResult(*module)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read kernel modules: %w", err)
+	}
+	return results, nil
+}
@@ -0,0 +1,46 @@
+//go:build linux
+
+package kernel_modules
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+	"github.com/scrymastic/goosquery/tables/internal/hostfstest"
+)
+
+func TestGenKernelModules(t *testing.T) {
+	proc := t.TempDir()
+	modules := "ext4 1040384 1 - Live 0xffffffffc0a3b000\n" +
+		"jbd2 196608 1 ext4, Live 0xffffffffc0a08000\n" +
+		"mbcache 16384 1 ext4,jbd2, Unloading 0x0000000000000000\n"
+	if err := os.WriteFile(filepath.Join(proc, "modules"), []byte(modules), 0644); err != nil {
+		t.Fatal(err)
+	}
+	hostfstest.SetProcRoot(t, proc)
+
+	ctx := sqlctx.NewContext()
+	ctx.SetColumns([]string{"*"})
+	results, err := GenKernelModules(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get kernel modules: %v", err)
+	}
+	if results.Size() != 3 {
+		t.Fatalf("Expected 3 modules, got %d", results.Size())
+	}
+
+	if ext4 := (*results)[0]; ext4.Get("name") != "ext4" || ext4.Get("size") != int64(1040384) || ext4.Get("used_by") != "" || ext4.Get("address") != "0xffffffffc0a3b000" {
+		t.Errorf("Unexpected module: %v", ext4)
+	}
+	if mbcache := (*results)[2]; mbcache.Get("used_by") != "ext4,jbd2" || mbcache.Get("status") != "Unloading" {
+		t.Errorf("Unexpected module: %v", mbcache)
+	}
+
+	// Kernels built without loadable modules have no /proc/modules
+	hostfstest.SetProcRoot(t, t.TempDir())
+	if results, err := GenKernelModules(ctx); err != nil || results.Size() != 0 {
+		t.Errorf("Expected no modules, got %v, %v", results, err)
+	}
+}
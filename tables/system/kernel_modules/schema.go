@@ -0,0 +1,15 @@
+package kernel_modules
+
+import (
+	"github.com/scrymastic/goosquery/sql/result"
+)
+
+var TableName = "kernel_modules"
+var Description = "Linux kernel modules loaded in the kernel."
+var Schema = result.Schema{
+	result.Column{Name: "name", Type: "TEXT", Description: "Module name"},
+	result.Column{Name: "size", Type: "BIGINT", Description: "Size of module content"},
+	result.Column{Name: "used_by", Type: "TEXT", Description: "Module reverse dependencies"},
+	result.Column{Name: "status", Type: "TEXT", Description: "Kernel module status"},
+	result.Column{Name: "address", Type: "TEXT", Description: "Kernel module address"},
+}
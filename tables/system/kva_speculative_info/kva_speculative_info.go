@@ -1,3 +1,5 @@
+//go:build windows
+
 package kva_speculative_info
 
 import (
@@ -0,0 +1,150 @@
+//go:build linux
+
+package logged_in_users
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/scrymastic/goosquery/sql/result"
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+	"github.com/scrymastic/goosquery/tables/internal/hostfs"
+)
+
+// Types of the utmp records
+const (
+	utmpBootTime    = 2
+	utmpUserProcess = 7
+	utmpDeadProcess = 8
+)
+
+// utmpRecord is a record of utmp and wtmp, as written by glibc on 64 bit
+// and 32 bit hosts alike
+type utmpRecord struct {
+	Type    int16
+	_       [2]byte
+	Pid     int32
+	Line    [32]byte
+	ID      [4]byte
+	User    [32]byte
+	Host    [256]byte
+	Exit    [2]int16
+	Session int32
+	Sec     int32
+	Usec    int32
+	Addr    [4]int32
+	_       [20]byte
+}
+
+// cString returns the text of a NUL padded field
+func cString(field []byte) string {
+	if end := bytes.IndexByte(field, 0); end >= 0 {
+		field = field[:end]
+	}
+	return string(field)
+}
+
+// readUtmp returns the records of a utmp or wtmp file
+func readUtmp(path string) ([]utmpRecord, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var records []utmpRecord
+	reader := bytes.NewReader(data)
+	for {
+		var record utmpRecord
+		if err := binary.Read(reader, binary.NativeEndian, &record); err != nil {
+			// A record being written is truncated
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				return records, nil
+			}
+			return nil, err
+		}
+		records = append(records, record)
+	}
+}
+
+// openSessions replays the records of wtmp, where a login is closed by a
+// dead process on the same line or by a reboot, and returns the sessions
+// still open
+func openSessions(records []utmpRecord) []utmpRecord {
+	sessions := make(map[string]int)
+	for i, record := range records {
+		line := cString(record.Line[:])
+		switch record.Type {
+		case utmpUserProcess:
+			sessions[line] = i
+		case utmpDeadProcess:
+			delete(sessions, line)
+		case utmpBootTime:
+			clear(sessions)
+		}
+	}
+
+	indexes := make([]int, 0, len(sessions))
+	for _, index := range sessions {
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+	open := make([]utmpRecord, 0, len(indexes))
+	for _, index := range indexes {
+		open = append(open, records[index])
+	}
+	return open
+}
+
+// readSessions returns the records of the current sessions from utmp, or
+// from wtmp when utmp is missing, as in offline images where /run is empty
+func readSessions() ([]utmpRecord, error) {
+	for _, path := range []string{hostfs.Path("run", "utmp"), hostfs.Path("var", "run", "utmp")} {
+		records, err := readUtmp(path)
+		if err == nil {
+			return records, nil
+		}
+		if !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+	records, err := readUtmp(hostfs.Path("var", "log", "wtmp"))
+	if err != nil {
+		return nil, err
+	}
+	return openSessions(records), nil
+}
+
+// GenLoggedInUsers returns the user sessions recorded in utmp
+func GenLoggedInUsers(ctx *sqlctx.Context) (*result.Results, error) {
+	records, err := readSessions()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sessions: %w", err)
+	}
+
+	results := result.NewQueryResult()
+	for _, record := range records {
+		// utmp also records the terminals waiting for a login and the
+		// sessions that ended
+		if record.Type != utmpUserProcess {
+			continue
+		}
+
+		user := result.NewResult(ctx, Schema)
+		user.Set("type", "user")
+		user.Set("user", cString(record.User[:]))
+		user.Set("tty", cString(record.Line[:]))
+		user.Set("host", cString(record.Host[:]))
+		user.Set("time", int64(record.Sec))
+		user.Set("pid", record.Pid)
+		user.Set("sid", "")
+		user.Set("registry_hive", "")
+
+		results.AppendResult(*user)
+	}
+
+	return results, nil
+}
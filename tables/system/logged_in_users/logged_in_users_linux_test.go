@@ -0,0 +1,114 @@
+//go:build linux
+
+package logged_in_users
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+	"github.com/scrymastic/goosquery/tables/internal/hostfstest"
+)
+
+// newRecord returns a utmp record
+func newRecord(recordType int16, pid int32, line, user, host string, sec int32) utmpRecord {
+	record := utmpRecord{Type: recordType, Pid: pid, Sec: sec}
+	copy(record.Line[:], line)
+	copy(record.User[:], user)
+	copy(record.Host[:], host)
+	return record
+}
+
+// writeUtmp writes the records of a utmp or wtmp file under the fixture root
+func writeUtmp(t *testing.T, root string, name string, records ...utmpRecord) {
+	t.Helper()
+	var buffer bytes.Buffer
+	for _, record := range records {
+		if err := binary.Write(&buffer, binary.NativeEndian, record); err != nil {
+			t.Fatal(err)
+		}
+	}
+	path := filepath.Join(root, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, buffer.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// useRoot makes the table read the files of a fixture root
+func useRoot(t *testing.T, root string) {
+	t.Helper()
+	hostfstest.SetRoot(t, root)
+}
+
+func TestUtmpRecordSize(t *testing.T) {
+	if size := binary.Size(utmpRecord{}); size != 384 {
+		t.Errorf("Expected records of 384 bytes, got %d", size)
+	}
+}
+
+func TestGenLoggedInUsersFromUtmp(t *testing.T) {
+	root := t.TempDir()
+	writeUtmp(t, root, "run/utmp",
+		newRecord(utmpBootTime, 0, "~", "reboot", "6.1.0", 1700000000),
+		newRecord(6, 512, "tty1", "LOGIN", "", 1700000010),
+		newRecord(utmpUserProcess, 1234, "pts/0", "alice", "192.0.2.10", 1700000100),
+		newRecord(utmpDeadProcess, 1300, "pts/1", "", "", 1700000200),
+	)
+	// utmp is preferred over the login history
+	writeUtmp(t, root, "var/log/wtmp", newRecord(utmpUserProcess, 99, "pts/9", "bob", "", 1600000000))
+	useRoot(t, root)
+
+	ctx := sqlctx.NewContext()
+	ctx.SetColumns([]string{"*"})
+	users, err := GenLoggedInUsers(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get logged in users: %v", err)
+	}
+	if users.Size() != 1 {
+		t.Fatalf("Expected 1 session, got %v", users)
+	}
+
+	expected := map[string]interface{}{
+		"type": "user",
+		"user": "alice",
+		"tty":  "pts/0",
+		"host": "192.0.2.10",
+		"time": int64(1700000100),
+		"pid":  int32(1234),
+	}
+	for column, value := range expected {
+		if (*users)[0].Get(column) != value {
+			t.Errorf("Expected %s = %v, got %v", column, value, (*users)[0].Get(column))
+		}
+	}
+}
+
+func TestGenLoggedInUsersFromWtmp(t *testing.T) {
+	// Offline images have no utmp, so the open sessions are replayed from wtmp
+	root := t.TempDir()
+	writeUtmp(t, root, "var/log/wtmp",
+		newRecord(utmpUserProcess, 10, "pts/0", "old", "", 1600000000),
+		newRecord(utmpBootTime, 0, "~", "reboot", "", 1700000000),
+		newRecord(utmpUserProcess, 20, "pts/0", "alice", "", 1700000100),
+		newRecord(utmpUserProcess, 21, "pts/1", "bob", "", 1700000200),
+		newRecord(utmpDeadProcess, 21, "pts/1", "", "", 1700000300),
+		newRecord(utmpUserProcess, 22, "tty1", "carol", "", 1700000400),
+	)
+	useRoot(t, root)
+
+	ctx := sqlctx.NewContext()
+	ctx.SetColumns([]string{"user", "tty"})
+	users, err := GenLoggedInUsers(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get logged in users: %v", err)
+	}
+	if users.Size() != 2 || (*users)[0].Get("user") != "alice" || (*users)[1].Get("user") != "carol" {
+		t.Errorf("Expected the sessions of alice and carol, got %v", users)
+	}
+}
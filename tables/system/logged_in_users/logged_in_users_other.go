@@ -0,0 +1,15 @@
+//go:build !linux && !windows
+
+package logged_in_users
+
+import (
+	"fmt"
+
+	"github.com/scrymastic/goosquery/sql/result"
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+)
+
+// GenLoggedInUsers fails, as sessions are only listed on Linux and Windows
+func GenLoggedInUsers(ctx *sqlctx.Context) (*result.Results, error) {
+	return nil, fmt.Errorf("logged in users are not supported on this platform")
+}
@@ -1,3 +1,5 @@
+//go:build windows
+
 package logged_in_users
 
 import (
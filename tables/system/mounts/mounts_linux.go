@@ -0,0 +1,124 @@
+//go:build linux
+
+package mounts
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/scrymastic/goosquery/sql/result"
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+	"github.com/scrymastic/goosquery/tables/internal/hostfs"
+)
+
+// mount is a line of /proc/self/mountinfo
+type mount struct {
+	path    string
+	options string
+	fsType  string
+	source  string
+}
+
+// unescape decodes the octal escapes of the paths of mountinfo, such as \040
+// for the spaces
+func unescape(value string) string {
+	if !strings.Contains(value, `\`) {
+		return value
+	}
+	var decoded strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] == '\\' && i+4 <= len(value) {
+			if code, err := strconv.ParseUint(value[i+1:i+4], 8, 8); err == nil {
+				decoded.WriteByte(byte(code))
+				i += 3
+				continue
+			}
+		}
+		decoded.WriteByte(value[i])
+	}
+	return decoded.String()
+}
+
+// parseMountInfo parses a line of mountinfo, such as
+// 28 1 254:0 / / rw,relatime shared:1 - ext4 /dev/vda rw,discard
+// where the optional fields before the hyphen vary in number
+func parseMountInfo(line string) (mount, bool) {
+	before, after, found := strings.Cut(line, " - ")
+	if !found {
+		return mount{}, false
+	}
+	fields, tail := strings.Fields(before), strings.Fields(after)
+	if len(fields) < 6 || len(tail) < 2 {
+		return mount{}, false
+	}
+	return mount{
+		path:    unescape(fields[4]),
+		options: fields[5],
+		fsType:  tail[0],
+		source:  unescape(tail[1]),
+	}, true
+}
+
+// deviceAlias follows the symbolic links of a device, such as the links of
+// /dev/mapper and /dev/disk
+func deviceAlias(device string) string {
+	if !strings.HasPrefix(device, "/") {
+		return device
+	}
+	resolved, err := filepath.EvalSymlinks(hostfs.Path(device))
+	if err != nil {
+		return device
+	}
+	relative, err := filepath.Rel(hostfs.Root, resolved)
+	if err != nil || strings.HasPrefix(relative, "..") {
+		return device
+	}
+	return filepath.Join("/", relative)
+}
+
+// GenMounts returns the mounts of /proc/self/mountinfo, with the usage of
+// their filesystems
+func GenMounts(ctx *sqlctx.Context) (*result.Results, error) {
+	file, err := os.Open(hostfs.Proc("self", "mountinfo"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mounts: %w", err)
+	}
+	defer file.Close()
+
+	results := result.NewQueryResult()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		entry, ok := parseMountInfo(scanner.Text())
+		if !ok {
+			continue
+		}
+		row := result.NewResult(ctx, Schema)
+		row.Set("device", entry.source)
+		row.Set("device_alias", deviceAlias(entry.source))
+		row.Set("path", entry.path)
+		row.Set("type", entry.fsType)
+		row.Set("flags", entry.options)
+
+		if ctx.IsAnyOfColumnsUsed([]string{"blocks_size", "blocks", "blocks_free", "blocks_available", "inodes", "inodes_free"}) {
+			var stat syscall.Statfs_t
+			if err := syscall.Statfs(hostfs.Path(entry.path), &stat); err == nil {
+				row.Set("blocks_size", int64(stat.Bsize))
+				row.Set("blocks", int64(stat.Blocks))
+				row.Set("blocks_free", int64(stat.Bfree))
+				row.Set("blocks_available", int64(stat.Bavail))
+				row.Set("inodes", int64(stat.Files))
+				row.Set("inodes_free", int64(stat.Ffree))
+			}
+		}
+		results.AppendResult(*row)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read mounts: %w", err)
+	}
+	return results, nil
+}
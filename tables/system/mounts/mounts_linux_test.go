@@ -0,0 +1,61 @@
+//go:build linux
+
+package mounts
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+	"github.com/scrymastic/goosquery/tables/internal/hostfstest"
+)
+
+func TestParseMountInfo(t *testing.T) {
+	entry, ok := parseMountInfo(`36 35 98:0 /mnt1 /mnt/my\040disk rw,noatime master:1 shared:2 - ext3 /dev/root rw,errors=continue`)
+	if !ok {
+		t.Fatal("Expected the line to be parsed")
+	}
+	expected := mount{path: "/mnt/my disk", options: "rw,noatime", fsType: "ext3", source: "/dev/root"}
+	if entry != expected {
+		t.Errorf("Expected %+v, got %+v", expected, entry)
+	}
+	if _, ok := parseMountInfo("36 35 98:0 /mnt1 /mnt2 rw"); ok {
+		t.Error("Expected a line without separator to be skipped")
+	}
+}
+
+func TestGenMounts(t *testing.T) {
+	proc := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(proc, "self"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	mountinfo := "28 1 254:0 / / rw,relatime shared:1 - ext4 /dev/vda rw\n" +
+		"25 28 0:6 / /dev rw,nosuid - devtmpfs devtmpfs rw,mode=755\n"
+	if err := os.WriteFile(filepath.Join(proc, "self", "mountinfo"), []byte(mountinfo), 0644); err != nil {
+		t.Fatal(err)
+	}
+	hostfstest.SetProcRoot(t, proc)
+
+	ctx := sqlctx.NewContext()
+	ctx.SetColumns([]string{"*"})
+	mounts, err := GenMounts(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get mounts: %v", err)
+	}
+	if mounts.Size() != 2 {
+		t.Fatalf("Expected 2 mounts, got %d", mounts.Size())
+	}
+
+	root := (*mounts)[0]
+	if root.Get("device") != "/dev/vda" || root.Get("path") != "/" || root.Get("type") != "ext4" || root.Get("flags") != "rw,relatime" {
+		t.Errorf("Unexpected mount: %v", root)
+	}
+	// The usage comes from the filesystem of the test host
+	if blocks, ok := root.Get("blocks").(int64); !ok || blocks <= 0 {
+		t.Errorf("Expected the blocks of / to be set, got %v", root.Get("blocks"))
+	}
+	if dev := (*mounts)[1]; dev.Get("device_alias") != "devtmpfs" {
+		t.Errorf("Unexpected mount: %v", dev)
+	}
+}
@@ -0,0 +1,21 @@
+package mounts
+
+import (
+	"github.com/scrymastic/goosquery/sql/result"
+)
+
+var TableName = "mounts"
+var Description = "System mounted devices and filesystems (not process specific)."
+var Schema = result.Schema{
+	result.Column{Name: "device", Type: "TEXT", Description: "Mounted device"},
+	result.Column{Name: "device_alias", Type: "TEXT", Description: "Mounted device alias, the device after following its symbolic links"},
+	result.Column{Name: "path", Type: "TEXT", Description: "Mounted device path"},
+	result.Column{Name: "type", Type: "TEXT", Description: "Mounted device type"},
+	result.Column{Name: "blocks_size", Type: "BIGINT", Description: "Block size in bytes"},
+	result.Column{Name: "blocks", Type: "BIGINT", Description: "Mounted device total blocks"},
+	result.Column{Name: "blocks_free", Type: "BIGINT", Description: "Mounted device free blocks"},
+	result.Column{Name: "blocks_available", Type: "BIGINT", Description: "Mounted device available blocks"},
+	result.Column{Name: "inodes", Type: "BIGINT", Description: "Mounted device total inodes"},
+	result.Column{Name: "inodes_free", Type: "BIGINT", Description: "Mounted device free inodes"},
+	result.Column{Name: "flags", Type: "TEXT", Description: "Mounted device flags"},
+}
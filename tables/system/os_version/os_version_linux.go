@@ -0,0 +1,87 @@
+//go:build linux
+
+package os_version
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/scrymastic/goosquery/sql/result"
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+	"github.com/scrymastic/goosquery/tables/internal/cpuinfo"
+	"github.com/scrymastic/goosquery/tables/internal/hostfs"
+)
+
+// readOSRelease parses os-release(5), from /etc or from /usr/lib where
+// the distributions install it
+func readOSRelease() (map[string]string, error) {
+	file, err := os.Open(hostfs.Path("etc", "os-release"))
+	if os.IsNotExist(err) {
+		file, err = os.Open(hostfs.Path("usr", "lib", "os-release"))
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	fields := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			continue
+		}
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			value = unquoted
+		} else {
+			value = strings.Trim(value, `'"`)
+		}
+		fields[key] = value
+	}
+	return fields, scanner.Err()
+}
+
+// GenOSVersion returns the distribution described by os-release
+func GenOSVersion(ctx *sqlctx.Context) (*result.Results, error) {
+	release, err := readOSRelease()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read os-release: %v", err)
+	}
+
+	osVersion := result.NewResult(ctx, Schema)
+
+	osVersion.Set("name", release["NAME"])
+	osVersion.Set("version", release["VERSION"])
+	if release["VERSION"] == "" {
+		// Rolling releases, such as Arch Linux, have no version
+		osVersion.Set("version", release["VERSION_ID"])
+	}
+	osVersion.Set("build", release["BUILD_ID"])
+	osVersion.Set("platform", release["ID"])
+	osVersion.Set("platform_like", release["ID_LIKE"])
+	osVersion.Set("codename", release["VERSION_CODENAME"])
+	osVersion.Set("arch", cpuinfo.Arch())
+
+	if ctx.IsAnyOfColumnsUsed([]string{"major", "minor", "patch"}) && release["VERSION_ID"] != "" {
+		// Missing parts are 0, so that "12" compares as 12.0.0
+		parts := strings.Split(release["VERSION_ID"], ".")
+		for i, column := range []string{"major", "minor", "patch"} {
+			value := int64(0)
+			if i < len(parts) {
+				value, _ = strconv.ParseInt(parts[i], 10, 32)
+			}
+			osVersion.Set(column, int32(value))
+		}
+	}
+
+	queryResult := result.NewQueryResult()
+	queryResult.AppendResult(*osVersion)
+	return queryResult, nil
+}
@@ -0,0 +1,77 @@
+//go:build linux
+
+package os_version
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+	"github.com/scrymastic/goosquery/tables/internal/hostfstest"
+)
+
+// useOSRelease makes the table read an os-release file at the given path
+// under a fixture root
+func useOSRelease(t *testing.T, name string, content string) {
+	t.Helper()
+	root := t.TempDir()
+	path := filepath.Join(root, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	hostfstest.SetRoot(t, root)
+}
+
+func TestGenOSVersionFromOSRelease(t *testing.T) {
+	useOSRelease(t, "etc/os-release", `# Ubuntu
+NAME="Ubuntu"
+VERSION="22.04.3 LTS (Jammy Jellyfish)"
+ID=ubuntu
+ID_LIKE=debian
+VERSION_ID="22.04"
+VERSION_CODENAME=jammy
+`)
+	ctx := sqlctx.NewContext()
+	ctx.SetColumns([]string{"*"})
+
+	versions, err := GenOSVersion(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get OS version: %v", err)
+	}
+	expected := map[string]interface{}{
+		"name":          "Ubuntu",
+		"version":       "22.04.3 LTS (Jammy Jellyfish)",
+		"major":         int32(22),
+		"minor":         int32(4),
+		"patch":         int32(0),
+		"platform":      "ubuntu",
+		"platform_like": "debian",
+		"codename":      "jammy",
+		"install_date":  int64(-1),
+	}
+	for column, value := range expected {
+		if (*versions)[0].Get(column) != value {
+			t.Errorf("Expected %s = %v, got %v", column, value, (*versions)[0].Get(column))
+		}
+	}
+}
+
+func TestGenOSVersionRollingRelease(t *testing.T) {
+	// Only /usr/lib/os-release exists, and there is no version
+	useOSRelease(t, "usr/lib/os-release", "NAME='Arch Linux'\nID=arch\nBUILD_ID=rolling\nVERSION_ID=20240101.0.204074\n")
+	ctx := sqlctx.NewContext()
+	ctx.SetColumns([]string{"name", "version", "major", "build"})
+
+	versions, err := GenOSVersion(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get OS version: %v", err)
+	}
+	row := (*versions)[0]
+	if row.Get("name") != "Arch Linux" || row.Get("version") != "20240101.0.204074" || row.Get("major") != int32(20240101) || row.Get("build") != "rolling" {
+		t.Errorf("Unexpected OS version: %v", row)
+	}
+}
@@ -0,0 +1,15 @@
+//go:build !linux && !windows
+
+package os_version
+
+import (
+	"fmt"
+
+	"github.com/scrymastic/goosquery/sql/result"
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+)
+
+// GenOSVersion fails, as the version is only read on Linux and Windows
+func GenOSVersion(ctx *sqlctx.Context) (*result.Results, error) {
+	return nil, fmt.Errorf("os version is not supported on this platform")
+}
@@ -0,0 +1,153 @@
+//go:build linux
+
+package physical_disk_performance
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/scrymastic/goosquery/sql/result"
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+	"github.com/scrymastic/goosquery/tables/internal/hostfs"
+)
+
+// sectorSize is the unit of the sector counts of /proc/diskstats
+const sectorSize = 512
+
+// diskStats are the counters of a line of /proc/diskstats, accumulated
+// since boot, with the times in milliseconds
+type diskStats struct {
+	name           string
+	reads          int64
+	sectorsRead    int64
+	readTicks      int64
+	writes         int64
+	sectorsWritten int64
+	writeTicks     int64
+	inFlight       int64
+	ioTicks        int64
+}
+
+// parseDiskStats parses a line of /proc/diskstats, such as
+// 254 0 vda 1289 330 82654 482 4401 2870 215474 5621 0 5456 6104 ...
+func parseDiskStats(line string) (diskStats, bool) {
+	fields := strings.Fields(line)
+	if len(fields) < 14 {
+		return diskStats{}, false
+	}
+	values := make([]int64, 11)
+	for i := range values {
+		value, err := strconv.ParseInt(fields[3+i], 10, 64)
+		if err != nil {
+			return diskStats{}, false
+		}
+		values[i] = value
+	}
+	return diskStats{
+		name:           fields[2],
+		reads:          values[0],
+		sectorsRead:    values[2],
+		readTicks:      values[3],
+		writes:         values[4],
+		sectorsWritten: values[6],
+		writeTicks:     values[7],
+		inFlight:       values[8],
+		ioTicks:        values[9],
+	}, true
+}
+
+// isPhysicalDisk reports whether a device is a whole disk, skipping the
+// partitions and the loop and ram devices
+func isPhysicalDisk(name string) bool {
+	if strings.HasPrefix(name, "loop") || strings.HasPrefix(name, "ram") {
+		return false
+	}
+	_, err := os.Stat(hostfs.Sys("block", name))
+	return err == nil
+}
+
+// readUptime returns the milliseconds since boot of /proc/uptime
+func readUptime() (int64, error) {
+	data, err := os.ReadFile(hostfs.Proc("uptime"))
+	if err != nil {
+		return 0, err
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("empty %s", hostfs.Proc("uptime"))
+	}
+	seconds, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0, err
+	}
+	return int64(seconds * 1000), nil
+}
+
+// ratio divides two counters, or returns 0 when there is nothing to divide
+func ratio(value int64, total int64) int64 {
+	if total <= 0 {
+		return 0
+	}
+	return value / total
+}
+
+// seconds returns the average time of an operation in seconds, from the
+// milliseconds spent on the operations, or 0 when there was none
+func seconds(ticks int64, operations int64) float64 {
+	if operations <= 0 {
+		return 0
+	}
+	return float64(ticks) / float64(operations) / 1000
+}
+
+// setPerformance sets the columns of a disk from its counters. As the
+// counters are not sampled, the averages and the percentages are the ones
+// since boot, and the times per operation are in seconds with fractions.
+func setPerformance(disk *result.Result, stats diskStats, uptime int64) {
+	disk.Set("name", stats.name)
+	disk.Set("avg_disk_bytes_per_read", ratio(stats.sectorsRead*sectorSize, stats.reads))
+	disk.Set("avg_disk_bytes_per_write", ratio(stats.sectorsWritten*sectorSize, stats.writes))
+	disk.Set("avg_disk_read_queue_length", ratio(stats.readTicks, uptime))
+	disk.Set("avg_disk_write_queue_length", ratio(stats.writeTicks, uptime))
+	disk.Set("avg_disk_sec_per_read", seconds(stats.readTicks, stats.reads))
+	disk.Set("avg_disk_sec_per_write", seconds(stats.writeTicks, stats.writes))
+	disk.Set("current_disk_queue_length", int32(stats.inFlight))
+	disk.Set("percent_disk_read_time", ratio(stats.readTicks*100, uptime))
+	disk.Set("percent_disk_write_time", ratio(stats.writeTicks*100, uptime))
+	busy := min(ratio(stats.ioTicks*100, uptime), 100)
+	disk.Set("percent_disk_time", busy)
+	disk.Set("percent_idle_time", 100-busy)
+}
+
+// GenPhysicalDiskPerformance returns the counters of the disks of
+// /proc/diskstats
+func GenPhysicalDiskPerformance(ctx *sqlctx.Context) (*result.Results, error) {
+	uptime, err := readUptime()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get uptime: %w", err)
+	}
+	file, err := os.Open(hostfs.Proc("diskstats"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read disk statistics: %w", err)
+	}
+	defer file.Close()
+
+	results := result.NewQueryResult()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		stats, ok := parseDiskStats(scanner.Text())
+		if !ok || !isPhysicalDisk(stats.name) {
+			continue
+		}
+		disk := result.NewResult(ctx, Schema)
+		setPerformance(disk, stats, uptime)
+		results.AppendResult(*disk)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read disk statistics: %w", err)
+	}
+	return results, nil
+}
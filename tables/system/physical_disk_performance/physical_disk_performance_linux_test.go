@@ -0,0 +1,77 @@
+//go:build linux
+
+package physical_disk_performance
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/scrymastic/goosquery/sql/result"
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+	"github.com/scrymastic/goosquery/tables/internal/hostfstest"
+)
+
+func TestGenPhysicalDiskPerformance(t *testing.T) {
+	proc, sys := t.TempDir(), t.TempDir()
+	diskstats := "   7       0 loop0 12 0 24 1 0 0 0 0 0 4 1 0 0 0 0\n" +
+		" 259       0 nvme0n1 1000 10 80000 2000 500 20 40000 6000 2 50000 8000 0 0 0 0\n" +
+		" 259       1 nvme0n1p1 900 10 72000 1800 500 20 40000 6000 0 45000 7800 0 0 0 0\n"
+	files := map[string]string{
+		filepath.Join(proc, "diskstats"): diskstats,
+		filepath.Join(proc, "uptime"):    "100.00 350.00\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(name, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, disk := range []string{"loop0", "nvme0n1"} {
+		if err := os.MkdirAll(filepath.Join(sys, "block", disk), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	hostfstest.SetProcRoot(t, proc)
+	hostfstest.SetSysRoot(t, sys)
+
+	ctx := sqlctx.NewContext()
+	ctx.SetColumns([]string{"*"})
+	disks, err := GenPhysicalDiskPerformance(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get disk performance: %v", err)
+	}
+	if disks.Size() != 1 {
+		t.Fatalf("Expected only the nvme disk, got %d disks", disks.Size())
+	}
+
+	expected := map[string]interface{}{
+		"name":                      "nvme0n1",
+		"avg_disk_bytes_per_read":   int64(40960),
+		"avg_disk_bytes_per_write":  int64(40960),
+		"avg_disk_sec_per_read":     0.002,
+		"avg_disk_sec_per_write":    0.012,
+		"current_disk_queue_length": int32(2),
+		"percent_disk_read_time":    int64(2),
+		"percent_disk_write_time":   int64(6),
+		"percent_disk_time":         int64(50),
+		"percent_idle_time":         int64(50),
+	}
+	disk := (*disks)[0]
+	for column, value := range expected {
+		if got := disk.Get(column); got != value {
+			t.Errorf("Expected %s to be %v, got %v", column, value, got)
+		}
+	}
+}
+
+func TestSetPerformanceSeconds(t *testing.T) {
+	ctx := sqlctx.NewContext()
+	ctx.SetColumns([]string{"avg_disk_sec_per_read", "avg_disk_sec_per_write"})
+	disk := result.NewResult(ctx, Schema)
+
+	// The times of /proc/diskstats are in milliseconds
+	setPerformance(disk, diskStats{reads: 4, readTicks: 10, writes: 0, writeTicks: 0}, 100000)
+	if disk.Get("avg_disk_sec_per_read") != 0.0025 || disk.Get("avg_disk_sec_per_write") != 0.0 {
+		t.Errorf("Expected 0.0025 and 0 seconds per operation, got %v", disk.ToMap())
+	}
+}
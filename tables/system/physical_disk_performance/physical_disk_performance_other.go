@@ -0,0 +1,16 @@
+//go:build !linux && !windows
+
+package physical_disk_performance
+
+import (
+	"fmt"
+
+	"github.com/scrymastic/goosquery/sql/result"
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+)
+
+// GenPhysicalDiskPerformance fails, as the performance of disks is only
+// listed on Linux and Windows
+func GenPhysicalDiskPerformance(ctx *sqlctx.Context) (*result.Results, error) {
+	return nil, fmt.Errorf("physical disk performance is not supported on this platform")
+}
@@ -1,3 +1,5 @@
+//go:build windows
+
 package physical_disk_performance
 
 import (
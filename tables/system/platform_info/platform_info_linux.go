@@ -0,0 +1,60 @@
+//go:build linux
+
+package platform_info
+
+import (
+	"os"
+	"strings"
+	"time"
+
+	"github.com/scrymastic/goosquery/sql/result"
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+	"github.com/scrymastic/goosquery/tables/internal/hostfs"
+)
+
+// readDMI returns an attribute of /sys/class/dmi/id
+func readDMI(attribute string) string {
+	data, err := os.ReadFile(hostfs.Sys("class", "dmi", "id", attribute))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// formatBIOSDate converts the date of the BIOS (like "06/04/2024") to the
+// ISO 8601 format used on Windows
+func formatBIOSDate(date string) string {
+	parsed, err := time.Parse("01/02/2006", date)
+	if err != nil {
+		// Return original if parsing fails
+		return date
+	}
+	return parsed.Format("2006-01-02")
+}
+
+// getFirmwareType determines the type of firmware from the EFI variables
+// exposed by the kernel of the hosts booted with UEFI
+func getFirmwareType() FirmwareType {
+	if _, err := os.Stat(hostfs.Sys("firmware", "efi")); err == nil {
+		return FirmwareTypeUefi
+	}
+	return FirmwareTypeBios
+}
+
+// GenPlatformInfo retrieves system BIOS and firmware information from
+// /sys/class/dmi/id
+func GenPlatformInfo(ctx *sqlctx.Context) (*result.Results, error) {
+	platformInfo := result.NewResult(ctx, Schema)
+
+	platformInfo.Set("vendor", readDMI("bios_vendor"))
+	platformInfo.Set("version", readDMI("bios_version"))
+	// Only exposed by kernels since 5.10
+	platformInfo.Set("revision", readDMI("bios_release"))
+	platformInfo.Set("date", formatBIOSDate(readDMI("bios_date")))
+	platformInfo.Set("extra", "")
+	platformInfo.Set("firmware_type", GetFirmwareTypeDescription(getFirmwareType()))
+
+	platformInfoResult := result.NewQueryResult()
+	platformInfoResult.AppendResult(*platformInfo)
+	return platformInfoResult, nil
+}
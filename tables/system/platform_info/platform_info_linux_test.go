@@ -0,0 +1,61 @@
+//go:build linux
+
+package platform_info
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+	"github.com/scrymastic/goosquery/tables/internal/hostfstest"
+)
+
+func TestGenPlatformInfoFromSys(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"class/dmi/id/bios_vendor":  "American Megatrends Inc.\n",
+		"class/dmi/id/bios_version": "F.42\n",
+		"class/dmi/id/bios_date":    "06/04/2024\n",
+		"class/dmi/id/bios_release": "5.17\n",
+		"firmware/efi/systab":       "",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	hostfstest.SetSysRoot(t, root)
+
+	ctx := sqlctx.NewContext()
+	ctx.SetColumns([]string{"*"})
+	platform, err := GenPlatformInfo(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get platform info: %v", err)
+	}
+
+	expected := map[string]string{
+		"vendor":        "American Megatrends Inc.",
+		"version":       "F.42",
+		"date":          "2024-06-04",
+		"revision":      "5.17",
+		"firmware_type": "uefi",
+	}
+	for column, value := range expected {
+		if (*platform)[0].Get(column) != value {
+			t.Errorf("Expected %s = %q, got %v", column, value, (*platform)[0].Get(column))
+		}
+	}
+
+	// Without EFI variables the host booted with a BIOS
+	if err := os.RemoveAll(filepath.Join(root, "firmware")); err != nil {
+		t.Fatal(err)
+	}
+	if firmware := GetFirmwareTypeDescription(getFirmwareType()); firmware != "bios" {
+		t.Errorf("Expected bios firmware, got %s", firmware)
+	}
+}
@@ -0,0 +1,15 @@
+//go:build !linux && !windows
+
+package platform_info
+
+import (
+	"fmt"
+
+	"github.com/scrymastic/goosquery/sql/result"
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+)
+
+// GenPlatformInfo fails, as the firmware is only described on Linux and Windows
+func GenPlatformInfo(ctx *sqlctx.Context) (*result.Results, error) {
+	return nil, fmt.Errorf("platform info is not supported on this platform")
+}
@@ -1,3 +1,5 @@
+//go:build windows
+
 package process_memory_map
 
 import (
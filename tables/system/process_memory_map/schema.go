@@ -7,7 +7,7 @@ import (
 var TableName = "process_memory_map"
 var Description = "Process memory mapped files and pseudo device/regions."
 var Schema = result.Schema{
-	result.Column{Name: "pid", Type: "INTEGER", Description: "Process (or thread) ID"},
+	result.Column{Name: "pid", Type: "INTEGER", Description: "Process (or thread) ID", Options: result.Required},
 	result.Column{Name: "start", Type: "TEXT", Description: "Virtual start address (hex)"},
 	result.Column{Name: "end", Type: "TEXT", Description: "Virtual end address (hex)"},
 	result.Column{Name: "permissions", Type: "TEXT", Description: "r=read, w=write, x=execute, p=private (cow)"},
@@ -0,0 +1,271 @@
+//go:build linux
+
+package processes
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/scrymastic/goosquery/sql/result"
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+	"github.com/scrymastic/goosquery/tables/internal/hostfs"
+)
+
+// clockTicks is the number of clock ticks per second used by /proc, which is
+// USER_HZ and 100 on every Linux architecture
+const clockTicks = 100
+
+// procStat holds the fields of /proc/<pid>/stat used by the table
+type procStat struct {
+	name      string
+	state     string
+	parent    int64
+	pgroup    int64
+	userTime  int64 // clock ticks
+	sysTime   int64 // clock ticks
+	nice      int32
+	threads   int32
+	startTime int64 // clock ticks after boot
+}
+
+// parseProcStat parses the content of /proc/<pid>/stat. The name is
+// enclosed in parentheses and may itself contain spaces and parentheses.
+func parseProcStat(data string) (*procStat, error) {
+	open := strings.IndexByte(data, '(')
+	end := strings.LastIndexByte(data, ')')
+	if open < 0 || end < open {
+		return nil, fmt.Errorf("malformed stat: %q", data)
+	}
+	// Fields after the name, starting with the state as field 3 of proc(5)
+	fields := strings.Fields(data[end+1:])
+	if len(fields) < 20 {
+		return nil, fmt.Errorf("malformed stat: %d fields", len(fields))
+	}
+
+	field := func(index int) int64 {
+		value, _ := strconv.ParseInt(fields[index], 10, 64)
+		return value
+	}
+	return &procStat{
+		name:      data[open+1 : end],
+		state:     fields[0],
+		parent:    field(1),
+		pgroup:    field(2),
+		userTime:  field(11),
+		sysTime:   field(12),
+		nice:      int32(field(16)),
+		threads:   int32(field(17)),
+		startTime: field(19),
+	}, nil
+}
+
+// parseKeyValues parses files made of "Key: value" lines, such as
+// /proc/<pid>/status and /proc/<pid>/io
+func parseKeyValues(data []byte) map[string]string {
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		key, value, found := strings.Cut(scanner.Text(), ":")
+		if found {
+			values[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+	return values
+}
+
+// statusField returns a numeric field of /proc/<pid>/status, such as the
+// first of the ids of "Uid:" or the kilobytes of "VmRSS:"
+func statusField(status map[string]string, key string) (int64, bool) {
+	fields := strings.Fields(status[key])
+	if len(fields) == 0 {
+		return 0, false
+	}
+	value, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	if len(fields) > 1 && fields[1] == "kB" {
+		value *= 1024
+	}
+	return value, true
+}
+
+// getBootTime returns the boot time in seconds since Epoch from /proc/stat
+func getBootTime() (int64, error) {
+	data, err := os.ReadFile(hostfs.Proc("stat"))
+	if err != nil {
+		return 0, err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if value, found := strings.CutPrefix(line, "btime "); found {
+			return strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		}
+	}
+	return 0, fmt.Errorf("btime not found in %s", hostfs.Proc("stat"))
+}
+
+// listProcessIDs returns the pids constrained by the query, or the pids of
+// all the processes of the proc filesystem
+func listProcessIDs(ctx *sqlctx.Context) ([]int64, error) {
+	var pids []int64
+	for _, constant := range ctx.GetConstants("pid") {
+		pid, err := strconv.ParseInt(constant, 10, 64)
+		if err != nil {
+			// Not a plain pid, e.g. a LIKE pattern, so every process is listed
+			pids = nil
+			break
+		}
+		pids = append(pids, pid)
+	}
+	if len(pids) > 0 {
+		return pids, nil
+	}
+
+	entries, err := os.ReadDir(hostfs.ProcRoot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", hostfs.ProcRoot, err)
+	}
+	for _, entry := range entries {
+		if pid, err := strconv.ParseInt(entry.Name(), 10, 64); err == nil && entry.IsDir() {
+			pids = append(pids, pid)
+		}
+	}
+	return pids, nil
+}
+
+// getProcessDetails fills the columns of a process from its files under
+// /proc/<pid>. Files that cannot be read, such as the links of processes
+// of other users, leave their columns to the default values.
+func getProcessDetails(ctx *sqlctx.Context, procInfo *result.Result, pid int64, bootTime int64) error {
+	dir := hostfs.Proc(strconv.FormatInt(pid, 10))
+	data, err := os.ReadFile(dir + "/stat")
+	if err != nil {
+		return err
+	}
+	stat, err := parseProcStat(string(data))
+	if err != nil {
+		return err
+	}
+
+	procInfo.Set("pid", pid)
+	procInfo.Set("name", stat.name)
+	procInfo.Set("state", stat.state)
+	procInfo.Set("parent", stat.parent)
+	procInfo.Set("pgroup", stat.pgroup)
+	procInfo.Set("nice", stat.nice)
+	procInfo.Set("threads", stat.threads)
+	procInfo.Set("user_time", stat.userTime*1000/clockTicks)
+	procInfo.Set("system_time", stat.sysTime*1000/clockTicks)
+	// Linux has no counter of unpageable memory
+	procInfo.Set("wired_size", int64(0))
+
+	if bootTime > 0 {
+		startTime := bootTime + stat.startTime/clockTicks
+		procInfo.Set("start_time", startTime)
+		procInfo.Set("elapsed_time", time.Now().Unix()-startTime)
+	}
+
+	if ctx.IsAnyOfColumnsUsed([]string{"path", "on_disk"}) {
+		if path, err := os.Readlink(dir + "/exe"); err == nil {
+			// The link of a binary deleted since it was executed ends with " (deleted)"
+			path, deleted := strings.CutSuffix(path, " (deleted)")
+			procInfo.Set("path", path)
+			if _, err := os.Stat(path); err == nil && !deleted {
+				procInfo.Set("on_disk", int32(1))
+			} else {
+				procInfo.Set("on_disk", int32(0))
+			}
+		}
+	}
+
+	if ctx.IsColumnUsed("cmdline") {
+		if data, err := os.ReadFile(dir + "/cmdline"); err == nil {
+			args := strings.Split(strings.TrimRight(string(data), "\x00"), "\x00")
+			procInfo.Set("cmdline", strings.Join(args, " "))
+		}
+	}
+
+	if ctx.IsColumnUsed("cwd") {
+		if cwd, err := os.Readlink(dir + "/cwd"); err == nil {
+			procInfo.Set("cwd", cwd)
+		}
+	}
+
+	if ctx.IsColumnUsed("root") {
+		if root, err := os.Readlink(dir + "/root"); err == nil {
+			procInfo.Set("root", root)
+		}
+	}
+
+	if ctx.IsAnyOfColumnsUsed([]string{"uid", "gid", "resident_size", "total_size"}) {
+		if data, err := os.ReadFile(dir + "/status"); err == nil {
+			status := parseKeyValues(data)
+			if uid, ok := statusField(status, "Uid"); ok {
+				procInfo.Set("uid", uid)
+			}
+			if gid, ok := statusField(status, "Gid"); ok {
+				procInfo.Set("gid", gid)
+			}
+			// Kernel threads have no memory fields
+			if rss, ok := statusField(status, "VmRSS"); ok {
+				procInfo.Set("resident_size", rss)
+			}
+			if size, ok := statusField(status, "VmSize"); ok {
+				procInfo.Set("total_size", size)
+			}
+		}
+	}
+
+	if ctx.IsAnyOfColumnsUsed([]string{"disk_bytes_read", "disk_bytes_written"}) {
+		// Only readable by the owner of the process
+		if data, err := os.ReadFile(dir + "/io"); err == nil {
+			counters := parseKeyValues(data)
+			if read, err := strconv.ParseInt(counters["read_bytes"], 10, 64); err == nil {
+				procInfo.Set("disk_bytes_read", read)
+			}
+			if written, err := strconv.ParseInt(counters["write_bytes"], 10, 64); err == nil {
+				procInfo.Set("disk_bytes_written", written)
+			}
+		}
+	}
+
+	if ctx.IsColumnUsed("handle_count") {
+		// The open file descriptors are the closest to the handles of Windows
+		if fds, err := os.ReadDir(dir + "/fd"); err == nil {
+			procInfo.Set("handle_count", int64(len(fds)))
+		}
+	}
+
+	return nil
+}
+
+// GenProcesses returns information about all processes, or about the
+// processes whose pid is constrained by the query
+func GenProcesses(ctx *sqlctx.Context) (*result.Results, error) {
+	pids, err := listProcessIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var bootTime int64
+	if ctx.IsAnyOfColumnsUsed([]string{"start_time", "elapsed_time"}) {
+		bootTime, _ = getBootTime()
+	}
+
+	procInfos := result.NewQueryResult()
+	for _, pid := range pids {
+		procInfo := result.NewResult(ctx, Schema)
+		// Processes may exit while they are listed, so they are skipped
+		if err := getProcessDetails(ctx, procInfo, pid, bootTime); err != nil {
+			continue
+		}
+		procInfos.AppendResult(*procInfo)
+	}
+
+	return procInfos, nil
+}
@@ -0,0 +1,157 @@
+//go:build linux
+
+package processes
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+	"github.com/scrymastic/goosquery/tables/internal/hostfstest"
+)
+
+// writeProcFixture creates a proc filesystem with the init process and a
+// shell whose binary was deleted, and makes the table read it
+func writeProcFixture(t *testing.T) {
+	t.Helper()
+	root := t.TempDir()
+	files := map[string]string{
+		"stat":       "cpu  1 2 3 4\nbtime 1700000000\nprocesses 42\n",
+		"1/stat":     "1 (systemd) S 0 1 1 0 -1 4194560 1 2 3 4 250 150 0 0 20 0 1 0 12 22900736 3000 18446744073709551615\n",
+		"1/status":   "Name:\tsystemd\nState:\tS (sleeping)\nUid:\t0\t0\t0\t0\nGid:\t0\t0\t0\t0\nVmSize:\t   22364 kB\nVmRSS:\t   12000 kB\nThreads:\t1\n",
+		"1/cmdline":  "/sbin/init\x00splash\x00",
+		"1/io":       "rchar: 100\nwchar: 200\nread_bytes: 4096\nwrite_bytes: 8192\n",
+		"42/stat":    "42 (my (odd) sh) R 1 42 42 0 -1 0 0 0 0 0 10 20 0 0 20 5 3 0 500 1000 10 0\n",
+		"42/status":  "Name:\tmy (odd) sh\nUid:\t1000\t1000\t1000\t1000\nGid:\t1000\t1000\t1000\t1000\n",
+		"42/cmdline": "sh\x00-c\x00sleep 10\x00",
+		"self":       "",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	binary := filepath.Join(root, "init")
+	if err := os.WriteFile(binary, nil, 0755); err != nil {
+		t.Fatal(err)
+	}
+	links := map[string]string{
+		"1/exe":  binary,
+		"1/cwd":  "/",
+		"42/exe": "/usr/bin/sh (deleted)",
+		"42/cwd": "/home/user",
+	}
+	for name, target := range links {
+		if err := os.Symlink(target, filepath.Join(root, name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	hostfstest.SetProcRoot(t, root)
+}
+
+func TestGenProcessesFromProc(t *testing.T) {
+	writeProcFixture(t)
+	ctx := sqlctx.NewContext()
+	ctx.SetColumns([]string{"*"})
+
+	processes, err := GenProcesses(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get processes: %v", err)
+	}
+	if processes.Size() != 2 {
+		t.Fatalf("Expected 2 processes, got %d", processes.Size())
+	}
+
+	expected := map[string]interface{}{
+		"pid":                int64(1),
+		"name":               "systemd",
+		"cmdline":            "/sbin/init splash",
+		"cwd":                "/",
+		"state":              "S",
+		"parent":             int64(0),
+		"pgroup":             int64(1),
+		"uid":                int64(0),
+		"on_disk":            int32(1),
+		"resident_size":      int64(12000 * 1024),
+		"total_size":         int64(22364 * 1024),
+		"user_time":          int64(2500),
+		"system_time":        int64(1500),
+		"disk_bytes_read":    int64(4096),
+		"disk_bytes_written": int64(8192),
+		"threads":            int32(1),
+		"nice":               int32(0),
+		"start_time":         int64(1700000000),
+	}
+	init := (*processes)[0]
+	for column, value := range expected {
+		if init.Get(column) != value {
+			t.Errorf("Expected %s = %v for pid 1, got %v", column, value, init.Get(column))
+		}
+	}
+
+	shell := (*processes)[1]
+	expected = map[string]interface{}{
+		"pid":        int64(42),
+		"name":       "my (odd) sh",
+		"path":       "/usr/bin/sh",
+		"on_disk":    int32(0),
+		"cmdline":    "sh -c sleep 10",
+		"uid":        int64(1000),
+		"nice":       int32(5),
+		"threads":    int32(3),
+		"start_time": int64(1700000005),
+		// Unreadable counters keep their default value
+		"disk_bytes_read": int64(-1),
+	}
+	for column, value := range expected {
+		if shell.Get(column) != value {
+			t.Errorf("Expected %s = %v for pid 42, got %v", column, value, shell.Get(column))
+		}
+	}
+}
+
+func TestGenProcessesPidConstraint(t *testing.T) {
+	writeProcFixture(t)
+	ctx := sqlctx.NewContext()
+	ctx.SetColumns([]string{"pid", "name"})
+	ctx.AddConstant("pid", "42")
+	ctx.AddConstant("pid", "7")
+
+	processes, err := GenProcesses(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get processes: %v", err)
+	}
+	// The missing pid 7 is skipped
+	if processes.Size() != 1 || (*processes)[0].Get("name") != "my (odd) sh" {
+		t.Fatalf("Expected only pid 42, got %v", processes)
+	}
+}
+
+func TestGenProcessesLive(t *testing.T) {
+	ctx := sqlctx.NewContext()
+	ctx.SetColumns([]string{"pid", "path"})
+	ctx.AddConstant("pid", "self")
+
+	processes, err := GenProcesses(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get processes: %v", err)
+	}
+	if processes.Size() == 0 {
+		t.Fatal("Expected the running processes to be listed")
+	}
+
+	self := int64(os.Getpid())
+	for _, process := range *processes {
+		if process.Get("pid") == self {
+			return
+		}
+	}
+	t.Errorf("Expected the test process %d to be listed", self)
+}
@@ -0,0 +1,15 @@
+//go:build !linux && !windows
+
+package processes
+
+import (
+	"fmt"
+
+	"github.com/scrymastic/goosquery/sql/result"
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+)
+
+// GenProcesses fails, as processes are only listed on Linux and Windows
+func GenProcesses(ctx *sqlctx.Context) (*result.Results, error) {
+	return nil, fmt.Errorf("processes are not supported on this platform")
+}
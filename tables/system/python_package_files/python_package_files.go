@@ -0,0 +1,57 @@
+package python_package_files
+
+import (
+	"fmt"
+
+	"github.com/scrymastic/goosquery/sql/result"
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+	"github.com/scrymastic/goosquery/tables/system/python_packages"
+)
+
+// genSiteFiles returns the installed files of the packages of a
+// site-packages directory
+func genSiteFiles(ctx *sqlctx.Context, siteDir string) []result.Result {
+	metadataDirs, err := python_packages.ListDistributions(siteDir)
+	if err != nil {
+		return nil
+	}
+
+	var files []result.Result
+	for _, metadataDir := range metadataDirs {
+		installed, err := python_packages.ReadInstalledFiles(metadataDir)
+		if err != nil {
+			continue // Packages installed without a file list
+		}
+		name := python_packages.PackageName(metadataDir)
+		for _, file := range installed {
+			row := result.NewResult(ctx, Schema)
+			row.Set("name", name)
+			row.Set("path", file.Path)
+			row.Set("hash", file.Hash)
+			row.Set("size", file.Size)
+			row.Set("directory", siteDir)
+			files = append(files, *row)
+		}
+	}
+	return files
+}
+
+// GenPythonPackageFiles returns the installed files of the Python packages
+// found by python_packages, or of the directories constrained by the query
+func GenPythonPackageFiles(ctx *sqlctx.Context) (*result.Results, error) {
+	siteDirs := ctx.GetConstants("directory")
+	if len(siteDirs) == 0 {
+		var err error
+		if siteDirs, err = python_packages.SitePackagesDirs(); err != nil {
+			return nil, fmt.Errorf("failed to find site-packages directories: %w", err)
+		}
+	}
+
+	results := result.NewQueryResult()
+	for _, siteDir := range siteDirs {
+		for _, file := range genSiteFiles(ctx, siteDir) {
+			results.AppendResult(file)
+		}
+	}
+	return results, nil
+}
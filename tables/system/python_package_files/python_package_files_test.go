@@ -0,0 +1,47 @@
+package python_package_files
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+)
+
+func TestGenPythonPackageFiles(t *testing.T) {
+	siteDir := t.TempDir()
+	files := map[string]string{
+		"requests-2.31.0.dist-info/METADATA": "Metadata-Version: 2.1\nName: requests\nVersion: 2.31.0\n",
+		"requests-2.31.0.dist-info/RECORD":   "requests/__init__.py,sha256=abc,4924\nrequests-2.31.0.dist-info/RECORD,,\n",
+		"six-1.16.0.dist-info/METADATA":      "Name: six\nVersion: 1.16.0\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(siteDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx := sqlctx.NewContext()
+	ctx.SetColumns([]string{"*"})
+	ctx.AddConstant("directory", siteDir)
+	results, err := GenPythonPackageFiles(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get python package files: %v", err)
+	}
+	if results.Size() != 2 {
+		t.Fatalf("Expected the 2 files of requests, got %d", results.Size())
+	}
+
+	init := (*results)[0]
+	if init.Get("name") != "requests" || init.Get("path") != filepath.Join(siteDir, "requests", "__init__.py") ||
+		init.Get("hash") != "sha256=abc" || init.Get("size") != int64(4924) || init.Get("directory") != siteDir {
+		t.Errorf("Unexpected file: %v", init.ToMap())
+	}
+	if record := (*results)[1]; record.Get("hash") != "" || record.Get("size") != int64(-1) {
+		t.Errorf("Unexpected file: %v", record.ToMap())
+	}
+}
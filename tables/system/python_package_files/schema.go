@@ -0,0 +1,15 @@
+package python_package_files
+
+import (
+	"github.com/scrymastic/goosquery/sql/result"
+)
+
+var TableName = "python_package_files"
+var Description = "The files installed by the Python packages, from their RECORD files."
+var Schema = result.Schema{
+	result.Column{Name: "name", Type: "TEXT", Description: "Name of the package owning the file"},
+	result.Column{Name: "path", Type: "TEXT", Description: "Path of the installed file"},
+	result.Column{Name: "hash", Type: "TEXT", Description: "Hash of the file recorded on installation, such as sha256=..."},
+	result.Column{Name: "size", Type: "BIGINT", Description: "Size of the file recorded on installation, in bytes, -1 when it is not recorded"},
+	result.Column{Name: "directory", Type: "TEXT", Description: "Directory where Python modules are located"},
+}
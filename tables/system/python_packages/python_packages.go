@@ -2,15 +2,39 @@ package python_packages
 
 import (
 	"bufio"
+	"encoding/csv"
+	"errors"
+	"io"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/scrymastic/goosquery/sql/result"
 	"github.com/scrymastic/goosquery/sql/sqlctx"
-	"golang.org/x/sys/windows/registry"
 )
 
+// InstalledFile is a file installed by a package, from its RECORD file. The
+// hash and the size are empty for the files of .egg-info packages, and for
+// the files generated on installation, such as the .pyc files.
+type InstalledFile struct {
+	Path string
+	Hash string
+	Size int64
+}
+
+// metadataFile returns the metadata file of a .dist-info or .egg-info
+// directory, or an empty string for the other directories
+func metadataFile(metadataDir string) string {
+	switch {
+	case strings.HasSuffix(metadataDir, ".dist-info"):
+		return filepath.Join(metadataDir, "METADATA")
+	case strings.HasSuffix(metadataDir, ".egg-info"):
+		return filepath.Join(metadataDir, "PKG-INFO")
+	}
+	return ""
+}
+
 // readPackageMetadata reads and parses package metadata from METADATA or PKG-INFO files
 func readPackageMetadata(path string, directory string, ctx *sqlctx.Context) (*result.Result, error) {
 	file, err := os.Open(path)
@@ -26,6 +50,10 @@ func readPackageMetadata(path string, directory string, ctx *sqlctx.Context) (*r
 	scanner := bufio.NewScanner(file)
 	for scanner.Scan() {
 		line := scanner.Text()
+		// The headers end before the long description
+		if line == "" {
+			break
+		}
 		parts := strings.SplitN(line, ":", 2)
 		if len(parts) != 2 {
 			continue
@@ -51,95 +79,155 @@ func readPackageMetadata(path string, directory string, ctx *sqlctx.Context) (*r
 	return pkg, scanner.Err()
 }
 
-// scanSitePackages scans a directory for Python packages
-func scanSitePackages(siteDir string, ctx *sqlctx.Context) (*result.Results, error) {
-	packages := result.NewQueryResult()
+// readInstaller returns the tool that installed a .dist-info package
+func readInstaller(metadataDir string) string {
+	data, err := os.ReadFile(filepath.Join(metadataDir, "INSTALLER"))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
 
+// ListDistributions returns the .dist-info and .egg-info directories of a
+// site-packages directory
+func ListDistributions(siteDir string) ([]string, error) {
 	entries, err := os.ReadDir(siteDir)
 	if err != nil {
 		return nil, err
 	}
 
+	var metadataDirs []string
 	for _, entry := range entries {
 		if !entry.IsDir() {
 			continue
 		}
+		metadataDir := filepath.Join(siteDir, entry.Name())
+		if metadataFile(metadataDir) != "" {
+			metadataDirs = append(metadataDirs, metadataDir)
+		}
+	}
+	return metadataDirs, nil
+}
+
+// PackageName returns the name of a package from the metadata of its
+// .dist-info or .egg-info directory
+func PackageName(metadataDir string) string {
+	ctx := sqlctx.NewContext()
+	ctx.SetColumns([]string{"name"})
+	pkg, err := readPackageMetadata(metadataFile(metadataDir), "", ctx)
+	if err != nil {
+		return ""
+	}
+	name, _ := pkg.Get("name").(string)
+	return name
+}
 
-		dirName := entry.Name()
-		fullPath := filepath.Join(siteDir, dirName)
+// ReadInstalledFiles returns the files of a package: the RECORD file of a
+// .dist-info directory, whose lines are like
+// requests/api.py,sha256=...,6449
+// with the paths relative to the site-packages directory, or the
+// installed-files.txt of an .egg-info directory, relative to the directory
+func ReadInstalledFiles(metadataDir string) ([]InstalledFile, error) {
+	if strings.HasSuffix(metadataDir, ".egg-info") {
+		return readInstalledFilesTxt(metadataDir)
+	}
 
-		var metadataPath string
-		if strings.HasSuffix(dirName, ".dist-info") {
-			metadataPath = filepath.Join(fullPath, "METADATA")
-		} else if strings.HasSuffix(dirName, ".egg-info") {
-			metadataPath = filepath.Join(fullPath, "PKG-INFO")
-		} else {
-			continue
-		}
+	file, err := os.Open(filepath.Join(metadataDir, "RECORD"))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
 
-		pkg, err := readPackageMetadata(metadataPath, siteDir, ctx)
+	var files []InstalledFile
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
 		if err != nil {
-			continue // Skip packages with unreadable metadata
+			return nil, err
 		}
+		if len(record) == 0 || record[0] == "" {
+			continue
+		}
+		installed := InstalledFile{Path: resolveInstalledPath(filepath.Dir(metadataDir), record[0]), Size: -1}
+		if len(record) > 1 {
+			installed.Hash = record[1]
+		}
+		if len(record) > 2 {
+			if size, err := strconv.ParseInt(record[2], 10, 64); err == nil {
+				installed.Size = size
+			}
+		}
+		files = append(files, installed)
+	}
+	return files, nil
+}
 
-		packages.AppendResult(*pkg)
+// readInstalledFilesTxt returns the files of installed-files.txt, written by
+// pip for the .egg-info packages
+func readInstalledFilesTxt(metadataDir string) ([]InstalledFile, error) {
+	file, err := os.Open(filepath.Join(metadataDir, "installed-files.txt"))
+	if err != nil {
+		return nil, err
 	}
+	defer file.Close()
 
-	return packages, nil
+	var files []InstalledFile
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			files = append(files, InstalledFile{Path: resolveInstalledPath(metadataDir, line), Size: -1})
+		}
+	}
+	return files, scanner.Err()
 }
 
-// getPythonInstallPaths gets Python installation paths from Windows registry
-func getPythonInstallPaths() ([]string, error) {
-	var paths []string
-
-	// Check HKEY_LOCAL_MACHINE
-	k, err := registry.OpenKey(registry.LOCAL_MACHINE, `SOFTWARE\Python\PythonCore`, registry.READ)
-	if err == nil {
-		defer k.Close()
-		versions, err := k.ReadSubKeyNames(-1)
-		if err == nil {
-			for _, ver := range versions {
-				installPath := `SOFTWARE\Python\PythonCore\` + ver + `\InstallPath`
-				k2, err := registry.OpenKey(registry.LOCAL_MACHINE, installPath, registry.READ)
-				if err == nil {
-					if path, _, err := k2.GetStringValue(""); err == nil {
-						sitePkgs := filepath.Join(path, "Lib", "site-packages")
-						paths = append(paths, sitePkgs)
-					}
-					k2.Close()
-				}
-			}
-		}
+// resolveInstalledPath returns the path of an installed file, given relative
+// to a directory, such as ../../../bin/pip for the scripts
+func resolveInstalledPath(dir string, path string) string {
+	path = filepath.FromSlash(path)
+	if filepath.IsAbs(path) {
+		return filepath.Clean(path)
 	}
+	return filepath.Join(dir, path)
+}
 
-	// Check HKEY_CURRENT_USER (similar pattern for all users would use HKEY_USERS)
-	k, err = registry.OpenKey(registry.CURRENT_USER, `SOFTWARE\Python\PythonCore`, registry.READ)
-	if err == nil {
-		defer k.Close()
-		versions, err := k.ReadSubKeyNames(-1)
-		if err == nil {
-			for _, ver := range versions {
-				installPath := `SOFTWARE\Python\PythonCore\` + ver + `\InstallPath`
-				k2, err := registry.OpenKey(registry.CURRENT_USER, installPath, registry.READ)
-				if err == nil {
-					if path, _, err := k2.GetStringValue(""); err == nil {
-						sitePkgs := filepath.Join(path, "Lib", "site-packages")
-						paths = append(paths, sitePkgs)
-					}
-					k2.Close()
-				}
-			}
+// scanSitePackages scans a directory for Python packages
+func scanSitePackages(siteDir string, ctx *sqlctx.Context) (*result.Results, error) {
+	packages := result.NewQueryResult()
+
+	metadataDirs, err := ListDistributions(siteDir)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, metadataDir := range metadataDirs {
+		pkg, err := readPackageMetadata(metadataFile(metadataDir), siteDir, ctx)
+		if err != nil {
+			continue // Skip packages with unreadable metadata
 		}
+		pkg.Set("installer", readInstaller(metadataDir))
+
+		packages.AppendResult(*pkg)
 	}
 
-	return paths, nil
+	return packages, nil
+}
+
+// SitePackagesDirs returns the site-packages directories of the Python
+// installations and environments found on the system
+func SitePackagesDirs() ([]string, error) {
+	return getPythonInstallPaths()
 }
 
 // GenPythonPackages returns all Python packages installed on the system
 func GenPythonPackages(ctx *sqlctx.Context) (*result.Results, error) {
 	allPackages := result.NewQueryResult()
 
-	// Get Python installation paths from registry
+	// Get the site-packages directories of the Python installations
 	paths, err := getPythonInstallPaths()
 	if err != nil {
 		return nil, err
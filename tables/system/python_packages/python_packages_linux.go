@@ -0,0 +1,162 @@
+//go:build linux
+
+package python_packages
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/scrymastic/goosquery/tables/internal/accounts"
+	"github.com/scrymastic/goosquery/tables/internal/hostfs"
+)
+
+// venvSearchDepth bounds the depth of the directories searched for
+// virtualenvs, such as ~/projects/app/.venv
+const venvSearchDepth = 3
+
+// systemSitePatterns are the site-packages of the Python interpreters of the
+// system, with the dist-packages directories of Debian
+var systemSitePatterns = []string{
+	"/usr/lib/python3/dist-packages",
+	"/usr/lib/python*/dist-packages",
+	"/usr/lib/python*/site-packages",
+	"/usr/lib64/python*/site-packages",
+	"/usr/local/lib/python*/dist-packages",
+	"/usr/local/lib/python*/site-packages",
+	"/usr/local/lib64/python*/site-packages",
+}
+
+// condaDirs are the usual installation directories of Anaconda and its
+// variants, in the home directories of the users or in /opt
+var condaDirs = []string{"anaconda3", "miniconda3", "miniforge3", "mambaforge", "conda"}
+
+// homeSitePatterns are the site-packages of the users: user installs, pyenv
+// versions and their virtualenvs, conda environments, and the virtualenvs of
+// virtualenvwrapper and pipenv
+var homeSitePatterns = []string{
+	".local/lib/python*/site-packages",
+	".pyenv/versions/*/lib/python*/site-packages",
+	".pyenv/versions/*/envs/*/lib/python*/site-packages",
+	".conda/envs/*/lib/python*/site-packages",
+	".virtualenvs/*/lib/python*/site-packages",
+	".local/share/virtualenvs/*/lib/python*/site-packages",
+}
+
+// minUserUID is the first uid given to the accounts of people, UID_MIN of
+// login.defs, the lower ones being system accounts
+const minUserUID = 1000
+
+// noLoginShells are the shells of the accounts which cannot log in
+var noLoginShells = map[string]bool{"nologin": true, "false": true, "sync": true, "shutdown": true, "halt": true}
+
+// isLoginUser reports whether an account is root or belongs to a person who
+// can log in, whose home directory may hold virtualenvs
+func isLoginUser(user accounts.User) bool {
+	if user.UID != 0 && user.UID < minUserUID {
+		return false
+	}
+	return !noLoginShells[filepath.Base(user.Shell)]
+}
+
+// venvSearchPath returns the directories searched for virtualenvs, the
+// directories of GOOSQUERY_PYTHON_PATH separated by colons, or /opt, /srv
+// and the home directories of the users who can log in
+func venvSearchPath(users []accounts.User) []string {
+	if value := os.Getenv("GOOSQUERY_PYTHON_PATH"); value != "" {
+		return filepath.SplitList(value)
+	}
+	dirs := []string{"/opt", "/srv"}
+	for _, user := range users {
+		if isLoginUser(user) && !slices.Contains(dirs, user.Directory) {
+			dirs = append(dirs, user.Directory)
+		}
+	}
+	return dirs
+}
+
+// findVirtualenvs returns the virtualenvs under a directory, identified by
+// their pyvenv.cfg files. The virtualenvs are not searched for nested ones.
+func findVirtualenvs(dir string, depth int) []string {
+	if _, err := os.Stat(filepath.Join(dir, "pyvenv.cfg")); err == nil {
+		return []string{dir}
+	}
+	if depth == 0 {
+		return nil
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var venvs []string
+	for _, entry := range entries {
+		// Symbolic links are not followed, so that loops are not walked
+		if entry.IsDir() && entry.Name() != "node_modules" && entry.Name() != ".git" {
+			venvs = append(venvs, findVirtualenvs(filepath.Join(dir, entry.Name()), depth-1)...)
+		}
+	}
+	return venvs
+}
+
+// getPythonInstallPaths returns the site-packages directories of the system
+// Pythons, of the users, of pyenv and conda, and of the virtualenvs
+func getPythonInstallPaths() ([]string, error) {
+	patterns := append([]string{}, systemSitePatterns...)
+	for _, dir := range condaDirs {
+		patterns = append(patterns,
+			filepath.Join("/opt", dir, "lib/python*/site-packages"),
+			filepath.Join("/opt", dir, "envs/*/lib/python*/site-packages"))
+	}
+
+	users, _ := accounts.ReadUsers()
+	homes := map[string]bool{}
+	for _, user := range users {
+		// Skip the shared homes of the system accounts, such as /
+		if homes[user.Directory] || user.Directory == "/" || user.Directory == "" {
+			continue
+		}
+		homes[user.Directory] = true
+		for _, pattern := range homeSitePatterns {
+			patterns = append(patterns, filepath.Join(user.Directory, pattern))
+		}
+		for _, dir := range condaDirs {
+			patterns = append(patterns,
+				filepath.Join(user.Directory, dir, "lib/python*/site-packages"),
+				filepath.Join(user.Directory, dir, "envs/*/lib/python*/site-packages"))
+		}
+	}
+
+	var paths []string
+	seen := map[string]bool{}
+	add := func(path string) {
+		// Directories reached through several links, such as lib64
+		key := path
+		if resolved, err := filepath.EvalSymlinks(path); err == nil {
+			key = resolved
+		}
+		if info, err := os.Stat(path); err == nil && info.IsDir() && !seen[key] {
+			seen[key] = true
+			paths = append(paths, path)
+		}
+	}
+	for _, pattern := range patterns {
+		matches, _ := filepath.Glob(hostfs.Path(pattern))
+		for _, match := range matches {
+			add(match)
+		}
+	}
+
+	for _, dir := range venvSearchPath(users) {
+		if dir == "/" || strings.TrimSpace(dir) == "" {
+			continue
+		}
+		for _, venv := range findVirtualenvs(hostfs.Path(dir), venvSearchDepth) {
+			matches, _ := filepath.Glob(filepath.Join(venv, "lib", "python*", "site-packages"))
+			for _, match := range matches {
+				add(match)
+			}
+		}
+	}
+	return paths, nil
+}
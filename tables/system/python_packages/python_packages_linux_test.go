@@ -0,0 +1,95 @@
+//go:build linux
+
+package python_packages
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/scrymastic/goosquery/tables/internal/accounts"
+	"github.com/scrymastic/goosquery/tables/internal/hostfstest"
+)
+
+func TestGetPythonInstallPaths(t *testing.T) {
+	root := t.TempDir()
+	dirs := []string{
+		"usr/lib/python3/dist-packages",
+		"usr/local/lib/python3.11/dist-packages",
+		"home/alice/.local/lib/python3.11/site-packages",
+		"home/alice/.pyenv/versions/3.12.1/lib/python3.12/site-packages",
+		"home/alice/miniconda3/envs/ml/lib/python3.10/site-packages",
+		"home/alice/code/app/.venv/lib/python3.11/site-packages",
+		"srv/api/venv/lib/python3.11/site-packages",
+		"data/envs/etl/lib/python3.9/site-packages",
+	}
+	for _, dir := range dirs {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	files := map[string]string{
+		"etc/passwd":                           "root:x:0:0:root:/root:/bin/bash\nalice:x:1000:1000::/home/alice:/bin/bash\n",
+		"home/alice/code/app/.venv/pyvenv.cfg": "home = /usr/bin\n",
+		"srv/api/venv/pyvenv.cfg":              "home = /usr/bin\n",
+		"data/envs/etl/pyvenv.cfg":             "home = /usr/bin\n",
+	}
+	for name, content := range files {
+		if err := os.MkdirAll(filepath.Join(root, filepath.Dir(name)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(root, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	hostfstest.SetRoot(t, root)
+
+	paths, err := getPythonInstallPaths()
+	if err != nil {
+		t.Fatalf("Failed to get Python paths: %v", err)
+	}
+	found := map[string]bool{}
+	for _, path := range paths {
+		found[path] = true
+	}
+	for _, dir := range dirs[:7] {
+		if !found[filepath.Join(root, dir)] {
+			t.Errorf("Expected %s to be found, got %v", dir, paths)
+		}
+	}
+	if found[filepath.Join(root, dirs[7])] {
+		t.Errorf("Expected the virtualenvs outside the search path to be skipped")
+	}
+
+	// The search path replaces /opt, /srv and the home directories
+	t.Setenv("GOOSQUERY_PYTHON_PATH", "/data")
+	paths, err = getPythonInstallPaths()
+	if err != nil {
+		t.Fatalf("Failed to get Python paths: %v", err)
+	}
+	found = map[string]bool{}
+	for _, path := range paths {
+		found[path] = true
+	}
+	if !found[filepath.Join(root, dirs[7])] || found[filepath.Join(root, dirs[6])] {
+		t.Errorf("Expected only the virtualenvs of /data, got %v", paths)
+	}
+}
+
+func TestVenvSearchPath(t *testing.T) {
+	t.Setenv("GOOSQUERY_PYTHON_PATH", "")
+	users := []accounts.User{
+		{Name: "root", UID: 0, Directory: "/root", Shell: "/bin/bash"},
+		{Name: "daemon", UID: 1, Directory: "/usr/sbin", Shell: "/usr/sbin/nologin"},
+		{Name: "postgres", UID: 114, Directory: "/var/lib/postgresql", Shell: "/bin/bash"},
+		{Name: "alice", UID: 1000, Directory: "/home/alice", Shell: "/bin/zsh"},
+		{Name: "alice2", UID: 1001, Directory: "/home/alice", Shell: "/bin/bash"},
+		{Name: "backup", UID: 1002, Directory: "/home/backup", Shell: "/bin/false"},
+		{Name: "nobody", UID: 65534, Directory: "/nonexistent", Shell: "/usr/sbin/nologin"},
+	}
+	expected := []string{"/opt", "/srv", "/root", "/home/alice"}
+	if dirs := venvSearchPath(users); !slices.Equal(dirs, expected) {
+		t.Errorf("Expected %v, got %v", expected, dirs)
+	}
+}
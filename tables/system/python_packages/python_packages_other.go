@@ -0,0 +1,9 @@
+//go:build !linux && !windows
+
+package python_packages
+
+// getPythonInstallPaths returns no directories, as Python installations are
+// only found on Linux and Windows so far
+func getPythonInstallPaths() ([]string, error) {
+	return nil, nil
+}
@@ -3,6 +3,9 @@ package python_packages
 import (
 	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
 
 	"github.com/scrymastic/goosquery/sql/sqlctx"
@@ -19,3 +22,87 @@ func TestPythonPackages(t *testing.T) {
 	}
 	fmt.Printf("Python Packages:\n%s\n", string(jsonData))
 }
+
+func TestScanSitePackages(t *testing.T) {
+	siteDir := t.TempDir()
+	files := map[string]string{
+		"requests-2.31.0.dist-info/METADATA":  "Metadata-Version: 2.1\nName: requests\nVersion: 2.31.0\nSummary: Python HTTP for Humans.\nAuthor: Kenneth Reitz\nLicense: Apache 2.0\n\nName: not a header\n",
+		"requests-2.31.0.dist-info/INSTALLER": "pip\n",
+		"six-1.16.0.egg-info/PKG-INFO":        "Name: six\nVersion: 1.16.0\n",
+		"requests/__init__.py":                "",
+	}
+	for name, content := range files {
+		path := filepath.Join(siteDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx := sqlctx.NewContext()
+	ctx.SetColumns([]string{"*"})
+	packages, err := scanSitePackages(siteDir, ctx)
+	if err != nil {
+		t.Fatalf("Failed to scan site-packages: %v", err)
+	}
+	if packages.Size() != 2 {
+		t.Fatalf("Expected 2 packages, got %d", packages.Size())
+	}
+
+	requests := (*packages)[0]
+	if requests.Get("name") != "requests" || requests.Get("version") != "2.31.0" || requests.Get("license") != "Apache 2.0" {
+		t.Errorf("Unexpected requests package: %v", requests.ToMap())
+	}
+	if requests.Get("installer") != "pip" {
+		t.Errorf("Expected installer pip, got %v", requests.Get("installer"))
+	}
+	if requests.Get("directory") != siteDir {
+		t.Errorf("Expected directory %s, got %v", siteDir, requests.Get("directory"))
+	}
+	if six := (*packages)[1]; six.Get("name") != "six" || six.Get("version") != "1.16.0" {
+		t.Errorf("Unexpected six package: %v", six.ToMap())
+	}
+}
+
+func TestReadInstalledFiles(t *testing.T) {
+	siteDir := t.TempDir()
+	record := "requests/__init__.py,sha256=abc,4924\n" +
+		"requests-2.31.0.dist-info/RECORD,,\n" +
+		"\"../../../bin/odd,name\",sha256=def,230\n"
+	files := map[string]string{
+		"requests-2.31.0.dist-info/RECORD":        record,
+		"six-1.16.0.egg-info/installed-files.txt": "../six.py\nPKG-INFO\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(siteDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	installed, err := ReadInstalledFiles(filepath.Join(siteDir, "requests-2.31.0.dist-info"))
+	if err != nil {
+		t.Fatalf("Failed to read RECORD: %v", err)
+	}
+	expected := []InstalledFile{
+		{Path: filepath.Join(siteDir, "requests", "__init__.py"), Hash: "sha256=abc", Size: 4924},
+		{Path: filepath.Join(siteDir, "requests-2.31.0.dist-info", "RECORD"), Size: -1},
+		{Path: filepath.Join(siteDir, "..", "..", "..", "bin", "odd,name"), Hash: "sha256=def", Size: 230},
+	}
+	if !reflect.DeepEqual(installed, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, installed)
+	}
+
+	installed, err = ReadInstalledFiles(filepath.Join(siteDir, "six-1.16.0.egg-info"))
+	if err != nil {
+		t.Fatalf("Failed to read installed-files.txt: %v", err)
+	}
+	if len(installed) != 2 || installed[0].Path != filepath.Join(siteDir, "six.py") {
+		t.Errorf("Unexpected egg files: %+v", installed)
+	}
+}
@@ -0,0 +1,56 @@
+//go:build windows
+
+package python_packages
+
+import (
+	"path/filepath"
+
+	"golang.org/x/sys/windows/registry"
+)
+
+// getPythonInstallPaths gets Python installation paths from Windows registry
+func getPythonInstallPaths() ([]string, error) {
+	var paths []string
+
+	// Check HKEY_LOCAL_MACHINE
+	k, err := registry.OpenKey(registry.LOCAL_MACHINE, `SOFTWARE\Python\PythonCore`, registry.READ)
+	if err == nil {
+		defer k.Close()
+		versions, err := k.ReadSubKeyNames(-1)
+		if err == nil {
+			for _, ver := range versions {
+				installPath := `SOFTWARE\Python\PythonCore\` + ver + `\InstallPath`
+				k2, err := registry.OpenKey(registry.LOCAL_MACHINE, installPath, registry.READ)
+				if err == nil {
+					if path, _, err := k2.GetStringValue(""); err == nil {
+						sitePkgs := filepath.Join(path, "Lib", "site-packages")
+						paths = append(paths, sitePkgs)
+					}
+					k2.Close()
+				}
+			}
+		}
+	}
+
+	// Check HKEY_CURRENT_USER (similar pattern for all users would use HKEY_USERS)
+	k, err = registry.OpenKey(registry.CURRENT_USER, `SOFTWARE\Python\PythonCore`, registry.READ)
+	if err == nil {
+		defer k.Close()
+		versions, err := k.ReadSubKeyNames(-1)
+		if err == nil {
+			for _, ver := range versions {
+				installPath := `SOFTWARE\Python\PythonCore\` + ver + `\InstallPath`
+				k2, err := registry.OpenKey(registry.CURRENT_USER, installPath, registry.READ)
+				if err == nil {
+					if path, _, err := k2.GetStringValue(""); err == nil {
+						sitePkgs := filepath.Join(path, "Lib", "site-packages")
+						paths = append(paths, sitePkgs)
+					}
+					k2.Close()
+				}
+			}
+		}
+	}
+
+	return paths, nil
+}
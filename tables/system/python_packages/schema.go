@@ -14,4 +14,5 @@ var Schema = result.Schema{
 	result.Column{Name: "license", Type: "TEXT", Description: "License under which package is launched"},
 	result.Column{Name: "path", Type: "TEXT", Description: "Path at which this module resides"},
 	result.Column{Name: "directory", Type: "TEXT", Description: "Directory where Python modules are located"},
+	result.Column{Name: "installer", Type: "TEXT", Description: "Tool that installed the package, such as pip, from the INSTALLER file"},
 }
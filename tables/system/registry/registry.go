@@ -1,3 +1,5 @@
+//go:build windows
+
 package registry
 
 import (
@@ -136,6 +138,9 @@ func getRegistryValues(rootKey registry.Key, keyPath string, searchKey string, c
 
 func GenRegistry(ctx *sqlctx.Context) (*result.Results, error) {
 	searchKey := ctx.GetConstants("search")
+	if len(searchKey) == 0 {
+		return nil, fmt.Errorf("search is not set")
+	}
 	results := result.NewQueryResult()
 	rootKey, keyPath, err := parseSearchKey(searchKey[0])
 	if err != nil {
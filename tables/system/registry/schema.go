@@ -7,7 +7,7 @@ import (
 var TableName = "registry"
 var Description = "All of the Windows registry hives."
 var Schema = result.Schema{
-	result.Column{Name: "search", Type: "TEXT", Description: "Name of the key to search for"},
+	result.Column{Name: "search", Type: "TEXT", Description: "Name of the key to search for", Options: result.Required},
 	result.Column{Name: "path", Type: "TEXT", Description: "Full path to the value"},
 	result.Column{Name: "name", Type: "TEXT", Description: "Name of the registry value entry"},
 	result.Column{Name: "type", Type: "TEXT", Description: "Type of the registry value"},
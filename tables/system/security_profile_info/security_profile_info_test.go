@@ -1,3 +1,5 @@
+//go:build windows
+
 package security_profile_info
 
 import (
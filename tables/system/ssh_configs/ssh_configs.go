@@ -11,14 +11,10 @@ import (
 	"github.com/scrymastic/goosquery/tables/system/users"
 )
 
-const (
-	WindowsSystemwideSSHConfig = "C:\\ProgramData\\ssh\\ssh_config"
-)
-
 func genSshConfig(ctx *sqlctx.Context, uid int64, configFilePath string) (*result.Results, error) {
 	sshConfigs := result.NewQueryResult()
 
-	file, err := os.Open(configFilePath)
+	file, err := os.Open(hostPath(configFilePath))
 	if err != nil {
 		return sshConfigs, err
 	}
@@ -61,7 +57,7 @@ func genSshConfigForUser(ctx *sqlctx.Context, uid int64, directory string) (*res
 	sshConfigFile := filepath.Join(directory, ".ssh", "config")
 
 	// Check if the file exists
-	if _, err := os.Stat(sshConfigFile); os.IsNotExist(err) {
+	if _, err := os.Stat(hostPath(sshConfigFile)); os.IsNotExist(err) {
 		return result.NewQueryResult(), nil
 	}
 
@@ -71,8 +67,10 @@ func genSshConfigForUser(ctx *sqlctx.Context, uid int64, directory string) (*res
 func GenSshConfigs(ctx *sqlctx.Context) (*result.Results, error) {
 	results := result.NewQueryResult()
 
-	// Get all users
-	users, err := users.GenUsers(ctx)
+	// Get all users, with the columns needed whatever the query selects
+	usersCtx := sqlctx.NewContext()
+	usersCtx.SetColumns([]string{"uid", "directory"})
+	users, err := users.GenUsers(usersCtx)
 	if err != nil {
 		return nil, err
 	}
@@ -90,8 +88,8 @@ func GenSshConfigs(ctx *sqlctx.Context) (*result.Results, error) {
 	}
 
 	// Check for system-wide SSH config
-	if _, err := os.Stat(WindowsSystemwideSSHConfig); err == nil {
-		configs, err := genSshConfig(ctx, 0, WindowsSystemwideSSHConfig)
+	if _, err := os.Stat(hostPath(systemSSHConfig)); err == nil {
+		configs, err := genSshConfig(ctx, 0, systemSSHConfig)
 		if err == nil {
 			results.AppendResults(*configs)
 		}
@@ -0,0 +1,56 @@
+//go:build linux
+
+package ssh_configs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+	"github.com/scrymastic/goosquery/tables/internal/hostfstest"
+)
+
+func TestGenSshConfigsFromRoot(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"etc/passwd": "root:x:0:0:root:/root:/bin/bash\n" +
+			"alice:x:1000:1000::/home/alice:/bin/sh\n",
+		"etc/ssh/ssh_config":     "Host *\n    SendEnv LANG\n",
+		"home/alice/.ssh/config": "# personal hosts\nHost work\n  HostName 192.0.2.5\n  User alice\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	hostfstest.SetRoot(t, root)
+
+	ctx := sqlctx.NewContext()
+	ctx.SetColumns([]string{"block", "option", "uid", "ssh_config_file"})
+	configs, err := GenSshConfigs(ctx)
+	if err != nil {
+		t.Fatalf("Error generating SSH configs: %v", err)
+	}
+
+	// The files are reported with their paths on the host
+	expected := []map[string]interface{}{
+		{"uid": int64(1000), "block": "Host work", "option": "HostName 192.0.2.5", "ssh_config_file": "/home/alice/.ssh/config"},
+		{"uid": int64(1000), "block": "Host work", "option": "User alice"},
+		{"uid": int64(0), "block": "Host *", "option": "SendEnv LANG", "ssh_config_file": "/etc/ssh/ssh_config"},
+	}
+	if configs.Size() != len(expected) {
+		t.Fatalf("Expected %d options, got %v", len(expected), configs)
+	}
+	for i, columns := range expected {
+		for column, value := range columns {
+			if (*configs)[i].Get(column) != value {
+				t.Errorf("Expected %s = %v for option %d, got %v", column, value, i, (*configs)[i].Get(column))
+			}
+		}
+	}
+}
@@ -0,0 +1,14 @@
+//go:build !windows
+
+package ssh_configs
+
+import "github.com/scrymastic/goosquery/tables/internal/hostfs"
+
+// systemSSHConfig is the path of the system-wide ssh_config
+const systemSSHConfig = "/etc/ssh/ssh_config"
+
+// hostPath returns the path of a file of the host under hostfs.Root, as
+// the home directories of /etc/passwd are relative to it
+func hostPath(path string) string {
+	return hostfs.Path(path)
+}
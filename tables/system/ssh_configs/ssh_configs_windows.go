@@ -0,0 +1,11 @@
+//go:build windows
+
+package ssh_configs
+
+// systemSSHConfig is the path of the system-wide ssh_config of OpenSSH for Windows
+const systemSSHConfig = `C:\ProgramData\ssh\ssh_config`
+
+// hostPath returns the path of a file of the host
+func hostPath(path string) string {
+	return path
+}
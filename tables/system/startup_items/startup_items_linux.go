@@ -0,0 +1,169 @@
+//go:build linux
+
+package startup_items
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/scrymastic/goosquery/sql/result"
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+	"github.com/scrymastic/goosquery/tables/internal/accounts"
+	"github.com/scrymastic/goosquery/tables/internal/hostfs"
+)
+
+// systemAutostartDir holds the XDG autostart entries of all the users, and
+// the ~/.config/autostart directories those of each user
+const systemAutostartDir = "/etc/xdg/autostart"
+
+// rcLocalPaths are the locations of rc.local, on Debian and on Red Hat
+var rcLocalPaths = []string{"/etc/rc.local", "/etc/rc.d/rc.local"}
+
+// readDesktopEntry returns the keys of the [Desktop Entry] group of a
+// .desktop file
+func readDesktopEntry(name string) (map[string]string, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	entry := map[string]string{}
+	group := ""
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			group = line[1 : len(line)-1]
+			continue
+		}
+		if group != "Desktop Entry" {
+			continue
+		}
+		if key, value, found := strings.Cut(line, "="); found {
+			entry[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+	return entry, scanner.Err()
+}
+
+// splitExec splits the Exec key of a desktop entry into its arguments,
+// removing the quotes and the field codes, such as %U, expanded on launch
+func splitExec(exec string) []string {
+	var args []string
+	var arg strings.Builder
+	inArg, quoted := false, false
+	for i := 0; i < len(exec); i++ {
+		c := exec[i]
+		switch {
+		case c == '"':
+			quoted, inArg = !quoted, true
+		case c == '\\' && quoted && i+1 < len(exec):
+			i++
+			arg.WriteByte(exec[i])
+		case (c == ' ' || c == '\t') && !quoted:
+			if inArg {
+				args = append(args, arg.String())
+				arg.Reset()
+				inArg = false
+			}
+		default:
+			arg.WriteByte(c)
+			inArg = true
+		}
+	}
+	if inArg {
+		args = append(args, arg.String())
+	}
+
+	kept := args[:0]
+	for _, arg := range args {
+		if len(arg) == 2 && arg[0] == '%' && arg[1] != '%' {
+			continue
+		}
+		kept = append(kept, strings.ReplaceAll(arg, "%%", "%"))
+	}
+	return kept
+}
+
+// genAutostartDir returns the startup items of the .desktop files of an
+// autostart directory
+func genAutostartDir(ctx *sqlctx.Context, dir string, username string) []result.Result {
+	matches, _ := filepath.Glob(hostfs.Path(dir, "*.desktop"))
+	var items []result.Result
+	for _, match := range matches {
+		entry, err := readDesktopEntry(match)
+		if err != nil {
+			continue
+		}
+		source := path.Join(dir, filepath.Base(match))
+		item := result.NewResult(ctx, Schema)
+		name := entry["Name"]
+		if name == "" {
+			name = strings.TrimSuffix(filepath.Base(match), ".desktop")
+		}
+		item.Set("name", name)
+		if args := splitExec(entry["Exec"]); len(args) > 0 {
+			item.Set("path", args[0])
+			item.Set("args", strings.Join(args[1:], " "))
+		}
+		item.Set("type", "Startup Item")
+		item.Set("source", source)
+		if entry["Hidden"] == "true" || entry["X-GNOME-Autostart-enabled"] == "false" {
+			item.Set("status", "disabled")
+		} else {
+			item.Set("status", "enabled")
+		}
+		item.Set("username", username)
+		items = append(items, *item)
+	}
+	return items
+}
+
+// genRcLocal returns rc.local as a startup item, enabled when it is
+// executable, as systemd only runs it then
+func genRcLocal(ctx *sqlctx.Context) []result.Result {
+	for _, name := range rcLocalPaths {
+		info, err := os.Stat(hostfs.Path(name))
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		item := result.NewResult(ctx, Schema)
+		item.Set("name", "rc.local")
+		item.Set("path", name)
+		item.Set("args", "")
+		item.Set("type", "Startup Item")
+		item.Set("source", name)
+		if info.Mode().Perm()&0111 != 0 {
+			item.Set("status", "enabled")
+		} else {
+			item.Set("status", "disabled")
+		}
+		item.Set("username", "root")
+		return []result.Result{*item}
+	}
+	return nil
+}
+
+// GenStartupItems returns the XDG autostart entries of the system and of the
+// users, and rc.local
+func GenStartupItems(ctx *sqlctx.Context) (*result.Results, error) {
+	results := result.NewQueryResult()
+	items := genAutostartDir(ctx, systemAutostartDir, "")
+	if users, err := accounts.ReadUsers(); err == nil {
+		for _, user := range users {
+			items = append(items, genAutostartDir(ctx, path.Join(user.Directory, ".config", "autostart"), user.Name)...)
+		}
+	}
+	items = append(items, genRcLocal(ctx)...)
+	for _, item := range items {
+		results.AppendResult(item)
+	}
+	return results, nil
+}
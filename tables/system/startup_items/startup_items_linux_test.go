@@ -0,0 +1,70 @@
+//go:build linux
+
+package startup_items
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+	"github.com/scrymastic/goosquery/tables/internal/hostfstest"
+)
+
+func TestSplitExec(t *testing.T) {
+	tests := map[string][]string{
+		"xdg-user-dirs-update": {"xdg-user-dirs-update"},
+		"firefox %u":           {"firefox"},
+		`"/opt/My App/app" --flag "a \"b\"" 100%%`:    {"/opt/My App/app", "--flag", `a "b"`, "100%"},
+		"  /usr/bin/env  FOO=1   /usr/bin/agent  %F ": {"/usr/bin/env", "FOO=1", "/usr/bin/agent"},
+	}
+	for exec, expected := range tests {
+		if args := splitExec(exec); !reflect.DeepEqual(args, expected) {
+			t.Errorf("Expected %q to be split into %q, got %q", exec, expected, args)
+		}
+	}
+}
+
+func TestGenStartupItems(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"etc/passwd": "alice:x:1000:1000::/home/alice:/bin/bash\n",
+		"etc/xdg/autostart/tracker.desktop": "[Desktop Entry]\nName=Tracker\nExec=/usr/libexec/tracker-miner-fs-3\n" +
+			"X-GNOME-Autostart-enabled=false\n\n[Desktop Action New]\nName=New\nExec=ignored\n",
+		"home/alice/.config/autostart/agent.desktop": "[Desktop Entry]\nType=Application\nExec=/home/alice/.agent --daemon %U\n",
+		"etc/rc.local": "#!/bin/sh\nexit 0\n",
+	}
+	for name, content := range files {
+		if err := os.MkdirAll(filepath.Join(root, filepath.Dir(name)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(root, name), []byte(content), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	hostfstest.SetRoot(t, root)
+
+	ctx := sqlctx.NewContext()
+	ctx.SetColumns([]string{"*"})
+	items, err := GenStartupItems(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get startup items: %v", err)
+	}
+	if items.Size() != 3 {
+		t.Fatalf("Expected 3 startup items, got %d", items.Size())
+	}
+
+	expected := []map[string]string{
+		{"name": "Tracker", "path": "/usr/libexec/tracker-miner-fs-3", "status": "disabled", "username": "", "source": "/etc/xdg/autostart/tracker.desktop"},
+		{"name": "agent", "path": "/home/alice/.agent", "args": "--daemon", "status": "enabled", "username": "alice"},
+		{"name": "rc.local", "path": "/etc/rc.local", "status": "enabled", "username": "root"},
+	}
+	for i, columns := range expected {
+		for column, value := range columns {
+			if got := (*items)[i].Get(column); got != value {
+				t.Errorf("Expected %s of item %d to be %q, got %v", column, i, value, got)
+			}
+		}
+	}
+}
@@ -0,0 +1,15 @@
+//go:build !linux && !windows
+
+package startup_items
+
+import (
+	"fmt"
+
+	"github.com/scrymastic/goosquery/sql/result"
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+)
+
+// GenStartupItems fails, as startup items are only listed on Linux and Windows
+func GenStartupItems(ctx *sqlctx.Context) (*result.Results, error) {
+	return nil, fmt.Errorf("startup items are not supported on this platform")
+}
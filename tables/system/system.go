@@ -1,18 +1,25 @@
 package system
 
 import (
+	"slices"
+
 	"github.com/scrymastic/goosquery/sql/result"
 	"github.com/scrymastic/goosquery/sql/sqlctx"
+	"github.com/scrymastic/goosquery/tables/system/apk_packages"
 	"github.com/scrymastic/goosquery/tables/system/appcompat_shims"
 	"github.com/scrymastic/goosquery/tables/system/authenticode"
 	"github.com/scrymastic/goosquery/tables/system/autoexec"
 	"github.com/scrymastic/goosquery/tables/system/background_activities_moderator"
 	"github.com/scrymastic/goosquery/tables/system/bitlocker_info"
+	"github.com/scrymastic/goosquery/tables/system/block_devices"
 	"github.com/scrymastic/goosquery/tables/system/certificates"
 	"github.com/scrymastic/goosquery/tables/system/chassis_info"
 	"github.com/scrymastic/goosquery/tables/system/chocolatey_packages"
 	"github.com/scrymastic/goosquery/tables/system/cpu_info"
 	"github.com/scrymastic/goosquery/tables/system/cpuid"
+	"github.com/scrymastic/goosquery/tables/system/crontab"
+	"github.com/scrymastic/goosquery/tables/system/deb_package_files"
+	"github.com/scrymastic/goosquery/tables/system/deb_packages"
 	"github.com/scrymastic/goosquery/tables/system/default_environment"
 	"github.com/scrymastic/goosquery/tables/system/deviceguard_status"
 	"github.com/scrymastic/goosquery/tables/system/disk_info"
@@ -22,11 +29,13 @@ import (
 	"github.com/scrymastic/goosquery/tables/system/hash"
 	"github.com/scrymastic/goosquery/tables/system/ie_extensions"
 	"github.com/scrymastic/goosquery/tables/system/kernel_info"
+	"github.com/scrymastic/goosquery/tables/system/kernel_modules"
 	"github.com/scrymastic/goosquery/tables/system/kva_speculative_info"
 	"github.com/scrymastic/goosquery/tables/system/logged_in_users"
 	"github.com/scrymastic/goosquery/tables/system/logical_drives"
 	"github.com/scrymastic/goosquery/tables/system/logon_sessions"
 	"github.com/scrymastic/goosquery/tables/system/memory_devices"
+	"github.com/scrymastic/goosquery/tables/system/mounts"
 	"github.com/scrymastic/goosquery/tables/system/ntdomains"
 	"github.com/scrymastic/goosquery/tables/system/ntfs_acl_permissions"
 	"github.com/scrymastic/goosquery/tables/system/os_version"
@@ -38,6 +47,7 @@ import (
 	"github.com/scrymastic/goosquery/tables/system/process_memory_map"
 	"github.com/scrymastic/goosquery/tables/system/processes"
 	"github.com/scrymastic/goosquery/tables/system/programs"
+	"github.com/scrymastic/goosquery/tables/system/python_package_files"
 	"github.com/scrymastic/goosquery/tables/system/python_packages"
 	"github.com/scrymastic/goosquery/tables/system/registry"
 	"github.com/scrymastic/goosquery/tables/system/scheduled_tasks"
@@ -49,6 +59,7 @@ import (
 	"github.com/scrymastic/goosquery/tables/system/ssh_configs"
 	"github.com/scrymastic/goosquery/tables/system/startup_items"
 	"github.com/scrymastic/goosquery/tables/system/system_info"
+	"github.com/scrymastic/goosquery/tables/system/systemd_units"
 	"github.com/scrymastic/goosquery/tables/system/tpm_info"
 	"github.com/scrymastic/goosquery/tables/system/uptime"
 	"github.com/scrymastic/goosquery/tables/system/user_groups"
@@ -71,66 +82,97 @@ import (
 	"github.com/scrymastic/goosquery/tables/system/wmi_script_event_consumers"
 )
 
-func GenAppCompatShims(ctx *sqlctx.Context) (*result.Results, error) {
-	return appcompat_shims.GenAppCompatShims(ctx)
-}
-
-func GenAuthenticode(ctx *sqlctx.Context) (*result.Results, error) {
-	return authenticode.GenAuthenticode(ctx)
-}
-
-func GenAutoexec(ctx *sqlctx.Context) (*result.Results, error) {
-	return autoexec.GenAutoexec(ctx)
-}
-
-func GenBackgroundActivitiesModerator(ctx *sqlctx.Context) (*result.Results, error) {
-	return background_activities_moderator.GenBackgroundActivitiesModerator(ctx)
-}
-
-func GenBitlockerInfo(ctx *sqlctx.Context) (*result.Results, error) {
-	return bitlocker_info.GenBitlockerInfo(ctx)
-}
-
-func GenCertificates(ctx *sqlctx.Context) (*result.Results, error) {
-	return certificates.GenCertificates(ctx)
-}
-
-func GenChassisInfo(ctx *sqlctx.Context) (*result.Results, error) {
-	return chassis_info.GenChassisInfo(ctx)
-}
-
-func GenChocolateyPackages(ctx *sqlctx.Context) (*result.Results, error) {
-	return chocolatey_packages.GenChocolateyPackages(ctx)
-}
+// Tables lists the system tables with their schemas and the platforms providing them
+var Tables = slices.Concat(
+	result.OnPlatform([]result.Table{
+		{Name: hash.TableName, Description: hash.Description, Schema: hash.Schema},
+	}, "windows", "linux", "darwin"),
+	result.OnPlatform([]result.Table{
+		{Name: cpu_info.TableName, Description: cpu_info.Description, Schema: cpu_info.Schema},
+		{Name: groups.TableName, Description: groups.Description, Schema: groups.Schema},
+		{Name: kernel_info.TableName, Description: kernel_info.Description, Schema: kernel_info.Schema},
+		{Name: logged_in_users.TableName, Description: logged_in_users.Description, Schema: logged_in_users.Schema},
+		{Name: os_version.TableName, Description: os_version.Description, Schema: os_version.Schema},
+		{Name: physical_disk_performance.TableName, Description: physical_disk_performance.Description, Schema: physical_disk_performance.Schema, NotImplementedOn: []string{"windows"}},
+		{Name: platform_info.TableName, Description: platform_info.Description, Schema: platform_info.Schema},
+		{Name: processes.TableName, Description: processes.Description, Schema: processes.Schema},
+		{Name: python_package_files.TableName, Description: python_package_files.Description, Schema: python_package_files.Schema},
+		{Name: python_packages.TableName, Description: python_packages.Description, Schema: python_packages.Schema},
+		{Name: ssh_configs.TableName, Description: ssh_configs.Description, Schema: ssh_configs.Schema},
+		{Name: startup_items.TableName, Description: startup_items.Description, Schema: startup_items.Schema, NotImplementedOn: []string{"windows"}},
+		{Name: system_info.TableName, Description: system_info.Description, Schema: system_info.Schema},
+		{Name: uptime.TableName, Description: uptime.Description, Schema: uptime.Schema},
+		{Name: user_groups.TableName, Description: user_groups.Description, Schema: user_groups.Schema},
+		{Name: users.TableName, Description: users.Description, Schema: users.Schema},
+	}, "windows", "linux"),
+	result.OnPlatform([]result.Table{
+		{Name: appcompat_shims.TableName, Description: appcompat_shims.Description, Schema: appcompat_shims.Schema},
+		{Name: authenticode.TableName, Description: authenticode.Description, Schema: authenticode.Schema},
+		{Name: autoexec.TableName, Description: autoexec.Description, Schema: autoexec.Schema, Status: result.NotImplemented},
+		{Name: background_activities_moderator.TableName, Description: background_activities_moderator.Description, Schema: background_activities_moderator.Schema},
+		{Name: bitlocker_info.TableName, Description: bitlocker_info.Description, Schema: bitlocker_info.Schema},
+		{Name: certificates.TableName, Description: certificates.Description, Schema: certificates.Schema, Status: result.NotImplemented},
+		{Name: chassis_info.TableName, Description: chassis_info.Description, Schema: chassis_info.Schema},
+		{Name: chocolatey_packages.TableName, Description: chocolatey_packages.Description, Schema: chocolatey_packages.Schema},
+		{Name: cpuid.TableName, Description: cpuid.Description, Schema: cpuid.Schema, Status: result.NotImplemented},
+		{Name: default_environment.TableName, Description: default_environment.Description, Schema: default_environment.Schema},
+		{Name: deviceguard_status.TableName, Description: deviceguard_status.Description, Schema: deviceguard_status.Schema},
+		{Name: disk_info.TableName, Description: disk_info.Description, Schema: disk_info.Schema},
+		{Name: dns_cache.TableName, Description: dns_cache.Description, Schema: dns_cache.Schema},
+		{Name: drivers.TableName, Description: drivers.Description, Schema: drivers.Schema},
+		{Name: ie_extensions.TableName, Description: ie_extensions.Description, Schema: ie_extensions.Schema, Status: result.NotImplemented},
+		{Name: kva_speculative_info.TableName, Description: kva_speculative_info.Description, Schema: kva_speculative_info.Schema},
+		{Name: logical_drives.TableName, Description: logical_drives.Description, Schema: logical_drives.Schema},
+		{Name: logon_sessions.TableName, Description: logon_sessions.Description, Schema: logon_sessions.Schema},
+		{Name: memory_devices.TableName, Description: memory_devices.Description, Schema: memory_devices.Schema},
+		{Name: ntdomains.TableName, Description: ntdomains.Description, Schema: ntdomains.Schema},
+		{Name: ntfs_acl_permissions.TableName, Description: ntfs_acl_permissions.Description, Schema: ntfs_acl_permissions.Schema, Status: result.NotImplemented},
+		{Name: patches.TableName, Description: patches.Description, Schema: patches.Schema},
+		{Name: pipes.TableName, Description: pipes.Description, Schema: pipes.Schema},
+		{Name: prefetch.TableName, Description: prefetch.Description, Schema: prefetch.Schema, Status: result.NotImplemented},
+		{Name: process_memory_map.TableName, Description: process_memory_map.Description, Schema: process_memory_map.Schema},
+		{Name: programs.TableName, Description: programs.Description, Schema: programs.Schema},
+		{Name: registry.TableName, Description: registry.Description, Schema: registry.Schema},
+		{Name: scheduled_tasks.TableName, Description: scheduled_tasks.Description, Schema: scheduled_tasks.Schema},
+		{Name: security_profile_info.TableName, Description: security_profile_info.Description, Schema: security_profile_info.Schema},
+		{Name: services.TableName, Description: services.Description, Schema: services.Schema},
+		{Name: shared_resources.TableName, Description: shared_resources.Description, Schema: shared_resources.Schema},
+		{Name: shellbags.TableName, Description: shellbags.Description, Schema: shellbags.Schema, Status: result.NotImplemented},
+		{Name: shimcache.TableName, Description: shimcache.Description, Schema: shimcache.Schema, Status: result.NotImplemented},
+		{Name: tpm_info.TableName, Description: tpm_info.Description, Schema: tpm_info.Schema, Status: result.NotImplemented},
+		{Name: user_ssh_keys.TableName, Description: user_ssh_keys.Description, Schema: user_ssh_keys.Schema, Status: result.NotImplemented},
+		{Name: userassist.TableName, Description: userassist.Description, Schema: userassist.Schema, Status: result.NotImplemented},
+		{Name: video_info.TableName, Description: video_info.Description, Schema: video_info.Schema, Status: result.NotImplemented},
+		{Name: winbaseobj.TableName, Description: winbaseobj.Description, Schema: winbaseobj.Schema},
+		{Name: windows_crashes.TableName, Description: windows_crashes.Description, Schema: windows_crashes.Schema, Status: result.NotImplemented},
+		{Name: windows_eventlog.TableName, Description: windows_eventlog.Description, Schema: windows_eventlog.Schema, Status: result.NotImplemented},
+		{Name: windows_optional_features.TableName, Description: windows_optional_features.Description, Schema: windows_optional_features.Schema},
+		{Name: windows_search.TableName, Description: windows_search.Description, Schema: windows_search.Schema, Status: result.NotImplemented},
+		{Name: windows_security_center.TableName, Description: windows_security_center.Description, Schema: windows_security_center.Schema},
+		{Name: windows_security_products.TableName, Description: windows_security_products.Description, Schema: windows_security_products.Schema},
+		{Name: windows_update_history.TableName, Description: windows_update_history.Description, Schema: windows_update_history.Schema},
+		{Name: wmi_bios_info.TableName, Description: wmi_bios_info.Description, Schema: wmi_bios_info.Schema, Status: result.NotImplemented},
+		{Name: wmi_cli_event_consumers.TableName, Description: wmi_cli_event_consumers.Description, Schema: wmi_cli_event_consumers.Schema, Status: result.NotImplemented},
+		{Name: wmi_event_filters.TableName, Description: wmi_event_filters.Description, Schema: wmi_event_filters.Schema},
+		{Name: wmi_filter_consumer_binding.TableName, Description: wmi_filter_consumer_binding.Description, Schema: wmi_filter_consumer_binding.Schema, Status: result.NotImplemented},
+		{Name: wmi_script_event_consumers.TableName, Description: wmi_script_event_consumers.Description, Schema: wmi_script_event_consumers.Schema, Status: result.NotImplemented},
+	}, "windows"),
+	result.OnPlatform([]result.Table{
+		{Name: apk_packages.TableName, Description: apk_packages.Description, Schema: apk_packages.Schema},
+		{Name: block_devices.TableName, Description: block_devices.Description, Schema: block_devices.Schema},
+		{Name: crontab.TableName, Description: crontab.Description, Schema: crontab.Schema},
+		{Name: deb_package_files.TableName, Description: deb_package_files.Description, Schema: deb_package_files.Schema},
+		{Name: deb_packages.TableName, Description: deb_packages.Description, Schema: deb_packages.Schema},
+		{Name: kernel_modules.TableName, Description: kernel_modules.Description, Schema: kernel_modules.Schema},
+		{Name: mounts.TableName, Description: mounts.Description, Schema: mounts.Schema},
+		{Name: systemd_units.TableName, Description: systemd_units.Description, Schema: systemd_units.Schema},
+	}, "linux"),
+)
 
 func GenCpuInfo(ctx *sqlctx.Context) (*result.Results, error) {
 	return cpu_info.GenCpuInfo(ctx)
 }
 
-func GenCpuId(ctx *sqlctx.Context) (*result.Results, error) {
-	return cpuid.GenCpuId(ctx)
-}
-
-func GenDefaultEnvironments(ctx *sqlctx.Context) (*result.Results, error) {
-	return default_environment.GenDefaultEnvironments(ctx)
-}
-
-func GenDeviceGuardStatus(ctx *sqlctx.Context) (*result.Results, error) {
-	return deviceguard_status.GenDeviceGuardStatus(ctx)
-}
-
-func GenDiskInfo(ctx *sqlctx.Context) (*result.Results, error) {
-	return disk_info.GenDiskInfo(ctx)
-}
-
-func GenDnsCache(ctx *sqlctx.Context) (*result.Results, error) {
-	return dns_cache.GenDnsCache(ctx)
-}
-
-func GenDrivers(ctx *sqlctx.Context) (*result.Results, error) {
-	return drivers.GenDrivers(ctx)
-}
-
 func GenGroups(ctx *sqlctx.Context) (*result.Results, error) {
 	return groups.GenGroups(ctx)
 }
@@ -139,110 +181,38 @@ func GenHash(ctx *sqlctx.Context) (*result.Results, error) {
 	return hash.GenHash(ctx)
 }
 
-func GenIeExtensions(ctx *sqlctx.Context) (*result.Results, error) {
-	return ie_extensions.GenIeExtensions(ctx)
-}
-
 func GenKernelInfo(ctx *sqlctx.Context) (*result.Results, error) {
 	return kernel_info.GenKernelInfo(ctx)
 }
 
-func GenKvaSpeculativeInfo(ctx *sqlctx.Context) (*result.Results, error) {
-	return kva_speculative_info.GenKvaSpeculativeInfo(ctx)
-}
-
 func GenLoggedInUsers(ctx *sqlctx.Context) (*result.Results, error) {
 	return logged_in_users.GenLoggedInUsers(ctx)
 }
 
-func GenLogicalDrives(ctx *sqlctx.Context) (*result.Results, error) {
-	return logical_drives.GenLogicalDrives(ctx)
-}
-
-func GenLogonSessions(ctx *sqlctx.Context) (*result.Results, error) {
-	return logon_sessions.GenLogonSessions(ctx)
-}
-
-func GenMemoryDevices(ctx *sqlctx.Context) (*result.Results, error) {
-	return memory_devices.GenMemoryDevices(ctx)
-}
-
-func GenNTDomains(ctx *sqlctx.Context) (*result.Results, error) {
-	return ntdomains.GenNTDomains(ctx)
-}
-
-func GenNtfsAclPermissions(ctx *sqlctx.Context) (*result.Results, error) {
-	return ntfs_acl_permissions.GenNtfsAclPermissions(ctx)
-}
-
 func GenOSVersion(ctx *sqlctx.Context) (*result.Results, error) {
 	return os_version.GenOSVersion(ctx)
 }
 
-func GenPatches(ctx *sqlctx.Context) (*result.Results, error) {
-	return patches.GenPatches(ctx)
-}
-
 func GenPhysicalDiskPerformance(ctx *sqlctx.Context) (*result.Results, error) {
 	return physical_disk_performance.GenPhysicalDiskPerformance(ctx)
 }
 
-func GenPipes(ctx *sqlctx.Context) (*result.Results, error) {
-	return pipes.GenPipes(ctx)
-}
-
 func GenPlatformInfo(ctx *sqlctx.Context) (*result.Results, error) {
 	return platform_info.GenPlatformInfo(ctx)
 }
 
-func GenPrefetch(ctx *sqlctx.Context) (*result.Results, error) {
-	return prefetch.GenPrefetch(ctx)
-}
-
-func GenProcessMemoryMap(ctx *sqlctx.Context) (*result.Results, error) {
-	return process_memory_map.GenProcessMemoryMap(ctx)
-}
-
 func GenProcesses(ctx *sqlctx.Context) (*result.Results, error) {
 	return processes.GenProcesses(ctx)
 }
 
-func GenPrograms(ctx *sqlctx.Context) (*result.Results, error) {
-	return programs.GenPrograms(ctx)
+func GenPythonPackageFiles(ctx *sqlctx.Context) (*result.Results, error) {
+	return python_package_files.GenPythonPackageFiles(ctx)
 }
 
 func GenPythonPackages(ctx *sqlctx.Context) (*result.Results, error) {
 	return python_packages.GenPythonPackages(ctx)
 }
 
-func GenRegistry(ctx *sqlctx.Context) (*result.Results, error) {
-	return registry.GenRegistry(ctx)
-}
-
-func GenScheduledTasks(ctx *sqlctx.Context) (*result.Results, error) {
-	return scheduled_tasks.GenScheduledTasks(ctx)
-}
-
-func GenSecurityProfileInfo(ctx *sqlctx.Context) (*result.Results, error) {
-	return security_profile_info.GenSecurityProfileInfo(ctx)
-}
-
-func GenServices(ctx *sqlctx.Context) (*result.Results, error) {
-	return services.GenServices(ctx)
-}
-
-func GenSharedResources(ctx *sqlctx.Context) (*result.Results, error) {
-	return shared_resources.GenSharedResources(ctx)
-}
-
-func GenShellbags(ctx *sqlctx.Context) (*result.Results, error) {
-	return shellbags.GenShellbags(ctx)
-}
-
-func GenShimcache(ctx *sqlctx.Context) (*result.Results, error) {
-	return shimcache.GenShimcache(ctx)
-}
-
 func GenSshConfigs(ctx *sqlctx.Context) (*result.Results, error) {
 	return ssh_configs.GenSshConfigs(ctx)
 }
@@ -255,10 +225,6 @@ func GenSystemInfo(ctx *sqlctx.Context) (*result.Results, error) {
 	return system_info.GenSystemInfo(ctx)
 }
 
-func GenTpmInfo(ctx *sqlctx.Context) (*result.Results, error) {
-	return tpm_info.GenTpmInfo(ctx)
-}
-
 func GenUptime(ctx *sqlctx.Context) (*result.Results, error) {
 	return uptime.GenUptime(ctx)
 }
@@ -267,70 +233,6 @@ func GenUserGroups(ctx *sqlctx.Context) (*result.Results, error) {
 	return user_groups.GenUserGroups(ctx)
 }
 
-func GenUserSshKeys(ctx *sqlctx.Context) (*result.Results, error) {
-	return user_ssh_keys.GenUserSshKeys(ctx)
-}
-
-func GenUserAssist(ctx *sqlctx.Context) (*result.Results, error) {
-	return userassist.GenUserAssist(ctx)
-}
-
 func GenUsers(ctx *sqlctx.Context) (*result.Results, error) {
 	return users.GenUsers(ctx)
 }
-
-func GenVideoInfo(ctx *sqlctx.Context) (*result.Results, error) {
-	return video_info.GenVideoInfo(ctx)
-}
-
-func GenWinbaseObj(ctx *sqlctx.Context) (*result.Results, error) {
-	return winbaseobj.GenWinbaseObj(ctx)
-}
-
-func GenWindowsCrashes(ctx *sqlctx.Context) (*result.Results, error) {
-	return windows_crashes.GenWindowsCrashes(ctx)
-}
-
-func GenWindowsEventLog(ctx *sqlctx.Context) (*result.Results, error) {
-	return windows_eventlog.GenWindowsEventLog(ctx)
-}
-
-func GenWindowsOptionalFeatures(ctx *sqlctx.Context) (*result.Results, error) {
-	return windows_optional_features.GenWindowsOptionalFeatures(ctx)
-}
-
-func GenWindowsSearch(ctx *sqlctx.Context) (*result.Results, error) {
-	return windows_search.GenWindowsSearch(ctx)
-}
-
-func GenWindowsSecurityCenter(ctx *sqlctx.Context) (*result.Results, error) {
-	return windows_security_center.GenWindowsSecurityCenter(ctx)
-}
-
-func GenWindowsSecurityProducts(ctx *sqlctx.Context) (*result.Results, error) {
-	return windows_security_products.GenWindowsSecurityProducts(ctx)
-}
-
-func GenWindowsUpdateHistory(ctx *sqlctx.Context) (*result.Results, error) {
-	return windows_update_history.GenWindowsUpdateHistory(ctx)
-}
-
-func GenWmiBiosInfo(ctx *sqlctx.Context) (*result.Results, error) {
-	return wmi_bios_info.GenWmiBiosInfo(ctx)
-}
-
-func GenWmiCliEventConsumers(ctx *sqlctx.Context) (*result.Results, error) {
-	return wmi_cli_event_consumers.GenWmiCliEventConsumers(ctx)
-}
-
-func GenWmiEventFilters(ctx *sqlctx.Context) (*result.Results, error) {
-	return wmi_event_filters.GenWmiEventFilters(ctx)
-}
-
-func GenWmiFilterConsumerBinding(ctx *sqlctx.Context) (*result.Results, error) {
-	return wmi_filter_consumer_binding.GenWmiFilterConsumerBinding(ctx)
-}
-
-func GenWmiScriptEventConsumers(ctx *sqlctx.Context) (*result.Results, error) {
-	return wmi_script_event_consumers.GenWmiScriptEventConsumers(ctx)
-}
@@ -0,0 +1,94 @@
+//go:build linux
+
+package system_info
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/scrymastic/goosquery/sql/result"
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+	"github.com/scrymastic/goosquery/tables/internal/cpuinfo"
+	"github.com/scrymastic/goosquery/tables/internal/hostfs"
+)
+
+// dmiColumns maps the columns to the attributes of /sys/class/dmi/id. The
+// serial numbers and the uuid are only readable by root.
+var dmiColumns = map[string]string{
+	"uuid":             "product_uuid",
+	"hardware_vendor":  "sys_vendor",
+	"hardware_model":   "product_name",
+	"hardware_version": "product_version",
+	"hardware_serial":  "product_serial",
+	"board_vendor":     "board_vendor",
+	"board_model":      "board_name",
+	"board_version":    "board_version",
+	"board_serial":     "board_serial",
+}
+
+// getPhysicalMemory returns the MemTotal of /proc/meminfo in bytes
+func getPhysicalMemory() (int64, bool) {
+	file, err := os.Open(hostfs.Proc("meminfo"))
+	if err != nil {
+		return 0, false
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if value, found := strings.CutPrefix(scanner.Text(), "MemTotal:"); found {
+			kilobytes, err := strconv.ParseInt(strings.TrimSuffix(strings.TrimSpace(value), " kB"), 10, 64)
+			return kilobytes * 1024, err == nil
+		}
+	}
+	return 0, false
+}
+
+// GenSystemInfo returns the hostname, the processors, the memory and the
+// hardware of the system
+func GenSystemInfo(ctx *sqlctx.Context) (*result.Results, error) {
+	info := result.NewResult(ctx, Schema)
+
+	// Get hostname
+	hostname, err := os.Hostname()
+	if err == nil {
+		info.Set("hostname", hostname)
+		info.Set("computer_name", hostname)
+		info.Set("local_hostname", hostname)
+	}
+
+	info.Set("cpu_type", cpuinfo.Arch())
+	if processors, err := cpuinfo.Read(); err == nil && len(processors) > 0 {
+		info.Set("cpu_brand", processors[0]["model name"])
+		info.Set("cpu_subtype", processors[0]["model"])
+		info.Set("cpu_microcode", processors[0]["microcode"])
+
+		packages := cpuinfo.Packages(processors)
+		cores := 0
+		logical := 0
+		for _, processors := range packages {
+			cores += cpuinfo.Cores(processors)
+			logical += len(processors)
+		}
+		info.Set("cpu_physical_cores", int32(cores))
+		info.Set("cpu_logical_cores", int32(logical))
+		info.Set("cpu_sockets", int32(len(packages)))
+	}
+
+	if memory, ok := getPhysicalMemory(); ok {
+		info.Set("physical_memory", memory)
+	}
+
+	// Virtual machines and containers may have no DMI tables
+	for column, attribute := range dmiColumns {
+		if data, err := os.ReadFile(hostfs.Sys("class", "dmi", "id", attribute)); err == nil {
+			info.Set(column, strings.TrimSpace(string(data)))
+		}
+	}
+
+	results := result.NewQueryResult()
+	results.AppendResult(*info)
+	return results, nil
+}
@@ -0,0 +1,71 @@
+//go:build linux
+
+package system_info
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+	"github.com/scrymastic/goosquery/tables/internal/hostfstest"
+)
+
+func TestGenSystemInfoFromProcAndSys(t *testing.T) {
+	procRoot := t.TempDir()
+	sysRoot := t.TempDir()
+	var cpuinfo string
+	// A socket of two cores with hyper-threading
+	for _, processor := range []string{"0", "1", "2", "3"} {
+		coreID := map[string]string{"0": "0", "1": "1", "2": "0", "3": "1"}[processor]
+		cpuinfo += "processor\t: " + processor + "\nvendor_id\t: GenuineIntel\nmodel\t\t: 158\n" +
+			"model name\t: Intel(R) Core(TM) i7-8700 CPU @ 3.20GHz\nmicrocode\t: 0xf4\n" +
+			"physical id\t: 0\ncore id\t\t: " + coreID + "\n\n"
+	}
+	files := map[string]string{
+		filepath.Join(procRoot, "cpuinfo"):                     cpuinfo,
+		filepath.Join(procRoot, "meminfo"):                     "MemTotal:       16318220 kB\nMemFree:         1024000 kB\n",
+		filepath.Join(sysRoot, "class/dmi/id/sys_vendor"):      "Dell Inc.\n",
+		filepath.Join(sysRoot, "class/dmi/id/product_name"):    "OptiPlex 7060\n",
+		filepath.Join(sysRoot, "class/dmi/id/board_name"):      "0C96W1\n",
+		filepath.Join(sysRoot, "class/dmi/id/product_version"): "\n",
+	}
+	for path, content := range files {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	hostfstest.SetProcRoot(t, procRoot)
+	hostfstest.SetSysRoot(t, sysRoot)
+
+	ctx := sqlctx.NewContext()
+	ctx.SetColumns([]string{"*"})
+	info, err := GenSystemInfo(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get system info: %v", err)
+	}
+
+	expected := map[string]interface{}{
+		"cpu_brand":          "Intel(R) Core(TM) i7-8700 CPU @ 3.20GHz",
+		"cpu_subtype":        "158",
+		"cpu_microcode":      "0xf4",
+		"cpu_physical_cores": int32(2),
+		"cpu_logical_cores":  int32(4),
+		"cpu_sockets":        int32(1),
+		"physical_memory":    int64(16318220 * 1024),
+		"hardware_vendor":    "Dell Inc.",
+		"hardware_model":     "OptiPlex 7060",
+		"hardware_version":   "",
+		"board_model":        "0C96W1",
+		// Only readable by root
+		"uuid": "",
+	}
+	for column, value := range expected {
+		if (*info)[0].Get(column) != value {
+			t.Errorf("Expected %s = %v, got %v", column, value, (*info)[0].Get(column))
+		}
+	}
+}
@@ -0,0 +1,15 @@
+//go:build !linux && !windows
+
+package system_info
+
+import (
+	"fmt"
+
+	"github.com/scrymastic/goosquery/sql/result"
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+)
+
+// GenSystemInfo fails, as the system is only described on Linux and Windows
+func GenSystemInfo(ctx *sqlctx.Context) (*result.Results, error) {
+	return nil, fmt.Errorf("system info is not supported on this platform")
+}
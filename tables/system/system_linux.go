@@ -0,0 +1,48 @@
+//go:build linux
+
+package system
+
+import (
+	"github.com/scrymastic/goosquery/sql/result"
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+	"github.com/scrymastic/goosquery/tables/system/apk_packages"
+	"github.com/scrymastic/goosquery/tables/system/block_devices"
+	"github.com/scrymastic/goosquery/tables/system/crontab"
+	"github.com/scrymastic/goosquery/tables/system/deb_package_files"
+	"github.com/scrymastic/goosquery/tables/system/deb_packages"
+	"github.com/scrymastic/goosquery/tables/system/kernel_modules"
+	"github.com/scrymastic/goosquery/tables/system/mounts"
+	"github.com/scrymastic/goosquery/tables/system/systemd_units"
+)
+
+func GenApkPackages(ctx *sqlctx.Context) (*result.Results, error) {
+	return apk_packages.GenApkPackages(ctx)
+}
+
+func GenBlockDevices(ctx *sqlctx.Context) (*result.Results, error) {
+	return block_devices.GenBlockDevices(ctx)
+}
+
+func GenCrontab(ctx *sqlctx.Context) (*result.Results, error) {
+	return crontab.GenCrontab(ctx)
+}
+
+func GenDebPackageFiles(ctx *sqlctx.Context) (*result.Results, error) {
+	return deb_package_files.GenDebPackageFiles(ctx)
+}
+
+func GenDebPackages(ctx *sqlctx.Context) (*result.Results, error) {
+	return deb_packages.GenDebPackages(ctx)
+}
+
+func GenKernelModules(ctx *sqlctx.Context) (*result.Results, error) {
+	return kernel_modules.GenKernelModules(ctx)
+}
+
+func GenMounts(ctx *sqlctx.Context) (*result.Results, error) {
+	return mounts.GenMounts(ctx)
+}
+
+func GenSystemdUnits(ctx *sqlctx.Context) (*result.Results, error) {
+	return systemd_units.GenSystemdUnits(ctx)
+}
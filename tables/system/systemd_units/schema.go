@@ -0,0 +1,22 @@
+package systemd_units
+
+import (
+	"github.com/scrymastic/goosquery/sql/result"
+)
+
+var TableName = "systemd_units"
+var Description = "The systemd unit files of the system and of the users, with their enablement state."
+var Schema = result.Schema{
+	result.Column{Name: "id", Type: "TEXT", Description: "Unique unit identifier, such as ssh.service"},
+	result.Column{Name: "description", Type: "TEXT", Description: "Unit description"},
+	result.Column{Name: "type", Type: "TEXT", Description: "Unit type, such as service, socket or timer"},
+	result.Column{Name: "scope", Type: "TEXT", Description: "Manager of the unit, system or user"},
+	result.Column{Name: "username", Type: "TEXT", Description: "Owner of the units of a home directory, empty for the units of the system and the global user units"},
+	result.Column{Name: "fragment_path", Type: "TEXT", Description: "Path of the unit file, after following its symbolic links"},
+	result.Column{Name: "drop_in_paths", Type: "TEXT", Description: "Paths of the drop-in files extending the unit, separated by spaces"},
+	result.Column{Name: "exec_start", Type: "TEXT", Description: "Commands of the ExecStart settings of the [Service] section, without their prefixes such as -, separated by semicolons"},
+	result.Column{Name: "user", Type: "TEXT", Description: "User setting of the [Service] section, the user running the service"},
+	result.Column{Name: "wanted_by", Type: "TEXT", Description: "Units of the WantedBy settings of the [Install] section, separated by spaces"},
+	result.Column{Name: "unit_file_state", Type: "TEXT", Description: "Enablement state from the filesystem: enabled, disabled, static, masked or alias"},
+	result.Column{Name: "root", Type: "TEXT", Description: "Root directory of the system holding the unit files, such as a mounted image, / by default"},
+}
@@ -0,0 +1,287 @@
+//go:build linux
+
+package systemd_units
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/scrymastic/goosquery/sql/result"
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+	"github.com/scrymastic/goosquery/tables/internal/accounts"
+	"github.com/scrymastic/goosquery/tables/internal/hostfs"
+)
+
+// unitTypes are the suffixes of the unit files
+var unitTypes = map[string]bool{
+	".service": true, ".socket": true, ".timer": true, ".target": true,
+	".path": true, ".mount": true, ".automount": true, ".swap": true,
+	".slice": true, ".scope": true, ".device": true,
+}
+
+// The directories of the system and the global user units, by precedence.
+// The first directory of each list holds the configuration of the
+// administrator, with the links enabling the units.
+var (
+	systemUnitDirs = []string{
+		"/etc/systemd/system",
+		"/run/systemd/system",
+		"/usr/local/lib/systemd/system",
+		"/usr/lib/systemd/system",
+		"/lib/systemd/system",
+	}
+	userUnitDirs = []string{
+		"/etc/systemd/user",
+		"/run/systemd/user",
+		"/usr/local/lib/systemd/user",
+		"/usr/lib/systemd/user",
+	}
+)
+
+// unitScope is a set of unit directories, read by the same manager
+type unitScope struct {
+	scope    string
+	username string
+	// dirs are the unit directories by precedence, as paths in the system
+	dirs []string
+	// configDirs hold the links enabling the units
+	configDirs []string
+}
+
+// listScopes returns the scopes of a root: the system units, the global user
+// units, and the units in the home directories of the users
+func listScopes(root string) []unitScope {
+	scopes := []unitScope{
+		{scope: "system", dirs: systemUnitDirs, configDirs: systemUnitDirs[:1]},
+		{scope: "user", dirs: userUnitDirs, configDirs: userUnitDirs[:1]},
+	}
+	users, err := accounts.ReadUsersUnder(root)
+	if err != nil {
+		return scopes
+	}
+	for _, user := range users {
+		dir := path.Join(user.Directory, ".config", "systemd", "user")
+		if info, err := os.Stat(hostfs.Under(root, dir)); err != nil || !info.IsDir() {
+			continue
+		}
+		scopes = append(scopes, unitScope{
+			scope:      "user",
+			username:   user.Name,
+			dirs:       []string{dir},
+			configDirs: []string{dir, userUnitDirs[0]},
+		})
+	}
+	return scopes
+}
+
+// resolve follows the symbolic links of a path of a root, within the root
+func resolve(root string, name string) string {
+	for i := 0; i < 8; i++ {
+		target, err := os.Readlink(hostfs.Under(root, name))
+		if err != nil {
+			break
+		}
+		if !path.IsAbs(target) {
+			target = path.Join(path.Dir(name), target)
+		}
+		name = target
+	}
+	return name
+}
+
+// templateName returns the template of an instance, such as getty@.service
+// for getty@tty1.service, or an empty string
+func templateName(name string) string {
+	at := strings.Index(name, "@")
+	if at < 0 || strings.HasPrefix(name[at:], "@.") {
+		return ""
+	}
+	return name[:at+1] + path.Ext(name)
+}
+
+// enabledUnits returns the units enabled by the links of the configuration
+// directories: the links of the .wants, .requires and .upholds directories
+// of the units they are attached to, and the aliases to other units
+func enabledUnits(root string, scope unitScope) map[string]bool {
+	enabled := map[string]bool{}
+	add := func(name string) {
+		enabled[name] = true
+		if template := templateName(name); template != "" {
+			enabled[template] = true
+		}
+	}
+	for _, dir := range scope.configDirs {
+		entries, err := os.ReadDir(hostfs.Under(root, dir))
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			name := entry.Name()
+			if entry.IsDir() {
+				if !strings.HasSuffix(name, ".wants") && !strings.HasSuffix(name, ".requires") && !strings.HasSuffix(name, ".upholds") {
+					continue
+				}
+				links, _ := os.ReadDir(hostfs.Under(root, dir, name))
+				for _, link := range links {
+					add(link.Name())
+				}
+				continue
+			}
+			if target, err := os.Readlink(hostfs.Under(root, dir, name)); err == nil && path.Base(target) != name {
+				add(path.Base(target))
+			}
+		}
+	}
+	return enabled
+}
+
+// dropIns returns the drop-ins of a unit, sorted by name. As with systemd, a
+// drop-in hides the drop-ins of the same name in the directories of lower
+// precedence.
+func dropIns(root string, scope unitScope, name string) []string {
+	paths := map[string]string{}
+	for _, dir := range scope.dirs {
+		entries, err := os.ReadDir(hostfs.Under(root, dir, name+".d"))
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if _, found := paths[entry.Name()]; !found && strings.HasSuffix(entry.Name(), ".conf") {
+				paths[entry.Name()] = path.Join(dir, name+".d", entry.Name())
+			}
+		}
+	}
+	names := make([]string, 0, len(paths))
+	for name := range paths {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	dropIns := make([]string, len(names))
+	for i, name := range names {
+		dropIns[i] = paths[name]
+	}
+	return dropIns
+}
+
+// parseFiles returns the settings of a unit file and its drop-ins
+func parseFiles(root string, paths ...string) (unitFile, error) {
+	unit := unitFile{}
+	for _, name := range paths {
+		file, err := os.Open(hostfs.Under(root, resolve(root, name)))
+		if err != nil {
+			return nil, err
+		}
+		err = unit.parse(file)
+		file.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return unit, nil
+}
+
+// genUnit returns the row of a unit file of a directory of a scope
+func genUnit(ctx *sqlctx.Context, root string, scope unitScope, dir string, name string, enabled map[string]bool) (*result.Result, error) {
+	unitPath := path.Join(dir, name)
+	row := result.NewResult(ctx, Schema)
+	row.Set("id", name)
+	row.Set("type", strings.TrimPrefix(path.Ext(name), "."))
+	row.Set("scope", scope.scope)
+	row.Set("username", scope.username)
+	row.Set("root", root)
+
+	target, err := os.Readlink(hostfs.Under(root, unitPath))
+	if err == nil && target == "/dev/null" {
+		row.Set("fragment_path", unitPath)
+		row.Set("unit_file_state", "masked")
+		return row, nil
+	}
+
+	fragment := resolve(root, unitPath)
+	drops := dropIns(root, scope, name)
+	unit, err := parseFiles(root, append([]string{unitPath}, drops...)...)
+	if err != nil {
+		return nil, err
+	}
+	row.Set("description", unit.last("Unit", "Description"))
+	row.Set("fragment_path", fragment)
+	row.Set("drop_in_paths", strings.Join(drops, " "))
+	var commands []string
+	for _, value := range unit["Service"]["ExecStart"] {
+		commands = append(commands, execCommand(value))
+	}
+	row.Set("exec_start", strings.Join(commands, "; "))
+	row.Set("user", unit.last("Service", "User"))
+	var wantedBy []string
+	for _, value := range unit["Install"]["WantedBy"] {
+		wantedBy = append(wantedBy, strings.Fields(value)...)
+	}
+	row.Set("wanted_by", strings.Join(wantedBy, " "))
+
+	switch {
+	case path.Base(fragment) != name:
+		row.Set("unit_file_state", "alias")
+	case enabled[name]:
+		row.Set("unit_file_state", "enabled")
+	case !unit.hasInstall():
+		row.Set("unit_file_state", "static")
+	default:
+		row.Set("unit_file_state", "disabled")
+	}
+	return row, nil
+}
+
+// genScope returns the units of a scope. A unit file hides the files of the
+// same name in the directories of lower precedence.
+func genScope(ctx *sqlctx.Context, root string, scope unitScope) ([]result.Result, error) {
+	enabled := enabledUnits(root, scope)
+	seen := map[string]bool{}
+	var units []result.Result
+	for _, dir := range scope.dirs {
+		entries, err := os.ReadDir(hostfs.Under(root, dir))
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			name := entry.Name()
+			if entry.IsDir() || !unitTypes[path.Ext(name)] || seen[name] {
+				continue
+			}
+			seen[name] = true
+			unit, err := genUnit(ctx, root, scope, dir, name, enabled)
+			// Dangling links and unreadable files of the users
+			if errors.Is(err, fs.ErrNotExist) || errors.Is(err, fs.ErrPermission) {
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to read unit %s: %w", path.Join(dir, name), err)
+			}
+			units = append(units, *unit)
+		}
+	}
+	return units, nil
+}
+
+// GenSystemdUnits returns the unit files of the system, of the global user
+// units and of the home directories, of the system or of the roots
+// constrained by the query
+func GenSystemdUnits(ctx *sqlctx.Context) (*result.Results, error) {
+	results := result.NewQueryResult()
+	for _, root := range hostfs.Roots(ctx.GetConstants("root")) {
+		for _, scope := range listScopes(root) {
+			units, err := genScope(ctx, root, scope)
+			if err != nil {
+				return nil, err
+			}
+			for _, unit := range units {
+				results.AppendResult(unit)
+			}
+		}
+	}
+	return results, nil
+}
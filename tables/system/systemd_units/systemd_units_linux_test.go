@@ -0,0 +1,107 @@
+//go:build linux
+
+package systemd_units
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+)
+
+func TestGenSystemdUnits(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"etc/passwd": "alice:x:1000:1000::/home/alice:/bin/bash\n",
+		"lib/systemd/system/ssh.service": "[Unit]\nDescription=OpenBSD Secure Shell server\n\n" +
+			"[Service]\nExecStart=/usr/sbin/sshd -D\n\n[Install]\nWantedBy=multi-user.target\nAlias=sshd.service\n",
+		"lib/systemd/system/rescue.service":            "[Unit]\nDescription=Rescue Shell\n\n[Service]\nExecStart=-/lib/systemd/systemd-sulogin-shell rescue\n",
+		"lib/systemd/system/cups.service":              "[Service]\nExecStart=/usr/sbin/cupsd -l\n\n[Install]\nWantedBy=printer.target multi-user.target\n",
+		"lib/systemd/system/getty@.service":            "[Service]\nExecStart=-/sbin/agetty %I\n\n[Install]\nWantedBy=getty.target\n",
+		"lib/systemd/system/foo.conf":                  "not a unit\n",
+		"lib/systemd/system/systemd-timesyncd.service": "[Service]\nExecStart=!!/lib/systemd/systemd-timesyncd\n",
+		"lib/systemd/system/backup.service": "[Service]\nType=oneshot\n" +
+			"ExecStart=-@:/usr/bin/tar tar -czf /var/backups/etc.tgz /etc\nExecStart=+/usr/bin/sync\nExecStart=!/usr/bin/logger done\n",
+		"etc/systemd/system/ssh.service.d/override.conf": "[Service]\nExecStart=\nExecStart=/usr/sbin/sshd -D -e\nUser=root\n",
+		"home/alice/.config/systemd/user/miner.service": "[Service]\nExecStart=/home/alice/.local/bin/miner \\\n  --quiet\n\n" +
+			"[Install]\nWantedBy=default.target\n",
+	}
+	for name, content := range files {
+		if err := os.MkdirAll(filepath.Join(root, filepath.Dir(name)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(root, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	links := map[string]string{
+		"etc/systemd/system/multi-user.target.wants/ssh.service":             "/lib/systemd/system/ssh.service",
+		"etc/systemd/system/getty.target.wants/getty@tty1.service":           "/lib/systemd/system/getty@.service",
+		"etc/systemd/system/sshd.service":                                    "/lib/systemd/system/ssh.service",
+		"etc/systemd/system/cryptdisks.service":                              "/dev/null",
+		"home/alice/.config/systemd/user/default.target.wants/miner.service": "../miner.service",
+	}
+	for name, target := range links {
+		if err := os.MkdirAll(filepath.Join(root, filepath.Dir(name)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Symlink(target, filepath.Join(root, name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx := sqlctx.NewContext()
+	ctx.SetColumns([]string{"*"})
+	ctx.AddConstant("root", root)
+	units, err := GenSystemdUnits(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get systemd units: %v", err)
+	}
+
+	byID := map[string]map[string]interface{}{}
+	for _, unit := range *units {
+		row := map[string]interface{}{}
+		for _, column := range []string{"scope", "username", "fragment_path", "drop_in_paths", "exec_start", "user", "wanted_by", "unit_file_state", "description"} {
+			row[column] = unit.Get(column)
+		}
+		byID[unit.Get("id").(string)] = row
+	}
+	if len(byID) != 9 {
+		t.Fatalf("Expected 9 units, got %d: %v", len(byID), byID)
+	}
+
+	expected := map[string]map[string]interface{}{
+		"ssh.service": {
+			"description":     "OpenBSD Secure Shell server",
+			"fragment_path":   "/lib/systemd/system/ssh.service",
+			"drop_in_paths":   "/etc/systemd/system/ssh.service.d/override.conf",
+			"exec_start":      "/usr/sbin/sshd -D -e",
+			"user":            "root",
+			"wanted_by":       "multi-user.target",
+			"unit_file_state": "enabled",
+		},
+		"sshd.service":       {"fragment_path": "/lib/systemd/system/ssh.service", "unit_file_state": "alias"},
+		"cryptdisks.service": {"unit_file_state": "masked"},
+		"rescue.service":     {"exec_start": "/lib/systemd/systemd-sulogin-shell rescue", "unit_file_state": "static"},
+		"cups.service":       {"wanted_by": "printer.target multi-user.target", "unit_file_state": "disabled"},
+		"getty@.service":     {"exec_start": "/sbin/agetty %I", "unit_file_state": "enabled"},
+
+		// The prefixes of the commands are not part of them
+		"systemd-timesyncd.service": {"exec_start": "/lib/systemd/systemd-timesyncd"},
+		"backup.service":            {"exec_start": "/usr/bin/tar tar -czf /var/backups/etc.tgz /etc; /usr/bin/sync; /usr/bin/logger done"},
+		"miner.service": {
+			"scope":           "user",
+			"username":        "alice",
+			"exec_start":      "/home/alice/.local/bin/miner --quiet",
+			"unit_file_state": "enabled",
+		},
+	}
+	for id, columns := range expected {
+		for column, value := range columns {
+			if got := byID[id][column]; got != value {
+				t.Errorf("Expected %s of %s to be %q, got %v", column, id, value, got)
+			}
+		}
+	}
+}
@@ -0,0 +1,86 @@
+//go:build linux
+
+package systemd_units
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// unitFile holds the settings of a unit file and its drop-ins, by section
+// and by key. Every setting keeps all its values, as the list settings such
+// as ExecStart and WantedBy can be given several times.
+type unitFile map[string]map[string][]string
+
+// parse adds the settings of a unit file or a drop-in. As with systemd, an
+// empty value resets the values of the setting given before.
+func (u unitFile) parse(r io.Reader) error {
+	section := ""
+	scanner := bufio.NewScanner(r)
+	var line string
+	for scanner.Scan() {
+		// Lines ending with a backslash continue on the next line
+		text := strings.TrimSpace(scanner.Text())
+		if strings.HasSuffix(text, "\\") {
+			line += strings.TrimSpace(strings.TrimSuffix(text, "\\")) + " "
+			continue
+		}
+		line, text = "", line+text
+
+		if text == "" || text[0] == '#' || text[0] == ';' {
+			continue
+		}
+		if text[0] == '[' && text[len(text)-1] == ']' {
+			section = text[1 : len(text)-1]
+			continue
+		}
+		key, value, found := strings.Cut(text, "=")
+		if !found || section == "" {
+			continue
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if u[section] == nil {
+			u[section] = map[string][]string{}
+		}
+		if value == "" {
+			delete(u[section], key)
+			continue
+		}
+		u[section][key] = append(u[section][key], value)
+	}
+	return scanner.Err()
+}
+
+// last returns the last value of a setting, the one applied for the settings
+// taking a single value
+func (u unitFile) last(section string, key string) string {
+	values := u[section][key]
+	if len(values) == 0 {
+		return ""
+	}
+	return values[len(values)-1]
+}
+
+// hasInstall reports whether the unit can be enabled, the units without an
+// [Install] section being static
+func (u unitFile) hasInstall() bool {
+	for _, key := range []string{"WantedBy", "RequiredBy", "UpheldBy", "Alias", "Also"} {
+		if len(u["Install"][key]) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// execCommandPrefixes are the characters systemd reads before the path of
+// the commands of settings such as ExecStart, to ignore their failure (-),
+// pass another argv[0] (@), skip the environment expansion (:), run them
+// with full privileges (+, ! and !!) or through the shell of the user (|)
+const execCommandPrefixes = "-@:+!|"
+
+// execCommand returns a command of an Exec setting without its prefixes,
+// such as /lib/systemd/systemd-timesyncd for !!/lib/systemd/systemd-timesyncd
+func execCommand(value string) string {
+	return strings.TrimLeft(value, execCommandPrefixes)
+}
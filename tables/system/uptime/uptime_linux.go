@@ -0,0 +1,45 @@
+//go:build linux
+
+package uptime
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/scrymastic/goosquery/sql/result"
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+	"github.com/scrymastic/goosquery/tables/internal/hostfs"
+)
+
+// GenUptime returns the system uptime information from /proc/uptime, whose
+// first field is the number of seconds since boot
+func GenUptime(ctx *sqlctx.Context) (*result.Results, error) {
+	data, err := os.ReadFile(hostfs.Proc("uptime"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get uptime: %v", err)
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("failed to get uptime: empty %s", hostfs.Proc("uptime"))
+	}
+	seconds, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get uptime: %v", err)
+	}
+
+	totalSeconds := int64(seconds)
+
+	uptime := result.NewResult(ctx, Schema)
+
+	uptime.Set("days", int32(totalSeconds/86400))
+	uptime.Set("hours", int32(totalSeconds%86400/3600))
+	uptime.Set("minutes", int32(totalSeconds%3600/60))
+	uptime.Set("seconds", int32(totalSeconds%60))
+	uptime.Set("total_seconds", totalSeconds)
+
+	results := result.NewQueryResult()
+	results.AppendResult(*uptime)
+	return results, nil
+}
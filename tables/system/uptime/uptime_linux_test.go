@@ -0,0 +1,41 @@
+//go:build linux
+
+package uptime
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+	"github.com/scrymastic/goosquery/tables/internal/hostfstest"
+)
+
+func TestGenUptimeFromProc(t *testing.T) {
+	root := t.TempDir()
+	// 2 days, 3 hours, 4 minutes and 5.67 seconds
+	if err := os.WriteFile(filepath.Join(root, "uptime"), []byte("183845.67 350000.12\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	hostfstest.SetProcRoot(t, root)
+
+	ctx := sqlctx.NewContext()
+	ctx.SetColumns([]string{"*"})
+	uptime, err := GenUptime(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get uptime: %v", err)
+	}
+
+	expected := map[string]interface{}{
+		"days":          int32(2),
+		"hours":         int32(3),
+		"minutes":       int32(4),
+		"seconds":       int32(5),
+		"total_seconds": int64(183845),
+	}
+	for column, value := range expected {
+		if (*uptime)[0].Get(column) != value {
+			t.Errorf("Expected %s = %v, got %v", column, value, (*uptime)[0].Get(column))
+		}
+	}
+}
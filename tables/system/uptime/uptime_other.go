@@ -0,0 +1,15 @@
+//go:build !linux && !windows
+
+package uptime
+
+import (
+	"fmt"
+
+	"github.com/scrymastic/goosquery/sql/result"
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+)
+
+// GenUptime fails, as the uptime is only read on Linux and Windows
+func GenUptime(ctx *sqlctx.Context) (*result.Results, error) {
+	return nil, fmt.Errorf("uptime is not supported on this platform")
+}
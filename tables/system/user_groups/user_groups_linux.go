@@ -0,0 +1,51 @@
+//go:build linux
+
+package user_groups
+
+import (
+	"fmt"
+
+	"github.com/scrymastic/goosquery/sql/result"
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+	"github.com/scrymastic/goosquery/tables/internal/accounts"
+)
+
+// GenUserGroups returns the primary group of the users of /etc/passwd and
+// the groups of /etc/group listing them as members
+func GenUserGroups(ctx *sqlctx.Context) (*result.Results, error) {
+	users, err := accounts.ReadUsers()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read users: %w", err)
+	}
+	groups, err := accounts.ReadGroups()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read groups: %w", err)
+	}
+
+	memberships := make(map[string][]uint32)
+	for _, group := range groups {
+		for _, member := range group.Members {
+			memberships[member] = append(memberships[member], group.GID)
+		}
+	}
+
+	userGroups := result.NewQueryResult()
+	for _, user := range users {
+		// A group may list the members of its primary group again
+		seen := make(map[uint32]bool)
+		for _, gid := range append([]uint32{user.GID}, memberships[user.Name]...) {
+			if seen[gid] {
+				continue
+			}
+			seen[gid] = true
+
+			userGroup := result.NewResult(ctx, Schema)
+			userGroup.Set("uid", int64(user.UID))
+			userGroup.Set("gid", int64(gid))
+
+			userGroups.AppendResult(*userGroup)
+		}
+	}
+
+	return userGroups, nil
+}
@@ -0,0 +1,53 @@
+//go:build linux
+
+package user_groups
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+	"github.com/scrymastic/goosquery/tables/internal/hostfstest"
+)
+
+func TestGenUserGroupsFromFiles(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"etc/passwd": "root:x:0:0:root:/root:/bin/bash\n" +
+			"alice:x:1000:1000::/home/alice:/bin/sh\n",
+		"etc/group": "root:x:0:\n" +
+			"sudo:x:27:alice,bob\n" +
+			"alice:x:1000:alice\n" +
+			"docker:x:999:alice\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	hostfstest.SetRoot(t, root)
+
+	ctx := sqlctx.NewContext()
+	ctx.SetColumns([]string{"*"})
+	userGroups, err := GenUserGroups(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get user groups: %v", err)
+	}
+
+	// The primary group comes first, and alice is listed once in the group of the same name
+	expected := [][2]int64{{0, 0}, {1000, 1000}, {1000, 27}, {1000, 999}}
+	if userGroups.Size() != len(expected) {
+		t.Fatalf("Expected %d memberships, got %v", len(expected), userGroups)
+	}
+	for i, membership := range expected {
+		row := (*userGroups)[i]
+		if row.Get("uid") != membership[0] || row.Get("gid") != membership[1] {
+			t.Errorf("Expected membership %v, got uid %v gid %v", membership, row.Get("uid"), row.Get("gid"))
+		}
+	}
+}
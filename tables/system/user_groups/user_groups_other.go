@@ -0,0 +1,15 @@
+//go:build !linux && !windows
+
+package user_groups
+
+import (
+	"fmt"
+
+	"github.com/scrymastic/goosquery/sql/result"
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+)
+
+// GenUserGroups fails, as users are only listed on Linux and Windows
+func GenUserGroups(ctx *sqlctx.Context) (*result.Results, error) {
+	return nil, fmt.Errorf("user groups are not supported on this platform")
+}
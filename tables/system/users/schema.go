@@ -18,4 +18,7 @@ var Schema = result.Schema{
 	result.Column{Name: "uuid", Type: "TEXT", Description: "User's UUID (Apple) or SID (Windows)"},
 
 	result.Column{Name: "type", Type: "TEXT", Description: "Whether the account is roaming (domain), local, or a system profile"},
+	result.Column{Name: "password_status", Type: "TEXT", Description: "Password status from /etc/shadow, one of active, locked, empty, not_set (Linux)"},
+	result.Column{Name: "password_last_change", Type: "BIGINT", Description: "Time of the last password change from /etc/shadow (Linux)"},
+	result.Column{Name: "expire", Type: "BIGINT", Description: "Time the account expires from /etc/shadow (Linux)"},
 }
@@ -0,0 +1,58 @@
+//go:build linux
+
+package users
+
+import (
+	"fmt"
+
+	"github.com/scrymastic/goosquery/sql/result"
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+	"github.com/scrymastic/goosquery/tables/internal/accounts"
+)
+
+// secondsPerDay converts the dates of /etc/shadow, counted in days
+const secondsPerDay = 24 * 60 * 60
+
+// GenUsers returns the users of /etc/passwd, with the fields of
+// /etc/shadow when it is readable
+func GenUsers(ctx *sqlctx.Context) (*result.Results, error) {
+	entries, err := accounts.ReadUsers()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read users: %w", err)
+	}
+
+	var shadows map[string]accounts.Shadow
+	if ctx.IsAnyOfColumnsUsed([]string{"password_status", "password_last_change", "expire"}) {
+		// Only readable by root
+		shadows, _ = accounts.ReadShadow()
+	}
+
+	users := result.NewQueryResult()
+	for _, entry := range entries {
+		user := result.NewResult(ctx, Schema)
+		user.Set("uid", int64(entry.UID))
+		user.Set("gid", int64(entry.GID))
+		user.Set("uid_signed", int64(int32(entry.UID)))
+		user.Set("gid_signed", int64(int32(entry.GID)))
+		user.Set("username", entry.Name)
+		user.Set("description", entry.Gecos)
+		user.Set("directory", entry.Directory)
+		user.Set("shell", entry.Shell)
+		user.Set("uuid", "")
+		user.Set("type", "local")
+
+		if shadow, ok := shadows[entry.Name]; ok {
+			user.Set("password_status", shadow.PasswordStatus())
+			if shadow.LastChange >= 0 {
+				user.Set("password_last_change", shadow.LastChange*secondsPerDay)
+			}
+			if shadow.Expire >= 0 {
+				user.Set("expire", shadow.Expire*secondsPerDay)
+			}
+		}
+
+		users.AppendResult(*user)
+	}
+
+	return users, nil
+}
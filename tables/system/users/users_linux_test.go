@@ -0,0 +1,82 @@
+//go:build linux
+
+package users
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+	"github.com/scrymastic/goosquery/tables/internal/hostfstest"
+)
+
+// writeAccountsFixture creates the account files of a host with root and a
+// user, and makes the table read them
+func writeAccountsFixture(t *testing.T, withShadow bool) {
+	t.Helper()
+	root := t.TempDir()
+	files := map[string]string{
+		"etc/passwd": "root:x:0:0:root:/root:/bin/bash\n" +
+			"nobody:x:65534:65534:nobody:/nonexistent:/usr/sbin/nologin\n" +
+			"alice:x:1000:1000:Alice Liddell,,,:/home/alice:/bin/zsh\n",
+	}
+	if withShadow {
+		files["etc/shadow"] = "root:!:19000:0:99999:7:::\n" +
+			"alice:$6$salt$hash:19500:0:99999:7::20000:\n"
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	hostfstest.SetRoot(t, root)
+}
+
+func TestGenUsersFromPasswd(t *testing.T) {
+	writeAccountsFixture(t, true)
+	ctx := sqlctx.NewContext()
+	ctx.SetColumns([]string{"*"})
+
+	users, err := GenUsers(ctx)
+	if err != nil {
+		t.Fatalf("Failed to generate users: %v", err)
+	}
+	if users.Size() != 3 {
+		t.Fatalf("Expected 3 users, got %d", users.Size())
+	}
+
+	expected := []map[string]interface{}{
+		{"uid": int64(0), "username": "root", "directory": "/root", "password_status": "not_set", "password_last_change": int64(19000 * 86400), "expire": int64(-1)},
+		{"uid": int64(65534), "uid_signed": int64(65534), "shell": "/usr/sbin/nologin", "password_status": ""},
+		{"uid": int64(1000), "gid": int64(1000), "username": "alice", "description": "Alice Liddell,,,", "shell": "/bin/zsh", "type": "local", "password_status": "active", "expire": int64(20000 * 86400)},
+	}
+	for i, columns := range expected {
+		user := (*users)[i]
+		for column, value := range columns {
+			if user.Get(column) != value {
+				t.Errorf("Expected %s = %v for user %d, got %v", column, value, i, user.Get(column))
+			}
+		}
+	}
+}
+
+func TestGenUsersWithoutShadow(t *testing.T) {
+	// /etc/shadow is only readable by root, so the users are still listed
+	writeAccountsFixture(t, false)
+	ctx := sqlctx.NewContext()
+	ctx.SetColumns([]string{"username", "password_status"})
+
+	users, err := GenUsers(ctx)
+	if err != nil {
+		t.Fatalf("Failed to generate users: %v", err)
+	}
+	if users.Size() != 3 || (*users)[2].Get("username") != "alice" || (*users)[2].Get("password_status") != "" {
+		t.Errorf("Expected the users without shadow fields, got %v", users)
+	}
+}
@@ -0,0 +1,15 @@
+//go:build !linux && !windows
+
+package users
+
+import (
+	"fmt"
+
+	"github.com/scrymastic/goosquery/sql/result"
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+)
+
+// GenUsers fails, as users are only listed on Linux and Windows
+func GenUsers(ctx *sqlctx.Context) (*result.Results, error) {
+	return nil, fmt.Errorf("users are not supported on this platform")
+}
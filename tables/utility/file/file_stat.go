@@ -1,3 +1,5 @@
+//go:build windows
+
 package file
 
 import (
@@ -259,11 +261,11 @@ func GetFileStat(ctx *sqlctx.Context, path string, fileStat *result.Result) erro
 	}
 	if ctx.IsColumnUsed("atime") {
 		winAttr := fileInfo.Sys().(*syscall.Win32FileAttributeData)
-		(*fileStat)["atime"] = (*windows.Filetime)(&winAttr.LastAccessTime).Nanoseconds() / 1e9
+		fileStat.Add("atime", (*windows.Filetime)(&winAttr.LastAccessTime).Nanoseconds()/1e9)
 	}
 	if ctx.IsColumnUsed("btime") {
 		winAttr := fileInfo.Sys().(*syscall.Win32FileAttributeData)
-		(*fileStat)["btime"] = (*windows.Filetime)(&winAttr.CreationTime).Nanoseconds() / 1e9
+		fileStat.Add("btime", (*windows.Filetime)(&winAttr.CreationTime).Nanoseconds()/1e9)
 	}
 
 	hFile, err := windows.CreateFile(
@@ -306,37 +308,37 @@ func GetFileStat(ctx *sqlctx.Context, path string, fileStat *result.Result) erro
 	}
 
 	if ctx.IsColumnUsed("file_id") {
-		(*fileStat)["file_id"] = fmt.Sprintf("0x%016X", uint64(byHandleFileInfo.FileIndexHigh)<<32|uint64(byHandleFileInfo.FileIndexLow))
+		fileStat.Add("file_id", fmt.Sprintf("0x%016X", uint64(byHandleFileInfo.FileIndexHigh)<<32|uint64(byHandleFileInfo.FileIndexLow)))
 	}
 	if ctx.IsColumnUsed("inode") {
-		(*fileStat)["inode"] = int64(uint64(byHandleFileInfo.FileIndexHigh)<<32 | uint64(byHandleFileInfo.FileIndexLow))
+		fileStat.Add("inode", int64(uint64(byHandleFileInfo.FileIndexHigh)<<32|uint64(byHandleFileInfo.FileIndexLow)))
 	}
 	if ctx.IsColumnUsed("uid") {
-		(*fileStat)["uid"] = getRidFromSid(sidOwner)
+		fileStat.Add("uid", getRidFromSid(sidOwner))
 	}
 	if ctx.IsColumnUsed("gid") {
-		(*fileStat)["gid"] = getRidFromSid(sidGroup)
+		fileStat.Add("gid", getRidFromSid(sidGroup))
 	}
 	if ctx.IsColumnUsed("mode") {
-		(*fileStat)["mode"] = "-1"
+		fileStat.Add("mode", "-1")
 	}
 	if ctx.IsColumnUsed("symlink") {
-		(*fileStat)["symlink"] = 0
+		fileStat.Add("symlink", 0)
 	}
 	if ctx.IsColumnUsed("hard_links") {
-		(*fileStat)["hard_links"] = int32(byHandleFileInfo.NumberOfLinks)
+		fileStat.Add("hard_links", int32(byHandleFileInfo.NumberOfLinks))
 	}
 	if ctx.IsColumnUsed("attributes") {
-		(*fileStat)["attributes"] = getFileAttributesString(byHandleFileInfo.FileAttributes)
+		fileStat.Add("attributes", getFileAttributesString(byHandleFileInfo.FileAttributes))
 	}
 	if ctx.IsColumnUsed("device") {
-		(*fileStat)["device"] = int64(byHandleFileInfo.VolumeSerialNumber)
+		fileStat.Add("device", int64(byHandleFileInfo.VolumeSerialNumber))
 	}
 	if ctx.IsColumnUsed("volume_serial") {
-		(*fileStat)["volume_serial"] = fmt.Sprintf("%04X-%04X",
+		fileStat.Add("volume_serial", fmt.Sprintf("%04X-%04X",
 			HIWORD(byHandleFileInfo.VolumeSerialNumber),
 			LOWORD(byHandleFileInfo.VolumeSerialNumber),
-		)
+		))
 	}
 
 	fileType, _, err := procGetFileType.Call(
@@ -346,7 +348,7 @@ func GetFileStat(ctx *sqlctx.Context, path string, fileStat *result.Result) erro
 		return fmt.Errorf("failed to get file type: %w", err)
 	}
 	if ctx.IsColumnUsed("type") {
-		(*fileStat)["type"] = getFileTypeString(uint32(fileType), byHandleFileInfo.FileAttributes, hFile)
+		fileStat.Add("type", getFileTypeString(uint32(fileType), byHandleFileInfo.FileAttributes, hFile))
 	}
 
 	if ctx.IsColumnUsed("block_size") {
@@ -366,7 +368,7 @@ func GetFileStat(ctx *sqlctx.Context, path string, fileStat *result.Result) erro
 				return fmt.Errorf("failed to get disk free space: %w", err)
 			}
 
-			(*fileStat)["block_size"] = int32(bytesPerSect)
+			fileStat.Add("block_size", int32(bytesPerSect))
 		}
 	}
 
@@ -384,7 +386,7 @@ func GetFileStat(ctx *sqlctx.Context, path string, fileStat *result.Result) erro
 		// Cast basicInfo to FILE_BASIC_INFO
 		basicInfoPtr := (*FILE_BASIC_INFO)(unsafe.Pointer(&basicInfo[0]))
 
-		(*fileStat)["ctime"] = basicInfoPtr.ChangeTime.Nanoseconds() / 1e9
+		fileStat.Add("ctime", basicInfoPtr.ChangeTime.Nanoseconds()/1e9)
 	}
 
 	if ctx.IsAnyOfColumnsUsed([]string{"product_version", "file_version", "original_filename"}) {
@@ -394,10 +396,10 @@ func GetFileStat(ctx *sqlctx.Context, path string, fileStat *result.Result) erro
 			fmt.Printf("failed to get version info: %v", err)
 		}
 		if ctx.IsColumnUsed("product_version") {
-			(*fileStat)["product_version"] = productVersion
+			fileStat.Add("product_version", productVersion)
 		}
 		if ctx.IsColumnUsed("file_version") {
-			(*fileStat)["file_version"] = fileVersion
+			fileStat.Add("file_version", fileVersion)
 		}
 	}
 
@@ -407,7 +409,7 @@ func GetFileStat(ctx *sqlctx.Context, path string, fileStat *result.Result) erro
 			// Log error
 			fmt.Printf("failed to get original filename: %v", err)
 		}
-		(*fileStat)["original_filename"] = originalFilename
+		fileStat.Add("original_filename", originalFilename)
 	}
 
 	return nil
@@ -1,3 +1,5 @@
+//go:build windows
+
 package file
 
 import (
@@ -121,24 +123,24 @@ func ParseLnkData(ctx *sqlctx.Context, linkPath string, lnkData *result.Result)
 	// Get shortcut properties
 	if ctx.IsColumnUsed("shortcut_target_path") {
 		targetPath, _ := oleutil.GetProperty(shortcut.ToIDispatch(), "TargetPath")
-		(*lnkData)["shortcut_target_path"] = targetPath.ToString()
+		lnkData.Add("shortcut_target_path", targetPath.ToString())
 	}
 	if ctx.IsColumnUsed("shortcut_start_in") {
 		workingDir, _ := oleutil.GetProperty(shortcut.ToIDispatch(), "WorkingDirectory")
-		(*lnkData)["shortcut_start_in"] = workingDir.ToString()
+		lnkData.Add("shortcut_start_in", workingDir.ToString())
 	}
 	if ctx.IsColumnUsed("shortcut_comment") {
 		description, _ := oleutil.GetProperty(shortcut.ToIDispatch(), "Description")
-		(*lnkData)["shortcut_comment"] = description.ToString()
+		lnkData.Add("shortcut_comment", description.ToString())
 	}
 	if ctx.IsColumnUsed("shortcut_run") {
 		windowStyle, _ := oleutil.GetProperty(shortcut.ToIDispatch(), "WindowStyle")
-		(*lnkData)["shortcut_run"] = showCmdToString(int(windowStyle.Val))
+		lnkData.Add("shortcut_run", showCmdToString(int(windowStyle.Val)))
 	}
 
 	if ctx.IsColumnUsed("shortcut_target_type") {
 		// Get attributes of target path
-		attributes, err := windows.GetFileAttributes(windows.StringToUTF16Ptr((*lnkData)["shortcut_target_path"].(string)))
+		attributes, err := windows.GetFileAttributes(windows.StringToUTF16Ptr(lnkData.Get("shortcut_target_path").(string)))
 		if err != nil {
 			return fmt.Errorf("failed to get file attributes: %v", err)
 		}
@@ -146,7 +148,7 @@ func ParseLnkData(ctx *sqlctx.Context, linkPath string, lnkData *result.Result)
 		var fileInfo SHFILEINFOW
 
 		ret, _, err := procSHGetFileInfoW.Call(
-			uintptr(unsafe.Pointer(windows.StringToUTF16Ptr((*lnkData)["shortcut_target_path"].(string)))),
+			uintptr(unsafe.Pointer(windows.StringToUTF16Ptr(lnkData.Get("shortcut_target_path").(string)))),
 			uintptr(attributes),
 			uintptr(unsafe.Pointer(&fileInfo)),
 			uintptr(unsafe.Sizeof(SHFILEINFOW{})),
@@ -157,11 +159,11 @@ func ParseLnkData(ctx *sqlctx.Context, linkPath string, lnkData *result.Result)
 			return fmt.Errorf("failed to get file info: %v", err)
 		}
 
-		(*lnkData)["shortcut_target_type"] = windows.UTF16ToString(fileInfo.szTypeName[:])
+		lnkData.Add("shortcut_target_type", windows.UTF16ToString(fileInfo.szTypeName[:]))
 	}
 	if ctx.IsColumnUsed("shortcut_target_location") {
 		// Target location is name of the folder that hold targettype
-		(*lnkData)["shortcut_target_location"] = filepath.Base(filepath.Dir((*lnkData)["shortcut_target_path"].(string)))
+		lnkData.Add("shortcut_target_location", filepath.Base(filepath.Dir(lnkData.Get("shortcut_target_path").(string))))
 	}
 
 	return nil
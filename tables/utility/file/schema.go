@@ -7,7 +7,7 @@ import (
 var TableName = "file"
 var Description = "Interactive filesystem attributes and metadata."
 var Schema = result.Schema{
-	result.Column{Name: "path", Type: "TEXT", Description: "Absolute file path"},
+	result.Column{Name: "path", Type: "TEXT", Description: "Absolute file path", Options: result.Required},
 	result.Column{Name: "directory", Type: "TEXT", Description: "Directory of file(s)"},
 	result.Column{Name: "filename", Type: "TEXT", Description: "Name portion of file path"},
 	result.Column{Name: "inode", Type: "BIGINT", Description: "Filesystem inode number"},
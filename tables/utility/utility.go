@@ -1,16 +1,23 @@
 package utility
 
 import (
+	"slices"
+
 	"github.com/scrymastic/goosquery/sql/result"
 	"github.com/scrymastic/goosquery/sql/sqlctx"
 	"github.com/scrymastic/goosquery/tables/utility/file"
 	time_info "github.com/scrymastic/goosquery/tables/utility/time"
 )
 
-// GenFile generates file information for the specified path and directory
-func GenFile(ctx *sqlctx.Context) (*result.Results, error) {
-	return file.GenFiles(ctx)
-}
+// Tables lists the utility tables with their schemas and the platforms providing them
+var Tables = slices.Concat(
+	result.OnPlatform([]result.Table{
+		{Name: time_info.TableName, Description: time_info.Description, Schema: time_info.Schema},
+	}, "windows", "linux", "darwin"),
+	result.OnPlatform([]result.Table{
+		{Name: file.TableName, Description: file.Description, Schema: file.Schema},
+	}, "windows"),
+)
 
 // GenTime generates current date and time information
 func GenTime(ctx *sqlctx.Context) (*result.Results, error) {
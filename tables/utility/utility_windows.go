@@ -0,0 +1,14 @@
+//go:build windows
+
+package utility
+
+import (
+	"github.com/scrymastic/goosquery/sql/result"
+	"github.com/scrymastic/goosquery/sql/sqlctx"
+	"github.com/scrymastic/goosquery/tables/utility/file"
+)
+
+// GenFile generates file information for the specified path and directory
+func GenFile(ctx *sqlctx.Context) (*result.Results, error) {
+	return file.GenFiles(ctx)
+}